@@ -111,12 +111,17 @@ const (
 	// advertised to older SDKs via the deprecated SupportsBindSuccess edge header.
 	RouterBindSuccess RouterCapability = RouterCapability(edge_client_pb.RouterCapability_BindSuccess)
 
+	// RouterMultipath indicates the router can forward a circuit over more than one path, sending acknowledgements
+	// over a single path and dropping the copies of a payload which arrive over the other paths. The controller
+	// only routes a circuit over more than one path when every router on the paths has this capability.
+	RouterMultipath RouterCapability = -3
+
 	// Example of a control-plane-only capability (not advertised). A capability
 	// that only the controller and router need — and that shouldn't wait on an
 	// SDK proto release — is defined here with a negative value, which places it
 	// at the top of the mask, invisible to the SDK and edge-api. Uncomment,
 	// rename, and add it to GetRouterCapabilitiesMask to advertise one; the next
-	// available slot is -1.
+	// available slot is -4.
 	//
 	// RouterExampleControlOnly RouterCapability = -1
 )
@@ -131,6 +136,7 @@ func GetRouterCapabilitiesMask() *RouterCapabilityMask {
 		RouterServiceSubscriptions,
 		RouterPostureChecks,
 		RouterBindSuccess,
+		RouterMultipath,
 	)
 }
 
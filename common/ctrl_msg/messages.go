@@ -84,6 +84,18 @@ const (
 	CreateCircuitV3ReqCircuitIdHeader  = 17
 )
 
+const (
+	// MultipathRouteTag is set on route messages for circuits which are routed over more than one path. The
+	// value is the multipath mode, which tells the endpoint routers how to distribute traffic across the paths.
+	MultipathRouteTag = "multipath"
+
+	// MultipathModeDuplicate sends every payload over every path. The first copy to arrive is used.
+	MultipathModeDuplicate = "duplicate"
+
+	// MultipathModeSpray sends each payload over a single path, rotating through the available paths.
+	MultipathModeSpray = "spray"
+)
+
 func NewCircuitSuccessMsg(sessionId, address string) *channel.Message {
 	msg := channel.NewMessage(CircuitSuccessType, []byte(sessionId))
 	msg.Headers[CircuitSuccessAddressHeader] = []byte(address)
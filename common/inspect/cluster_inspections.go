@@ -0,0 +1,34 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package inspect
+
+import "time"
+
+// ClusterMemberStatusKey is the inspection key used to retrieve a controller's local raft replication state
+const ClusterMemberStatusKey = "cluster-member-status"
+
+// ClusterMemberRaftStatus is a controller's own view of its raft log and state machine
+type ClusterMemberRaftStatus struct {
+	Id                string     `json:"id"`
+	State             string     `json:"state"`
+	AppliedIndex      uint64     `json:"appliedIndex"`
+	CommitIndex       uint64     `json:"commitIndex"`
+	LastLogIndex      uint64     `json:"lastLogIndex"`
+	LastSnapshotIndex uint64     `json:"lastSnapshotIndex"`
+	LastSnapshotTime  *time.Time `json:"lastSnapshotTime,omitempty"`
+	LastContact       *time.Time `json:"lastContact,omitempty"`
+}
@@ -18,6 +18,8 @@ package inspect
 
 type ControllerInspectDetails struct {
 	LeaderId    string                              `json:"leaderId"`
+	Region      string                              `json:"region,omitempty"`
+	Zone        string                              `json:"zone,omitempty"`
 	Controllers map[string]*ControllerInspectDetail `json:"controllers"`
 }
 
@@ -30,4 +32,8 @@ type ControllerInspectDetail struct {
 	Version              string `json:"version"`
 	TimeSinceLastContact string `json:"timeSinceLastContact"`
 	IsLeader             bool   `json:"isLeader"`
+	Region               string `json:"region,omitempty"`
+	Zone                 string `json:"zone,omitempty"`
+	Affinity             string `json:"affinity"`
+	IsPreferred          bool   `json:"isPreferred"`
 }
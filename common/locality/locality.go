@@ -0,0 +1,126 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package locality
+
+import (
+	"fmt"
+
+	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
+)
+
+// Affinity ranks how close two localities are. Higher values are closer.
+type Affinity int
+
+const (
+	AffinityNone   Affinity = 0
+	AffinityRegion Affinity = 1
+	AffinityZone   Affinity = 2
+)
+
+func (self Affinity) String() string {
+	switch self {
+	case AffinityZone:
+		return "zone"
+	case AffinityRegion:
+		return "region"
+	default:
+		return "none"
+	}
+}
+
+// Locality describes where a controller or router runs. Both values are free-form labels, such as a cloud
+// provider's region and availability zone names. A zone is only meaningful within its region.
+type Locality struct {
+	Region string `json:"region,omitempty"`
+	Zone   string `json:"zone,omitempty"`
+}
+
+// IsSet returns true if a region has been configured
+func (self Locality) IsSet() bool {
+	return self.Region != ""
+}
+
+func (self Locality) String() string {
+	if self.Zone == "" {
+		return self.Region
+	}
+	return self.Region + "/" + self.Zone
+}
+
+// AffinityTo reports how close other is to this locality. Unset localities have no affinity to anything, so
+// that a deployment which doesn't configure localities keeps its existing behavior.
+func (self Locality) AffinityTo(other Locality) Affinity {
+	if !self.IsSet() || self.Region != other.Region {
+		return AffinityNone
+	}
+	if self.Zone != "" && self.Zone == other.Zone {
+		return AffinityZone
+	}
+	return AffinityRegion
+}
+
+// PutHeaders adds the locality to a set of hello headers. Nothing is added if no region is configured.
+func (self Locality) PutHeaders(headers map[int32][]byte) {
+	if !self.IsSet() {
+		return
+	}
+	headers[ctrl_pb.RegionHeader] = []byte(self.Region)
+	if self.Zone != "" {
+		headers[ctrl_pb.ZoneHeader] = []byte(self.Zone)
+	}
+}
+
+// FromHeaders reads the locality from a peer's hello headers. Peers which don't report a locality, including
+// those running older versions, return an unset locality.
+func FromHeaders(headers map[int32][]byte) Locality {
+	return Locality{
+		Region: string(headers[ctrl_pb.RegionHeader]),
+		Zone:   string(headers[ctrl_pb.ZoneHeader]),
+	}
+}
+
+// Load parses a locality config section, which has the form:
+//
+//	locality:
+//	  region: us-east-1
+//	  zone: us-east-1a
+func Load(value interface{}) (Locality, error) {
+	result := Locality{}
+
+	submap, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return result, fmt.Errorf("invalid locality configuration, should be map, not %T", value)
+	}
+
+	if val, found := submap["region"]; found {
+		if result.Region, ok = val.(string); !ok {
+			return result, fmt.Errorf("invalid locality.region value, should be string, not %T", val)
+		}
+	}
+
+	if val, found := submap["zone"]; found {
+		if result.Zone, ok = val.(string); !ok {
+			return result, fmt.Errorf("invalid locality.zone value, should be string, not %T", val)
+		}
+	}
+
+	if result.Zone != "" && result.Region == "" {
+		return result, fmt.Errorf("locality.zone '%s' configured without a locality.region", result.Zone)
+	}
+
+	return result, nil
+}
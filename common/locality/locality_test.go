@@ -0,0 +1,81 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package locality
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAffinityTo(t *testing.T) {
+	req := require.New(t)
+
+	local := Locality{Region: "us-east", Zone: "us-east-1a"}
+
+	req.Equal(AffinityZone, local.AffinityTo(Locality{Region: "us-east", Zone: "us-east-1a"}))
+	req.Equal(AffinityRegion, local.AffinityTo(Locality{Region: "us-east", Zone: "us-east-1b"}))
+	req.Equal(AffinityRegion, local.AffinityTo(Locality{Region: "us-east"}))
+	req.Equal(AffinityNone, local.AffinityTo(Locality{Region: "eu-west", Zone: "us-east-1a"}))
+	req.Equal(AffinityNone, local.AffinityTo(Locality{}))
+
+	req.Equal(AffinityRegion, Locality{Region: "us-east"}.AffinityTo(local), "no zone only matches on region")
+	req.Equal(AffinityNone, Locality{}.AffinityTo(Locality{}), "unset localities have no affinity")
+}
+
+func TestHeaders(t *testing.T) {
+	t.Run("round trips", func(t *testing.T) {
+		req := require.New(t)
+		headers := map[int32][]byte{}
+		local := Locality{Region: "us-east", Zone: "us-east-1a"}
+		local.PutHeaders(headers)
+		req.Equal(local, FromHeaders(headers))
+	})
+
+	t.Run("unset adds nothing", func(t *testing.T) {
+		req := require.New(t)
+		headers := map[int32][]byte{}
+		Locality{}.PutHeaders(headers)
+		req.Empty(headers)
+		req.False(FromHeaders(headers).IsSet())
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("region and zone", func(t *testing.T) {
+		req := require.New(t)
+		result, err := Load(map[interface{}]interface{}{"region": "us-east", "zone": "us-east-1a"})
+		req.NoError(err)
+		req.Equal(Locality{Region: "us-east", Zone: "us-east-1a"}, result)
+		req.Equal("us-east/us-east-1a", result.String())
+	})
+
+	t.Run("zone requires region", func(t *testing.T) {
+		req := require.New(t)
+		_, err := Load(map[interface{}]interface{}{"zone": "us-east-1a"})
+		req.Error(err)
+	})
+
+	t.Run("invalid types", func(t *testing.T) {
+		req := require.New(t)
+		_, err := Load("us-east")
+		req.Error(err)
+
+		_, err = Load(map[interface{}]interface{}{"region": 5})
+		req.Error(err)
+	})
+}
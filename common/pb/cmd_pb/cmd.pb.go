@@ -91,6 +91,8 @@ const (
 	CommandType_UpdateEntityType           CommandType = 2
 	CommandType_DeleteEntityType           CommandType = 3
 	CommandType_DeleteTerminatorsBatchType CommandType = 4
+	CommandType_BatchType                  CommandType = 5
+	CommandType_FixIntegrityIssuesType     CommandType = 6
 	CommandType_SyncSnapshot               CommandType = 10
 	CommandType_InitClusterId              CommandType = 11
 )
@@ -103,6 +105,8 @@ var (
 		2:  "UpdateEntityType",
 		3:  "DeleteEntityType",
 		4:  "DeleteTerminatorsBatchType",
+		5:  "BatchType",
+		6:  "FixIntegrityIssuesType",
 		10: "SyncSnapshot",
 		11: "InitClusterId",
 	}
@@ -112,6 +116,8 @@ var (
 		"UpdateEntityType":           2,
 		"DeleteEntityType":           3,
 		"DeleteTerminatorsBatchType": 4,
+		"BatchType":                  5,
+		"FixIntegrityIssuesType":     6,
 		"SyncSnapshot":               10,
 		"InitClusterId":              11,
 	}
@@ -1326,6 +1332,112 @@ func (x *Interface) GetAddresses() []string {
 	return nil
 }
 
+// BatchCommand holds encoded commands which are applied in order, in a single transaction
+type BatchCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commands      [][]byte               `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
+	Ctx           *ChangeContext         `protobuf:"bytes,2,opt,name=ctx,proto3" json:"ctx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCommand) Reset() {
+	*x = BatchCommand{}
+	mi := &file_cmd_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCommand) ProtoMessage() {}
+
+func (x *BatchCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCommand.ProtoReflect.Descriptor instead.
+func (*BatchCommand) Descriptor() ([]byte, []int) {
+	return file_cmd_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BatchCommand) GetCommands() [][]byte {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+func (x *BatchCommand) GetCtx() *ChangeContext {
+	if x != nil {
+		return x.Ctx
+	}
+	return nil
+}
+
+// FixIntegrityIssuesCommand applies the fixes for the given data integrity issues
+type FixIntegrityIssuesCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IssueIds      []string               `protobuf:"bytes,1,rep,name=issueIds,proto3" json:"issueIds,omitempty"`
+	Ctx           *ChangeContext         `protobuf:"bytes,2,opt,name=ctx,proto3" json:"ctx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FixIntegrityIssuesCommand) Reset() {
+	*x = FixIntegrityIssuesCommand{}
+	mi := &file_cmd_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FixIntegrityIssuesCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FixIntegrityIssuesCommand) ProtoMessage() {}
+
+func (x *FixIntegrityIssuesCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FixIntegrityIssuesCommand.ProtoReflect.Descriptor instead.
+func (*FixIntegrityIssuesCommand) Descriptor() ([]byte, []int) {
+	return file_cmd_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *FixIntegrityIssuesCommand) GetIssueIds() []string {
+	if x != nil {
+		return x.IssueIds
+	}
+	return nil
+}
+
+func (x *FixIntegrityIssuesCommand) GetCtx() *ChangeContext {
+	if x != nil {
+		return x.Ctx
+	}
+	return nil
+}
+
 var File_cmd_proto protoreflect.FileDescriptor
 
 const file_cmd_proto_rawDesc = "" +
@@ -1462,7 +1574,13 @@ const file_cmd_proto_rawDesc = "" +
 	"\x03mtu\x18\x03 \x01(\x03R\x03mtu\x12\x14\n" +
 	"\x05index\x18\x04 \x01(\x03R\x05index\x12\x14\n" +
 	"\x05flags\x18\x05 \x01(\x04R\x05flags\x12\x1c\n" +
-	"\taddresses\x18\x06 \x03(\tR\taddresses*\xc3\x01\n" +
+	"\taddresses\x18\x06 \x03(\tR\taddresses\"X\n" +
+	"\fBatchCommand\x12\x1a\n" +
+	"\bcommands\x18\x01 \x03(\fR\bcommands\x12,\n" +
+	"\x03ctx\x18\x02 \x01(\v2\x1a.ziti.cmd.pb.ChangeContextR\x03ctx\"e\n" +
+	"\x19FixIntegrityIssuesCommand\x12\x1a\n" +
+	"\bissueIds\x18\x01 \x03(\tR\bissueIds\x12,\n" +
+	"\x03ctx\x18\x02 \x01(\v2\x1a.ziti.cmd.pb.ChangeContextR\x03ctx*\xc3\x01\n" +
 	"\vContentType\x12\x13\n" +
 	"\x0fContentTypeZero\x10\x00\x12\x14\n" +
 	"\x0fNewLogEntryType\x10\x82\x10\x12\x16\n" +
@@ -1470,13 +1588,15 @@ const file_cmd_proto_rawDesc = "" +
 	"\x13SuccessResponseType\x10\x84\x10\x12\x17\n" +
 	"\x12AddPeerRequestType\x10\x85\x10\x12\x1a\n" +
 	"\x15RemovePeerRequestType\x10\x86\x10\x12\"\n" +
-	"\x1dTransferLeadershipRequestType\x10\x87\x10*\x9e\x01\n" +
+	"\x1dTransferLeadershipRequestType\x10\x87\x10*\xc9\x01\n" +
 	"\vCommandType\x12\b\n" +
 	"\x04Zero\x10\x00\x12\x14\n" +
 	"\x10CreateEntityType\x10\x01\x12\x14\n" +
 	"\x10UpdateEntityType\x10\x02\x12\x14\n" +
 	"\x10DeleteEntityType\x10\x03\x12\x1e\n" +
-	"\x1aDeleteTerminatorsBatchType\x10\x04\x12\x10\n" +
+	"\x1aDeleteTerminatorsBatchType\x10\x04\x12\r\n" +
+	"\tBatchType\x10\x05\x12\x1a\n" +
+	"\x16FixIntegrityIssuesType\x10\x06\x12\x10\n" +
 	"\fSyncSnapshot\x10\n" +
 	"\x12\x11\n" +
 	"\rInitClusterId\x10\vB&Z$github.com/openziti/fabric/pb/cmd_pbb\x06proto3"
@@ -1494,7 +1614,7 @@ func file_cmd_proto_rawDescGZIP() []byte {
 }
 
 var file_cmd_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_cmd_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_cmd_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_cmd_proto_goTypes = []any{
 	(ContentType)(0),                      // 0: ziti.cmd.pb.ContentType
 	(CommandType)(0),                      // 1: ziti.cmd.pb.CommandType
@@ -1514,15 +1634,17 @@ var file_cmd_proto_goTypes = []any{
 	(*Router)(nil),                        // 15: ziti.cmd.pb.Router
 	(*Terminator)(nil),                    // 16: ziti.cmd.pb.Terminator
 	(*Interface)(nil),                     // 17: ziti.cmd.pb.Interface
-	nil,                                   // 18: ziti.cmd.pb.ChangeContext.AttributesEntry
-	nil,                                   // 19: ziti.cmd.pb.Service.TagsEntry
-	nil,                                   // 20: ziti.cmd.pb.Router.TagsEntry
-	nil,                                   // 21: ziti.cmd.pb.Router.CtrlChanListenersEntry
-	nil,                                   // 22: ziti.cmd.pb.Terminator.PeerDataEntry
-	nil,                                   // 23: ziti.cmd.pb.Terminator.TagsEntry
+	(*BatchCommand)(nil),                  // 18: ziti.cmd.pb.BatchCommand
+	(*FixIntegrityIssuesCommand)(nil),     // 19: ziti.cmd.pb.FixIntegrityIssuesCommand
+	nil,                                   // 20: ziti.cmd.pb.ChangeContext.AttributesEntry
+	nil,                                   // 21: ziti.cmd.pb.Service.TagsEntry
+	nil,                                   // 22: ziti.cmd.pb.Router.TagsEntry
+	nil,                                   // 23: ziti.cmd.pb.Router.CtrlChanListenersEntry
+	nil,                                   // 24: ziti.cmd.pb.Terminator.PeerDataEntry
+	nil,                                   // 25: ziti.cmd.pb.Terminator.TagsEntry
 }
 var file_cmd_proto_depIdxs = []int32{
-	18, // 0: ziti.cmd.pb.ChangeContext.attributes:type_name -> ziti.cmd.pb.ChangeContext.AttributesEntry
+	20, // 0: ziti.cmd.pb.ChangeContext.attributes:type_name -> ziti.cmd.pb.ChangeContext.AttributesEntry
 	2,  // 1: ziti.cmd.pb.AddPeerRequest.ctx:type_name -> ziti.cmd.pb.ChangeContext
 	2,  // 2: ziti.cmd.pb.RemovePeerRequest.ctx:type_name -> ziti.cmd.pb.ChangeContext
 	2,  // 3: ziti.cmd.pb.TransferLeadershipRequest.ctx:type_name -> ziti.cmd.pb.ChangeContext
@@ -1530,21 +1652,23 @@ var file_cmd_proto_depIdxs = []int32{
 	2,  // 5: ziti.cmd.pb.UpdateEntityCommand.ctx:type_name -> ziti.cmd.pb.ChangeContext
 	2,  // 6: ziti.cmd.pb.DeleteEntityCommand.ctx:type_name -> ziti.cmd.pb.ChangeContext
 	2,  // 7: ziti.cmd.pb.DeleteTerminatorsBatchCommand.ctx:type_name -> ziti.cmd.pb.ChangeContext
-	19, // 8: ziti.cmd.pb.Service.tags:type_name -> ziti.cmd.pb.Service.TagsEntry
-	20, // 9: ziti.cmd.pb.Router.tags:type_name -> ziti.cmd.pb.Router.TagsEntry
+	21, // 8: ziti.cmd.pb.Service.tags:type_name -> ziti.cmd.pb.Service.TagsEntry
+	22, // 9: ziti.cmd.pb.Router.tags:type_name -> ziti.cmd.pb.Router.TagsEntry
 	17, // 10: ziti.cmd.pb.Router.interfaces:type_name -> ziti.cmd.pb.Interface
-	21, // 11: ziti.cmd.pb.Router.ctrlChanListeners:type_name -> ziti.cmd.pb.Router.CtrlChanListenersEntry
-	22, // 12: ziti.cmd.pb.Terminator.peerData:type_name -> ziti.cmd.pb.Terminator.PeerDataEntry
-	23, // 13: ziti.cmd.pb.Terminator.tags:type_name -> ziti.cmd.pb.Terminator.TagsEntry
-	12, // 14: ziti.cmd.pb.Service.TagsEntry.value:type_name -> ziti.cmd.pb.TagValue
-	12, // 15: ziti.cmd.pb.Router.TagsEntry.value:type_name -> ziti.cmd.pb.TagValue
-	14, // 16: ziti.cmd.pb.Router.CtrlChanListenersEntry.value:type_name -> ziti.cmd.pb.CtrlChanListenerDetail
-	12, // 17: ziti.cmd.pb.Terminator.TagsEntry.value:type_name -> ziti.cmd.pb.TagValue
-	18, // [18:18] is the sub-list for method output_type
-	18, // [18:18] is the sub-list for method input_type
-	18, // [18:18] is the sub-list for extension type_name
-	18, // [18:18] is the sub-list for extension extendee
-	0,  // [0:18] is the sub-list for field type_name
+	23, // 11: ziti.cmd.pb.Router.ctrlChanListeners:type_name -> ziti.cmd.pb.Router.CtrlChanListenersEntry
+	24, // 12: ziti.cmd.pb.Terminator.peerData:type_name -> ziti.cmd.pb.Terminator.PeerDataEntry
+	25, // 13: ziti.cmd.pb.Terminator.tags:type_name -> ziti.cmd.pb.Terminator.TagsEntry
+	2,  // 14: ziti.cmd.pb.BatchCommand.ctx:type_name -> ziti.cmd.pb.ChangeContext
+	2,  // 15: ziti.cmd.pb.FixIntegrityIssuesCommand.ctx:type_name -> ziti.cmd.pb.ChangeContext
+	12, // 16: ziti.cmd.pb.Service.TagsEntry.value:type_name -> ziti.cmd.pb.TagValue
+	12, // 17: ziti.cmd.pb.Router.TagsEntry.value:type_name -> ziti.cmd.pb.TagValue
+	14, // 18: ziti.cmd.pb.Router.CtrlChanListenersEntry.value:type_name -> ziti.cmd.pb.CtrlChanListenerDetail
+	12, // 19: ziti.cmd.pb.Terminator.TagsEntry.value:type_name -> ziti.cmd.pb.TagValue
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_cmd_proto_init() }
@@ -1564,7 +1688,7 @@ func file_cmd_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cmd_proto_rawDesc), len(file_cmd_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   22,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -52,6 +52,14 @@ func (x *DeleteTerminatorsBatchCommand) GetCommandType() int32 {
 	return int32(CommandType_DeleteTerminatorsBatchType)
 }
 
+func (x *BatchCommand) GetCommandType() int32 {
+	return int32(CommandType_BatchType)
+}
+
+func (x *FixIntegrityIssuesCommand) GetCommandType() int32 {
+	return int32(CommandType_FixIntegrityIssuesType)
+}
+
 func (x *SyncSnapshotCommand) GetCommandType() int32 {
 	return int32(CommandType_SyncSnapshot)
 }
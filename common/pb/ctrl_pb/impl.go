@@ -127,3 +127,10 @@ const (
 	LegacyCapabilitiesHeader   int32 = 12
 )
 
+// Locality header IDs, exchanged in both directions on control channel hellos. They sit above the
+// ControlHeaders range so that values added to that enum won't collide with them.
+const (
+	RegionHeader int32 = 1100
+	ZoneHeader   int32 = 1101
+)
+
@@ -0,0 +1,329 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+// Package pcapng writes pipe trace records to pcapng files, so that traced channel and xgress messages can be
+// analysed offline with Wireshark and other standard tooling.
+//
+// Each traced message becomes one packet. Every distinct identity/channel pair gets its own interface, named
+// '<identity>/<channel>', so traffic can be filtered per channel using frame.interface_name.
+//
+// The packet payload is a trace record, laid out as follows. All integers are big-endian.
+//
+//	offset  size  field
+//	0       4     magic, the ASCII string "ZTRC"
+//	4       1     layout version, currently 1
+//	5       1     flags, bit 0 set if the message was received, clear if it was sent
+//	6       2     reserved, zero
+//	8       8     timestamp, nanoseconds since the unix epoch
+//	16      4     content type
+//	20      4     sequence, -1 if not applicable
+//	24      4     reply for, -1 if not a reply
+//	28      4     length of the traced message body
+//	32      2     identity length (n)
+//	34      n     identity
+//	34+n    2     channel length (m)
+//	36+n    m     channel
+//	36+n+m  4     decode length (k)
+//	40+n+m  k     decode, the JSON rendering of the message produced by the trace decoders
+//
+// Records are either written directly with the LINKTYPE_USER0 link type, or encapsulated in synthetic IPv4/UDP
+// packets on port 6262, so that they can be dissected without configuring a user DLT.
+package pcapng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	RecordMagic   = "ZTRC"
+	RecordVersion = 1
+
+	RecordFlagRx = 0x01
+
+	// UdpPort is the source and destination port used for synthetic UDP encapsulation
+	UdpPort = 6262
+
+	LinkTypeUser0 = 147
+	LinkTypeIpv4  = 228
+
+	blockTypeSectionHeader       = 0x0A0D0D0A
+	blockTypeInterfaceDescriptor = 0x00000001
+	blockTypeEnhancedPacket      = 0x00000006
+
+	byteOrderMagic = 0x1A2B3C4D
+
+	optionEndOfOpt    = 0
+	optionIfName      = 2
+	optionIfTsResol   = 9
+	optionShbUserAppl = 4
+
+	maxSnapLen = 262144
+)
+
+// Encapsulation determines how trace records are framed inside packets
+type Encapsulation string
+
+const (
+	EncapsulationUser0 Encapsulation = "user0"
+	EncapsulationUdp   Encapsulation = "udp"
+)
+
+func ParseEncapsulation(val string) (Encapsulation, error) {
+	switch Encapsulation(val) {
+	case EncapsulationUser0, EncapsulationUdp:
+		return Encapsulation(val), nil
+	}
+	return "", errors.Errorf("invalid encapsulation '%s', must be one of %s or %s", val, EncapsulationUser0, EncapsulationUdp)
+}
+
+// Record is a single traced message
+type Record struct {
+	Timestamp   int64
+	Identity    string
+	Channel     string
+	IsRx        bool
+	ContentType int32
+	Sequence    int32
+	ReplyFor    int32
+	Length      int32
+	Decode      []byte
+}
+
+// Encode returns the record in the layout described in the package documentation
+func (self *Record) Encode() ([]byte, error) {
+	if len(self.Identity) > math.MaxUint16 || len(self.Channel) > math.MaxUint16 {
+		return nil, errors.New("trace record identity or channel is too long")
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(RecordMagic)
+	buf.WriteByte(RecordVersion)
+	var flags byte
+	if self.IsRx {
+		flags |= RecordFlagRx
+	}
+	buf.WriteByte(flags)
+	writeBE(buf, uint16(0))
+	writeBE(buf, self.Timestamp)
+	writeBE(buf, self.ContentType)
+	writeBE(buf, self.Sequence)
+	writeBE(buf, self.ReplyFor)
+	writeBE(buf, self.Length)
+	writeBE(buf, uint16(len(self.Identity)))
+	buf.WriteString(self.Identity)
+	writeBE(buf, uint16(len(self.Channel)))
+	buf.WriteString(self.Channel)
+	writeBE(buf, uint32(len(self.Decode)))
+	buf.Write(self.Decode)
+	return buf.Bytes(), nil
+}
+
+// Writer writes trace records to a pcapng stream. It is safe for concurrent use.
+type Writer struct {
+	lock          sync.Mutex
+	out           io.Writer
+	encapsulation Encapsulation
+	interfaces    map[string]uint32
+	ipId          uint16
+}
+
+// NewWriter writes the pcapng section header to the given writer and returns a Writer ready to accept records
+func NewWriter(out io.Writer, encapsulation Encapsulation, application string) (*Writer, error) {
+	if _, err := ParseEncapsulation(string(encapsulation)); err != nil {
+		return nil, err
+	}
+
+	result := &Writer{
+		out:           out,
+		encapsulation: encapsulation,
+		interfaces:    map[string]uint32{},
+	}
+
+	body := &bytes.Buffer{}
+	writeLE(body, uint32(byteOrderMagic))
+	writeLE(body, uint16(1)) // major version
+	writeLE(body, uint16(0)) // minor version
+	writeLE(body, int64(-1)) // section length not specified
+	if application != "" {
+		writeOption(body, optionShbUserAppl, []byte(application))
+	}
+	writeOption(body, optionEndOfOpt, nil)
+
+	if err := result.writeBlock(blockTypeSectionHeader, body.Bytes()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WriteRecord writes the record as a packet, emitting an interface description first if this is the first record
+// seen for the record's identity and channel
+func (self *Writer) WriteRecord(record *Record) error {
+	payload, err := record.Encode()
+	if err != nil {
+		return err
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	interfaceId, err := self.getInterfaceId(record.Identity + "/" + record.Channel)
+	if err != nil {
+		return err
+	}
+
+	if self.encapsulation == EncapsulationUdp {
+		payload, err = self.encapsulateUdp(payload, record.IsRx)
+		if err != nil {
+			return err
+		}
+	}
+
+	captured := payload
+	if len(captured) > maxSnapLen {
+		captured = captured[:maxSnapLen]
+	}
+
+	body := &bytes.Buffer{}
+	writeLE(body, interfaceId)
+	timestamp := uint64(record.Timestamp)
+	writeLE(body, uint32(timestamp>>32))
+	writeLE(body, uint32(timestamp))
+	writeLE(body, uint32(len(captured)))
+	writeLE(body, uint32(len(payload)))
+	body.Write(captured)
+	writePadding(body, len(captured))
+	writeOption(body, optionEndOfOpt, nil)
+
+	return self.writeBlock(blockTypeEnhancedPacket, body.Bytes())
+}
+
+func (self *Writer) getInterfaceId(name string) (uint32, error) {
+	if id, found := self.interfaces[name]; found {
+		return id, nil
+	}
+
+	linkType := uint16(LinkTypeUser0)
+	if self.encapsulation == EncapsulationUdp {
+		linkType = LinkTypeIpv4
+	}
+
+	body := &bytes.Buffer{}
+	writeLE(body, linkType)
+	writeLE(body, uint16(0)) // reserved
+	writeLE(body, uint32(maxSnapLen))
+	writeOption(body, optionIfName, []byte(name))
+	writeOption(body, optionIfTsResol, []byte{9}) // nanosecond timestamps
+	writeOption(body, optionEndOfOpt, nil)
+
+	if err := self.writeBlock(blockTypeInterfaceDescriptor, body.Bytes()); err != nil {
+		return 0, err
+	}
+
+	id := uint32(len(self.interfaces))
+	self.interfaces[name] = id
+	return id, nil
+}
+
+// encapsulateUdp wraps the payload in IPv4 and UDP headers. Sent messages go from 127.0.0.1 to 127.0.0.2 and
+// received messages go the other way, so conversations are grouped by the direction of the traced channel.
+func (self *Writer) encapsulateUdp(payload []byte, rx bool) ([]byte, error) {
+	const ipHeaderLen = 20
+	const udpHeaderLen = 8
+
+	totalLen := ipHeaderLen + udpHeaderLen + len(payload)
+	if totalLen > math.MaxUint16 {
+		return nil, errors.New("trace record too large for udp encapsulation")
+	}
+
+	src := [4]byte{127, 0, 0, 1}
+	dst := [4]byte{127, 0, 0, 2}
+	if rx {
+		src, dst = dst, src
+	}
+
+	self.ipId++
+
+	ipHeader := make([]byte, ipHeaderLen)
+	ipHeader[0] = 0x45 // version 4, 5 word header
+	binary.BigEndian.PutUint16(ipHeader[2:], uint16(totalLen))
+	binary.BigEndian.PutUint16(ipHeader[4:], self.ipId)
+	ipHeader[8] = 64 // ttl
+	ipHeader[9] = 17 // udp
+	copy(ipHeader[12:], src[:])
+	copy(ipHeader[16:], dst[:])
+	binary.BigEndian.PutUint16(ipHeader[10:], ipChecksum(ipHeader))
+
+	udpHeader := make([]byte, udpHeaderLen)
+	binary.BigEndian.PutUint16(udpHeader[0:], UdpPort)
+	binary.BigEndian.PutUint16(udpHeader[2:], UdpPort)
+	binary.BigEndian.PutUint16(udpHeader[4:], uint16(udpHeaderLen+len(payload)))
+	// udp checksum is optional for IPv4 and left as zero
+
+	result := make([]byte, 0, totalLen)
+	result = append(result, ipHeader...)
+	result = append(result, udpHeader...)
+	result = append(result, payload...)
+	return result, nil
+}
+
+func (self *Writer) writeBlock(blockType uint32, body []byte) error {
+	totalLen := uint32(12 + len(body))
+	buf := &bytes.Buffer{}
+	writeLE(buf, blockType)
+	writeLE(buf, totalLen)
+	buf.Write(body)
+	writeLE(buf, totalLen)
+	_, err := self.out.Write(buf.Bytes())
+	return err
+}
+
+func ipChecksum(header []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(header); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(header[i:]))
+	}
+	for sum > 0xFFFF {
+		sum = (sum >> 16) + (sum & 0xFFFF)
+	}
+	return ^uint16(sum)
+}
+
+func writeOption(buf *bytes.Buffer, code uint16, value []byte) {
+	writeLE(buf, code)
+	writeLE(buf, uint16(len(value)))
+	buf.Write(value)
+	writePadding(buf, len(value))
+}
+
+func writePadding(buf *bytes.Buffer, length int) {
+	if rem := length % 4; rem != 0 {
+		buf.Write(make([]byte, 4-rem))
+	}
+}
+
+func writeLE(buf *bytes.Buffer, val any) {
+	_ = binary.Write(buf, binary.LittleEndian, val)
+}
+
+func writeBE(buf *bytes.Buffer, val any) {
+	_ = binary.Write(buf, binary.BigEndian, val)
+}
@@ -0,0 +1,170 @@
+package pcapng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testBlock struct {
+	blockType uint32
+	body      []byte
+}
+
+func readBlocks(t *testing.T, data []byte) []testBlock {
+	req := require.New(t)
+	var result []testBlock
+	for len(data) > 0 {
+		req.GreaterOrEqual(len(data), 12)
+		blockType := binary.LittleEndian.Uint32(data)
+		totalLen := binary.LittleEndian.Uint32(data[4:])
+		req.Zero(totalLen%4, "blocks must be 32 bit aligned")
+		req.GreaterOrEqual(uint32(len(data)), totalLen)
+		req.Equal(totalLen, binary.LittleEndian.Uint32(data[totalLen-4:]))
+		result = append(result, testBlock{blockType: blockType, body: data[8 : totalLen-4]})
+		data = data[totalLen:]
+	}
+	return result
+}
+
+func testRecords() []*Record {
+	return []*Record{
+		{
+			Timestamp:   1_700_000_000_123_456_789,
+			Identity:    "router1",
+			Channel:     "ctrl",
+			ContentType: 1001,
+			Sequence:    7,
+			ReplyFor:    -1,
+			Length:      42,
+			Decode:      []byte(`{"__decoder__":"ctrl"}`),
+		},
+		{
+			Timestamp:   1_700_000_000_223_456_789,
+			Identity:    "router1",
+			Channel:     "ctrl",
+			IsRx:        true,
+			ContentType: 1002,
+			Sequence:    8,
+			ReplyFor:    7,
+			Length:      3,
+		},
+		{
+			Timestamp:   1_700_000_000_323_456_789,
+			Identity:    "router1",
+			Channel:     "l/abc",
+			ContentType: 1100,
+			Sequence:    -1,
+			ReplyFor:    -1,
+			Length:      1024,
+		},
+	}
+}
+
+func TestRecordEncode(t *testing.T) {
+	req := require.New(t)
+	record := testRecords()[0]
+	encoded, err := record.Encode()
+	req.NoError(err)
+
+	req.Equal(RecordMagic, string(encoded[0:4]))
+	req.Equal(byte(RecordVersion), encoded[4])
+	req.Equal(byte(0), encoded[5])
+	req.Equal(uint64(record.Timestamp), binary.BigEndian.Uint64(encoded[8:]))
+	req.Equal(uint32(1001), binary.BigEndian.Uint32(encoded[16:]))
+	req.Equal(uint32(7), binary.BigEndian.Uint32(encoded[20:]))
+	req.Equal(uint32(0xFFFFFFFF), binary.BigEndian.Uint32(encoded[24:]))
+	req.Equal(uint32(42), binary.BigEndian.Uint32(encoded[28:]))
+	req.Equal(uint16(7), binary.BigEndian.Uint16(encoded[32:]))
+	req.Equal("router1", string(encoded[34:41]))
+	req.Equal(uint16(4), binary.BigEndian.Uint16(encoded[41:]))
+	req.Equal("ctrl", string(encoded[43:47]))
+	req.Equal(uint32(len(record.Decode)), binary.BigEndian.Uint32(encoded[47:]))
+	req.Equal(record.Decode, encoded[51:])
+}
+
+func TestWriterUser0(t *testing.T) {
+	req := require.New(t)
+	buf := &bytes.Buffer{}
+	writer, err := NewWriter(buf, EncapsulationUser0, "test")
+	req.NoError(err)
+
+	for _, record := range testRecords() {
+		req.NoError(writer.WriteRecord(record))
+	}
+
+	blocks := readBlocks(t, buf.Bytes())
+	req.Len(blocks, 6)
+
+	req.Equal(uint32(blockTypeSectionHeader), blocks[0].blockType)
+	req.Equal(uint32(byteOrderMagic), binary.LittleEndian.Uint32(blocks[0].body))
+
+	// one interface per identity/channel pair, declared before first use
+	req.Equal(uint32(blockTypeInterfaceDescriptor), blocks[1].blockType)
+	req.Equal(uint16(LinkTypeUser0), binary.LittleEndian.Uint16(blocks[1].body))
+	req.Equal(uint32(blockTypeEnhancedPacket), blocks[2].blockType)
+	req.Equal(uint32(blockTypeEnhancedPacket), blocks[3].blockType)
+	req.Equal(uint32(blockTypeInterfaceDescriptor), blocks[4].blockType)
+	req.Equal(uint32(blockTypeEnhancedPacket), blocks[5].blockType)
+
+	req.Equal(uint32(0), binary.LittleEndian.Uint32(blocks[3].body))
+	req.Equal(uint32(1), binary.LittleEndian.Uint32(blocks[5].body))
+
+	epb := blocks[2].body
+	timestamp := uint64(binary.LittleEndian.Uint32(epb[4:]))<<32 | uint64(binary.LittleEndian.Uint32(epb[8:]))
+	req.Equal(uint64(testRecords()[0].Timestamp), timestamp)
+
+	capturedLen := binary.LittleEndian.Uint32(epb[12:])
+	expected, err := testRecords()[0].Encode()
+	req.NoError(err)
+	req.Equal(uint32(len(expected)), capturedLen)
+	req.Equal(expected, epb[20:20+capturedLen])
+}
+
+func TestWriterUdp(t *testing.T) {
+	req := require.New(t)
+	buf := &bytes.Buffer{}
+	writer, err := NewWriter(buf, EncapsulationUdp, "")
+	req.NoError(err)
+
+	record := testRecords()[1]
+	req.NoError(writer.WriteRecord(record))
+
+	blocks := readBlocks(t, buf.Bytes())
+	req.Len(blocks, 3)
+	req.Equal(uint16(LinkTypeIpv4), binary.LittleEndian.Uint16(blocks[1].body))
+
+	epb := blocks[2].body
+	capturedLen := binary.LittleEndian.Uint32(epb[12:])
+	packet := epb[20 : 20+capturedLen]
+
+	ipHeader := packet[:20]
+	req.Equal(byte(0x45), ipHeader[0])
+	req.Equal(byte(17), ipHeader[9])
+	req.Equal(uint16(0), ipChecksum(ipHeader), "header including its checksum must verify")
+	req.Equal(uint16(len(packet)), binary.BigEndian.Uint16(ipHeader[2:]))
+
+	// received messages flow from 127.0.0.2 to 127.0.0.1
+	req.Equal([]byte{127, 0, 0, 2}, ipHeader[12:16])
+	req.Equal([]byte{127, 0, 0, 1}, ipHeader[16:20])
+
+	udpHeader := packet[20:28]
+	req.Equal(uint16(UdpPort), binary.BigEndian.Uint16(udpHeader[0:]))
+	req.Equal(uint16(UdpPort), binary.BigEndian.Uint16(udpHeader[2:]))
+
+	expected, err := record.Encode()
+	req.NoError(err)
+	req.Equal(expected, packet[28:])
+}
+
+func TestParseEncapsulation(t *testing.T) {
+	req := require.New(t)
+	val, err := ParseEncapsulation("udp")
+	req.NoError(err)
+	req.Equal(EncapsulationUdp, val)
+
+	_, err = ParseEncapsulation("ethernet")
+	req.Error(err)
+}
@@ -0,0 +1,53 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package trace
+
+import (
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/channel/v5/trace/pb"
+	"github.com/openziti/ziti/v2/common/trace/pcapng"
+)
+
+// NewPcapngSink returns an EventHandler which writes trace events to the given pcapng writer
+func NewPcapngSink(writer *pcapng.Writer) EventHandler {
+	return &pcapngSink{writer: writer}
+}
+
+type pcapngSink struct {
+	writer *pcapng.Writer
+}
+
+func (sink *pcapngSink) Accept(event *trace_pb.ChannelMessage) {
+	if err := sink.writer.WriteRecord(ChannelMessageToPcapngRecord(event)); err != nil {
+		pfxlog.Logger().WithError(err).Error("failed to write trace event to pcapng")
+	}
+}
+
+// ChannelMessageToPcapngRecord converts a trace event into a record suitable for writing to a pcapng file
+func ChannelMessageToPcapngRecord(event *trace_pb.ChannelMessage) *pcapng.Record {
+	return &pcapng.Record{
+		Timestamp:   event.Timestamp,
+		Identity:    event.Identity,
+		Channel:     event.Channel,
+		IsRx:        event.IsRx,
+		ContentType: event.ContentType,
+		Sequence:    event.Sequence,
+		ReplyFor:    event.ReplyFor,
+		Length:      event.Length,
+		Decode:      event.Decode,
+	}
+}
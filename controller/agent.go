@@ -24,6 +24,7 @@ const (
 	AgentAddrHeader       = 11
 	AgentIsVoterHeader    = 12
 	AgentSnapshotFileName = 13
+	AgentRestoreOnline    = 14
 )
 
 func (self *Controller) RegisterAgentBindHandler(bindHandler channel.BindHandler) {
@@ -291,22 +292,37 @@ func (self *Controller) agentOpInitFromDb(m *channel.Message, ch channel.Channel
 }
 
 func (self *Controller) agentOpRestoreFromDb(m *channel.Message, ch channel.Channel) {
+	op := "cluster.restore-from-db"
+	online, _ := m.GetBoolHeader(AgentRestoreOnline)
+	if online {
+		op = "cluster.restore"
+	}
+
 	if self.raftController == nil {
-		handler_common.SendOpResult(m, ch, "cluster.restore-from-db", "controller not running in clustered mode", false)
+		handler_common.SendOpResult(m, ch, op, "controller not running in clustered mode", false)
 		return
 	}
 
 	sourceDbPath := string(m.Body)
 	if len(sourceDbPath) == 0 {
-		handler_common.SendOpResult(m, ch, "cluster.restore-from-db", "source db not supplied", false)
+		handler_common.SendOpResult(m, ch, op, "source db not supplied", false)
+		return
+	}
+
+	if online {
+		if err := self.RaftRestoreOnline(sourceDbPath); err != nil {
+			handler_common.SendOpResult(m, ch, op, err.Error(), false)
+			return
+		}
+		handler_common.SendOpResult(m, ch, op, fmt.Sprintf("success, cluster restored from [%v], members will restart", sourceDbPath), true)
 		return
 	}
 
 	if err := self.RaftRestoreFromBoltDb(sourceDbPath); err != nil {
-		handler_common.SendOpResult(m, ch, "cluster.restore-from-db", err.Error(), false)
+		handler_common.SendOpResult(m, ch, op, err.Error(), false)
 		return
 	}
-	handler_common.SendOpResult(m, ch, "cluster.restore-from-db", fmt.Sprintf("success, initialized from [%v]", sourceDbPath), true)
+	handler_common.SendOpResult(m, ch, op, fmt.Sprintf("success, initialized from [%v]", sourceDbPath), true)
 }
 
 func (self *Controller) agentOpInit(m *channel.Message, ch channel.Channel) {
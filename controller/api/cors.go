@@ -35,8 +35,12 @@ func WrapCorsHandler(innerHandler http.Handler) http.Handler {
 			"content-type",
 			"accept",
 			"authorization",
+			"if-match",
 			ZitiSession,
 		}),
+		handlers.ExposedHeaders([]string{
+			"etag",
+		}),
 		handlers.AllowedMethods([]string{
 			http.MethodGet,
 			http.MethodHead,
@@ -460,3 +460,12 @@ func NewTransferLeadershipError(err error) *errorz.ApiError {
 		AppendCause: true,
 	}
 }
+
+func NewPreconditionFailed(err error) *errorz.ApiError {
+	return &errorz.ApiError{
+		AppCode: PreconditionFailedCode,
+		Message: PreconditionFailedMessage,
+		Status:  PreconditionFailedStatus,
+		Cause:   err,
+	}
+}
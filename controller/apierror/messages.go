@@ -234,4 +234,8 @@ const (
 	ClusterHasNoLeaderCode    string = "CLUSTER_NO_LEADER"
 	ClusterHasNoLeaderMessage string = "Cluster has no leader, unable to make model updates."
 	ClusterHasNoLeaderStatus  int    = http.StatusServiceUnavailable
+
+	PreconditionFailedCode    string = "PRECONDITION_FAILED"
+	PreconditionFailedMessage string = "The entity has been modified, its current ETag does not match If-Match"
+	PreconditionFailedStatus  int    = http.StatusPreconditionFailed
 )
@@ -37,6 +37,8 @@ const (
 	SourceMethod  = "src.method"
 	SourceLocal   = "src.local"
 	SourceRemote  = "src.remote"
+	IfMatchKey    = "ifMatch"
+	IfMatchIdKey  = "ifMatch.id"
 )
 
 type AuthorType string
@@ -54,6 +56,7 @@ const (
 	SourceTypeRest           = "rest"
 	SourceTypeWebSocket      = "websocket"
 	SourceTypeXt             = "xt"
+	SourceTypeEst            = "est"
 )
 
 func New() *Context {
@@ -126,6 +129,14 @@ func (self *Context) SetSourceRemote(val string) *Context {
 	return self
 }
 
+// SetIfMatch makes updates and deletes of the entity with the given id conditional on the entity's current
+// entity tag matching one of the tags in ifMatch, which has the format of an HTTP If-Match header
+func (self *Context) SetIfMatch(id string, ifMatch string) *Context {
+	self.Attributes[IfMatchIdKey] = id
+	self.Attributes[IfMatchKey] = ifMatch
+	return self
+}
+
 func (self *Context) GetAuthor() *Author {
 	if self == nil {
 		return nil
@@ -184,6 +195,9 @@ func (self *Context) AddToContext(ctx context.Context) context.Context {
 	if self == nil {
 		return ctx
 	}
+	if id, found := self.Attributes[IfMatchIdKey]; found {
+		ctx = boltz.WithExpectedEntityTags(ctx, id, boltz.ParseEntityTags(self.Attributes[IfMatchKey]))
+	}
 	return context.WithValue(ctx, ContextKey, self)
 }
 
@@ -39,6 +39,7 @@ import (
 	transporttls "github.com/openziti/transport/v2/tls"
 	"github.com/openziti/ziti/v2/common"
 	"github.com/openziti/ziti/v2/common/config"
+	"github.com/openziti/ziti/v2/common/locality"
 	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
 	"github.com/openziti/ziti/v2/common/pb/mgmt_pb"
 	"github.com/openziti/ziti/v2/controller/command"
@@ -129,6 +130,8 @@ type Config struct {
 	Id                     *identity.TokenId
 	SpiffeIdTrustDomain    *url.URL
 	AdditionalTrustDomains []*url.URL
+	// Locality is reported to routers, which prefer controllers in their own region and zone
+	Locality locality.Locality
 
 	Raft    *RaftConfig
 	Network *NetworkConfig
@@ -174,6 +177,8 @@ type Config struct {
 
 	ConnectEventsConfig ConnectEventsConfig
 
+	DbBackup DbBackupConfig
+
 	Src map[interface{}]interface{}
 }
 
@@ -279,6 +284,12 @@ func LoadConfig(path string) (*Config, error) {
 		}
 	}
 
+	if value, found := cfgmap["locality"]; found {
+		if controllerConfig.Locality, err = locality.Load(value); err != nil {
+			return nil, err
+		}
+	}
+
 	if value, found := cfgmap["network"]; found {
 		if submap, ok := value.(map[interface{}]interface{}); ok {
 			if options, err := LoadNetworkConfig(submap); err == nil {
@@ -1070,12 +1081,22 @@ func LoadConfig(path string) (*Config, error) {
 		}
 	}
 
+	if value, found := cfgmap["dbBackup"]; found {
+		if err = loadDbBackupConfig(&controllerConfig.DbBackup, value); err != nil {
+			return nil, err
+		}
+	}
+
 	edgeConfig, err := LoadEdgeConfigFromMap(cfgmap)
 	if err != nil {
 		return nil, err
 	}
 	controllerConfig.Edge = edgeConfig
 
+	if err = edgeConfig.SigningKeyRotation.validateKeyStorage(identityConfig); err != nil {
+		return nil, err
+	}
+
 	return controllerConfig, nil
 }
 
@@ -0,0 +1,169 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultDbBackupInterval       = 24 * time.Hour
+	DefaultDbBackupRetentionCount = 7
+	MinDbBackupInterval           = time.Minute
+)
+
+// DbBackupConfig configures scheduled backups of the controller database.
+//
+//	dbBackup:
+//	  interval: 24h
+//	  offset: 2h
+//	  dir: /var/lib/ziti/backups
+//	  retention:
+//	    count: 7
+//	    maxAge: 720h
+//	  compress: true
+//	  verify: true
+type DbBackupConfig struct {
+	Enabled bool
+
+	// Interval is the time between backups. Backups are aligned to multiples of the interval since the unix epoch,
+	// so a 24h interval produces a backup at midnight UTC and a 6h interval at 00:00, 06:00, 12:00 and 18:00 UTC.
+	Interval time.Duration
+
+	// Offset shifts the aligned backup times. For example, a 24h interval with a 2h offset backs up at 02:00 UTC.
+	Offset time.Duration
+
+	// Dir is the directory backups are written to. Defaults to a backups directory next to the database file.
+	Dir string
+
+	// RetentionCount is the number of backups to keep. Zero means backups aren't removed based on count.
+	RetentionCount int
+
+	// RetentionAge is how long backups are kept. Zero means backups aren't removed based on age.
+	RetentionAge time.Duration
+
+	// Compress enables gzip compression of backups
+	Compress bool
+
+	// Verify enables an integrity check of each backup before it's kept
+	Verify bool
+}
+
+func loadDbBackupConfig(cfg *DbBackupConfig, value interface{}) error {
+	submap, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return errors.Errorf("invalid dbBackup configuration, should be map, not %T", value)
+	}
+
+	cfg.Enabled = true
+	cfg.Interval = DefaultDbBackupInterval
+	cfg.RetentionCount = DefaultDbBackupRetentionCount
+
+	if value, found := submap["enabled"]; found {
+		enabled, ok := value.(bool)
+		if !ok {
+			return errors.Errorf("invalid value for 'dbBackup.enabled', must be a boolean, not %T", value)
+		}
+		cfg.Enabled = enabled
+	}
+
+	if value, found := submap["interval"]; found {
+		val, err := time.ParseDuration(fmt.Sprintf("%v", value))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse dbBackup.interval value '%v'", value)
+		}
+		if val < MinDbBackupInterval {
+			return errors.Errorf("invalid value for 'dbBackup.interval', must be at least %v", MinDbBackupInterval)
+		}
+		cfg.Interval = val
+	}
+
+	if value, found := submap["offset"]; found {
+		val, err := time.ParseDuration(fmt.Sprintf("%v", value))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse dbBackup.offset value '%v'", value)
+		}
+		if val < 0 || val >= cfg.Interval {
+			return errors.New("invalid value for 'dbBackup.offset', must be at least zero and less than the interval")
+		}
+		cfg.Offset = val
+	}
+
+	if value, found := submap["dir"]; found {
+		dir, ok := value.(string)
+		if !ok {
+			return errors.Errorf("invalid value for 'dbBackup.dir', must be a string, not %T", value)
+		}
+		cfg.Dir = dir
+	}
+
+	if value, found := submap["retention"]; found {
+		retention, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return errors.Errorf("invalid dbBackup.retention configuration, should be map, not %T", value)
+		}
+
+		if value, found := retention["count"]; found {
+			count, ok := value.(int)
+			if !ok || count < 0 {
+				return errors.New("invalid value for 'dbBackup.retention.count', must be an integer >= 0")
+			}
+			cfg.RetentionCount = count
+		}
+
+		if value, found := retention["maxAge"]; found {
+			val, err := time.ParseDuration(fmt.Sprintf("%v", value))
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse dbBackup.retention.maxAge value '%v'", value)
+			}
+			if val < 0 {
+				return errors.New("invalid value for 'dbBackup.retention.maxAge', must be >= 0")
+			}
+			cfg.RetentionAge = val
+		}
+	}
+
+	if value, found := submap["compress"]; found {
+		compress, ok := value.(bool)
+		if !ok {
+			return errors.Errorf("invalid value for 'dbBackup.compress', must be a boolean, not %T", value)
+		}
+		cfg.Compress = compress
+	}
+
+	if value, found := submap["verify"]; found {
+		verify, ok := value.(bool)
+		if !ok {
+			return errors.Errorf("invalid value for 'dbBackup.verify', must be a boolean, not %T", value)
+		}
+		cfg.Verify = verify
+	}
+
+	return nil
+}
+
+// NextBackupTime returns the first scheduled backup time after the given time
+func (self *DbBackupConfig) NextBackupTime(after time.Time) time.Time {
+	next := after.Add(-self.Offset).Truncate(self.Interval).Add(self.Offset)
+	for !next.After(after) {
+		next = next.Add(self.Interval)
+	}
+	return next
+}
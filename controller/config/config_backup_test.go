@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDbBackupConfig(t *testing.T) {
+	t.Run("defaults are applied", func(t *testing.T) {
+		req := require.New(t)
+
+		cfg := &DbBackupConfig{}
+		req.NoError(loadDbBackupConfig(cfg, map[interface{}]interface{}{}))
+		req.True(cfg.Enabled)
+		req.Equal(DefaultDbBackupInterval, cfg.Interval)
+		req.Equal(DefaultDbBackupRetentionCount, cfg.RetentionCount)
+		req.False(cfg.Compress)
+		req.False(cfg.Verify)
+	})
+
+	t.Run("values are parsed", func(t *testing.T) {
+		req := require.New(t)
+
+		cfg := &DbBackupConfig{}
+		req.NoError(loadDbBackupConfig(cfg, map[interface{}]interface{}{
+			"interval": "6h",
+			"offset":   "30m",
+			"dir":      "/tmp/backups",
+			"retention": map[interface{}]interface{}{
+				"count":  3,
+				"maxAge": "72h",
+			},
+			"compress": true,
+			"verify":   true,
+		}))
+		req.Equal(6*time.Hour, cfg.Interval)
+		req.Equal(30*time.Minute, cfg.Offset)
+		req.Equal("/tmp/backups", cfg.Dir)
+		req.Equal(3, cfg.RetentionCount)
+		req.Equal(72*time.Hour, cfg.RetentionAge)
+		req.True(cfg.Compress)
+		req.True(cfg.Verify)
+	})
+
+	t.Run("invalid values are rejected", func(t *testing.T) {
+		req := require.New(t)
+
+		req.Error(loadDbBackupConfig(&DbBackupConfig{}, map[interface{}]interface{}{"interval": "10s"}))
+		req.Error(loadDbBackupConfig(&DbBackupConfig{}, map[interface{}]interface{}{"interval": "1h", "offset": "2h"}))
+		req.Error(loadDbBackupConfig(&DbBackupConfig{}, map[interface{}]interface{}{
+			"retention": map[interface{}]interface{}{"count": -1},
+		}))
+	})
+}
+
+func TestDbBackupNextBackupTime(t *testing.T) {
+	req := require.New(t)
+
+	cfg := &DbBackupConfig{
+		Interval: 24 * time.Hour,
+		Offset:   2 * time.Hour,
+	}
+
+	now := time.Date(2026, 10, 18, 1, 30, 0, 0, time.UTC)
+	req.Equal(time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC), cfg.NextBackupTime(now))
+
+	now = time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC)
+	req.Equal(time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC), cfg.NextBackupTime(now))
+
+	cfg.Interval = 6 * time.Hour
+	cfg.Offset = 0
+	now = time.Date(2026, 10, 18, 13, 0, 0, 0, time.UTC)
+	req.Equal(time.Date(2026, 10, 18, 18, 0, 0, 0, time.UTC), cfg.NextBackupTime(now))
+}
@@ -86,6 +86,9 @@ const (
 	DefaultJwksFetchMaxRedirects = 5
 )
 
+// Enrollment configures how identities and routers are enrolled. The SigningCert, which issues their certificates, is
+// not part of SigningKeyRotation, as its replacement has to be issued by the network's CA. It's replaced by hand, by
+// changing the configured files and restarting the controller.
 type Enrollment struct {
 	SigningCert       identity.Identity
 	SigningCertConfig identity.Config
@@ -223,6 +226,7 @@ type EdgeConfig struct {
 	caCertPool           *x509.CertPool
 	DisablePostureChecks bool
 	ExternalJwtSigners   ExternalJwtSigners
+	SigningKeyRotation   SigningKeyRotation
 }
 
 type HttpTimeouts struct {
@@ -1084,6 +1088,10 @@ func LoadEdgeConfigFromMap(configMap map[interface{}]interface{}) (*EdgeConfig,
 		return nil, err
 	}
 
+	if err = edgeConfig.loadSigningKeyRotationSection(edgeConfigMap); err != nil {
+		return nil, err
+	}
+
 	if v, ok := edgeConfigMap["disablePostureChecks"]; ok {
 		if boolVal, ok := v.(bool); ok {
 			edgeConfig.DisablePostureChecks = boolVal
@@ -20,6 +20,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/openziti/ziti/v2/common/ctrl_msg"
 	"github.com/pkg/errors"
 
 	"github.com/sirupsen/logrus"
@@ -37,6 +38,11 @@ const (
 	DefaultOptionsRouterMessagingQueueSize  = 100
 	DefaultOptionsRouteTimeout              = 10 * time.Second
 
+	DefaultOptionsPathSelectionCandidates    = 1
+	DefaultOptionsPathSelectionCostTolerance = 10
+	DefaultOptionsPathSelectionLinkCapacity  = 1
+	MaxPathSelectionCandidates               = 16
+
 	DefaultOptionsSmartRerouteCap          = 4
 	DefaultOptionsSmartRerouteFraction     = 0.02
 	DefaultOptionsSmartRerouteMinCostDelta = 15
@@ -72,6 +78,112 @@ type NetworkConfig struct {
 		RerouteCap      uint32
 		MinCostDelta    uint32
 	}
+	CircuitQuotas CircuitQuotaConfig
+	Multipath     MultipathConfig
+	PathSelection PathSelectionConfig
+}
+
+// PathSelectionConfig controls how new circuits are spread across paths of near-equal cost. When enabled, the
+// controller computes several candidate paths between the endpoint routers and picks the candidate whose busiest
+// link has the lowest number of circuits relative to its capacity.
+type PathSelectionConfig struct {
+	// Candidates is the number of shortest paths to consider. Values below 2 disable load balancing.
+	Candidates uint32
+	// CostTolerance is how far, as a percentage, a candidate's cost may exceed the cheapest path's cost and still
+	// be considered near-equal
+	CostTolerance uint32
+	// DefaultLinkCapacity is the capacity of links whose protocol has no explicit capacity
+	DefaultLinkCapacity uint32
+	// LinkCapacities maps link protocols to the relative capacity of links using that protocol
+	LinkCapacities map[string]uint32
+}
+
+// IsEnabled returns true if circuits should be balanced across multiple candidate paths
+func (self *PathSelectionConfig) IsEnabled() bool {
+	return self.Candidates > 1
+}
+
+// IsNearEqual returns true if the given cost is within the configured tolerance of the best cost
+func (self *PathSelectionConfig) IsNearEqual(cost, bestCost int64) bool {
+	return cost*100 <= bestCost*(100+int64(self.CostTolerance))
+}
+
+// GetLinkCapacity returns the relative capacity of links using the given protocol
+func (self *PathSelectionConfig) GetLinkCapacity(protocol string) uint32 {
+	if capacity, found := self.LinkCapacities[protocol]; found {
+		return capacity
+	}
+	return self.DefaultLinkCapacity
+}
+
+// MultipathConfig selects services whose circuits are routed over two link-disjoint paths
+type MultipathConfig struct {
+	// Services maps service ids to the multipath mode used for circuits of that service
+	Services map[string]string
+}
+
+// GetServiceMode returns the multipath mode for the given service, or an empty string if circuits for the service
+// should use a single path
+func (self *MultipathConfig) GetServiceMode(serviceId string) string {
+	return self.Services[serviceId]
+}
+
+// CircuitQuotaConfig defines limits on the number of concurrent circuits an identity, router or service may have. A
+// limit of zero means unlimited. Circuits are counted by each controller independently, so in an HA cluster a limit
+// applies to the circuits created through each controller, not to the cluster as a whole.
+type CircuitQuotaConfig struct {
+	// IdentityDefault applies to identities which have neither an explicit override nor an identity type limit
+	IdentityDefault uint32
+	// IdentityTypes maps identity type ids to the limit applied to identities of that type
+	IdentityTypes map[string]uint32
+	// Identities maps identity ids to an identity specific limit
+	Identities map[string]uint32
+	// RouterDefault applies to circuits originated by routers, such as those from tunneler enabled edge routers,
+	// which don't have an explicit override
+	RouterDefault uint32
+	// Routers maps router ids to a router specific limit
+	Routers map[string]uint32
+	// ServiceDefault applies to services which don't have an explicit override
+	ServiceDefault uint32
+	// Services maps service ids to a service specific limit
+	Services map[string]uint32
+}
+
+// IsEnabled returns true if any circuit quota has been configured
+func (self *CircuitQuotaConfig) IsEnabled() bool {
+	return self.IdentityDefault > 0 || len(self.IdentityTypes) > 0 || len(self.Identities) > 0 ||
+		self.RouterDefault > 0 || len(self.Routers) > 0 ||
+		self.ServiceDefault > 0 || len(self.Services) > 0
+}
+
+// GetIdentityLimit returns the circuit limit for the given identity. The identity type is only consulted if there
+// is no identity specific override, so the typeProvider is only invoked when needed.
+func (self *CircuitQuotaConfig) GetIdentityLimit(identityId string, typeProvider func() string) uint32 {
+	if limit, found := self.Identities[identityId]; found {
+		return limit
+	}
+	if len(self.IdentityTypes) > 0 {
+		if limit, found := self.IdentityTypes[typeProvider()]; found {
+			return limit
+		}
+	}
+	return self.IdentityDefault
+}
+
+// GetRouterLimit returns the circuit limit for circuits originated by the given router
+func (self *CircuitQuotaConfig) GetRouterLimit(routerId string) uint32 {
+	if limit, found := self.Routers[routerId]; found {
+		return limit
+	}
+	return self.RouterDefault
+}
+
+// GetServiceLimit returns the circuit limit for the given service
+func (self *CircuitQuotaConfig) GetServiceLimit(serviceId string) uint32 {
+	if limit, found := self.Services[serviceId]; found {
+		return limit
+	}
+	return self.ServiceDefault
 }
 
 func DefaultNetworkConfig() *NetworkConfig {
@@ -100,6 +212,11 @@ func DefaultNetworkConfig() *NetworkConfig {
 			RerouteCap:      DefaultOptionsSmartRerouteCap,
 			MinCostDelta:    DefaultOptionsSmartRerouteMinCostDelta,
 		},
+		PathSelection: PathSelectionConfig{
+			Candidates:          DefaultOptionsPathSelectionCandidates,
+			CostTolerance:       DefaultOptionsPathSelectionCostTolerance,
+			DefaultLinkCapacity: DefaultOptionsPathSelectionLinkCapacity,
+		},
 	}
 	return options
 }
@@ -164,6 +281,36 @@ func LoadNetworkConfig(src map[interface{}]interface{}) (*NetworkConfig, error)
 		}
 	}
 
+	if value, found := src["circuitQuotas"]; found {
+		if submap, ok := value.(map[interface{}]interface{}); ok {
+			if err := loadCircuitQuotaConfig(&options.CircuitQuotas, submap); err != nil {
+				return nil, err
+			}
+		} else {
+			return nil, errors.New("invalid value for 'circuitQuotas'")
+		}
+	}
+
+	if value, found := src["multipath"]; found {
+		if submap, ok := value.(map[interface{}]interface{}); ok {
+			if err := loadMultipathConfig(&options.Multipath, submap); err != nil {
+				return nil, err
+			}
+		} else {
+			return nil, errors.New("invalid value for 'multipath'")
+		}
+	}
+
+	if value, found := src["pathSelection"]; found {
+		if submap, ok := value.(map[interface{}]interface{}); ok {
+			if err := loadPathSelectionConfig(&options.PathSelection, submap); err != nil {
+				return nil, err
+			}
+		} else {
+			return nil, errors.New("invalid value for 'pathSelection'")
+		}
+	}
+
 	if value, found := src["routerMessaging"]; found {
 		if submap, ok := value.(map[interface{}]interface{}); ok {
 			if value, found := submap["queueSize"]; found {
@@ -268,3 +415,202 @@ func LoadNetworkConfig(src map[interface{}]interface{}) (*NetworkConfig, error)
 
 	return options, nil
 }
+
+func loadCircuitQuotaConfig(quotas *CircuitQuotaConfig, src map[interface{}]interface{}) error {
+	if value, found := src["identity"]; found {
+		submap, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return errors.New("invalid value for 'circuitQuotas.identity'")
+		}
+
+		if value, found := submap["default"]; found {
+			limit, err := parseCircuitQuotaLimit("circuitQuotas.identity.default", value)
+			if err != nil {
+				return err
+			}
+			quotas.IdentityDefault = limit
+		}
+
+		if value, found := submap["types"]; found {
+			limits, err := parseCircuitQuotaLimits("circuitQuotas.identity.types", value)
+			if err != nil {
+				return err
+			}
+			quotas.IdentityTypes = limits
+		}
+
+		if value, found := submap["overrides"]; found {
+			limits, err := parseCircuitQuotaLimits("circuitQuotas.identity.overrides", value)
+			if err != nil {
+				return err
+			}
+			quotas.Identities = limits
+		}
+	}
+
+	if value, found := src["router"]; found {
+		submap, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return errors.New("invalid value for 'circuitQuotas.router'")
+		}
+
+		if value, found := submap["default"]; found {
+			limit, err := parseCircuitQuotaLimit("circuitQuotas.router.default", value)
+			if err != nil {
+				return err
+			}
+			quotas.RouterDefault = limit
+		}
+
+		if value, found := submap["overrides"]; found {
+			limits, err := parseCircuitQuotaLimits("circuitQuotas.router.overrides", value)
+			if err != nil {
+				return err
+			}
+			quotas.Routers = limits
+		}
+	}
+
+	if value, found := src["service"]; found {
+		submap, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return errors.New("invalid value for 'circuitQuotas.service'")
+		}
+
+		if value, found := submap["default"]; found {
+			limit, err := parseCircuitQuotaLimit("circuitQuotas.service.default", value)
+			if err != nil {
+				return err
+			}
+			quotas.ServiceDefault = limit
+		}
+
+		if value, found := submap["overrides"]; found {
+			limits, err := parseCircuitQuotaLimits("circuitQuotas.service.overrides", value)
+			if err != nil {
+				return err
+			}
+			quotas.Services = limits
+		}
+	}
+
+	return nil
+}
+
+func loadMultipathConfig(multipath *MultipathConfig, src map[interface{}]interface{}) error {
+	value, found := src["services"]
+	if !found {
+		return nil
+	}
+
+	submap, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return errors.New("invalid value for 'multipath.services'")
+	}
+
+	multipath.Services = map[string]string{}
+	for k, v := range submap {
+		serviceId, ok := k.(string)
+		if !ok {
+			return errors.Errorf("invalid key '%v' in 'multipath.services', must be a string", k)
+		}
+		mode, ok := v.(string)
+		if !ok || (mode != ctrl_msg.MultipathModeDuplicate && mode != ctrl_msg.MultipathModeSpray) {
+			return errors.Errorf("invalid value for 'multipath.services.%s', must be one of %s or %s",
+				serviceId, ctrl_msg.MultipathModeDuplicate, ctrl_msg.MultipathModeSpray)
+		}
+		multipath.Services[serviceId] = mode
+	}
+	return nil
+}
+
+func loadPathSelectionConfig(pathSelection *PathSelectionConfig, src map[interface{}]interface{}) error {
+	if value, found := src["candidates"]; found {
+		candidates, ok := value.(int)
+		if !ok || candidates < 1 || candidates > MaxPathSelectionCandidates {
+			return errors.Errorf("invalid value for 'pathSelection.candidates', must be an integer between 1 and %d", MaxPathSelectionCandidates)
+		}
+		pathSelection.Candidates = uint32(candidates)
+	}
+
+	if value, found := src["costTolerance"]; found {
+		tolerance, ok := value.(int)
+		if !ok || tolerance < 0 || tolerance > 1000 {
+			return errors.New("invalid value for 'pathSelection.costTolerance', must be a percentage between 0 and 1000")
+		}
+		pathSelection.CostTolerance = uint32(tolerance)
+	}
+
+	if value, found := src["linkCapacity"]; found {
+		submap, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return errors.New("invalid value for 'pathSelection.linkCapacity'")
+		}
+
+		if value, found := submap["default"]; found {
+			capacity, err := parseLinkCapacity("pathSelection.linkCapacity.default", value)
+			if err != nil {
+				return err
+			}
+			pathSelection.DefaultLinkCapacity = capacity
+		}
+
+		if value, found := submap["protocols"]; found {
+			protocols, ok := value.(map[interface{}]interface{})
+			if !ok {
+				return errors.New("invalid value for 'pathSelection.linkCapacity.protocols'")
+			}
+			pathSelection.LinkCapacities = map[string]uint32{}
+			for k, v := range protocols {
+				protocol, ok := k.(string)
+				if !ok {
+					return errors.Errorf("invalid key '%v' in 'pathSelection.linkCapacity.protocols', must be a string", k)
+				}
+				capacity, err := parseLinkCapacity("pathSelection.linkCapacity.protocols."+protocol, v)
+				if err != nil {
+					return err
+				}
+				pathSelection.LinkCapacities[protocol] = capacity
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseLinkCapacity(name string, value interface{}) (uint32, error) {
+	capacity, ok := value.(int)
+	if !ok || capacity < 1 || capacity > math.MaxUint32 {
+		return 0, errors.Errorf("invalid value for '%s', must be an integer between 1 and %v", name, uint32(math.MaxUint32))
+	}
+	return uint32(capacity), nil
+}
+
+func parseCircuitQuotaLimits(name string, value interface{}) (map[string]uint32, error) {
+	submap, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.Errorf("invalid value for '%s'", name)
+	}
+
+	result := map[string]uint32{}
+	for k, v := range submap {
+		key, ok := k.(string)
+		if !ok {
+			return nil, errors.Errorf("invalid key '%v' in '%s', must be a string", k, name)
+		}
+		limit, err := parseCircuitQuotaLimit(name+"."+key, v)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = limit
+	}
+	return result, nil
+}
+
+func parseCircuitQuotaLimit(name string, value interface{}) (uint32, error) {
+	limit, ok := value.(int)
+	if !ok || limit < 0 || limit > math.MaxUint32 {
+		return 0, errors.Errorf("invalid value for '%s', must be an integer between 0 and %v", name, uint32(math.MaxUint32))
+	}
+	return uint32(limit), nil
+}
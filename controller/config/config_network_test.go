@@ -0,0 +1,116 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadNetworkConfigCircuitQuotas(t *testing.T) {
+	t.Run("quotas are parsed", func(t *testing.T) {
+		req := require.New(t)
+
+		options, err := LoadNetworkConfig(map[interface{}]interface{}{
+			"circuitQuotas": map[interface{}]interface{}{
+				"identity": map[interface{}]interface{}{
+					"default": 100,
+					"types": map[interface{}]interface{}{
+						"Device": 10,
+					},
+					"overrides": map[interface{}]interface{}{
+						"busy-identity": 1000,
+					},
+				},
+				"router": map[interface{}]interface{}{
+					"default": 2000,
+					"overrides": map[interface{}]interface{}{
+						"busy-router": 0,
+					},
+				},
+				"service": map[interface{}]interface{}{
+					"default": 5000,
+					"overrides": map[interface{}]interface{}{
+						"big-service": 20000,
+					},
+				},
+			},
+		})
+		req.NoError(err)
+
+		quotas := &options.CircuitQuotas
+		req.True(quotas.IsEnabled())
+
+		deviceType := func() string { return "Device" }
+		defaultType := func() string { return "Default" }
+
+		req.Equal(uint32(1000), quotas.GetIdentityLimit("busy-identity", deviceType))
+		req.Equal(uint32(10), quotas.GetIdentityLimit("other", deviceType))
+		req.Equal(uint32(100), quotas.GetIdentityLimit("other", defaultType))
+		req.Equal(uint32(0), quotas.GetRouterLimit("busy-router"))
+		req.Equal(uint32(2000), quotas.GetRouterLimit("other"))
+		req.Equal(uint32(20000), quotas.GetServiceLimit("big-service"))
+		req.Equal(uint32(5000), quotas.GetServiceLimit("other"))
+	})
+
+	t.Run("quotas are disabled by default", func(t *testing.T) {
+		req := require.New(t)
+		options, err := LoadNetworkConfig(map[interface{}]interface{}{})
+		req.NoError(err)
+		req.False(options.CircuitQuotas.IsEnabled())
+	})
+
+	t.Run("a router default alone enables quotas", func(t *testing.T) {
+		req := require.New(t)
+		options, err := LoadNetworkConfig(map[interface{}]interface{}{
+			"circuitQuotas": map[interface{}]interface{}{
+				"router": map[interface{}]interface{}{
+					"default": 500,
+				},
+			},
+		})
+		req.NoError(err)
+		req.True(options.CircuitQuotas.IsEnabled())
+	})
+
+	t.Run("negative limits are rejected", func(t *testing.T) {
+		req := require.New(t)
+		_, err := LoadNetworkConfig(map[interface{}]interface{}{
+			"circuitQuotas": map[interface{}]interface{}{
+				"service": map[interface{}]interface{}{
+					"default": -1,
+				},
+			},
+		})
+		req.Error(err)
+	})
+}
+
+func TestLoadNetworkConfigMultipath(t *testing.T) {
+	t.Run("services are loaded", func(t *testing.T) {
+		req := require.New(t)
+		options, err := LoadNetworkConfig(map[interface{}]interface{}{
+			"multipath": map[interface{}]interface{}{
+				"services": map[interface{}]interface{}{
+					"svc-a": "duplicate",
+					"svc-b": "spray",
+				},
+			},
+		})
+		req.NoError(err)
+		req.Equal("duplicate", options.Multipath.GetServiceMode("svc-a"))
+		req.Equal("spray", options.Multipath.GetServiceMode("svc-b"))
+		req.Equal("", options.Multipath.GetServiceMode("svc-c"))
+	})
+
+	t.Run("invalid modes are rejected", func(t *testing.T) {
+		req := require.New(t)
+		_, err := LoadNetworkConfig(map[interface{}]interface{}{
+			"multipath": map[interface{}]interface{}{
+				"services": map[interface{}]interface{}{
+					"svc-a": "broadcast",
+				},
+			},
+		})
+		req.Error(err)
+	})
+}
@@ -0,0 +1,150 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openziti/identity"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultSigningKeyRotationInterval    = 365 * 24 * time.Hour
+	DefaultSigningKeyRotationPublishLead = 24 * time.Hour
+	DefaultSigningKeyRotationRetention   = 7 * 24 * time.Hour
+	MinSigningKeyRotationInterval        = time.Hour
+
+	pkcs11KeyPrefix = "pkcs11:"
+)
+
+// SigningKeyRotation configures scheduled rotation of the key the controller signs JWTs with. Without it, tokens are
+// signed with the key of the controller's server certificate. Rotated keys are stored on disk, so rotation can't be
+// enabled when the server key is held by a PKCS#11 token. The enrollment signing certificate isn't rotated, see
+// Enrollment.
+//
+//	edge:
+//	  signingKeyRotation:
+//	    interval: 8760h
+//	    publishLead: 24h
+//	    retention: 168h
+//	    dir: /var/lib/ziti/signing-keys
+type SigningKeyRotation struct {
+	Enabled bool
+
+	// Interval is how long a key is used for signing before it's replaced
+	Interval time.Duration
+
+	// PublishLead is how long the next key is published for verification before it's used for signing, so that
+	// routers, peer controllers and clients caching the JWKS have it before the first token signed with it arrives
+	PublishLead time.Duration
+
+	// Retention is how long a replaced key is kept for verification. It should be longer than any token signed with
+	// it stays in use. It's never shorter than the OIDC refresh token duration.
+	Retention time.Duration
+
+	// Dir is where the private keys are stored. Defaults to a signing-keys directory next to the database file.
+	Dir string
+}
+
+func (c *EdgeConfig) loadSigningKeyRotationSection(edgeConfigMap map[interface{}]interface{}) error {
+	if value, found := edgeConfigMap["signingKeyRotation"]; found {
+		return loadSigningKeyRotationConfig(&c.SigningKeyRotation, value)
+	}
+	return nil
+}
+
+func loadSigningKeyRotationConfig(cfg *SigningKeyRotation, value interface{}) error {
+	submap, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return errors.Errorf("invalid edge.signingKeyRotation configuration, should be map, not %T", value)
+	}
+
+	cfg.Enabled = true
+	cfg.Interval = DefaultSigningKeyRotationInterval
+	cfg.PublishLead = DefaultSigningKeyRotationPublishLead
+	cfg.Retention = DefaultSigningKeyRotationRetention
+
+	if value, found := submap["enabled"]; found {
+		enabled, ok := value.(bool)
+		if !ok {
+			return errors.Errorf("invalid value for 'edge.signingKeyRotation.enabled', must be a boolean, not %T", value)
+		}
+		cfg.Enabled = enabled
+	}
+
+	if value, found := submap["interval"]; found {
+		val, err := time.ParseDuration(fmt.Sprintf("%v", value))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse edge.signingKeyRotation.interval value '%v'", value)
+		}
+		if val < MinSigningKeyRotationInterval {
+			return errors.Errorf("invalid value for 'edge.signingKeyRotation.interval', must be at least %v", MinSigningKeyRotationInterval)
+		}
+		cfg.Interval = val
+	}
+
+	if value, found := submap["publishLead"]; found {
+		val, err := time.ParseDuration(fmt.Sprintf("%v", value))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse edge.signingKeyRotation.publishLead value '%v'", value)
+		}
+		cfg.PublishLead = val
+	}
+
+	if cfg.PublishLead < 0 || cfg.PublishLead >= cfg.Interval {
+		return errors.New("invalid value for 'edge.signingKeyRotation.publishLead', must be at least zero and less than the interval")
+	}
+
+	if value, found := submap["retention"]; found {
+		val, err := time.ParseDuration(fmt.Sprintf("%v", value))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse edge.signingKeyRotation.retention value '%v'", value)
+		}
+		if val < 0 {
+			return errors.New("invalid value for 'edge.signingKeyRotation.retention', must be >= 0")
+		}
+		cfg.Retention = val
+	}
+
+	if value, found := submap["dir"]; found {
+		dir, ok := value.(string)
+		if !ok {
+			return errors.Errorf("invalid value for 'edge.signingKeyRotation.dir', must be a string, not %T", value)
+		}
+		cfg.Dir = dir
+	}
+
+	return nil
+}
+
+// validateKeyStorage rejects rotation when the controller's server key is held by a PKCS#11 token. Rotated keys are
+// generated in software and kept on disk, so enabling rotation would move token signing out of the token.
+func (c *SigningKeyRotation) validateKeyStorage(identityConfig *identity.Config) error {
+	if !c.Enabled || identityConfig == nil {
+		return nil
+	}
+
+	if strings.HasPrefix(identityConfig.ServerKey, pkcs11KeyPrefix) {
+		return errors.New("invalid edge.signingKeyRotation configuration, rotation isn't supported when the controller " +
+			"identity's server key is held by a PKCS#11 token, as rotated keys are stored on disk")
+	}
+
+	return nil
+}
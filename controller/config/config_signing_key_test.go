@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openziti/identity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSigningKeyRotationConfig(t *testing.T) {
+	t.Run("defaults are applied", func(t *testing.T) {
+		req := require.New(t)
+
+		cfg := &SigningKeyRotation{}
+		req.NoError(loadSigningKeyRotationConfig(cfg, map[interface{}]interface{}{}))
+		req.True(cfg.Enabled)
+		req.Equal(DefaultSigningKeyRotationInterval, cfg.Interval)
+		req.Equal(DefaultSigningKeyRotationPublishLead, cfg.PublishLead)
+		req.Equal(DefaultSigningKeyRotationRetention, cfg.Retention)
+		req.Empty(cfg.Dir)
+	})
+
+	t.Run("values are parsed", func(t *testing.T) {
+		req := require.New(t)
+
+		cfg := &SigningKeyRotation{}
+		req.NoError(loadSigningKeyRotationConfig(cfg, map[interface{}]interface{}{
+			"interval":    "720h",
+			"publishLead": "12h",
+			"retention":   "48h",
+			"dir":         "/tmp/keys",
+		}))
+		req.Equal(720*time.Hour, cfg.Interval)
+		req.Equal(12*time.Hour, cfg.PublishLead)
+		req.Equal(48*time.Hour, cfg.Retention)
+		req.Equal("/tmp/keys", cfg.Dir)
+	})
+
+	t.Run("invalid values are rejected", func(t *testing.T) {
+		req := require.New(t)
+
+		req.Error(loadSigningKeyRotationConfig(&SigningKeyRotation{}, map[interface{}]interface{}{"interval": "10m"}))
+		req.Error(loadSigningKeyRotationConfig(&SigningKeyRotation{}, map[interface{}]interface{}{"interval": "2h", "publishLead": "2h"}))
+		req.Error(loadSigningKeyRotationConfig(&SigningKeyRotation{}, map[interface{}]interface{}{"retention": "-1h"}))
+		req.Error(loadSigningKeyRotationConfig(&SigningKeyRotation{}, map[interface{}]interface{}{"enabled": "yes"}))
+	})
+}
+
+func TestSigningKeyRotationKeyStorage(t *testing.T) {
+	t.Run("rotation is rejected when the server key is held by a PKCS#11 token", func(t *testing.T) {
+		req := require.New(t)
+		cfg := &SigningKeyRotation{Enabled: true}
+		err := cfg.validateKeyStorage(&identity.Config{ServerKey: "pkcs11:///usr/lib/softhsm/libsofthsm2.so?slot=0&id=01"})
+		req.ErrorContains(err, "PKCS#11")
+	})
+
+	t.Run("rotation is allowed with a file based server key", func(t *testing.T) {
+		req := require.New(t)
+		cfg := &SigningKeyRotation{Enabled: true}
+		req.NoError(cfg.validateKeyStorage(&identity.Config{ServerKey: "file:///etc/ziti/server.key"}))
+		req.NoError(cfg.validateKeyStorage(&identity.Config{ServerKey: "/etc/ziti/server.key"}))
+	})
+
+	t.Run("a PKCS#11 server key is fine when rotation is disabled", func(t *testing.T) {
+		req := require.New(t)
+		cfg := &SigningKeyRotation{}
+		req.NoError(cfg.validateKeyStorage(&identity.Config{ServerKey: "pkcs11:///usr/lib/softhsm/libsofthsm2.so?slot=0&id=01"}))
+	})
+}
@@ -405,6 +405,14 @@ func (c *Controller) initWeb() {
 		if err = c.xweb.GetRegistry().Add(oidcApiFactory); err != nil {
 			pfxlog.Logger().Fatalf("failed to create OIDC API factory: %v", err)
 		}
+
+		if err = c.xweb.GetRegistry().Add(webapis.NewPkiApiFactory(c.env)); err != nil {
+			pfxlog.Logger().Fatalf("failed to create PKI API factory: %v", err)
+		}
+
+		if err = c.xweb.GetRegistry().Add(webapis.NewEstApiFactory(c.env)); err != nil {
+			pfxlog.Logger().Fatalf("failed to create EST API factory: %v", err)
+		}
 	} else {
 		// if no edge  we need 1 default API, make the fabric api the default
 		fabricManagementFactory.MakeDefault = true
@@ -528,6 +536,7 @@ func (c *Controller) Run() error {
 		int32(ctrl_pb.ControlHeaders_CapabilitiesHeader): capabilityMask.Bytes(),
 		ctrl_pb.LegacyCapabilitiesHeader:                 capabilityMask.Bytes(), // for pre-2.0 routers
 	}
+	c.config.Locality.PutHeaders(headers)
 
 	/**
 	 * ctrl listener/accepter.
@@ -619,6 +628,10 @@ func (c *Controller) Run() error {
 		go ctrlDialer.Run()
 	}
 
+	if c.config.DbBackup.Enabled {
+		go c.network.RunDbBackups(&c.config.DbBackup)
+	}
+
 	c.network.Run()
 
 	return nil
@@ -886,23 +899,116 @@ func validateMigrationSourceDb(sourceDb boltz.Db) error {
 	return nil
 }
 
-func (c *Controller) RaftRestoreFromBoltDb(sourceDbPath string) error {
-	log := pfxlog.Logger()
+// validateSnapshotSchemaVersion uses the migration manager to read the edge datastore version of the restore
+// source. Older versions are migrated when the restored controllers restart, but a version newer than this
+// controller supports, or older than it can migrate from, would leave every member unable to start.
+func validateSnapshotSchemaVersion(sourceDb boltz.Db) error {
+	version, err := boltz.NewMigratorManager(sourceDb).GetComponentVersion("edge")
+	if err != nil {
+		return errors.Wrap(err, "unable to read edge datastore version from source db")
+	}
+	return checkSnapshotSchemaVersion(version)
+}
+
+func checkSnapshotSchemaVersion(version int) error {
+	if version > db.CurrentDbVersion {
+		return errors.Errorf("source db edge datastore version %v is newer than the version supported by this controller (%v)", version, db.CurrentDbVersion)
+	}
+	if version < db.MinSupportedDbVersion {
+		return errors.Errorf("source db edge datastore version %v is older than the oldest version which can be migrated (%v)", version, db.MinSupportedDbVersion)
+	}
+	return nil
+}
 
+func (c *Controller) RaftRestoreFromBoltDb(sourceDbPath string) error {
 	if c.raftController == nil {
 		return errors.New("can't initialize non-raft controller using initialize from db")
 	}
 
+	cmd, err := c.newRestoreSnapshotCommand(sourceDbPath)
+	if err != nil {
+		return err
+	}
+
+	if err = c.raftController.Bootstrap(); err != nil {
+		return fmt.Errorf("unable to bootstrap cluster (%w)", err)
+	}
+
+	// Carry the cluster id Bootstrap established so RestoreSnapshot can write it back after the
+	// restore (the migration source has none). Blank here means a bug in Bootstrap, so fail.
+	cmd.ClusterId = c.raftController.GetClusterId()
+	if cmd.ClusterId == "" {
+		return errors.New("cluster id is blank after bootstrap; refusing to restore without a durable cluster id")
+	}
+
+	return c.raftController.Dispatch(cmd)
+}
+
+// RaftRestoreOnline restores a running cluster to the state in the given db snapshot. The snapshot is
+// replicated through raft, so every member installs it, keeps the cluster id and restarts. The snapshot
+// gets a new timeline id, so routers holding a data model from the previous timeline do a full resync.
+func (c *Controller) RaftRestoreOnline(snapshotPath string) error {
+	if c.raftController == nil {
+		return errors.New("can't restore non-raft controller online")
+	}
+	return c.restoreOnline(c.raftController, snapshotPath)
+}
+
+// onlineRestoreCluster is the part of the raft controller an online restore needs
+type onlineRestoreCluster interface {
+	IsBootstrapped() bool
+	IsLeader() bool
+	GetLeaderAddr() string
+	GetClusterId() string
+	Dispatch(cmd command.Command) error
+}
+
+// restoreOnline only runs on the leader. The whole snapshot goes into a single command, which a follower
+// would have to forward to the leader in one message, subject to the peer message size limit and the
+// forwarding timeout. On the leader the command is applied directly.
+func (c *Controller) restoreOnline(cluster onlineRestoreCluster, snapshotPath string) error {
+	if !cluster.IsBootstrapped() {
+		return errors.New("cluster is not initialized, use restore-from-db to initialize it from a db snapshot")
+	}
+
+	leaderAddr := cluster.GetLeaderAddr()
+	if leaderAddr == "" {
+		return errors.New("cluster has no leader, online restore requires a quorum of voting members")
+	}
+
+	if !cluster.IsLeader() {
+		return errors.Errorf("online restore must be run on the cluster leader, the current leader is %v", leaderAddr)
+	}
+
+	clusterId := cluster.GetClusterId()
+	if clusterId == "" {
+		return errors.New("cluster id is blank; refusing to restore without a durable cluster id")
+	}
+
+	cmd, err := c.newRestoreSnapshotCommand(snapshotPath)
+	if err != nil {
+		return err
+	}
+	cmd.ClusterId = clusterId
+
+	return cluster.Dispatch(cmd)
+}
+
+// newRestoreSnapshotCommand validates the source db and packages it as a snapshot command. The source is
+// given a new timeline id, which marks everything derived from the previous db as stale.
+func (c *Controller) newRestoreSnapshotCommand(sourceDbPath string) (*command.SyncSnapshotCommand, error) {
+	log := pfxlog.Logger()
+
 	if _, err := os.Stat(sourceDbPath); err != nil {
 		if os.IsNotExist(err) {
-			return errors.Wrapf(err, "source db not found at [%v]", sourceDbPath)
+			return nil, errors.Wrapf(err, "source db not found at [%v]", sourceDbPath)
 		}
-		return errors.Wrapf(err, "invalid db path [%v]", sourceDbPath)
+		return nil, errors.Wrapf(err, "invalid db path [%v]", sourceDbPath)
 	}
 
 	sourceDb, err := db.Open(sourceDbPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		if err = sourceDb.Close(); err != nil {
@@ -911,12 +1017,16 @@ func (c *Controller) RaftRestoreFromBoltDb(sourceDbPath string) error {
 	}()
 
 	if err = validateMigrationSourceDb(sourceDb); err != nil {
-		return errors.Wrapf(err, "migration source db [%v] is not a valid initialized controller database", sourceDbPath)
+		return nil, errors.Wrapf(err, "migration source db [%v] is not a valid initialized controller database", sourceDbPath)
+	}
+
+	if err = validateSnapshotSchemaVersion(sourceDb); err != nil {
+		return nil, errors.Wrapf(err, "migration source db [%v] can't be restored", sourceDbPath)
 	}
 
 	timelineId, err := sourceDb.GetTimelineId(boltz.TimelineModeForceReset, shortid.Generate)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	log.WithField("timelineId", timelineId).WithField("path", sourceDbPath).Info("restoring from bolt db")
 
@@ -926,31 +1036,18 @@ func (c *Controller) RaftRestoreFromBoltDb(sourceDbPath string) error {
 		if closeErr := gzWriter.Close(); closeErr != nil {
 			log.WithError(closeErr).Error("error closing db snapshot buffer")
 		}
-		return err
+		return nil, err
 	}
 
 	if err = gzWriter.Close(); err != nil {
-		return errors.Wrap(err, "error finishing gz compression of migration snapshot")
+		return nil, errors.Wrap(err, "error finishing gz compression of migration snapshot")
 	}
 
-	cmd := &command.SyncSnapshotCommand{
+	return &command.SyncSnapshotCommand{
 		TimelineId:   timelineId,
 		Snapshot:     buf.Bytes(),
 		SnapshotSink: c.network.RestoreSnapshot,
-	}
-
-	if err = c.raftController.Bootstrap(); err != nil {
-		return fmt.Errorf("unable to bootstrap cluster (%w)", err)
-	}
-
-	// Carry the cluster id Bootstrap established so RestoreSnapshot can write it back after the
-	// restore (the migration source has none). Blank here means a bug in Bootstrap, so fail.
-	cmd.ClusterId = c.raftController.GetClusterId()
-	if cmd.ClusterId == "" {
-		return errors.New("cluster id is blank after bootstrap; refusing to restore without a durable cluster id")
-	}
-
-	return c.raftController.Dispatch(cmd)
+	}, nil
 }
 
 // TODO: this functions is a temporary hack and should be provided by xweb
@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/openziti/ziti/v2/controller/command"
 	"github.com/openziti/ziti/v2/controller/db"
 	"github.com/openziti/ziti/v2/controller/storage/boltz"
 	"github.com/stretchr/testify/require"
@@ -55,6 +56,127 @@ func TestValidateMigrationSourceDb(t *testing.T) {
 	})
 }
 
+func TestValidateSnapshotSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		valid   bool
+	}{
+		{name: "accepts the current version", version: db.CurrentDbVersion, valid: true},
+		{name: "accepts an older version which can be migrated", version: db.MinSupportedDbVersion, valid: true},
+		{name: "rejects a version newer than this controller supports", version: db.CurrentDbVersion + 1},
+		{name: "rejects a version too old to migrate", version: db.MinSupportedDbVersion - 1},
+		{name: "rejects a db with no version", version: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+			sourceDb, err := db.Open(filepath.Join(t.TempDir(), "source.db"))
+			req.NoError(err)
+			defer func() { _ = sourceDb.Close() }()
+
+			if test.version > 0 {
+				req.NoError(setEdgeVersion(sourceDb, test.version))
+			}
+
+			if test.valid {
+				req.NoError(validateSnapshotSchemaVersion(sourceDb))
+			} else {
+				req.Error(validateSnapshotSchemaVersion(sourceDb))
+			}
+		})
+	}
+}
+
+type testRestoreCluster struct {
+	leader     bool
+	leaderAddr string
+	dispatched []command.Command
+}
+
+func (self *testRestoreCluster) IsBootstrapped() bool {
+	return true
+}
+
+func (self *testRestoreCluster) IsLeader() bool {
+	return self.leader
+}
+
+func (self *testRestoreCluster) GetLeaderAddr() string {
+	return self.leaderAddr
+}
+
+func (self *testRestoreCluster) GetClusterId() string {
+	return "test-cluster"
+}
+
+func (self *testRestoreCluster) Dispatch(cmd command.Command) error {
+	self.dispatched = append(self.dispatched, cmd)
+	return nil
+}
+
+func TestRestoreOnline(t *testing.T) {
+	newSnapshot := func(t *testing.T) string {
+		req := require.New(t)
+		path := filepath.Join(t.TempDir(), "snapshot.db")
+		sourceDb, err := db.Open(path)
+		req.NoError(err)
+		req.NoError(addIdentity(sourceDb, "admin-identity", true))
+		req.NoError(setEdgeVersion(sourceDb, db.CurrentDbVersion))
+		req.NoError(sourceDb.Close())
+		return path
+	}
+
+	t.Run("the leader dispatches the snapshot", func(t *testing.T) {
+		req := require.New(t)
+		cluster := &testRestoreCluster{leader: true, leaderAddr: "tls:ctrl1:6262"}
+
+		req.NoError((&Controller{}).restoreOnline(cluster, newSnapshot(t)))
+		req.Len(cluster.dispatched, 1)
+
+		cmd, ok := cluster.dispatched[0].(*command.SyncSnapshotCommand)
+		req.True(ok)
+		req.Equal("test-cluster", cmd.ClusterId)
+		req.NotEmpty(cmd.TimelineId)
+		req.NotEmpty(cmd.Snapshot)
+	})
+
+	t.Run("followers refuse and name the leader", func(t *testing.T) {
+		req := require.New(t)
+		cluster := &testRestoreCluster{leaderAddr: "tls:ctrl1:6262"}
+
+		err := (&Controller{}).restoreOnline(cluster, newSnapshot(t))
+		req.ErrorContains(err, "tls:ctrl1:6262")
+		req.Empty(cluster.dispatched)
+	})
+
+	t.Run("a leaderless cluster refuses", func(t *testing.T) {
+		req := require.New(t)
+		cluster := &testRestoreCluster{}
+
+		req.Error((&Controller{}).restoreOnline(cluster, newSnapshot(t)))
+		req.Empty(cluster.dispatched)
+	})
+
+	t.Run("an invalid snapshot isn't dispatched", func(t *testing.T) {
+		req := require.New(t)
+		cluster := &testRestoreCluster{leader: true, leaderAddr: "tls:ctrl1:6262"}
+
+		req.Error((&Controller{}).restoreOnline(cluster, filepath.Join(t.TempDir(), "missing.db")))
+		req.Empty(cluster.dispatched)
+	})
+}
+
+// setEdgeVersion writes the edge component version where the migration manager reads it from
+func setEdgeVersion(sourceDb boltz.Db, version int) error {
+	return sourceDb.Update(nil, func(ctx boltz.MutateContext) error {
+		versions := boltz.GetOrCreatePath(ctx.Tx(), db.RootBucket, "versions")
+		versions.SetInt64("edge", int64(version), nil)
+		return versions.GetError()
+	})
+}
+
 // addIdentity writes an identity bucket with the isDefaultAdmin field set, using boltz so the
 // stored value is read back the same way the controller reads it.
 func addIdentity(sourceDb boltz.Db, id string, isDefaultAdmin bool) error {
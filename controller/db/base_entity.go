@@ -37,7 +37,9 @@ const (
 	EntityTypeIdentityTypes             = "identityTypes"
 	EntityTypeMfas                      = "mfas"
 	EntityTypeRevocations               = "revocations"
+	EntityTypeRevokedCertificates       = "revokedCertificates"
 	EntityTypeServicePolicies           = "servicePolicies"
+	EntityTypeSigningKeys               = "signingKeys"
 	EntityTypeServiceEdgeRouterPolicies = "serviceEdgeRouterPolicies"
 	EntityTypeSessions                  = "sessions"
 	EntityTypeSessionCerts              = "sessionCerts"
@@ -38,6 +38,14 @@ const (
 	FieldCaExternalIdClaimMatcherCriteria = "externalIdClaim.matcherCriteria"
 	FieldCaExternalIdClaimParser          = "externalIdClaim.parser"
 	FieldCaExternalIdClaimParserCriteria  = "externalIdClaim.parserSeparator"
+
+	FieldCaRevocationCheck                          = "revocationCheck"
+	FieldCaRevocationCheckCrlEnabled                = "revocationCheck.crlEnabled"
+	FieldCaRevocationCheckCrlUrls                   = "revocationCheck.crlUrls"
+	FieldCaRevocationCheckCrlRefreshIntervalSeconds = "revocationCheck.crlRefreshIntervalSeconds"
+	FieldCaRevocationCheckOcspEnabled               = "revocationCheck.ocspEnabled"
+	FieldCaRevocationCheckOcspUrl                   = "revocationCheck.ocspUrl"
+	FieldCaRevocationCheckFailMode                  = "revocationCheck.failMode"
 )
 
 const (
@@ -54,6 +62,11 @@ const (
 	ExternalIdClaimParserSplit = "SPLIT"
 )
 
+const (
+	RevocationFailModeSoft = "SOFT"
+	RevocationFailModeHard = "HARD"
+)
+
 type Ca struct {
 	boltz.BaseExtEntity
 	Name                      string           `json:"name"`
@@ -67,6 +80,7 @@ type Ca struct {
 	IdentityRoles             []string         `json:"identityRoles"`
 	IdentityNameFormat        string           `json:"identityNameFormat"`
 	ExternalIdClaim           *ExternalIdClaim `json:"externalIdClaim"`
+	RevocationCheck           *RevocationCheck `json:"revocationCheck"`
 }
 
 type ExternalIdClaim struct {
@@ -78,6 +92,17 @@ type ExternalIdClaim struct {
 	Index           int64  `json:"index"`
 }
 
+// RevocationCheck configures how the revocation status of certificates issued by a third party CA is checked
+// during certificate authentication
+type RevocationCheck struct {
+	CrlEnabled                bool     `json:"crlEnabled"`
+	CrlUrls                   []string `json:"crlUrls"`
+	CrlRefreshIntervalSeconds int64    `json:"crlRefreshIntervalSeconds"`
+	OcspEnabled               bool     `json:"ocspEnabled"`
+	OcspUrl                   string   `json:"ocspUrl"`
+	FailMode                  string   `json:"failMode"`
+}
+
 func (entity *Ca) GetName() string {
 	return entity.Name
 }
@@ -147,6 +172,16 @@ func (store *caStoreImpl) FillEntity(entity *Ca, bucket *boltz.TypedBucket) {
 		entity.ExternalIdClaim.ParserCriteria = externalField.GetStringWithDefault(FieldCaExternalIdClaimParserCriteria, "")
 		entity.ExternalIdClaim.Index = externalField.GetInt64WithDefault(FieldCaExternalIdClaimIndex, 0)
 	}
+
+	if revocationField := bucket.GetBucket(FieldCaRevocationCheck); revocationField != nil {
+		entity.RevocationCheck = &RevocationCheck{}
+		entity.RevocationCheck.CrlEnabled = revocationField.GetBoolWithDefault(FieldCaRevocationCheckCrlEnabled, false)
+		entity.RevocationCheck.CrlUrls = revocationField.GetStringList(FieldCaRevocationCheckCrlUrls)
+		entity.RevocationCheck.CrlRefreshIntervalSeconds = revocationField.GetInt64WithDefault(FieldCaRevocationCheckCrlRefreshIntervalSeconds, 0)
+		entity.RevocationCheck.OcspEnabled = revocationField.GetBoolWithDefault(FieldCaRevocationCheckOcspEnabled, false)
+		entity.RevocationCheck.OcspUrl = revocationField.GetStringWithDefault(FieldCaRevocationCheckOcspUrl, "")
+		entity.RevocationCheck.FailMode = revocationField.GetStringWithDefault(FieldCaRevocationCheckFailMode, RevocationFailModeSoft)
+	}
 }
 
 func (store *caStoreImpl) PersistEntity(entity *Ca, ctx *boltz.PersistContext) {
@@ -173,6 +208,18 @@ func (store *caStoreImpl) PersistEntity(entity *Ca, ctx *boltz.PersistContext) {
 	} else {
 		_ = ctx.Bucket.DeleteBucket([]byte(FieldCaExternalIdClaim))
 	}
+
+	if entity.RevocationCheck != nil {
+		revocationField := ctx.Bucket.GetOrCreateBucket(FieldCaRevocationCheck)
+		revocationField.SetBool(FieldCaRevocationCheckCrlEnabled, entity.RevocationCheck.CrlEnabled, ctx.FieldChecker)
+		revocationField.SetStringList(FieldCaRevocationCheckCrlUrls, entity.RevocationCheck.CrlUrls, ctx.FieldChecker)
+		revocationField.SetInt64(FieldCaRevocationCheckCrlRefreshIntervalSeconds, entity.RevocationCheck.CrlRefreshIntervalSeconds, ctx.FieldChecker)
+		revocationField.SetBool(FieldCaRevocationCheckOcspEnabled, entity.RevocationCheck.OcspEnabled, ctx.FieldChecker)
+		revocationField.SetString(FieldCaRevocationCheckOcspUrl, entity.RevocationCheck.OcspUrl, ctx.FieldChecker)
+		revocationField.SetString(FieldCaRevocationCheckFailMode, entity.RevocationCheck.FailMode, ctx.FieldChecker)
+	} else if ctx.ProceedWithSet(FieldCaRevocationCheck) {
+		_ = ctx.Bucket.DeleteBucket([]byte(FieldCaRevocationCheck))
+	}
 }
 
 func (store *caStoreImpl) DeleteById(ctx boltz.MutateContext, id string) error {
@@ -0,0 +1,152 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package db
+
+import (
+	nfpem "github.com/openziti/foundation/v2/pem"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+)
+
+// authenticatorCertRevocationConstraint revokes network issued identity certificates in the same transaction as
+// the authenticator change which invalidates them: deleting the authenticator, or replacing its certificate during
+// re-enrollment or extension.
+type authenticatorCertRevocationConstraint struct {
+	revokedCerts *revokedCertificateStoreImpl
+}
+
+func (self *authenticatorCertRevocationConstraint) ProcessPreCommit(state *boltz.EntityChangeState[*Authenticator]) error {
+	if state.InitialState == nil {
+		return nil
+	}
+
+	initial := state.InitialState.ToCert()
+	if initial == nil || !initial.IsIssuedByNetwork || initial.Pem == "" {
+		return nil
+	}
+
+	switch state.ChangeType {
+	case boltz.EntityDeleted:
+		return self.revokedCerts.revokePem(state.GetCtx(), initial.Pem, RevokedCertificateOwnerIdentity,
+			initial.IdentityId, CertRevocationReasonCessationOfOperation)
+	case boltz.EntityUpdated:
+		if state.FinalState == nil {
+			return nil
+		}
+		if final := state.FinalState.ToCert(); final != nil && final.Pem != initial.Pem {
+			return self.revokedCerts.revokePem(state.GetCtx(), initial.Pem, RevokedCertificateOwnerIdentity,
+				initial.IdentityId, CertRevocationReasonSuperseded)
+		}
+	}
+	return nil
+}
+
+func (self *authenticatorCertRevocationConstraint) ProcessPostCommit(*boltz.EntityChangeState[*Authenticator]) {
+}
+
+// edgeRouterCertRevocationConstraint revokes edge router certificates when the router is deleted or re-enrolled
+type edgeRouterCertRevocationConstraint struct {
+	revokedCerts *revokedCertificateStoreImpl
+}
+
+func (self *edgeRouterCertRevocationConstraint) ProcessPreCommit(state *boltz.EntityChangeState[*EdgeRouter]) error {
+	if state.InitialState == nil || state.InitialState.CertPem == nil || *state.InitialState.CertPem == "" {
+		return nil
+	}
+
+	initialPem := *state.InitialState.CertPem
+
+	switch state.ChangeType {
+	case boltz.EntityDeleted:
+		return self.revokedCerts.revokePem(state.GetCtx(), initialPem, RevokedCertificateOwnerRouter,
+			state.InitialState.Id, CertRevocationReasonCessationOfOperation)
+	case boltz.EntityUpdated:
+		if state.FinalState != nil && (state.FinalState.CertPem == nil || *state.FinalState.CertPem != initialPem) {
+			return self.revokedCerts.revokePem(state.GetCtx(), initialPem, RevokedCertificateOwnerRouter,
+				state.InitialState.Id, CertRevocationReasonSuperseded)
+		}
+	}
+	return nil
+}
+
+func (self *edgeRouterCertRevocationConstraint) ProcessPostCommit(*boltz.EntityChangeState[*EdgeRouter]) {
+}
+
+// identityCertHoldConstraint places the network issued certificates of an identity on hold while the identity is
+// disabled and releases them when it is re-enabled. Deleted identities are covered by their authenticators being
+// deleted.
+type identityCertHoldConstraint struct {
+	revokedCerts *revokedCertificateStoreImpl
+}
+
+func (self *identityCertHoldConstraint) ProcessPreCommit(state *boltz.EntityChangeState[*Identity]) error {
+	if state.ChangeType != boltz.EntityUpdated || state.InitialState == nil || state.FinalState == nil {
+		return nil
+	}
+
+	wasDisabled := state.InitialState.DisabledAt != nil
+	isDisabled := state.FinalState.DisabledAt != nil
+
+	if wasDisabled && !isDisabled {
+		return self.revokedCerts.releaseHolds(state.GetCtx(), state.FinalState.Id)
+	}
+
+	if !wasDisabled && isDisabled {
+		return self.hold(state.GetCtx(), state.FinalState)
+	}
+
+	return nil
+}
+
+func (self *identityCertHoldConstraint) ProcessPostCommit(*boltz.EntityChangeState[*Identity]) {}
+
+func (self *identityCertHoldConstraint) hold(ctx boltz.MutateContext, identity *Identity) error {
+	authenticatorStore := self.revokedCerts.stores.authenticator
+	authenticatorIds := self.revokedCerts.stores.identity.GetRelatedEntitiesIdList(ctx.Tx(), identity.Id, FieldIdentityAuthenticators)
+
+	for _, authenticatorId := range authenticatorIds {
+		authenticator, found, err := authenticatorStore.FindById(ctx.Tx(), authenticatorId)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		cert := authenticator.ToCert()
+		if cert == nil || !cert.IsIssuedByNetwork {
+			continue
+		}
+
+		certs := nfpem.PemStringToCertificates(cert.Pem)
+		if len(certs) == 0 {
+			continue
+		}
+
+		// a time limited disable only holds the certificate until the identity is enabled again
+		expiresAt := certs[0].NotAfter
+		if until := identity.DisabledUntil; until != nil && until.Before(expiresAt) {
+			expiresAt = *until
+		}
+
+		if err = self.revokedCerts.revokeCert(ctx, certs[0], RevokedCertificateOwnerIdentity, identity.Id,
+			CertRevocationReasonCertificateHold, expiresAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
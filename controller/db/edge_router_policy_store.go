@@ -201,21 +201,59 @@ func (store *edgeRouterPolicyStoreImpl) DeleteById(ctx boltz.MutateContext, id s
 }
 
 func (store *edgeRouterPolicyStoreImpl) CheckIntegrity(ctx boltz.MutateContext, fix bool, errorSink func(error, bool)) error {
-	checkCtx := &denormCheckCtx{
-		name:                   "edge-router-policies",
-		mutateCtx:              ctx,
-		sourceStore:            store.stores.identity,
-		targetStore:            store.stores.edgeRouter,
-		policyStore:            store,
-		sourceCollection:       store.identityCollection,
-		targetCollection:       store.edgeRouterCollection,
-		targetDenormCollection: store.stores.identity.edgeRoutersCollection,
-		repair:                 fix,
-		errorSink:              errorSink,
-	}
-	if err := validatePolicyDenormalization(checkCtx); err != nil {
-		return err
+	for _, checkCtx := range store.denormChecks(ctx) {
+		checkCtx.repair = fix
+		checkCtx.errorSink = errorSink
+		if err := validatePolicyDenormalization(checkCtx); err != nil {
+			return err
+		}
 	}
 
 	return store.BaseStore.CheckIntegrity(ctx, fix, errorSink)
 }
+
+func (store *edgeRouterPolicyStoreImpl) denormChecks(ctx boltz.MutateContext) []*denormCheckCtx {
+	return []*denormCheckCtx{
+		{
+			name:                   "edge-router-policies",
+			mutateCtx:              ctx,
+			sourceStore:            store.stores.identity,
+			targetStore:            store.stores.edgeRouter,
+			policyStore:            store,
+			sourceCollection:       store.identityCollection,
+			targetCollection:       store.edgeRouterCollection,
+			targetDenormCollection: store.stores.identity.edgeRoutersCollection,
+		},
+	}
+}
+
+func (store *edgeRouterPolicyStoreImpl) roleChecks() []*policyRoleCheck {
+	reevaluate := func(update func(persistCtx *boltz.PersistContext, policy *EdgeRouterPolicy)) func(ctx boltz.MutateContext, policyId string) error {
+		return func(ctx boltz.MutateContext, policyId string) error {
+			policy, err := store.LoadById(ctx.Tx(), policyId)
+			if err != nil {
+				return err
+			}
+			return reevaluatePolicy(ctx, store, policyId, func(persistCtx *boltz.PersistContext) {
+				update(persistCtx, policy)
+			})
+		}
+	}
+
+	return []*policyRoleCheck{
+		{
+			policyStore:          store,
+			rolesSymbol:          store.symbolIdentityRoles,
+			linkCollection:       store.identityCollection,
+			roleAttributesSymbol: store.stores.identity.symbolRoleAttributes,
+			reevaluate:           reevaluate(store.identityRolesUpdated),
+		},
+		{
+			policyStore:          store,
+			rolesSymbol:          store.symbolEdgeRouterRoles,
+			linkCollection:       store.edgeRouterCollection,
+			roleAttributesSymbol: store.stores.edgeRouter.symbolRoleAttributes,
+			reevaluate:           reevaluate(store.edgeRouterRolesUpdated),
+		},
+	}
+}
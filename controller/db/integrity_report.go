@@ -0,0 +1,564 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/ziti/v2/controller/storage/ast"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	IntegrityCheckPolicyRoles           = "policy-roles"
+	IntegrityCheckPolicyDenormalization = "policy-denormalization"
+	IntegrityCheckRoleAttributeIndexes  = "role-attribute-indexes"
+	IntegrityCheckConfigReferences      = "config-references"
+	IntegrityCheckTerminators           = "terminators"
+)
+
+// IntegrityFixAction describes how one or more integrity issues can be repaired. Issues which are repaired together,
+// such as all the missing links of a policy, share a fix action.
+type IntegrityFixAction struct {
+	Id          string `json:"id"`
+	Description string `json:"description"`
+	apply       func(ctx boltz.MutateContext) error
+}
+
+// IntegrityIssue is a single problem found by the extended integrity checks. The id is derived from the issue
+// contents, so the same problem has the same id each time the checks are run.
+type IntegrityIssue struct {
+	Id          string              `json:"id"`
+	Check       string              `json:"check"`
+	EntityType  string              `json:"entityType"`
+	EntityId    string              `json:"entityId"`
+	Description string              `json:"description"`
+	FixAction   *IntegrityFixAction `json:"fixAction,omitempty"`
+}
+
+// IntegrityFixResult reports the outcome of fixing a single integrity issue
+type IntegrityFixResult struct {
+	IssueId     string `json:"issueId"`
+	FixActionId string `json:"fixActionId,omitempty"`
+	Fixed       bool   `json:"fixed"`
+	Error       string `json:"error,omitempty"`
+}
+
+type integrityIssueCollector struct {
+	issues    []*IntegrityIssue
+	actions   map[string]*IntegrityFixAction
+	maxIssues int
+	truncated bool
+}
+
+func (self *integrityIssueCollector) add(check, entityType, entityId, description string, action *IntegrityFixAction) {
+	if self.maxIssues > 0 && len(self.issues) >= self.maxIssues {
+		self.truncated = true
+		return
+	}
+
+	hash := sha256.Sum256([]byte(check + "|" + entityType + "|" + entityId + "|" + description))
+
+	if action != nil {
+		if existing, ok := self.actions[action.Id]; ok {
+			action = existing
+		} else {
+			self.actions[action.Id] = action
+		}
+	}
+
+	self.issues = append(self.issues, &IntegrityIssue{
+		Id:          hex.EncodeToString(hash[:8]),
+		Check:       check,
+		EntityType:  entityType,
+		EntityId:    entityId,
+		Description: description,
+		FixAction:   action,
+	})
+}
+
+// CheckExtendedIntegrity validates policy role evaluation and denormalization, role attribute indexes, config
+// references and terminator references. Nothing is changed. At most maxIssues issues are returned, unless maxIssues
+// is zero. The returned bool is true if more issues were found than were returned.
+func (stores *Stores) CheckExtendedIntegrity(db boltz.Db, ctx context.Context, maxIssues int) ([]*IntegrityIssue, bool, error) {
+	var issues []*IntegrityIssue
+	var truncated bool
+	err := db.View(func(tx *bbolt.Tx) error {
+		var err error
+		issues, truncated, err = stores.checkExtendedIntegrityInTx(boltz.NewTxMutateContext(ctx, tx), maxIssues)
+		return err
+	})
+	return issues, truncated, err
+}
+
+// GetIntegrityFixResults reports which of the issues with the given ids FixIntegrityIssues would currently fix,
+// without changing anything.
+func (stores *Stores) GetIntegrityFixResults(db boltz.Db, ctx context.Context, issueIds []string) ([]*IntegrityFixResult, error) {
+	var results []*IntegrityFixResult
+	err := db.View(func(tx *bbolt.Tx) error {
+		issues, _, err := stores.checkExtendedIntegrityInTx(boltz.NewTxMutateContext(ctx, tx), 0)
+		if err != nil {
+			return err
+		}
+		results, _ = selectIntegrityFixes(issues, issueIds)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// FixIntegrityIssues re-runs the extended integrity checks and applies the fix actions of the issues with the given
+// ids. Issues which no longer exist or which have no fix action are reported as not fixed. A snapshot of the
+// database is made before any fixes are applied. If any fix action fails, none of the fixes are kept. Fixes are
+// applied directly to the given database, so on a controller which is part of an HA cluster this must only be
+// called when applying a replicated command.
+func (stores *Stores) FixIntegrityIssues(db boltz.Db, ctx boltz.MutateContext, issueIds []string) ([]*IntegrityFixResult, error) {
+	var results []*IntegrityFixResult
+	err := db.Update(ctx, func(ctx boltz.MutateContext) error {
+		issues, _, err := stores.checkExtendedIntegrityInTx(ctx, 0)
+		if err != nil {
+			return err
+		}
+
+		var actions []*IntegrityFixAction
+		results, actions = selectIntegrityFixes(issues, issueIds)
+
+		if len(actions) == 0 {
+			return nil
+		}
+
+		pfxlog.Logger().Info("creating database snapshot before fixing data integrity issues")
+		if _, _, err = db.SnapshotInTx(ctx.Tx(), db.GetDefaultSnapshotPath()); err != nil {
+			return err
+		}
+
+		for _, action := range actions {
+			pfxlog.Logger().WithField("fixActionId", action.Id).Infof("applying data integrity fix: %s", action.Description)
+			if err = action.apply(ctx); err != nil {
+				return fmt.Errorf("data integrity fix '%s' failed (%w)", action.Id, err)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// selectIntegrityFixes returns a result for each of the given issue ids, along with the distinct fix actions of
+// the issues which can be fixed
+func selectIntegrityFixes(issues []*IntegrityIssue, issueIds []string) ([]*IntegrityFixResult, []*IntegrityFixAction) {
+	issueMap := map[string]*IntegrityIssue{}
+	for _, issue := range issues {
+		issueMap[issue.Id] = issue
+	}
+
+	var results []*IntegrityFixResult
+	var actions []*IntegrityFixAction
+	actionSet := map[string]struct{}{}
+
+	for _, issueId := range issueIds {
+		result := &IntegrityFixResult{IssueId: issueId}
+		results = append(results, result)
+
+		issue, found := issueMap[issueId]
+		if !found {
+			result.Error = "issue not found, it may already have been fixed"
+			continue
+		}
+		if issue.FixAction == nil {
+			result.Error = "issue has no automated fix"
+			continue
+		}
+
+		result.FixActionId = issue.FixAction.Id
+		result.Fixed = true
+		if _, ok := actionSet[issue.FixAction.Id]; !ok {
+			actionSet[issue.FixAction.Id] = struct{}{}
+			actions = append(actions, issue.FixAction)
+		}
+	}
+
+	return results, actions
+}
+
+func (stores *Stores) checkExtendedIntegrityInTx(ctx boltz.MutateContext, maxIssues int) ([]*IntegrityIssue, bool, error) {
+	collector := &integrityIssueCollector{
+		actions:   map[string]*IntegrityFixAction{},
+		maxIssues: maxIssues,
+	}
+
+	internal := stores.internal
+
+	var roleChecks []*policyRoleCheck
+	roleChecks = append(roleChecks, internal.servicePolicy.roleChecks()...)
+	roleChecks = append(roleChecks, internal.edgeRouterPolicy.roleChecks()...)
+	roleChecks = append(roleChecks, internal.serviceEdgeRouterPolicy.roleChecks()...)
+	for _, check := range roleChecks {
+		check.collectIssues(ctx.Tx(), collector)
+	}
+
+	var denormChecks []*denormCheckCtx
+	denormChecks = append(denormChecks, internal.servicePolicy.denormChecks(ctx)...)
+	denormChecks = append(denormChecks, internal.edgeRouterPolicy.denormChecks(ctx)...)
+	denormChecks = append(denormChecks, internal.serviceEdgeRouterPolicy.denormChecks(ctx)...)
+	for _, check := range denormChecks {
+		check.collectIssues(collector)
+	}
+
+	roleAttributeIndexes := []boltz.SetReadIndex{
+		internal.identity.indexRoleAttributes,
+		internal.service.indexRoleAttributes,
+		internal.edgeRouter.indexRoleAttributes,
+		internal.postureCheck.indexRoleAttributes,
+	}
+	for _, index := range roleAttributeIndexes {
+		collectRoleAttributeIndexIssues(ctx.Tx(), index, collector)
+	}
+
+	stores.collectConfigReferenceIssues(ctx.Tx(), collector)
+	stores.collectTerminatorIssues(ctx.Tx(), collector)
+
+	return collector.issues, collector.truncated, nil
+}
+
+// policyRoleCheck verifies that the entities linked to a policy are exactly the entities selected by its roles
+type policyRoleCheck struct {
+	policyStore          boltz.Store
+	rolesSymbol          boltz.EntitySetSymbol
+	linkCollection       boltz.LinkCollection
+	roleAttributesSymbol boltz.EntitySetSymbol
+	entityFilter         func(tx *bbolt.Tx, entityId []byte) bool
+	reevaluate           func(ctx boltz.MutateContext, policyId string) error
+}
+
+func (self *policyRoleCheck) collectIssues(tx *bbolt.Tx, collector *integrityIssueCollector) {
+	targetStore := self.linkCollection.GetLinkedSymbol().GetStore()
+	semanticSymbol := self.policyStore.GetSymbol(FieldSemantic)
+	policyType := self.policyStore.GetSingularEntityType()
+	targetType := targetStore.GetSingularEntityType()
+
+	entityAttributes := map[string][]string{}
+	for cursor := self.roleAttributesSymbol.GetStore().IterateIds(tx, ast.BoolNodeTrue); cursor.IsValid(); cursor.Next() {
+		entityId := cursor.Current()
+		if self.entityFilter != nil && !self.entityFilter(tx, entityId) {
+			continue
+		}
+		entityAttributes[string(entityId)] = self.roleAttributesSymbol.EvalStringList(tx, entityId)
+	}
+
+	for policyCursor := self.policyStore.IterateIds(tx, ast.BoolNodeTrue); policyCursor.IsValid(); policyCursor.Next() {
+		policyId := string(policyCursor.Current())
+
+		roles, ids, err := splitRolesAndIds(self.rolesSymbol.EvalStringList(tx, []byte(policyId)))
+		if err != nil {
+			collector.add(IntegrityCheckPolicyRoles, self.policyStore.GetEntityType(), policyId,
+				fmt.Sprintf("%s %s has invalid %s: %v", policyType, policyId, self.rolesSymbol.GetName(), err), nil)
+			continue
+		}
+
+		semantic := SemanticAllOf
+		if _, semanticValue := semanticSymbol.Eval(tx, []byte(policyId)); semanticValue != nil {
+			semantic = string(semanticValue)
+		}
+
+		action := &IntegrityFixAction{
+			Id:          fmt.Sprintf("reevaluate-policy:%s/%s/%s", self.policyStore.GetEntityType(), policyId, self.rolesSymbol.GetName()),
+			Description: fmt.Sprintf("re-evaluate %s of %s %s", self.rolesSymbol.GetName(), policyType, policyId),
+			apply: func(ctx boltz.MutateContext) error {
+				return self.reevaluate(ctx, policyId)
+			},
+		}
+
+		linked := map[string]struct{}{}
+		for _, linkedId := range self.linkCollection.GetLinks(tx, policyId) {
+			linked[linkedId] = struct{}{}
+			if _, found := entityAttributes[linkedId]; !found {
+				collector.add(IntegrityCheckPolicyRoles, self.policyStore.GetEntityType(), policyId,
+					fmt.Sprintf("%s %s is linked to %s %s, which doesn't exist or can't be selected by policies",
+						policyType, policyId, targetType, linkedId), action)
+			}
+		}
+
+		for _, entityId := range sortedKeys(entityAttributes) {
+			matches := policyMatchesEntity(semantic, entityId, ids, roles, entityAttributes[entityId])
+			_, isLinked := linked[entityId]
+			if matches && !isLinked {
+				collector.add(IntegrityCheckPolicyRoles, self.policyStore.GetEntityType(), policyId,
+					fmt.Sprintf("%s %s selects %s %s with %s, but isn't linked to it",
+						policyType, policyId, targetType, entityId, self.rolesSymbol.GetName()), action)
+			} else if !matches && isLinked {
+				collector.add(IntegrityCheckPolicyRoles, self.policyStore.GetEntityType(), policyId,
+					fmt.Sprintf("%s %s is linked to %s %s, but doesn't select it with %s",
+						policyType, policyId, targetType, entityId, self.rolesSymbol.GetName()), action)
+			}
+		}
+	}
+}
+
+// reevaluatePolicy runs the role evaluation which normally happens when a policy's roles are updated
+func reevaluatePolicy(ctx boltz.MutateContext, store boltz.Store, policyId string, evaluate func(persistCtx *boltz.PersistContext)) error {
+	entityBucket := store.GetEntityBucket(ctx.Tx(), []byte(policyId))
+	if entityBucket == nil {
+		return boltz.NewNotFoundError(store.GetSingularEntityType(), "id", policyId)
+	}
+
+	persistCtx := &boltz.PersistContext{
+		MutateContext: ctx,
+		Id:            policyId,
+		Store:         store,
+		Bucket:        entityBucket,
+	}
+	evaluate(persistCtx)
+	return persistCtx.Bucket.GetError()
+}
+
+func (ctx *denormCheckCtx) collectIssues(collector *integrityIssueCollector) {
+	tx := ctx.mutateCtx.Tx()
+	links := ctx.getExpectedLinkCounts()
+
+	sourceType := ctx.sourceStore.GetSingularEntityType()
+	targetType := ctx.targetStore.GetSingularEntityType()
+
+	for sourceCursor := ctx.sourceStore.IterateIds(tx, ast.BoolNodeTrue); sourceCursor.IsValid(); sourceCursor.Next() {
+		sourceId := string(sourceCursor.Current())
+		for targetCursor := ctx.targetStore.IterateIds(tx, ast.BoolNodeTrue); targetCursor.IsValid(); targetCursor.Next() {
+			targetId := string(targetCursor.Current())
+
+			expected := int32(links[sourceId][targetId])
+			sourceCount, targetCount := ctx.targetDenormCollection.GetLinkCounts(tx, []byte(sourceId), []byte(targetId))
+			if int32Value(sourceCount) == expected && int32Value(targetCount) == expected {
+				continue
+			}
+
+			action := &IntegrityFixAction{
+				Id:          fmt.Sprintf("set-link-count:%s/%s/%s", ctx.name, sourceId, targetId),
+				Description: fmt.Sprintf("recalculate %s link count between %s %s and %s %s", ctx.name, sourceType, sourceId, targetType, targetId),
+				apply: func(mutateCtx boltz.MutateContext) error {
+					count := 0
+					for policyCursor := ctx.policyStore.IterateIds(mutateCtx.Tx(), ast.BoolNodeTrue); policyCursor.IsValid(); policyCursor.Next() {
+						policyId := policyCursor.Current()
+						if (ctx.policyFilter == nil || ctx.policyFilter(policyId)) &&
+							ctx.sourceCollection.IsLinked(mutateCtx.Tx(), policyId, []byte(sourceId)) &&
+							ctx.targetCollection.IsLinked(mutateCtx.Tx(), policyId, []byte(targetId)) {
+							count++
+						}
+					}
+					_, _, err := ctx.targetDenormCollection.SetLinkCount(mutateCtx.Tx(), []byte(sourceId), []byte(targetId), count)
+					return err
+				},
+			}
+
+			collector.add(IntegrityCheckPolicyDenormalization, ctx.sourceStore.GetEntityType(), sourceId,
+				fmt.Sprintf("%s: link count between %s %s and %s %s is %v/%v, should be %v",
+					ctx.name, sourceType, sourceId, targetType, targetId, int32Value(sourceCount), int32Value(targetCount), expected), action)
+		}
+	}
+}
+
+func int32Value(val *int32) int32 {
+	if val == nil {
+		return 0
+	}
+	return *val
+}
+
+func collectRoleAttributeIndexIssues(tx *bbolt.Tx, index boltz.SetReadIndex, collector *integrityIssueCollector) {
+	symbol := index.GetSymbol()
+	store := symbol.GetStore()
+
+	indexed := map[string]map[string]struct{}{}
+	index.ReadKeys(tx, func(key []byte) {
+		attr := string(key)
+		ids := map[string]struct{}{}
+		index.Read(tx, key, func(val []byte) {
+			ids[string(val)] = struct{}{}
+		})
+		indexed[attr] = ids
+	})
+
+	action := &IntegrityFixAction{
+		Id:          fmt.Sprintf("rebuild-index:%s.%s", store.GetEntityType(), symbol.GetName()),
+		Description: fmt.Sprintf("rebuild the %s index of %s", symbol.GetName(), store.GetEntityType()),
+		apply: func(ctx boltz.MutateContext) error {
+			checkable, ok := index.(boltz.Checkable)
+			if !ok {
+				return fmt.Errorf("index %s.%s can't be rebuilt", store.GetEntityType(), symbol.GetName())
+			}
+			return checkable.CheckIntegrity(ctx, true, func(error, bool) {})
+		},
+	}
+
+	entityAttributes := map[string]map[string]struct{}{}
+	for cursor := store.IterateIds(tx, ast.BoolNodeTrue); cursor.IsValid(); cursor.Next() {
+		entityId := string(cursor.Current())
+		attrs := map[string]struct{}{}
+		for _, attr := range symbol.EvalStringList(tx, []byte(entityId)) {
+			attrs[attr] = struct{}{}
+			if _, found := indexed[attr][entityId]; !found {
+				collector.add(IntegrityCheckRoleAttributeIndexes, store.GetEntityType(), entityId,
+					fmt.Sprintf("%s %s has role attribute %s, but isn't in the %s index", store.GetSingularEntityType(), entityId, attr, symbol.GetName()), action)
+			}
+		}
+		entityAttributes[entityId] = attrs
+	}
+
+	for _, attr := range sortedKeys(indexed) {
+		for _, entityId := range sortedKeys(indexed[attr]) {
+			attrs, found := entityAttributes[entityId]
+			if !found {
+				collector.add(IntegrityCheckRoleAttributeIndexes, store.GetEntityType(), entityId,
+					fmt.Sprintf("%s index value %s references %s %s, which doesn't exist", symbol.GetName(), attr, store.GetSingularEntityType(), entityId), action)
+			} else if _, hasAttr := attrs[attr]; !hasAttr {
+				collector.add(IntegrityCheckRoleAttributeIndexes, store.GetEntityType(), entityId,
+					fmt.Sprintf("%s index value %s references %s %s, which doesn't have that role attribute", symbol.GetName(), attr, store.GetSingularEntityType(), entityId), action)
+			}
+		}
+	}
+}
+
+func (stores *Stores) collectConfigReferenceIssues(tx *bbolt.Tx, collector *integrityIssueCollector) {
+	internal := stores.internal
+	serviceConfigs := internal.service.GetLinkCollection(EntityTypeConfigs)
+
+	for cursor := internal.service.IterateIds(tx, ast.BoolNodeTrue); cursor.IsValid(); cursor.Next() {
+		serviceId := string(cursor.Current())
+		for _, configId := range internal.service.GetRelatedEntitiesIdList(tx, serviceId, EntityTypeConfigs) {
+			if internal.config.IsEntityPresent(tx, configId) {
+				continue
+			}
+			collector.add(IntegrityCheckConfigReferences, EntityTypeServices, serviceId,
+				fmt.Sprintf("service %s references config %s, which doesn't exist", serviceId, configId),
+				&IntegrityFixAction{
+					Id:          fmt.Sprintf("remove-service-config:%s/%s", serviceId, configId),
+					Description: fmt.Sprintf("remove config %s from service %s", configId, serviceId),
+					apply: func(ctx boltz.MutateContext) error {
+						return serviceConfigs.RemoveLinks(ctx.Tx(), serviceId, configId)
+					},
+				})
+		}
+	}
+
+	configTypeSymbol := internal.config.GetSymbol(FieldConfigType)
+
+	for cursor := internal.identity.IterateIds(tx, ast.BoolNodeTrue); cursor.IsValid(); cursor.Next() {
+		identityId := string(cursor.Current())
+		entityBucket := internal.identity.GetEntityBucket(tx, []byte(identityId))
+		if entityBucket == nil {
+			continue
+		}
+
+		identity := &Identity{}
+		internal.identity.fillServiceConfig(identity, entityBucket)
+
+		for _, serviceId := range sortedKeys(identity.ServiceConfigs) {
+			serviceConfigs := identity.ServiceConfigs[serviceId]
+			serviceMissing := !internal.service.IsEntityPresent(tx, serviceId)
+
+			for _, configTypeId := range sortedKeys(serviceConfigs) {
+				configId := serviceConfigs[configTypeId]
+
+				var problem string
+				if serviceMissing {
+					problem = fmt.Sprintf("identity %s has a config override for service %s, which doesn't exist", identityId, serviceId)
+				} else if !internal.config.IsEntityPresent(tx, configId) {
+					problem = fmt.Sprintf("identity %s has a config override for service %s with config %s, which doesn't exist", identityId, serviceId, configId)
+				} else if actualType := boltz.FieldToString(configTypeSymbol.Eval(tx, []byte(configId))); actualType == nil || *actualType != configTypeId {
+					problem = fmt.Sprintf("identity %s has a config override for service %s with config %s, which isn't of config type %s", identityId, serviceId, configId, configTypeId)
+				}
+
+				if problem == "" {
+					continue
+				}
+
+				removeServiceRefs := serviceMissing
+				collector.add(IntegrityCheckConfigReferences, EntityTypeIdentities, identityId, problem, &IntegrityFixAction{
+					Id:          fmt.Sprintf("remove-identity-service-config:%s/%s/%s", identityId, serviceId, configTypeId),
+					Description: fmt.Sprintf("remove the %s config override of identity %s for service %s", configTypeId, identityId, serviceId),
+					apply: func(ctx boltz.MutateContext) error {
+						return internal.identity.removeServiceConfigs(ctx.Tx(), identityId, removeServiceRefs, func(s, ct, c string) bool {
+							return s == serviceId && ct == configTypeId && c == configId
+						})
+					},
+				})
+			}
+		}
+	}
+}
+
+func (stores *Stores) collectTerminatorIssues(tx *bbolt.Tx, collector *integrityIssueCollector) {
+	internal := stores.internal
+	routerSymbol := internal.terminator.GetSymbol(FieldTerminatorRouter)
+	serviceSymbol := internal.terminator.GetSymbol(FieldTerminatorService)
+
+	for cursor := internal.terminator.IterateIds(tx, ast.BoolNodeTrue); cursor.IsValid(); cursor.Next() {
+		terminatorId := string(cursor.Current())
+
+		var problems []string
+		if routerId := boltz.FieldToString(routerSymbol.Eval(tx, []byte(terminatorId))); routerId == nil || *routerId == "" {
+			problems = append(problems, fmt.Sprintf("terminator %s has no router", terminatorId))
+		} else if !internal.router.IsEntityPresent(tx, *routerId) {
+			problems = append(problems, fmt.Sprintf("terminator %s references router %s, which doesn't exist", terminatorId, *routerId))
+		}
+
+		if serviceId := boltz.FieldToString(serviceSymbol.Eval(tx, []byte(terminatorId))); serviceId == nil || *serviceId == "" {
+			problems = append(problems, fmt.Sprintf("terminator %s has no service", terminatorId))
+		} else if !internal.service.IsEntityPresent(tx, *serviceId) {
+			problems = append(problems, fmt.Sprintf("terminator %s references service %s, which doesn't exist", terminatorId, *serviceId))
+		}
+
+		if len(problems) == 0 {
+			continue
+		}
+
+		action := &IntegrityFixAction{
+			Id:          fmt.Sprintf("remove-terminator:%s", terminatorId),
+			Description: fmt.Sprintf("remove terminator %s", terminatorId),
+			apply: func(ctx boltz.MutateContext) error {
+				// the terminator can't be deleted through the store, as the referenced router or service is missing.
+				// Remove the record, then let the store integrity fix clean up index entries referencing it.
+				if err := internal.terminator.DeleteDanglingById(ctx, terminatorId); err != nil {
+					return err
+				}
+				return internal.terminator.CheckIntegrity(ctx, true, func(error, bool) {})
+			},
+		}
+
+		for _, problem := range problems {
+			collector.add(IntegrityCheckTerminators, EntityTypeTerminators, terminatorId, problem, action)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
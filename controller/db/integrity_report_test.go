@@ -0,0 +1,145 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openziti/ziti/v2/common/eid"
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/openziti/ziti/v2/controller/storage/boltztest"
+	"go.etcd.io/bbolt"
+)
+
+func Test_ExtendedIntegrity(t *testing.T) {
+	ctx := NewTestContext(t)
+	defer ctx.Cleanup()
+	ctx.Init()
+
+	t.Run("clean database has no issues", ctx.testExtendedIntegrityClean)
+	t.Run("missing policy link is reported and fixed", ctx.testExtendedIntegrityFixPolicyLink)
+	t.Run("unknown issue ids are reported", ctx.testExtendedIntegrityUnknownIssue)
+	t.Run("terminator with missing router is removed with events", ctx.testExtendedIntegrityFixTerminator)
+}
+
+func (ctx *TestContext) requirePolicyRoleIssues() []*IntegrityIssue {
+	issues, truncated, err := ctx.stores.CheckExtendedIntegrity(ctx.GetDb(), change.New().GetContext(), 100)
+	ctx.NoError(err)
+	ctx.False(truncated)
+
+	var result []*IntegrityIssue
+	for _, issue := range issues {
+		if issue.Check == IntegrityCheckPolicyRoles {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+func (ctx *TestContext) testExtendedIntegrityClean(_ *testing.T) {
+	ctx.CleanupAll()
+
+	identity := ctx.RequireNewIdentity(eid.New(), false)
+	service := ctx.RequireNewService(eid.New())
+	ctx.requireNewServicePolicy(PolicyTypeDial, ss(entityRef(identity.Id)), ss(entityRef(service.Id)))
+
+	ctx.Empty(ctx.requirePolicyRoleIssues())
+}
+
+func (ctx *TestContext) testExtendedIntegrityFixPolicyLink(_ *testing.T) {
+	ctx.CleanupAll()
+
+	identity := ctx.RequireNewIdentity(eid.New(), false)
+	service := ctx.RequireNewService(eid.New())
+	policy := ctx.requireNewServicePolicy(PolicyTypeDial, ss(entityRef(identity.Id)), ss(entityRef(service.Id)))
+
+	ctx.NoError(ctx.GetDb().Update(change.New().NewMutateContext(), func(mutateCtx boltz.MutateContext) error {
+		return ctx.stores.internal.servicePolicy.identityCollection.RemoveLinks(mutateCtx.Tx(), policy.Id, identity.Id)
+	}))
+
+	issues := ctx.requirePolicyRoleIssues()
+	ctx.Len(issues, 1)
+	issue := issues[0]
+	ctx.Equal(EntityTypeServicePolicies, issue.EntityType)
+	ctx.Equal(policy.Id, issue.EntityId)
+	ctx.NotNil(issue.FixAction)
+
+	// ids are stable across runs
+	ctx.Equal(issue.Id, ctx.requirePolicyRoleIssues()[0].Id)
+
+	results, err := ctx.stores.FixIntegrityIssues(ctx.GetDb(), change.New().NewMutateContext(), []string{issue.Id})
+	ctx.NoError(err)
+	ctx.Len(results, 1)
+	ctx.True(results[0].Fixed, results[0].Error)
+	ctx.Equal(issue.FixAction.Id, results[0].FixActionId)
+
+	ctx.Empty(ctx.requirePolicyRoleIssues())
+	ctx.NoError(ctx.GetDb().View(func(tx *bbolt.Tx) error {
+		ctx.Contains(ctx.stores.internal.servicePolicy.identityCollection.GetLinks(tx, policy.Id), identity.Id)
+		return nil
+	}))
+}
+
+func (ctx *TestContext) testExtendedIntegrityUnknownIssue(_ *testing.T) {
+	results, err := ctx.stores.FixIntegrityIssues(ctx.GetDb(), change.New().NewMutateContext(), []string{"not-an-issue"})
+	ctx.NoError(err)
+	ctx.Len(results, 1)
+	ctx.False(results[0].Fixed)
+	ctx.NotEmpty(results[0].Error)
+}
+
+func (ctx *TestContext) testExtendedIntegrityFixTerminator(_ *testing.T) {
+	ctx.CleanupAll()
+
+	router := ctx.requireNewRouter()
+	service := ctx.requireNewService()
+	terminator := &Terminator{
+		BaseExtEntity: boltz.BaseExtEntity{Id: uuid.New().String()},
+		Service:       service.Id,
+		Router:        router.Id,
+		Binding:       "transport",
+		Address:       "tcp:localhost:22",
+	}
+	boltztest.RequireCreate(ctx, terminator)
+
+	deleted := make(chan string, 1)
+	ctx.stores.internal.terminator.AddEntityIdListener(func(id string) {
+		if id == terminator.Id {
+			deleted <- id
+		}
+	}, boltz.EntityDeleted)
+
+	// remove the router record without going through the store, leaving the terminator referencing it
+	ctx.NoError(ctx.GetDb().Update(change.New().NewMutateContext(), func(mutateCtx boltz.MutateContext) error {
+		bucket := ctx.stores.internal.router.GetEntitiesBucket(mutateCtx.Tx())
+		bucket.DeleteEntity(router.Id)
+		return bucket.Err
+	}))
+
+	issues, _, err := ctx.stores.CheckExtendedIntegrity(ctx.GetDb(), change.New().GetContext(), 100)
+	ctx.NoError(err)
+
+	var issueIds []string
+	for _, issue := range issues {
+		if issue.Check == IntegrityCheckTerminators {
+			ctx.Equal(terminator.Id, issue.EntityId)
+			issueIds = append(issueIds, issue.Id)
+		}
+	}
+	ctx.Len(issueIds, 1)
+
+	results, err := ctx.stores.FixIntegrityIssues(ctx.GetDb(), change.New().NewMutateContext(), issueIds)
+	ctx.NoError(err)
+	ctx.Len(results, 1)
+	ctx.True(results[0].Fixed, results[0].Error)
+
+	select {
+	case id := <-deleted:
+		ctx.Equal(terminator.Id, id)
+	case <-time.After(time.Second):
+		ctx.FailNow("timed out waiting for terminator delete event")
+	}
+
+	boltztest.ValidateDeleted(ctx, terminator.Id)
+}
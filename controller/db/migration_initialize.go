@@ -297,6 +297,20 @@ var tunnelDefinitions = map[string]interface{}{
 			},
 		},
 	},
+	"proxyProtocolConfiguration": map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required": []interface{}{
+			"version",
+		},
+		"properties": map[string]interface{}{
+			"version": map[string]interface{}{
+				"type":        "integer",
+				"enum":        []interface{}{1, 2},
+				"description": "The PROXY protocol version. Version 2 also carries the name of the dialing identity in a TLV of type 0xE0",
+			},
+		},
+	},
 	"ipv4AddressTranslation": map[string]interface{}{
 		"type":                 "object",
 		"additionalProperties": false,
@@ -433,6 +447,10 @@ var hostV1SchemaSansDefs = map[string]interface{}{
 				"$ref":        "#/definitions/proxyConfiguration",
 				"description": "If defined, outgoing connections will be send through this proxy server",
 			},
+			"proxyProtocol": map[string]interface{}{
+				"$ref":        "#/definitions/proxyProtocolConfiguration",
+				"description": "If defined, a PROXY protocol header with the address of the intercepted client will be sent on outgoing tcp connections",
+			},
 		},
 	),
 	"additionalProperties": false,
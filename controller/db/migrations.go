@@ -25,8 +25,11 @@ import (
 )
 
 const (
-	CurrentDbVersion = 48
+	CurrentDbVersion = 49
 	FieldVersion     = "version"
+
+	// MinSupportedDbVersion is the oldest edge datastore version which can still be migrated to CurrentDbVersion
+	MinSupportedDbVersion = 13
 )
 
 type Migrations struct {
@@ -54,7 +57,7 @@ func (m *Migrations) migrate(step *boltz.MigrationStep) int {
 		return step.CurrentVersion
 	}
 
-	if step.CurrentVersion < 13 {
+	if step.CurrentVersion < MinSupportedDbVersion {
 		step.SetError(errors.Errorf("Unsupported edge datastore version: %v", step.CurrentVersion))
 		return step.CurrentVersion
 	}
@@ -225,6 +228,12 @@ func (m *Migrations) migrate(step *boltz.MigrationStep) int {
 		m.createOrUpdateConfigType(step, routerLinkV1ConfigType) // migration 48
 	}
 
+	if step.CurrentVersion < 49 {
+		// host.v1 and host.v2 gained the proxyProtocol option
+		step.SetError(m.stores.ConfigType.Update(step.Ctx, hostV1ConfigType, nil))
+		step.SetError(m.stores.ConfigType.Update(step.Ctx, hostV2ConfigType, nil))
+	}
+
 	// current version
 	if step.CurrentVersion <= CurrentDbVersion {
 		return CurrentDbVersion
@@ -305,15 +305,20 @@ func UpdateRelatedRoles(ctx *roleAttributeChangeContext, entityId []byte, newRol
 }
 
 func evaluatePolicyAgainstEntity(ctx *roleAttributeChangeContext, semantic string, entityId, policyId []byte, ids, roles, roleAttributes []string, log *logrus.Entry) (bool, bool) {
-	if stringz.Contains(ids, string(entityId)) || stringz.Contains(roles, "all") ||
-		(strings.EqualFold(semantic, SemanticAllOf) && len(roles) > 0 && stringz.ContainsAll(roleAttributes, roles...)) ||
-		(strings.EqualFold(semantic, SemanticAnyOf) && len(roles) > 0 && stringz.ContainsAny(roleAttributes, roles...)) {
+	if policyMatchesEntity(semantic, string(entityId), ids, roles, roleAttributes) {
 		return true, ProcessEntityPolicyMatched(ctx, entityId, policyId, log)
 	} else {
 		return false, ProcessEntityPolicyUnmatched(ctx, entityId, policyId, log)
 	}
 }
 
+// policyMatchesEntity returns true if a policy with the given semantic, explicit ids and roles selects the entity
+func policyMatchesEntity(semantic string, entityId string, ids, roles, roleAttributes []string) bool {
+	return stringz.Contains(ids, entityId) || stringz.Contains(roles, "all") ||
+		(strings.EqualFold(semantic, SemanticAllOf) && len(roles) > 0 && stringz.ContainsAll(roleAttributes, roles...)) ||
+		(strings.EqualFold(semantic, SemanticAnyOf) && len(roles) > 0 && stringz.ContainsAny(roleAttributes, roles...))
+}
+
 func ProcessEntityPolicyMatched(ctx *roleAttributeChangeContext, entityId, policyId []byte, log *logrus.Entry) bool {
 	// first add it to the denormalize link table from the policy to the entity (ex: service policy -> identity)
 	// If it's already there (in other words, this policy didn't change in relation to the entity,
@@ -391,7 +396,8 @@ type denormCheckCtx struct {
 	repair                 bool
 }
 
-func validatePolicyDenormalization(ctx *denormCheckCtx) error {
+// getExpectedLinkCounts returns, for each source entity, how many policies link it to each target entity
+func (ctx *denormCheckCtx) getExpectedLinkCounts() map[string]map[string]int {
 	tx := ctx.mutateCtx.Tx()
 
 	links := map[string]map[string]int{}
@@ -414,6 +420,13 @@ func validatePolicyDenormalization(ctx *denormCheckCtx) error {
 		}
 	}
 
+	return links
+}
+
+func validatePolicyDenormalization(ctx *denormCheckCtx) error {
+	tx := ctx.mutateCtx.Tx()
+	links := ctx.getExpectedLinkCounts()
+
 	for sourceCursor := ctx.sourceStore.IterateIds(tx, ast.BoolNodeTrue); sourceCursor.IsValid(); sourceCursor.Next() {
 		sourceEntityId := sourceCursor.Current()
 		for targetCursor := ctx.targetStore.IterateIds(tx, ast.BoolNodeTrue); targetCursor.IsValid(); targetCursor.Next() {
@@ -0,0 +1,216 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package db
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	nfpem "github.com/openziti/foundation/v2/pem"
+	"github.com/openziti/ziti/v2/controller/storage/ast"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+)
+
+const (
+	FieldRevokedCertificateOwnerType      = "ownerType"
+	FieldRevokedCertificateOwnerId        = "ownerId"
+	FieldRevokedCertificateAuthorityKeyId = "authorityKeyId"
+	FieldRevokedCertificateReason         = "reason"
+	FieldRevokedCertificateRevokedAt      = "revokedAt"
+	FieldRevokedCertificateExpiresAt      = "expiresAt"
+
+	RevokedCertificateOwnerIdentity = "identity"
+	RevokedCertificateOwnerRouter   = "router"
+
+	// Revocation reasons, using the RFC 5280 CRLReason codes so they can be published as-is
+	CertRevocationReasonSuperseded           = 4
+	CertRevocationReasonCessationOfOperation = 5
+	CertRevocationReasonCertificateHold      = 6
+
+	// revokedCertificatePruneBatchSize bounds how many expired entries are removed per recorded revocation
+	revokedCertificatePruneBatchSize = 100
+
+	// revokedCertificatePruneGrace keeps expired entries around for a while, so that controllers with slightly
+	// different clocks prune the same entries
+	revokedCertificatePruneGrace = 24 * time.Hour
+)
+
+// RevokedCertificate records a network issued certificate which is no longer valid. The id is the certificate
+// serial number in lowercase hex. Entries are only published until the certificate itself expires.
+type RevokedCertificate struct {
+	boltz.BaseExtEntity
+	OwnerType      string    `json:"ownerType"`
+	OwnerId        string    `json:"ownerId"`
+	AuthorityKeyId string    `json:"authorityKeyId"`
+	Reason         int       `json:"reason"`
+	RevokedAt      time.Time `json:"revokedAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
+
+func (entity *RevokedCertificate) GetEntityType() string {
+	return EntityTypeRevokedCertificates
+}
+
+// IsHold returns true if the certificate is only suspended and may become valid again
+func (entity *RevokedCertificate) IsHold() bool {
+	return entity.Reason == CertRevocationReasonCertificateHold
+}
+
+// SerialToRevokedCertificateId returns the id used for the revoked certificate entry of the given serial number
+func SerialToRevokedCertificateId(serial *big.Int) string {
+	return serial.Text(16)
+}
+
+var _ RevokedCertificateStore = (*revokedCertificateStoreImpl)(nil)
+
+type RevokedCertificateStore interface {
+	Store[*RevokedCertificate]
+}
+
+func newRevokedCertificateStore(stores *stores) *revokedCertificateStoreImpl {
+	store := &revokedCertificateStoreImpl{}
+	store.baseStore = newBaseStore[*RevokedCertificate](stores, store)
+	store.InitImpl(store)
+
+	stores.authenticator.AddEntityConstraint(&authenticatorCertRevocationConstraint{revokedCerts: store})
+	stores.edgeRouter.AddEntityConstraint(&edgeRouterCertRevocationConstraint{revokedCerts: store})
+	stores.identity.AddEntityConstraint(&identityCertHoldConstraint{revokedCerts: store})
+
+	return store
+}
+
+type revokedCertificateStoreImpl struct {
+	*baseStore[*RevokedCertificate]
+}
+
+func (store *revokedCertificateStoreImpl) initializeLocal() {
+	store.AddExtEntitySymbols()
+	store.AddSymbol(FieldRevokedCertificateOwnerType, ast.NodeTypeString)
+	store.AddSymbol(FieldRevokedCertificateOwnerId, ast.NodeTypeString)
+	store.AddSymbol(FieldRevokedCertificateAuthorityKeyId, ast.NodeTypeString)
+	store.AddSymbol(FieldRevokedCertificateReason, ast.NodeTypeInt64)
+	store.AddSymbol(FieldRevokedCertificateRevokedAt, ast.NodeTypeDatetime)
+	store.AddSymbol(FieldRevokedCertificateExpiresAt, ast.NodeTypeDatetime)
+}
+
+func (store *revokedCertificateStoreImpl) initializeLinked() {}
+
+func (store *revokedCertificateStoreImpl) NewEntity() *RevokedCertificate {
+	return &RevokedCertificate{}
+}
+
+func (store *revokedCertificateStoreImpl) FillEntity(entity *RevokedCertificate, bucket *boltz.TypedBucket) {
+	entity.LoadBaseValues(bucket)
+	entity.OwnerType = bucket.GetStringWithDefault(FieldRevokedCertificateOwnerType, "")
+	entity.OwnerId = bucket.GetStringWithDefault(FieldRevokedCertificateOwnerId, "")
+	entity.AuthorityKeyId = bucket.GetStringWithDefault(FieldRevokedCertificateAuthorityKeyId, "")
+	entity.Reason = int(bucket.GetInt64WithDefault(FieldRevokedCertificateReason, 0))
+	entity.RevokedAt = bucket.GetTimeOrDefault(FieldRevokedCertificateRevokedAt, time.Time{})
+	entity.ExpiresAt = bucket.GetTimeOrDefault(FieldRevokedCertificateExpiresAt, time.Time{})
+}
+
+func (store *revokedCertificateStoreImpl) PersistEntity(entity *RevokedCertificate, ctx *boltz.PersistContext) {
+	entity.SetBaseValues(ctx)
+	ctx.SetString(FieldRevokedCertificateOwnerType, entity.OwnerType)
+	ctx.SetString(FieldRevokedCertificateOwnerId, entity.OwnerId)
+	ctx.SetString(FieldRevokedCertificateAuthorityKeyId, entity.AuthorityKeyId)
+	ctx.SetInt64(FieldRevokedCertificateReason, int64(entity.Reason))
+	ctx.SetTimeP(FieldRevokedCertificateRevokedAt, &entity.RevokedAt)
+	ctx.SetTimeP(FieldRevokedCertificateExpiresAt, &entity.ExpiresAt)
+}
+
+// revokePem records the leaf certificate of the given PEM as revoked. Already expired certificates are skipped.
+// A hold is upgraded to a permanent revocation, but an existing permanent revocation is left untouched.
+func (store *revokedCertificateStoreImpl) revokePem(ctx boltz.MutateContext, certPem string, ownerType, ownerId string, reason int) error {
+	certs := nfpem.PemStringToCertificates(certPem)
+	if len(certs) == 0 {
+		return nil
+	}
+	return store.revokeCert(ctx, certs[0], ownerType, ownerId, reason, certs[0].NotAfter)
+}
+
+func (store *revokedCertificateStoreImpl) revokeCert(ctx boltz.MutateContext, cert *x509.Certificate, ownerType, ownerId string, reason int, expiresAt time.Time) error {
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return nil
+	}
+
+	id := SerialToRevokedCertificateId(cert.SerialNumber)
+	existing, found, err := store.FindById(ctx.Tx(), id)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		if !existing.IsHold() || reason == CertRevocationReasonCertificateHold {
+			return nil
+		}
+		if err = store.DeleteById(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err = store.pruneExpired(ctx, now); err != nil {
+		return err
+	}
+
+	return store.Create(ctx, &RevokedCertificate{
+		BaseExtEntity:  *boltz.NewExtEntity(id, nil),
+		OwnerType:      ownerType,
+		OwnerId:        ownerId,
+		AuthorityKeyId: hex.EncodeToString(cert.AuthorityKeyId),
+		Reason:         reason,
+		RevokedAt:      now,
+		ExpiresAt:      expiresAt,
+	})
+}
+
+// releaseHolds removes the certificate holds placed on behalf of the given owner
+func (store *revokedCertificateStoreImpl) releaseHolds(ctx boltz.MutateContext, ownerId string) error {
+	query := fmt.Sprintf(`%s = "%s" and %s = %d limit none`, FieldRevokedCertificateOwnerId, ownerId,
+		FieldRevokedCertificateReason, CertRevocationReasonCertificateHold)
+	ids, _, err := store.QueryIds(ctx.Tx(), query)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err = store.DeleteById(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneExpired removes entries for certificates which expired more than a grace period ago. Expired certificates
+// are rejected on their own, so there's no need to keep publishing them.
+func (store *revokedCertificateStoreImpl) pruneExpired(ctx boltz.MutateContext, now time.Time) error {
+	cutoff := now.Add(-revokedCertificatePruneGrace).UTC().Format(time.RFC3339)
+	query := fmt.Sprintf(`%s < datetime(%s) limit %d`, FieldRevokedCertificateExpiresAt, cutoff, revokedCertificatePruneBatchSize)
+	ids, _, err := store.QueryIds(ctx.Tx(), query)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err = store.DeleteById(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
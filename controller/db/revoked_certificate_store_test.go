@@ -0,0 +1,180 @@
+package db
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/openziti/ziti/v2/controller/storage/boltztest"
+	"go.etcd.io/bbolt"
+)
+
+func newRevocationTestCertPem(t *testing.T, serial int64, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: uuid.NewString()},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func (ctx *TestContext) loadRevokedCertificate(serial int64) *RevokedCertificate {
+	var result *RevokedCertificate
+	err := ctx.GetDb().View(func(tx *bbolt.Tx) error {
+		entity, found, err := ctx.stores.RevokedCertificate.FindById(tx, SerialToRevokedCertificateId(big.NewInt(serial)))
+		if found {
+			result = entity
+		}
+		return err
+	})
+	ctx.NoError(err)
+	return result
+}
+
+func Test_RevokedCertificates(t *testing.T) {
+	ctx := NewTestContext(t)
+	defer ctx.Cleanup()
+	ctx.Init()
+
+	validUntil := time.Now().Add(time.Hour)
+
+	newCertAuthenticator := func(identityId string, certPem string, issuedByNetwork bool) *Authenticator {
+		authenticator := &Authenticator{
+			BaseExtEntity: *boltz.NewExtEntity(uuid.NewString(), nil),
+			Type:          MethodAuthenticatorCert,
+			IdentityId:    identityId,
+			SubType: &AuthenticatorCert{
+				Fingerprint:       uuid.NewString(),
+				Pem:               certPem,
+				IsIssuedByNetwork: issuedByNetwork,
+			},
+		}
+		boltztest.RequireCreate(ctx, authenticator)
+		return authenticator
+	}
+
+	t.Run("deleting an authenticator revokes its certificate", func(t *testing.T) {
+		ctx.NextTest(t)
+		identity := ctx.RequireNewIdentity(uuid.NewString(), false)
+		authenticator := newCertAuthenticator(identity.Id, newRevocationTestCertPem(t, 1001, validUntil), true)
+
+		ctx.Nil(ctx.loadRevokedCertificate(1001))
+		boltztest.RequireDelete(ctx, authenticator)
+
+		revoked := ctx.loadRevokedCertificate(1001)
+		ctx.NotNil(revoked)
+		ctx.Equal(CertRevocationReasonCessationOfOperation, revoked.Reason)
+		ctx.Equal(RevokedCertificateOwnerIdentity, revoked.OwnerType)
+		ctx.Equal(identity.Id, revoked.OwnerId)
+		ctx.Equal(validUntil.Unix(), revoked.ExpiresAt.Unix())
+	})
+
+	t.Run("certificates not issued by the network are not revoked", func(t *testing.T) {
+		ctx.NextTest(t)
+		identity := ctx.RequireNewIdentity(uuid.NewString(), false)
+		authenticator := newCertAuthenticator(identity.Id, newRevocationTestCertPem(t, 1002, validUntil), false)
+
+		boltztest.RequireDelete(ctx, authenticator)
+		ctx.Nil(ctx.loadRevokedCertificate(1002))
+	})
+
+	t.Run("expired certificates are not revoked", func(t *testing.T) {
+		ctx.NextTest(t)
+		identity := ctx.RequireNewIdentity(uuid.NewString(), false)
+		authenticator := newCertAuthenticator(identity.Id, newRevocationTestCertPem(t, 1003, time.Now().Add(-time.Minute)), true)
+
+		boltztest.RequireDelete(ctx, authenticator)
+		ctx.Nil(ctx.loadRevokedCertificate(1003))
+	})
+
+	t.Run("replacing a certificate supersedes the old one", func(t *testing.T) {
+		ctx.NextTest(t)
+		identity := ctx.RequireNewIdentity(uuid.NewString(), false)
+		authenticator := newCertAuthenticator(identity.Id, newRevocationTestCertPem(t, 1004, validUntil), true)
+
+		authenticator.SubType.(*AuthenticatorCert).Pem = newRevocationTestCertPem(t, 1005, validUntil)
+		boltztest.RequireUpdate(ctx, authenticator)
+
+		revoked := ctx.loadRevokedCertificate(1004)
+		ctx.NotNil(revoked)
+		ctx.Equal(CertRevocationReasonSuperseded, revoked.Reason)
+		ctx.Nil(ctx.loadRevokedCertificate(1005))
+	})
+
+	t.Run("disabling an identity holds its certificates until it is enabled", func(t *testing.T) {
+		ctx.NextTest(t)
+		identity := ctx.RequireNewIdentity(uuid.NewString(), false)
+		newCertAuthenticator(identity.Id, newRevocationTestCertPem(t, 1006, validUntil), true)
+
+		now := time.Now()
+		identity.DisabledAt = &now
+		boltztest.RequireUpdate(ctx, identity)
+
+		revoked := ctx.loadRevokedCertificate(1006)
+		ctx.NotNil(revoked)
+		ctx.True(revoked.IsHold())
+
+		identity.DisabledAt = nil
+		boltztest.RequireUpdate(ctx, identity)
+		ctx.Nil(ctx.loadRevokedCertificate(1006))
+	})
+
+	t.Run("deleting a held certificate revokes it permanently", func(t *testing.T) {
+		ctx.NextTest(t)
+		identity := ctx.RequireNewIdentity(uuid.NewString(), false)
+		authenticator := newCertAuthenticator(identity.Id, newRevocationTestCertPem(t, 1007, validUntil), true)
+
+		now := time.Now()
+		identity.DisabledAt = &now
+		boltztest.RequireUpdate(ctx, identity)
+		ctx.True(ctx.loadRevokedCertificate(1007).IsHold())
+
+		boltztest.RequireDelete(ctx, authenticator)
+		ctx.Equal(CertRevocationReasonCessationOfOperation, ctx.loadRevokedCertificate(1007).Reason)
+
+		identity.DisabledAt = nil
+		boltztest.RequireUpdate(ctx, identity)
+		ctx.NotNil(ctx.loadRevokedCertificate(1007))
+	})
+
+	t.Run("deleting an edge router revokes its certificate", func(t *testing.T) {
+		ctx.NextTest(t)
+		certPem := newRevocationTestCertPem(t, 1008, validUntil)
+		edgeRouter := &EdgeRouter{
+			Router: Router{
+				BaseExtEntity: *boltz.NewExtEntity(uuid.NewString(), nil),
+				Name:          uuid.NewString(),
+			},
+			CertPem: &certPem,
+		}
+		boltztest.RequireCreate(ctx, edgeRouter)
+
+		// the revocation entry deliberately outlives the router, so it can keep being published
+		boltztest.RequireDelete(ctx, edgeRouter, "/"+RootBucket+"/"+EntityTypeRevokedCertificates)
+
+		revoked := ctx.loadRevokedCertificate(1008)
+		ctx.NotNil(revoked)
+		ctx.Equal(RevokedCertificateOwnerRouter, revoked.OwnerType)
+		ctx.Equal(edgeRouter.Id, revoked.OwnerId)
+	})
+}
@@ -199,21 +199,60 @@ func (store *serviceEdgeRouterPolicyStoreImpl) DeleteById(ctx boltz.MutateContex
 }
 
 func (store *serviceEdgeRouterPolicyStoreImpl) CheckIntegrity(mutateCtx boltz.MutateContext, fix bool, errorSink func(err error, fixed bool)) error {
-	ctx := &denormCheckCtx{
-		name:                   "service-edge-router-policies",
-		mutateCtx:              mutateCtx,
-		sourceStore:            store.stores.service,
-		targetStore:            store.stores.edgeRouter,
-		policyStore:            store,
-		sourceCollection:       store.serviceCollection,
-		targetCollection:       store.edgeRouterCollection,
-		targetDenormCollection: store.stores.service.edgeRoutersCollection,
-		errorSink:              errorSink,
-		repair:                 fix,
-	}
-	if err := validatePolicyDenormalization(ctx); err != nil {
-		return err
+	for _, ctx := range store.denormChecks(mutateCtx) {
+		ctx.errorSink = errorSink
+		ctx.repair = fix
+		if err := validatePolicyDenormalization(ctx); err != nil {
+			return err
+		}
 	}
 
 	return store.BaseStore.CheckIntegrity(mutateCtx, fix, errorSink)
 }
+
+func (store *serviceEdgeRouterPolicyStoreImpl) denormChecks(mutateCtx boltz.MutateContext) []*denormCheckCtx {
+	return []*denormCheckCtx{
+		{
+			name:                   "service-edge-router-policies",
+			mutateCtx:              mutateCtx,
+			sourceStore:            store.stores.service,
+			targetStore:            store.stores.edgeRouter,
+			policyStore:            store,
+			sourceCollection:       store.serviceCollection,
+			targetCollection:       store.edgeRouterCollection,
+			targetDenormCollection: store.stores.service.edgeRoutersCollection,
+		},
+	}
+}
+
+func (store *serviceEdgeRouterPolicyStoreImpl) roleChecks() []*policyRoleCheck {
+	reevaluate := func(update func(persistCtx *boltz.PersistContext, policy *ServiceEdgeRouterPolicy)) func(ctx boltz.MutateContext, policyId string) error {
+		return func(ctx boltz.MutateContext, policyId string) error {
+			policy, err := store.LoadById(ctx.Tx(), policyId)
+			if err != nil {
+				return err
+			}
+			return reevaluatePolicy(ctx, store, policyId, func(persistCtx *boltz.PersistContext) {
+				update(persistCtx, policy)
+			})
+		}
+	}
+
+	return []*policyRoleCheck{
+		{
+			policyStore:          store,
+			rolesSymbol:          store.symbolServiceRoles,
+			linkCollection:       store.serviceCollection,
+			roleAttributesSymbol: store.stores.service.symbolRoleAttributes,
+			entityFilter:         store.stores.service.isNotFabricOnly,
+			reevaluate:           reevaluate(store.serviceRolesUpdated),
+		},
+		{
+			policyStore:          store,
+			rolesSymbol:          store.symbolEdgeRouterRoles,
+			linkCollection:       store.edgeRouterCollection,
+			roleAttributesSymbol: store.stores.edgeRouter.symbolRoleAttributes,
+			reevaluate:           reevaluate(store.edgeRouterRolesUpdated),
+		},
+	}
+}
@@ -432,54 +432,91 @@ func (store *servicePolicyStoreImpl) DeleteById(ctx boltz.MutateContext, id stri
 }
 
 func (store *servicePolicyStoreImpl) CheckIntegrity(mutateCtx boltz.MutateContext, fix bool, errorSink func(err error, fixed bool)) error {
-	ctx := &denormCheckCtx{
-		name:                   "service-policies/bind",
-		mutateCtx:              mutateCtx,
-		sourceStore:            store.stores.identity,
-		targetStore:            store.stores.service,
-		policyStore:            store,
-		sourceCollection:       store.identityCollection,
-		targetCollection:       store.serviceCollection,
-		targetDenormCollection: store.stores.identity.bindServicesCollection,
-		errorSink:              errorSink,
-		repair:                 fix,
-		policyFilter: func(policyId []byte) bool {
-			policyType := PolicyTypeInvalid
-			if result := boltz.FieldToInt32(store.symbolPolicyType.Eval(mutateCtx.Tx(), policyId)); result != nil {
-				policyType = GetPolicyTypeForId(*result)
-			}
-			return policyType == PolicyTypeBind
-		},
-	}
-	if err := validatePolicyDenormalization(ctx); err != nil {
-		return err
+	for _, ctx := range store.denormChecks(mutateCtx) {
+		ctx.errorSink = errorSink
+		ctx.repair = fix
+		if err := validatePolicyDenormalization(ctx); err != nil {
+			return err
+		}
 	}
 
-	ctx = &denormCheckCtx{
-		name:                   "service-policies/dial",
-		mutateCtx:              mutateCtx,
-		sourceStore:            store.stores.identity,
-		targetStore:            store.stores.service,
-		policyStore:            store,
-		sourceCollection:       store.identityCollection,
-		targetCollection:       store.serviceCollection,
-		targetDenormCollection: store.stores.identity.dialServicesCollection,
-		errorSink:              errorSink,
-		repair:                 fix,
-		policyFilter: func(policyId []byte) bool {
+	return store.BaseStore.CheckIntegrity(mutateCtx, fix, errorSink)
+}
+
+func (store *servicePolicyStoreImpl) denormChecks(mutateCtx boltz.MutateContext) []*denormCheckCtx {
+	policyTypeFilter := func(expected PolicyType) func(policyId []byte) bool {
+		return func(policyId []byte) bool {
 			policyType := PolicyTypeInvalid
 			if result := boltz.FieldToInt32(store.symbolPolicyType.Eval(mutateCtx.Tx(), policyId)); result != nil {
 				policyType = GetPolicyTypeForId(*result)
 			}
-			return policyType == PolicyTypeDial
+			return policyType == expected
+		}
+	}
+
+	return []*denormCheckCtx{
+		{
+			name:                   "service-policies/bind",
+			mutateCtx:              mutateCtx,
+			sourceStore:            store.stores.identity,
+			targetStore:            store.stores.service,
+			policyStore:            store,
+			sourceCollection:       store.identityCollection,
+			targetCollection:       store.serviceCollection,
+			targetDenormCollection: store.stores.identity.bindServicesCollection,
+			policyFilter:           policyTypeFilter(PolicyTypeBind),
+		},
+		{
+			name:                   "service-policies/dial",
+			mutateCtx:              mutateCtx,
+			sourceStore:            store.stores.identity,
+			targetStore:            store.stores.service,
+			policyStore:            store,
+			sourceCollection:       store.identityCollection,
+			targetCollection:       store.serviceCollection,
+			targetDenormCollection: store.stores.identity.dialServicesCollection,
+			policyFilter:           policyTypeFilter(PolicyTypeDial),
 		},
 	}
+}
 
-	if err := validatePolicyDenormalization(ctx); err != nil {
-		return err
+func (store *servicePolicyStoreImpl) roleChecks() []*policyRoleCheck {
+	reevaluate := func(update func(persistCtx *boltz.PersistContext, policy *ServicePolicy)) func(ctx boltz.MutateContext, policyId string) error {
+		return func(ctx boltz.MutateContext, policyId string) error {
+			policy, err := store.LoadById(ctx.Tx(), policyId)
+			if err != nil {
+				return err
+			}
+			return reevaluatePolicy(ctx, store, policyId, func(persistCtx *boltz.PersistContext) {
+				update(persistCtx, policy)
+			})
+		}
 	}
 
-	return store.BaseStore.CheckIntegrity(mutateCtx, fix, errorSink)
+	return []*policyRoleCheck{
+		{
+			policyStore:          store,
+			rolesSymbol:          store.symbolIdentityRoles,
+			linkCollection:       store.identityCollection,
+			roleAttributesSymbol: store.stores.identity.symbolRoleAttributes,
+			reevaluate:           reevaluate(store.identityRolesUpdated),
+		},
+		{
+			policyStore:          store,
+			rolesSymbol:          store.symbolServiceRoles,
+			linkCollection:       store.serviceCollection,
+			roleAttributesSymbol: store.stores.service.symbolRoleAttributes,
+			entityFilter:         store.stores.service.isNotFabricOnly,
+			reevaluate:           reevaluate(store.serviceRolesUpdated),
+		},
+		{
+			policyStore:          store,
+			rolesSymbol:          store.symbolPostureCheckRoles,
+			linkCollection:       store.postureCheckCollection,
+			roleAttributesSymbol: store.stores.postureCheck.symbolRoleAttributes,
+			reevaluate:           reevaluate(store.postureCheckRolesUpdated),
+		},
+	}
 }
 
 type FieldCheckerF func(string) bool
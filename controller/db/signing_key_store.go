@@ -0,0 +1,116 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package db
+
+import (
+	"time"
+
+	"github.com/openziti/ziti/v2/controller/storage/ast"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+)
+
+const (
+	FieldSigningKeyControllerId = "controllerId"
+	FieldSigningKeyPublicKey    = "publicKey"
+	FieldSigningKeyAlgorithm    = "algorithm"
+	FieldSigningKeyState        = "state"
+	FieldSigningKeyActivatesAt  = "activatesAt"
+	FieldSigningKeyActivatedAt  = "activatedAt"
+	FieldSigningKeyExpiresAt    = "expiresAt"
+
+	// SigningKeyStateNext keys are published for verification, but not yet used for signing
+	SigningKeyStateNext = "next"
+
+	// SigningKeyStateActive keys are used by their controller to sign new tokens
+	SigningKeyStateActive = "active"
+
+	// SigningKeyStateRetired keys are no longer used for signing, but are kept for verification until ExpiresAt
+	SigningKeyStateRetired = "retired"
+)
+
+// SigningKey is the public half of a rotating JWT signing key. The private key never leaves the controller which
+// generated it. The id is the key id used in JWT headers, the hex encoded SHA-1 of the DER encoded public key, and
+// PublicKey holds the same public key PEM encoded.
+type SigningKey struct {
+	boltz.BaseExtEntity
+	ControllerId string     `json:"controllerId"`
+	PublicKey    string     `json:"publicKey"`
+	Algorithm    string     `json:"algorithm"`
+	State        string     `json:"state"`
+	ActivatesAt  time.Time  `json:"activatesAt"`
+	ActivatedAt  *time.Time `json:"activatedAt"`
+	ExpiresAt    *time.Time `json:"expiresAt"`
+}
+
+func (entity *SigningKey) GetEntityType() string {
+	return EntityTypeSigningKeys
+}
+
+var _ SigningKeyStore = (*signingKeyStoreImpl)(nil)
+
+type SigningKeyStore interface {
+	Store[*SigningKey]
+}
+
+func newSigningKeyStore(stores *stores) *signingKeyStoreImpl {
+	store := &signingKeyStoreImpl{}
+	store.baseStore = newBaseStore[*SigningKey](stores, store)
+	store.InitImpl(store)
+	return store
+}
+
+type signingKeyStoreImpl struct {
+	*baseStore[*SigningKey]
+}
+
+func (store *signingKeyStoreImpl) initializeLocal() {
+	store.AddExtEntitySymbols()
+	store.AddSymbol(FieldSigningKeyControllerId, ast.NodeTypeString)
+	store.AddSymbol(FieldSigningKeyAlgorithm, ast.NodeTypeString)
+	store.AddSymbol(FieldSigningKeyState, ast.NodeTypeString)
+	store.AddSymbol(FieldSigningKeyActivatesAt, ast.NodeTypeDatetime)
+	store.AddSymbol(FieldSigningKeyActivatedAt, ast.NodeTypeDatetime)
+	store.AddSymbol(FieldSigningKeyExpiresAt, ast.NodeTypeDatetime)
+}
+
+func (store *signingKeyStoreImpl) initializeLinked() {}
+
+func (store *signingKeyStoreImpl) NewEntity() *SigningKey {
+	return &SigningKey{}
+}
+
+func (store *signingKeyStoreImpl) FillEntity(entity *SigningKey, bucket *boltz.TypedBucket) {
+	entity.LoadBaseValues(bucket)
+	entity.ControllerId = bucket.GetStringWithDefault(FieldSigningKeyControllerId, "")
+	entity.PublicKey = bucket.GetStringWithDefault(FieldSigningKeyPublicKey, "")
+	entity.Algorithm = bucket.GetStringWithDefault(FieldSigningKeyAlgorithm, "")
+	entity.State = bucket.GetStringWithDefault(FieldSigningKeyState, "")
+	entity.ActivatesAt = bucket.GetTimeOrDefault(FieldSigningKeyActivatesAt, time.Time{})
+	entity.ActivatedAt = bucket.GetTime(FieldSigningKeyActivatedAt)
+	entity.ExpiresAt = bucket.GetTime(FieldSigningKeyExpiresAt)
+}
+
+func (store *signingKeyStoreImpl) PersistEntity(entity *SigningKey, ctx *boltz.PersistContext) {
+	entity.SetBaseValues(ctx)
+	ctx.SetString(FieldSigningKeyControllerId, entity.ControllerId)
+	ctx.SetString(FieldSigningKeyPublicKey, entity.PublicKey)
+	ctx.SetString(FieldSigningKeyAlgorithm, entity.Algorithm)
+	ctx.SetString(FieldSigningKeyState, entity.State)
+	ctx.SetTimeP(FieldSigningKeyActivatesAt, &entity.ActivatesAt)
+	ctx.SetTimeP(FieldSigningKeyActivatedAt, entity.ActivatedAt)
+	ctx.SetTimeP(FieldSigningKeyExpiresAt, entity.ExpiresAt)
+}
@@ -116,8 +116,10 @@ type Stores struct {
 	Index                   boltz.Store
 	Session                 SessionStore
 	Revocation              RevocationStore
+	RevokedCertificate      RevokedCertificateStore
 	ServiceEdgeRouterPolicy ServiceEdgeRouterPolicyStore
 	ServicePolicy           ServicePolicyStore
+	SigningKey              SigningKeyStore
 	TransitRouter           TransitRouterStore
 	Enrollment              EnrollmentStore
 	Authenticator           AuthenticatorStore
@@ -215,9 +217,11 @@ type stores struct {
 	identity                *identityStoreImpl
 	identityType            *IdentityTypeStoreImpl
 	revocation              *revocationStoreImpl
+	revokedCertificate      *revokedCertificateStoreImpl
 	serviceEdgeRouterPolicy *serviceEdgeRouterPolicyStoreImpl
 	servicePolicy           *servicePolicyStoreImpl
 	session                 *sessionStoreImpl
+	signingKey              *signingKeyStoreImpl
 	transitRouter           *transitRouterStoreImpl
 	enrollment              *enrollmentStoreImpl
 	authenticator           *authenticatorStoreImpl
@@ -275,9 +279,12 @@ func InitStores(db boltz.Db, rateLimiter rate.RateLimiter, signingCert *x509.Cer
 	internalStores.serviceEdgeRouterPolicy = newServiceEdgeRouterPolicyStore(internalStores)
 	internalStores.servicePolicy = newServicePolicyStore(internalStores)
 	internalStores.session = newSessionStore(internalStores)
+	internalStores.signingKey = newSigningKeyStore(internalStores)
 	internalStores.postureCheck = newPostureCheckStore(internalStores)
 	internalStores.postureCheckType = newPostureCheckTypeStore(internalStores)
 	internalStores.mfa = newMfaStore(internalStores)
+	// registers constraints on the authenticator, edge router and identity stores, so must come after them
+	internalStores.revokedCertificate = newRevokedCertificateStore(internalStores)
 
 	externalStores := &Stores{
 		internal: internalStores,
@@ -301,9 +308,11 @@ func InitStores(db boltz.Db, rateLimiter rate.RateLimiter, signingCert *x509.Cer
 		Identity:                internalStores.identity,
 		IdentityType:            internalStores.identityType,
 		Revocation:              internalStores.revocation,
+		RevokedCertificate:      internalStores.revokedCertificate,
 		ServiceEdgeRouterPolicy: internalStores.serviceEdgeRouterPolicy,
 		ServicePolicy:           internalStores.servicePolicy,
 		Session:                 internalStores.session,
+		SigningKey:              internalStores.signingKey,
 		Authenticator:           internalStores.authenticator,
 		Enrollment:              internalStores.enrollment,
 		PostureCheck:            internalStores.postureCheck,
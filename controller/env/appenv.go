@@ -123,6 +123,9 @@ type AppEnv struct {
 	timelineId   concurrenz.AtomicValue[string]
 
 	TokenIssuerCache *model.TokenIssuerCache
+
+	// ManagementApiHandlers holds handlers for management API paths which aren't part of the generated API, keyed by path
+	ManagementApiHandlers map[string]AppHandler
 }
 
 // GetTokenIssuerCache returns the TokenIssuerCache instance for verifying external JWT tokens.
@@ -310,7 +313,8 @@ func (ae *AppEnv) GetClientApiDefaultTlsJwtSigner() *jwtsigner.TlsJwtSigner {
 	return ae.clientApiDefaultSigner
 }
 
-// GetRootTlsJwtSigner creates and returns a JWT signer using the root server certificate.
+// GetRootTlsJwtSigner creates and returns a JWT signer using the root server certificate. When signing key
+// rotation is enabled and a rotated key is active, tokens are signed with that key instead.
 func (ae *AppEnv) GetRootTlsJwtSigner() *jwtsigner.TlsJwtSigner {
 	rootCerts := ae.GetConfig().Id.ServerCert()
 	var rootCert *tls.Certificate
@@ -332,6 +336,15 @@ func (ae *AppEnv) GetRootTlsJwtSigner() *jwtsigner.TlsJwtSigner {
 		pfxlog.Logger().WithError(err).Panic("failed to set root controller identity signer")
 	}
 
+	if ae.Managers != nil && ae.Managers.SigningKey != nil {
+		if activeKey := ae.Managers.SigningKey.GetActiveKey(); activeKey != nil {
+			if err = rootSigner.SetKey(activeKey.Signer, activeKey.Kid); err != nil {
+				pfxlog.Logger().WithError(err).WithField("kid", activeKey.Kid).
+					Error("failed to use active signing key, signing with server certificate")
+			}
+		}
+	}
+
 	return rootSigner
 }
 
@@ -527,6 +540,14 @@ func (ae *AppEnv) GetCommandDispatcher() command.Dispatcher {
 	return ae.HostController.GetCommandDispatcher()
 }
 
+// AddManagementApiHandler registers a handler for a management API path which isn't part of the generated API.
+func (ae *AppEnv) AddManagementApiHandler(path string, handler AppHandler) {
+	if ae.ManagementApiHandlers == nil {
+		ae.ManagementApiHandlers = map[string]AppHandler{}
+	}
+	ae.ManagementApiHandlers[path] = handler
+}
+
 // AddRouterPresenceHandler registers a handler for router connect/disconnect events.
 func (ae *AppEnv) AddRouterPresenceHandler(h model.RouterPresenceHandler) {
 	ae.HostController.GetNetwork().AddRouterPresenceHandler(h)
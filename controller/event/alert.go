@@ -24,7 +24,8 @@ import (
 const (
 	AlertEventNS = "alert"
 
-	AlertSourceTypeRouter = "router"
+	AlertSourceTypeRouter     = "router"
+	AlertSourceTypeController = "controller"
 )
 
 // An AlertEvent is emitted when a ziti component generates an alert. Alerts are expected to be something that
@@ -32,13 +33,15 @@ const (
 //
 // Valid values for alert source type:
 //   - router
+//   - controller
 //
-// In the future, other alert sources may be supported, such as controller or SDK.
+// In the future, other alert sources may be supported, such as SDK.
 //
 // Valid values for severity:
 //   - error
+//   - warning
 //
-// In the future, other severities may be supported, such as info or warning.
+// In the future, other severities may be supported, such as info.
 //
 // Example: An alert generated because a config referenced an interface which was currently unavailable.
 //
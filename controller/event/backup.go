@@ -0,0 +1,107 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package event
+
+import (
+	"fmt"
+	"time"
+)
+
+type BackupEventType string
+
+const (
+	BackupEventNS = "backup"
+
+	BackupCompleted BackupEventType = "completed"
+	BackupFailed    BackupEventType = "failed"
+	BackupPruned    BackupEventType = "pruned"
+)
+
+// A BackupEvent is emitted when the controller makes a scheduled backup of its database, or removes an old backup
+// because of the configured retention policy.
+//
+// Valid values for event type:
+//   - completed - a backup was created and, if configured, verified and compressed
+//   - failed - a backup could not be created, verified or compressed. The error field describes the failure
+//   - pruned - an old backup was removed
+//
+// Example: Backup Completed Event
+//
+//	{
+//	  "namespace": "backup",
+//	  "event_type": "completed",
+//	  "event_src_id": "ctrl1",
+//	  "timestamp": "2026-10-18T02:00:00.412365802-04:00",
+//	  "path": "/var/lib/ziti/backups/ctrl.db-backup-20261018-020000.gz",
+//	  "size": 1048576,
+//	  "raft_index": 4211,
+//	  "compressed": true,
+//	  "verified": true,
+//	  "duration": 412365802
+//	}
+//
+// Example: Backup Failed Event
+//
+//	{
+//	  "namespace": "backup",
+//	  "event_type": "failed",
+//	  "event_src_id": "ctrl1",
+//	  "timestamp": "2026-10-18T02:00:00.012365802-04:00",
+//	  "path": "/var/lib/ziti/backups/ctrl.db-backup-20261018-020000",
+//	  "duration": 12365802,
+//	  "error": "open /var/lib/ziti/backups/ctrl.db-backup-20261018-020000: no space left on device"
+//	}
+type BackupEvent struct {
+	Namespace  string          `json:"namespace"`
+	EventType  BackupEventType `json:"event_type"`
+	EventSrcId string          `json:"event_src_id"`
+	Timestamp  time.Time       `json:"timestamp"`
+
+	// The path of the backup file
+	Path string `json:"path,omitempty"`
+
+	// The size of the backup file, in bytes
+	Size int64 `json:"size,omitempty"`
+
+	// The raft index of the database when the backup was made. Zero if the controller isn't clustered
+	RaftIndex uint64 `json:"raft_index,omitempty"`
+
+	// Whether the backup was compressed
+	Compressed bool `json:"compressed,omitempty"`
+
+	// Whether the backup passed an integrity check
+	Verified bool `json:"verified,omitempty"`
+
+	// How long the backup took, in nanoseconds
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Describes why the backup failed
+	Error string `json:"error,omitempty"`
+}
+
+func (event *BackupEvent) String() string {
+	return fmt.Sprintf("%v.%v path=%v size=%v error=%v", event.Namespace, event.EventType, event.Path, event.Size, event.Error)
+}
+
+type BackupEventHandler interface {
+	AcceptBackupEvent(event *BackupEvent)
+}
+
+type BackupEventHandlerWrapper interface {
+	BackupEventHandler
+	IsWrapping(value BackupEventHandler) bool
+}
@@ -61,6 +61,15 @@ type CircuitPath struct {
 
 	// The remote address on the terminating ziti component.
 	TerminatorRemoteAddr string `json:"terminator_remote_addr,omitempty"`
+
+	// For multipath circuits, the routers traversed by the secondary path.
+	SecondaryNodes []string `json:"secondary_nodes,omitempty"`
+
+	// For multipath circuits, the links traversed by the secondary path.
+	SecondaryLinks []string `json:"secondary_links,omitempty"`
+
+	// For multipath circuits, how traffic is distributed across the paths. Either duplicate or spray.
+	MultipathMode string `json:"multipath_mode,omitempty"`
 }
 
 func (self *CircuitPath) String() string {
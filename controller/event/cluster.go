@@ -37,6 +37,8 @@ const (
 	ClusterStateReadOnly    ClusterEventType = "state.ro"
 	ClusterStateReadWrite   ClusterEventType = "state.rw"
 	ClusterPeerNotMember    ClusterEventType = "peer.not_member"
+	ClusterQuorumAtRisk     ClusterEventType = "quorum.at_risk"
+	ClusterQuorumRestored   ClusterEventType = "quorum.restored"
 )
 
 // A ClusterPeer represents a controller which is a member of the cluster.
@@ -86,6 +88,8 @@ func (self *ClusterPeer) String() string {
 //   - state.is_leaderless - The cluster became leaderless
 //   - state.ro - The cluster is not accepting state changes, likely due to version mismatches in cluster members
 //   - state.rw - The cluster is accepting state changes
+//   - quorum.at_risk - The leader sees no margin left, the loss of one more voting member will lose quorum
+//   - quorum.restored - The leader sees the cluster can again lose a voting member without losing quorum
 //
 // Example: Cluster Members Changed Event
 //
@@ -174,6 +178,53 @@ type ClusterEvent struct {
 
 	// The leader id. Only populated for state.has_leader events.
 	LeaderId string `json:"leaderId,omitempty"`
+
+	// The quorum state as seen by the leader. Only populated for quorum.at_risk and quorum.restored events.
+	Quorum *ClusterQuorum `json:"quorum,omitempty"`
+}
+
+// A ClusterQuorum describes how many voting members a cluster can lose before it loses quorum.
+type ClusterQuorum struct {
+	// The number of voting members.
+	Voters int `json:"voters"`
+
+	// The number of voting members which are currently reachable.
+	HealthyVoters int `json:"healthyVoters"`
+
+	// The number of voting members required for quorum.
+	Quorum int `json:"quorum"`
+
+	// How many more healthy voting members can be lost without losing quorum.
+	FailuresTolerable int `json:"failuresTolerable"`
+
+	// Indicates if enough voting members are reachable to form a quorum.
+	HasQuorum bool `json:"hasQuorum"`
+}
+
+// NewClusterQuorum computes the quorum state for a cluster with the given number of voting members, of which
+// healthyVoters are reachable.
+func NewClusterQuorum(voters, healthyVoters int) *ClusterQuorum {
+	result := &ClusterQuorum{
+		Voters:        voters,
+		HealthyVoters: healthyVoters,
+	}
+
+	if voters > 0 {
+		result.Quorum = voters/2 + 1
+	}
+
+	result.HasQuorum = voters > 0 && healthyVoters >= result.Quorum
+	if result.HasQuorum {
+		result.FailuresTolerable = healthyVoters - result.Quorum
+	}
+
+	return result
+}
+
+// IsAtRisk returns true if the cluster has more than one voting member, but can't lose another one without losing
+// quorum. A single voter cluster never has any margin, so isn't reported as at risk.
+func (self *ClusterQuorum) IsAtRisk() bool {
+	return self.Voters > 1 && self.FailuresTolerable == 0
 }
 
 func (event *ClusterEvent) String() string {
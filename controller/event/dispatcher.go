@@ -83,6 +83,9 @@ type Dispatcher interface {
 	AddAlertEventHandler(handler AlertEventHandler)
 	RemoveAlertEventHandler(handler AlertEventHandler)
 
+	AddBackupEventHandler(handler BackupEventHandler)
+	RemoveBackupEventHandler(handler BackupEventHandler)
+
 	AddCircuitEventHandler(handler CircuitEventHandler)
 	RemoveCircuitEventHandler(handler CircuitEventHandler)
 
@@ -104,6 +107,9 @@ type Dispatcher interface {
 	AddServiceEventHandler(handler ServiceEventHandler)
 	RemoveServiceEventHandler(handler ServiceEventHandler)
 
+	AddSigningKeyEventHandler(handler SigningKeyEventHandler)
+	RemoveSigningKeyEventHandler(handler SigningKeyEventHandler)
+
 	AddTerminatorEventHandler(handler TerminatorEventHandler)
 	RemoveTerminatorEventHandler(handler TerminatorEventHandler)
 
@@ -134,6 +140,7 @@ type Dispatcher interface {
 	AlertEventHandler
 	ApiSessionEventHandler
 	AuthenticationEventHandler
+	BackupEventHandler
 	CircuitEventHandler
 	ConnectEventHandler
 	ClusterEventHandler
@@ -145,6 +152,7 @@ type Dispatcher interface {
 	SdkEventHandler
 	SessionEventHandler
 	ServiceEventHandler
+	SigningKeyEventHandler
 	TerminatorEventHandler
 	UsageEventHandler
 }
@@ -34,6 +34,18 @@ func (d DispatcherMock) RemoveAlertEventHandler(handler AlertEventHandler) {}
 
 func (d DispatcherMock) AcceptAlertEvent(event *AlertEvent) {}
 
+func (d DispatcherMock) AddBackupEventHandler(handler BackupEventHandler) {}
+
+func (d DispatcherMock) RemoveBackupEventHandler(handler BackupEventHandler) {}
+
+func (d DispatcherMock) AcceptBackupEvent(event *BackupEvent) {}
+
+func (d DispatcherMock) AddSigningKeyEventHandler(handler SigningKeyEventHandler) {}
+
+func (d DispatcherMock) RemoveSigningKeyEventHandler(handler SigningKeyEventHandler) {}
+
+func (d DispatcherMock) AcceptSigningKeyEvent(event *SigningKeyEvent) {}
+
 func (d DispatcherMock) AcceptSessionEvent(event *SessionEvent) {}
 
 func (d DispatcherMock) AcceptAuthenticationEvent(event *AuthenticationEvent) {}
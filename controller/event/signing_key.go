@@ -0,0 +1,101 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package event
+
+import (
+	"fmt"
+	"time"
+)
+
+type SigningKeyEventType string
+
+const (
+	SigningKeyEventNS = "signingKey"
+
+	SigningKeyPublished SigningKeyEventType = "published"
+	SigningKeyActivated SigningKeyEventType = "activated"
+	SigningKeyRetired   SigningKeyEventType = "retired"
+	SigningKeyRemoved   SigningKeyEventType = "removed"
+	SigningKeyFailed    SigningKeyEventType = "failed"
+)
+
+// A SigningKeyEvent is emitted as a controller moves its JWT signing keys through scheduled rotation.
+//
+// Valid values for event type:
+//   - published - a new key was generated and published for verification. It will be used for signing at activates_at
+//   - activated - the key is now used to sign new tokens
+//   - retired - the key was replaced and is only kept for verification until expires_at
+//   - removed - the key was removed and tokens signed with it no longer verify
+//   - failed - a rotation step failed and will be retried. The error field describes the failure
+//
+// Example: Signing Key Published Event
+//
+//	{
+//	  "namespace": "signingKey",
+//	  "event_type": "published",
+//	  "event_src_id": "ctrl1",
+//	  "timestamp": "2026-10-18T02:00:00.412365802-04:00",
+//	  "kid": "5f3c9d1e0a7b4c2d8e6f1a3b5c7d9e0f1a2b3c4d",
+//	  "algorithm": "ES256",
+//	  "activates_at": "2026-10-19T02:00:00.412365802-04:00"
+//	}
+//
+// Example: Signing Key Retired Event
+//
+//	{
+//	  "namespace": "signingKey",
+//	  "event_type": "retired",
+//	  "event_src_id": "ctrl1",
+//	  "timestamp": "2026-10-19T02:00:00.412365802-04:00",
+//	  "kid": "0a1b2c3d4e5f60718293a4b5c6d7e8f901234567",
+//	  "algorithm": "ES256",
+//	  "expires_at": "2026-10-26T02:00:00.412365802-04:00"
+//	}
+type SigningKeyEvent struct {
+	Namespace  string              `json:"namespace"`
+	EventType  SigningKeyEventType `json:"event_type"`
+	EventSrcId string              `json:"event_src_id"`
+	Timestamp  time.Time           `json:"timestamp"`
+
+	// The key id, as used in the kid header of tokens signed with the key
+	Kid string `json:"kid,omitempty"`
+
+	// The JWS algorithm the key signs with
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// When a published key will start being used for signing
+	ActivatesAt *time.Time `json:"activates_at,omitempty"`
+
+	// When a retired key will be removed
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Describes why a rotation step failed
+	Error string `json:"error,omitempty"`
+}
+
+func (event *SigningKeyEvent) String() string {
+	return fmt.Sprintf("%v.%v kid=%v algorithm=%v error=%v", event.Namespace, event.EventType, event.Kid, event.Algorithm, event.Error)
+}
+
+type SigningKeyEventHandler interface {
+	AcceptSigningKeyEvent(event *SigningKeyEvent)
+}
+
+type SigningKeyEventHandlerWrapper interface {
+	SigningKeyEventHandler
+	IsWrapping(value SigningKeyEventHandler) bool
+}
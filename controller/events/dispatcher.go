@@ -69,6 +69,7 @@ func NewDispatcher(closeNotify <-chan struct{}) *Dispatcher {
 	result.RegisterEventTypeFunctions(event.AlertEventNS, result.registerAlertEventHandler, result.unregisterAlertEventHandler)
 	result.RegisterEventTypeFunctions(event.ApiSessionEventNS, result.registerApiSessionEventHandler, result.unregisterApiSessionEventHandler)
 	result.RegisterEventTypeFunctions(event.AuthenticationEventNS, result.registerAuthenticationEventHandler, result.unregisterAuthenticationEventHandler)
+	result.RegisterEventTypeFunctions(event.BackupEventNS, result.registerBackupEventHandler, result.unregisterBackupEventHandler)
 	result.RegisterEventTypeFunctions(event.CircuitEventNS, result.registerCircuitEventHandler, result.unregisterCircuitEventHandler)
 	result.RegisterEventTypeFunctions(event.ClusterEventNS, result.registerClusterEventHandler, result.unregisterClusterEventHandler)
 	result.RegisterEventTypeFunctions(event.ConnectEventNS, result.registerConnectEventHandler, result.unregisterConnectEventHandler)
@@ -80,6 +81,7 @@ func NewDispatcher(closeNotify <-chan struct{}) *Dispatcher {
 	result.RegisterEventTypeFunctions(event.ServiceEventNS, result.registerServiceEventHandler, result.unregisterServiceEventHandler)
 	result.RegisterEventTypeFunctions(event.SessionEventNS, result.registerSessionEventHandler, result.unregisterSessionEventHandler)
 	result.RegisterEventTypeFunctions(event.SdkEventNS, result.registerSdkEventHandler, result.unregisterSdkEventHandler)
+	result.RegisterEventTypeFunctions(event.SigningKeyEventNS, result.registerSigningKeyEventHandler, result.unregisterSigningKeyEventHandler)
 	result.RegisterEventTypeFunctions(event.TerminatorEventNS, result.registerTerminatorEventHandler, result.unregisterTerminatorEventHandler)
 	result.RegisterEventTypeFunctions(event.UsageEventNS, result.registerUsageEventHandler, result.unregisterUsageEventHandler)
 
@@ -100,6 +102,7 @@ var _ event.Dispatcher = (*Dispatcher)(nil)
 type Dispatcher struct {
 	ctrlId                    string
 	alertEventHandlers        concurrenz.CopyOnWriteSlice[event.AlertEventHandler]
+	backupEventHandlers       concurrenz.CopyOnWriteSlice[event.BackupEventHandler]
 	circuitEventHandlers      concurrenz.CopyOnWriteSlice[event.CircuitEventHandler]
 	entityChangeEventHandlers concurrenz.CopyOnWriteSlice[event.EntityChangeEventHandler]
 	linkEventHandlers         concurrenz.CopyOnWriteSlice[event.LinkEventHandler]
@@ -107,6 +110,7 @@ type Dispatcher struct {
 	metricsMsgEventHandlers   concurrenz.CopyOnWriteSlice[event.MetricsMessageHandler]
 	routerEventHandlers       concurrenz.CopyOnWriteSlice[event.RouterEventHandler]
 	serviceEventHandlers      concurrenz.CopyOnWriteSlice[event.ServiceEventHandler]
+	signingKeyEventHandlers   concurrenz.CopyOnWriteSlice[event.SigningKeyEventHandler]
 	terminatorEventHandlers   concurrenz.CopyOnWriteSlice[event.TerminatorEventHandler]
 	usageEventHandlers        concurrenz.CopyOnWriteSlice[event.UsageEventHandler]
 	usageEventV3Handlers      concurrenz.CopyOnWriteSlice[event.UsageEventV3Handler]
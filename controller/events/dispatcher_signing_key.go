@@ -0,0 +1,62 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package events
+
+import (
+	"github.com/openziti/ziti/v2/controller/event"
+	"github.com/pkg/errors"
+)
+
+func (self *Dispatcher) AddSigningKeyEventHandler(handler event.SigningKeyEventHandler) {
+	self.signingKeyEventHandlers.Append(handler)
+}
+
+func (self *Dispatcher) RemoveSigningKeyEventHandler(handler event.SigningKeyEventHandler) {
+	self.signingKeyEventHandlers.DeleteIf(func(val event.SigningKeyEventHandler) bool {
+		if val == handler {
+			return true
+		}
+		if w, ok := val.(event.SigningKeyEventHandlerWrapper); ok {
+			return w.IsWrapping(handler)
+		}
+		return false
+	})
+}
+
+func (self *Dispatcher) AcceptSigningKeyEvent(evt *event.SigningKeyEvent) {
+	evt.EventSrcId = self.ctrlId
+	for _, handler := range self.signingKeyEventHandlers.Value() {
+		go handler.AcceptSigningKeyEvent(evt)
+	}
+}
+
+func (self *Dispatcher) registerSigningKeyEventHandler(_ string, val interface{}, _ map[string]interface{}) error {
+	handler, ok := val.(event.SigningKeyEventHandler)
+
+	if !ok {
+		return errors.Errorf("type %T doesn't implement the event.SigningKeyEventHandler interface", val)
+	}
+
+	self.AddSigningKeyEventHandler(handler)
+	return nil
+}
+
+func (self *Dispatcher) unregisterSigningKeyEventHandler(val interface{}) {
+	if handler, ok := val.(event.SigningKeyEventHandler); ok {
+		self.RemoveSigningKeyEventHandler(handler)
+	}
+}
@@ -106,6 +106,26 @@ func (event *JsonAlertEvent) Format() ([]byte, error) {
 	return MarshalJson(event)
 }
 
+type JsonBackupEvent event.BackupEvent
+
+func (event *JsonBackupEvent) GetEventType() string {
+	return "backup"
+}
+
+func (event *JsonBackupEvent) Format() ([]byte, error) {
+	return MarshalJson(event)
+}
+
+type JsonSigningKeyEvent event.SigningKeyEvent
+
+func (event *JsonSigningKeyEvent) GetEventType() string {
+	return "signingKey"
+}
+
+func (event *JsonSigningKeyEvent) Format() ([]byte, error) {
+	return MarshalJson(event)
+}
+
 type JsonCircuitEvent event.CircuitEvent
 
 func (event *JsonCircuitEvent) GetEventType() string {
@@ -286,6 +306,14 @@ func (formatter *JsonFormatter) AcceptAlertEvent(evt *event.AlertEvent) {
 	formatter.AcceptLoggingEvent((*JsonAlertEvent)(evt))
 }
 
+func (formatter *JsonFormatter) AcceptBackupEvent(evt *event.BackupEvent) {
+	formatter.AcceptLoggingEvent((*JsonBackupEvent)(evt))
+}
+
+func (formatter *JsonFormatter) AcceptSigningKeyEvent(evt *event.SigningKeyEvent) {
+	formatter.AcceptLoggingEvent((*JsonSigningKeyEvent)(evt))
+}
+
 func (formatter *JsonFormatter) AcceptCircuitEvent(evt *event.CircuitEvent) {
 	formatter.AcceptLoggingEvent((*JsonCircuitEvent)(evt))
 }
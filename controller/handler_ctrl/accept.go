@@ -22,6 +22,7 @@ import (
 	"github.com/openziti/channel/v5"
 	"github.com/openziti/ziti/v2/common/capabilities"
 	"github.com/openziti/ziti/v2/common/ctrlchan"
+	"github.com/openziti/ziti/v2/common/locality"
 	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
 	"github.com/openziti/ziti/v2/controller/change"
 	"github.com/openziti/ziti/v2/controller/network"
@@ -134,6 +135,11 @@ func (self *CtrlAccepter) Bind(binding channel.Binding) error {
 		r.SetLinkListeners(nil)
 		headers := ch.Underlay().Headers()
 
+		r.Locality = locality.FromHeaders(headers)
+		if r.Locality.IsSet() {
+			log = log.WithField("locality", r.Locality.String())
+		}
+
 		// Determine header locations based on router capabilities. 2.0+ routers
 		// send a CapabilitiesHeader with RouterMultiChannel set and use header IDs
 		// in the 1000+ range. Pre-2.0 routers use legacy IDs (10-12) and don't
@@ -202,7 +202,7 @@ func (handler *validateCircuitsHandler) validRouterCircuits(router *model.Router
 
 	for _, circuit := range circuitMgr.All() {
 		routerRelevant := false
-		for _, pathRouter := range circuit.Path.Nodes {
+		for _, pathRouter := range circuit.Path.AllNodes() {
 			if pathRouter.Id == router.Id {
 				routerRelevant = true
 				break
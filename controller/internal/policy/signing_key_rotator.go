@@ -0,0 +1,68 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package policy
+
+import (
+	"time"
+
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/ziti/v2/common/runner"
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/config"
+	"github.com/openziti/ziti/v2/controller/env"
+)
+
+const (
+	SigningKeyRotatorRun    = "signing.key.rotator.run"
+	SigningKeyRotatorSource = "signing.key.rotator"
+)
+
+// SigningKeyRotator periodically checks whether this controller's JWT signing key is due to be replaced, and moves
+// its keys through rotation. Unlike the other enforcers it runs on every controller, as each controller owns its
+// own signing keys.
+type SigningKeyRotator struct {
+	appEnv *env.AppEnv
+	config *config.SigningKeyRotation
+	*runner.BaseOperation
+}
+
+// NewSigningKeyRotator creates a SigningKeyRotator that runs at the given frequency.
+func NewSigningKeyRotator(appEnv *env.AppEnv, cfg *config.SigningKeyRotation, frequency time.Duration) *SigningKeyRotator {
+	return &SigningKeyRotator{
+		appEnv:        appEnv,
+		config:        cfg,
+		BaseOperation: runner.NewBaseOperation("SigningKeyRotator", frequency),
+	}
+}
+
+// Run performs one rotation step. Failures are logged and reported as signing key events, and retried on the
+// next run.
+func (r *SigningKeyRotator) Run() error {
+	startTime := time.Now()
+
+	defer func() {
+		r.appEnv.GetMetricsRegistry().Timer(SigningKeyRotatorRun).UpdateSince(startTime)
+	}()
+
+	ctx := change.New().SetSourceType(SigningKeyRotatorSource).SetChangeAuthorType(change.AuthorTypeController)
+
+	if err := r.appEnv.GetManagers().SigningKey.Rotate(r.config, startTime, ctx); err != nil {
+		pfxlog.Logger().WithError(err).Error("failed to rotate JWT signing keys")
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package routes
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+	"github.com/openziti/edge-api/rest_model"
+	"github.com/openziti/foundation/v2/errorz"
+	"github.com/openziti/ziti/v2/controller/apierror"
+	"github.com/openziti/ziti/v2/controller/env"
+	"github.com/openziti/ziti/v2/controller/model"
+	"github.com/openziti/ziti/v2/controller/permissions"
+	"github.com/openziti/ziti/v2/controller/response"
+	"github.com/openziti/ziti/v2/controller/webapis"
+)
+
+const (
+	AccessMatrixFormatJson = "json"
+	AccessMatrixFormatCsv  = "csv"
+)
+
+func init() {
+	r := NewAccessMatrixRouter()
+	env.AddRouter(r)
+}
+
+// AccessMatrixRouter reports the effective access every identity has to every service, optionally limited by
+// identity and service filters. The report can be returned as JSON or CSV.
+type AccessMatrixRouter struct {
+	BasePath string
+}
+
+func NewAccessMatrixRouter() *AccessMatrixRouter {
+	return &AccessMatrixRouter{
+		BasePath: "/policy-advisor/access-matrix",
+	}
+}
+
+func (r *AccessMatrixRouter) Register(ae *env.AppEnv) {
+	ae.AddManagementApiHandler(webapis.ManagementRestApiBaseUrlLatest+r.BasePath, r.Matrix)
+}
+
+func (r *AccessMatrixRouter) Matrix(ae *env.AppEnv, rc *response.RequestContext) {
+	if rc.Request.Method != http.MethodGet {
+		rc.RespondWithApiError(apierror.NewMethodNotAllowed())
+		return
+	}
+
+	for _, entityType := range []string{"identity", "service", "service-policy", "edge-router-policy", "service-edge-router-policy", "posture-check"} {
+		rc.InitPermissionsContext(permissions.Management, entityType, permissions.Read)
+		if !permissions.DefaultManagementAccess().IsAllowed(rc) {
+			if securityErr := rc.SecurityCtx.GetError(); securityErr != nil {
+				rc.RespondWithError(securityErr)
+			} else {
+				rc.RespondWithApiError(errorz.NewUnauthorized())
+			}
+			return
+		}
+	}
+
+	params := rc.Request.URL.Query()
+
+	format := params.Get("format")
+	if format == "" {
+		format = AccessMatrixFormatJson
+	}
+
+	if format != AccessMatrixFormatJson && format != AccessMatrixFormatCsv {
+		rc.RespondWithFieldError(errorz.NewFieldError("format must be json or csv", "format", format))
+		return
+	}
+
+	entries, err := ae.Managers.PolicyAdvisor.AccessMatrix(params.Get("identityFilter"), params.Get("serviceFilter"))
+	if err != nil {
+		rc.RespondWithError(err)
+		return
+	}
+
+	if format == AccessMatrixFormatCsv {
+		rc.ResponseWriter.Header().Set("Content-Type", "text/csv")
+		rc.ResponseWriter.Header().Set("Content-Disposition", `attachment; filename="access-matrix.csv"`)
+		rc.RespondWithProducer(runtime.ProducerFunc(writeAccessMatrixCsv), entries, http.StatusOK)
+		return
+	}
+
+	if entries == nil {
+		entries = []*model.AdvisorAccessMatrixEntry{}
+	}
+	rc.RespondWithOk(entries, &rest_model.Meta{})
+}
+
+// writeAccessMatrixCsv writes one row per identity/service pair. Posture checks and edge routers are listed by name,
+// separated by semicolons.
+func writeAccessMatrixCsv(w io.Writer, data any) error {
+	entries, ok := data.([]*model.AdvisorAccessMatrixEntry)
+	if !ok {
+		return fmt.Errorf("unexpected access matrix data type %T", data)
+	}
+
+	joinNames := func(refs []model.AdvisorEntityRef) string {
+		var names []string
+		for _, ref := range refs {
+			names = append(names, ref.Name)
+		}
+		return strings.Join(names, ";")
+	}
+
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{
+		"identityId", "identityName", "serviceId", "serviceName", "dial", "bind",
+		"dialPostureChecks", "bindPostureChecks", "edgeRouters", "onlineEdgeRouters",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		var edgeRouters, onlineEdgeRouters []model.AdvisorEntityRef
+		for _, edgeRouter := range entry.EdgeRouters {
+			edgeRouters = append(edgeRouters, edgeRouter.AdvisorEntityRef)
+			if edgeRouter.IsOnline {
+				onlineEdgeRouters = append(onlineEdgeRouters, edgeRouter.AdvisorEntityRef)
+			}
+		}
+
+		err = writer.Write([]string{
+			entry.Identity.Id,
+			entry.Identity.Name,
+			entry.Service.Id,
+			entry.Service.Name,
+			strconv.FormatBool(entry.Dial),
+			strconv.FormatBool(entry.Bind),
+			joinNames(entry.DialPostureChecks),
+			joinNames(entry.BindPostureChecks),
+			joinNames(edgeRouters),
+			joinNames(onlineEdgeRouters),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -94,7 +94,7 @@ func (r *AuthPolicyRouter) Delete(ae *env.AppEnv, rc *response.RequestContext) {
 }
 
 func (r *AuthPolicyRouter) Update(ae *env.AppEnv, rc *response.RequestContext, params auth_policy.UpdateAuthPolicyParams) {
-	Update(rc, func(id string) error {
+	Update(ae, rc, func(id string) error {
 		if err := params.AuthPolicy.AuthPolicyCreate.Validate(strfmt.Default); err != nil {
 			return errorz.NewCouldNotValidate(err)
 		}
@@ -103,7 +103,7 @@ func (r *AuthPolicyRouter) Update(ae *env.AppEnv, rc *response.RequestContext, p
 }
 
 func (r *AuthPolicyRouter) Patch(ae *env.AppEnv, rc *response.RequestContext, params auth_policy.PatchAuthPolicyParams) {
-	Patch(rc, func(id string, fields fields.UpdatedFields) error {
+	Patch(ae, rc, func(id string, fields fields.UpdatedFields) error {
 		return ae.Managers.AuthPolicy.Update(MapPatchAuthPolicyToModel(params.ID, params.AuthPolicy), fields.FilterMaps("tags"), rc.NewChangeContext())
 	})
 }
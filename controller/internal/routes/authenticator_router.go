@@ -129,7 +129,7 @@ func (r *AuthenticatorRouter) Update(ae *env.AppEnv, rc *response.RequestContext
 		return
 	}
 
-	Update(rc, func(id string) error {
+	Update(ae, rc, func(id string) error {
 		return ae.Managers.Authenticator.Update(MapUpdateAuthenticatorToModel(params.ID, params.Authenticator), false, nil, rc.NewChangeContext())
 	})
 }
@@ -139,7 +139,7 @@ func (r *AuthenticatorRouter) Patch(ae *env.AppEnv, rc *response.RequestContext,
 		return
 	}
 
-	Patch(rc, func(id string, fields fields.UpdatedFields) error {
+	Patch(ae, rc, func(id string, fields fields.UpdatedFields) error {
 		modelEntity := MapPatchAuthenticatorToModel(params.ID, params.Authenticator)
 
 		if fields.IsUpdated("password") {
@@ -260,6 +260,9 @@ func DetailWithHandler[E hasId](ae *env.AppEnv, rc *response.RequestContext, loa
 		if err != nil {
 			return nil, err
 		}
+		if versioned, ok := any(entity).(models.Versioned); ok {
+			response.AddEntityTagHeader(rc, versioned.GetRevision())
+		}
 		return mapper(ae, rc, entity)
 	})
 }
@@ -331,11 +334,11 @@ func Delete(rc *response.RequestContext, deleteF ModelDeleteF) {
 
 type ModelUpdateF func(id string) error
 
-func Update(rc *response.RequestContext, updateF ModelUpdateF) {
-	UpdateAllowEmptyBody(rc, updateF)
+func Update(ae *env.AppEnv, rc *response.RequestContext, updateF ModelUpdateF) {
+	UpdateAllowEmptyBody(ae, rc, updateF)
 }
 
-func UpdateAllowEmptyBody(rc *response.RequestContext, updateF ModelUpdateF) {
+func UpdateAllowEmptyBody(ae *env.AppEnv, rc *response.RequestContext, updateF ModelUpdateF) {
 	id, err := rc.GetEntityId()
 
 	if err != nil {
@@ -365,12 +368,13 @@ func UpdateAllowEmptyBody(rc *response.RequestContext, updateF ModelUpdateF) {
 		return
 	}
 
+	addUpdatedEntityTagHeader(ae, rc, id)
 	rc.RespondWithEmptyOk()
 }
 
 type ModelPatchF func(id string, fields fields.UpdatedFields) error
 
-func Patch(rc *response.RequestContext, patchF ModelPatchF) {
+func Patch(ae *env.AppEnv, rc *response.RequestContext, patchF ModelPatchF) {
 	id, err := rc.GetEntityId()
 
 	if err != nil {
@@ -407,9 +411,31 @@ func Patch(rc *response.RequestContext, patchF ModelPatchF) {
 		return
 	}
 
+	addUpdatedEntityTagHeader(ae, rc, id)
 	rc.RespondWithEmptyOk()
 }
 
+// addUpdatedEntityTagHeader sets the ETag header to the revision of the entity with the given id, so that clients can
+// make their next conditional write without re-reading the entity. Updates wait until they've been applied locally,
+// so the revision read here includes the update.
+func addUpdatedEntityTagHeader(ae *env.AppEnv, rc *response.RequestContext, id string) {
+	err := ae.GetDb().View(func(tx *bbolt.Tx) error {
+		for _, store := range ae.GetStores().GetStoreList() {
+			if store.IsChildStore() {
+				continue
+			}
+			if entityBucket := store.GetEntityBucket(tx, []byte(id)); entityBucket != nil {
+				response.AddEntityTagHeader(rc, entityBucket.GetInt64WithDefault(boltz.FieldRevision, 0))
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		pfxlog.Logger().WithError(err).WithField("id", id).Warn("unable to read entity revision for ETag header")
+	}
+}
+
 func listWithId(rc *response.RequestContext, f func(id string) ([]interface{}, error)) {
 	id, err := rc.GetEntityId()
 
@@ -0,0 +1,606 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/openziti/edge-api/rest_model"
+	"github.com/openziti/foundation/v2/errorz"
+	"github.com/openziti/ziti/v2/controller/api"
+	"github.com/openziti/ziti/v2/controller/apierror"
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/env"
+	"github.com/openziti/ziti/v2/controller/fields"
+	"github.com/openziti/ziti/v2/controller/model"
+	"github.com/openziti/ziti/v2/controller/models"
+	"github.com/openziti/ziti/v2/controller/permissions"
+	"github.com/openziti/ziti/v2/controller/response"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/openziti/ziti/v2/controller/webapis"
+)
+
+const (
+	BatchMethodCreate = "create"
+	BatchMethodUpdate = "update"
+	BatchMethodPatch  = "patch"
+	BatchMethodDelete = "delete"
+)
+
+// batchRefPattern matches references to the ids of entities created by earlier operations in the same batch
+var batchRefPattern = regexp.MustCompile(`\$\{([A-Za-z0-9_.-]+)}`)
+
+func init() {
+	r := NewBatchRouter()
+	env.AddRouter(r)
+}
+
+// BatchRouter handles management API batches. A batch is an ordered list of create, update, patch and delete
+// operations which are applied as a single command, so either all of them are committed or none are.
+//
+// Operations may be given a ref. Later operations can use the id of an entity created by an earlier operation by
+// including ${ref} in their id or in any string in their body, for example in a role attribute: "@${myService}".
+type BatchRouter struct {
+	BasePath string
+	handlers map[string]*batchEntityHandler
+}
+
+func NewBatchRouter() *BatchRouter {
+	return &BatchRouter{
+		BasePath: "/batch",
+		handlers: map[string]*batchEntityHandler{
+			EntityNameConfig:                  configBatchHandler(),
+			EntityNameConfigType:              configTypeBatchHandler(),
+			EntityNameService:                 serviceBatchHandler(),
+			EntityNameIdentity:                identityBatchHandler(),
+			EntityNameServicePolicy:           servicePolicyBatchHandler(),
+			EntityNameEdgeRouterPolicy:        edgeRouterPolicyBatchHandler(),
+			EntityNameServiceEdgeRouterPolicy: serviceEdgeRouterPolicyBatchHandler(),
+		},
+	}
+}
+
+func (r *BatchRouter) Register(ae *env.AppEnv) {
+	ae.AddManagementApiHandler(webapis.ManagementRestApiBaseUrlLatest+r.BasePath, r.Batch)
+}
+
+type BatchRequest struct {
+	Operations []*BatchOperation `json:"operations"`
+}
+
+type BatchOperation struct {
+	Ref        string          `json:"ref,omitempty"`
+	Method     string          `json:"method"`
+	EntityType string          `json:"entityType"`
+	Id         string          `json:"id,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+type BatchOperationResult struct {
+	Index      int    `json:"index"`
+	Ref        string `json:"ref,omitempty"`
+	Method     string `json:"method"`
+	EntityType string `json:"entityType"`
+	Id         string `json:"id"`
+}
+
+func (r *BatchRouter) Batch(ae *env.AppEnv, rc *response.RequestContext) {
+	if rc.Request.Method != http.MethodPost {
+		rc.RespondWithApiError(apierror.NewMethodNotAllowed())
+		return
+	}
+
+	request := &BatchRequest{}
+	if err := json.Unmarshal(rc.Body, request); err != nil {
+		rc.RespondWithApiError(apierror.NewCouldNotParseBody(err))
+		return
+	}
+
+	if len(request.Operations) == 0 {
+		rc.RespondWithFieldError(errorz.NewFieldError("at least one operation is required", "operations", nil))
+		return
+	}
+
+	changeCtx := rc.NewChangeContext()
+	batch := ae.Managers.Command.NewBatch(changeCtx)
+
+	results, commandOffsets, apiErr := r.record(ae, rc, batch, request.Operations)
+	if apiErr != nil {
+		rc.RespondWithApiError(apiErr)
+		return
+	}
+
+	if err := batch.Dispatch(); err != nil {
+		respondWithBatchError(rc, err, commandOffsets)
+		return
+	}
+
+	rc.RespondWithOk(results, &rest_model.Meta{})
+}
+
+// record records the commands for each operation in the batch. Along with the operation results, it returns the index
+// of the first command recorded by each operation, so failed commands can be mapped back to operations. If an
+// operation can't be recorded, the batch is discarded.
+func (r *BatchRouter) record(ae *env.AppEnv, rc *response.RequestContext, batch *model.Batch, ops []*BatchOperation) ([]*BatchOperationResult, []int, *errorz.ApiError) {
+	refs := map[string]string{}
+	var results []*BatchOperationResult
+	var commandOffsets []int
+
+	for idx, op := range ops {
+		commandOffsets = append(commandOffsets, batch.Len())
+		result, err := r.apply(ae, rc, batch.ChangeContext(), op, refs)
+		if err != nil {
+			batch.Discard()
+			return nil, nil, newBatchOperationError(idx, err)
+		}
+		result.Index = idx
+		results = append(results, result)
+	}
+
+	return results, commandOffsets, nil
+}
+
+func (r *BatchRouter) apply(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, op *BatchOperation, refs map[string]string) (*BatchOperationResult, error) {
+	handler, found := r.handlers[op.EntityType]
+	if !found {
+		return nil, errorz.NewFieldError("unsupported entity type", "entityType", op.EntityType)
+	}
+
+	if op.Ref != "" {
+		if _, found = refs[op.Ref]; found {
+			return nil, errorz.NewFieldError("duplicate ref", "ref", op.Ref)
+		}
+	}
+
+	id, err := resolveBatchRefs(op.Id, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := resolveBatchBodyRefs(op.Body, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	var action permissions.Action
+	switch op.Method {
+	case BatchMethodCreate:
+		action = permissions.Create
+	case BatchMethodUpdate, BatchMethodPatch:
+		action = permissions.Update
+	case BatchMethodDelete:
+		action = permissions.Delete
+	default:
+		return nil, errorz.NewFieldError("unsupported method", "method", op.Method)
+	}
+
+	if op.Method != BatchMethodCreate && id == "" {
+		return nil, errorz.NewFieldError("id is required", "id", op.Id)
+	}
+
+	rc.InitPermissionsContext(permissions.Management, handler.permission, action)
+	if !permissions.DefaultManagementAccess().IsAllowed(rc) {
+		if securityErr := rc.SecurityCtx.GetError(); securityErr != nil {
+			return nil, securityErr
+		}
+		return nil, errorz.NewUnauthorized()
+	}
+
+	switch op.Method {
+	case BatchMethodCreate:
+		id, err = handler.create(ae, rc, ctx, body)
+	case BatchMethodUpdate:
+		err = handler.update(ae, rc, ctx, id, body)
+	case BatchMethodPatch:
+		var updatedFields fields.UpdatedFields
+		if updatedFields, err = api.GetFields(body); err == nil {
+			err = handler.patch(ae, rc, ctx, id, body, updatedFields)
+		}
+	case BatchMethodDelete:
+		err = handler.delete(ae, rc, ctx, id)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if op.Ref != "" {
+		refs[op.Ref] = id
+	}
+
+	return &BatchOperationResult{
+		Ref:        op.Ref,
+		Method:     op.Method,
+		EntityType: op.EntityType,
+		Id:         id,
+	}, nil
+}
+
+// respondWithBatchError responds with an error from applying a batch. If a command failed, the error identifies the
+// operation which recorded it.
+func respondWithBatchError(rc *response.RequestContext, err error, commandOffsets []int) {
+	var batchErr *model.BatchError
+	if !errors.As(err, &batchErr) {
+		rc.RespondWithError(err)
+		return
+	}
+
+	opIdx := 0
+	for idx, offset := range commandOffsets {
+		if offset <= batchErr.Index {
+			opIdx = idx
+		}
+	}
+	rc.RespondWithApiError(newBatchOperationError(opIdx, batchErr.Cause))
+}
+
+func newBatchOperationError(idx int, err error) *errorz.ApiError {
+	var uie *boltz.UniqueIndexDuplicateError
+	if errors.As(err, &uie) {
+		err = errorz.NewFieldError(uie.Error(), uie.Field, uie.Value)
+	}
+
+	result := *models.ToApiError(err)
+	result.Message = fmt.Sprintf("batch operation %d failed: %s", idx, result.Message)
+	return &result
+}
+
+// resolveBatchRefs replaces ${ref} with the id of the entity created by the operation with the given ref
+func resolveBatchRefs(val string, refs map[string]string) (string, error) {
+	var err error
+	result := batchRefPattern.ReplaceAllStringFunc(val, func(match string) string {
+		ref := batchRefPattern.FindStringSubmatch(match)[1]
+		id, found := refs[ref]
+		if !found && err == nil {
+			err = errorz.NewFieldError("unknown ref, refs must be defined by an earlier operation", "ref", ref)
+		}
+		return id
+	})
+	return result, err
+}
+
+func resolveBatchBodyRefs(body json.RawMessage, refs map[string]string) ([]byte, error) {
+	if len(body) == 0 {
+		return []byte("{}"), nil
+	}
+
+	if !batchRefPattern.Match(body) {
+		return body, nil
+	}
+
+	var parsed any
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, apierror.NewCouldNotParseBody(err)
+	}
+
+	resolved, err := resolveBatchValueRefs(parsed, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(resolved)
+}
+
+func resolveBatchValueRefs(val any, refs map[string]string) (any, error) {
+	var err error
+	switch v := val.(type) {
+	case string:
+		return resolveBatchRefs(v, refs)
+	case []any:
+		for i, elem := range v {
+			if v[i], err = resolveBatchValueRefs(elem, refs); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]any:
+		for k, elem := range v {
+			if v[k], err = resolveBatchValueRefs(elem, refs); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return val, nil
+}
+
+func batchCreate[T models.Entity](f func(T, *change.Context) error, entity T, ctx *change.Context) (string, error) {
+	if err := f(entity, ctx); err != nil {
+		return "", err
+	}
+	return entity.GetId(), nil
+}
+
+type validatable interface {
+	Validate(formats strfmt.Registry) error
+}
+
+func decodeBatchBody(body []byte, v validatable) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return apierror.NewCouldNotParseBody(err)
+	}
+	if err := v.Validate(strfmt.Default); err != nil {
+		return errorz.NewCouldNotValidate(err)
+	}
+	return nil
+}
+
+// batchEntityHandler maps batch operations for a single entity type to manager calls. The handlers mirror the
+// logic of the corresponding entity routers.
+type batchEntityHandler struct {
+	permission string
+	create     func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, body []byte) (string, error)
+	update     func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte) error
+	patch      func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte, fields fields.UpdatedFields) error
+	delete     func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string) error
+}
+
+func configBatchHandler() *batchEntityHandler {
+	return &batchEntityHandler{
+		permission: "config",
+		create: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, body []byte) (string, error) {
+			restEntity := &rest_model.ConfigCreate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return "", err
+			}
+			if restEntity.Data == nil {
+				return "", errorz.NewFieldError("data is required", "data", nil)
+			}
+			entity, err := MapCreateConfigToModel(restEntity)
+			if err != nil {
+				return "", err
+			}
+			return batchCreate(ae.Managers.Config.Create, entity, ctx)
+		},
+		update: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte) error {
+			restEntity := &rest_model.ConfigUpdate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			entity, err := MapUpdateConfigToModel(id, restEntity)
+			if err != nil {
+				return err
+			}
+			return ae.Managers.Config.Update(entity, nil, ctx)
+		},
+		patch: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte, fields fields.UpdatedFields) error {
+			restEntity := &rest_model.ConfigPatch{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			entity, err := MapPatchConfigToModel(id, restEntity)
+			if err != nil {
+				return err
+			}
+			return ae.Managers.Config.Update(entity, fields.FilterMaps("tags", "data"), ctx)
+		},
+		delete: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string) error {
+			return ae.Managers.Config.Delete(id, ctx)
+		},
+	}
+}
+
+func configTypeBatchHandler() *batchEntityHandler {
+	return &batchEntityHandler{
+		permission: "config-type",
+		create: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, body []byte) (string, error) {
+			restEntity := &rest_model.ConfigTypeCreate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return "", err
+			}
+			if restEntity.Schema != nil {
+				if _, ok := restEntity.Schema.(map[string]interface{}); !ok {
+					return "", errorz.NewFieldError("invalid type, expected object", "schema", restEntity.Schema)
+				}
+			}
+			entity := MapCreateConfigTypeToModel(restEntity)
+			return batchCreate(ae.Managers.ConfigType.Create, entity, ctx)
+		},
+		update: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte) error {
+			restEntity := &rest_model.ConfigTypeUpdate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.ConfigType.Update(MapUpdateConfigTypeToModel(id, restEntity), nil, ctx)
+		},
+		patch: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte, fields fields.UpdatedFields) error {
+			restEntity := &rest_model.ConfigTypePatch{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.ConfigType.Update(MapPatchConfigTypeToModel(id, restEntity), fields.FilterMaps("tags", "schema"), ctx)
+		},
+		delete: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string) error {
+			return ae.Managers.ConfigType.Delete(id, ctx)
+		},
+	}
+}
+
+func serviceBatchHandler() *batchEntityHandler {
+	return &batchEntityHandler{
+		permission: "service",
+		create: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, body []byte) (string, error) {
+			restEntity := &rest_model.ServiceCreate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return "", err
+			}
+			entity := MapCreateServiceToModel(restEntity)
+			return batchCreate(ae.Managers.EdgeService.Create, entity, ctx)
+		},
+		update: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte) error {
+			restEntity := &rest_model.ServiceUpdate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.EdgeService.Update(MapUpdateServiceToModel(id, restEntity), nil, ctx)
+		},
+		patch: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte, fields fields.UpdatedFields) error {
+			restEntity := &rest_model.ServicePatch{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.EdgeService.Update(MapPatchServiceToModel(id, restEntity), fields.FilterMaps("tags").MapField("maxIdleTimeMillis", "maxIdleTime"), ctx)
+		},
+		delete: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string) error {
+			return ae.Managers.EdgeService.Delete(id, ctx)
+		},
+	}
+}
+
+func identityBatchHandler() *batchEntityHandler {
+	return &batchEntityHandler{
+		permission: "identity",
+		create: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, body []byte) (string, error) {
+			restEntity := &rest_model.IdentityCreate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return "", err
+			}
+			if err := checkIdentityCreateAllowed(rc, restEntity); err != nil {
+				return "", err
+			}
+			identityModel, enrollments := MapCreateIdentityToModel(restEntity)
+			if err := ae.Managers.Identity.CreateWithEnrollments(identityModel, enrollments, ctx); err != nil {
+				return "", err
+			}
+			return identityModel.Id, nil
+		},
+		update: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte) error {
+			if err := checkIdentityUpdateAllowed(rc); err != nil {
+				return err
+			}
+			restEntity := &rest_model.IdentityUpdate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.Identity.Update(MapUpdateIdentityToModel(id, restEntity, getIdentityTypeId(ae, *restEntity.Type)), nil, ctx)
+		},
+		patch: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte, fields fields.UpdatedFields) error {
+			restEntity := &rest_model.IdentityPatch{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			fields = fields.FilterMaps(boltz.FieldTags, db.FieldIdentityAppData, db.FieldIdentityServiceHostingCosts, db.FieldIdentityServiceHostingPrecedences)
+			if err := checkIdentityPatchAllowed(ae, rc, id, fields); err != nil {
+				return err
+			}
+			return ae.Managers.Identity.Update(MapPatchIdentityToModel(id, restEntity, getIdentityTypeId(ae, restEntity.Type)), fields, ctx)
+		},
+		delete: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string) error {
+			if err := checkIdentityDeleteAllowed(ae, rc, id); err != nil {
+				return err
+			}
+			return ae.Managers.Identity.Delete(id, ctx)
+		},
+	}
+}
+
+func servicePolicyBatchHandler() *batchEntityHandler {
+	return &batchEntityHandler{
+		permission: "service-policy",
+		create: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, body []byte) (string, error) {
+			restEntity := &rest_model.ServicePolicyCreate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return "", err
+			}
+			entity := MapCreateServicePolicyToModel(restEntity)
+			return batchCreate(ae.Managers.ServicePolicy.Create, entity, ctx)
+		},
+		update: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte) error {
+			restEntity := &rest_model.ServicePolicyUpdate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.ServicePolicy.Update(MapUpdateServicePolicyToModel(id, restEntity), nil, ctx)
+		},
+		patch: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte, fields fields.UpdatedFields) error {
+			restEntity := &rest_model.ServicePolicyPatch{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.ServicePolicy.Update(MapPatchServicePolicyToModel(id, restEntity), fields.FilterMaps("tags"), ctx)
+		},
+		delete: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string) error {
+			return ae.Managers.ServicePolicy.Delete(id, ctx)
+		},
+	}
+}
+
+func edgeRouterPolicyBatchHandler() *batchEntityHandler {
+	return &batchEntityHandler{
+		permission: "edge-router-policy",
+		create: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, body []byte) (string, error) {
+			restEntity := &rest_model.EdgeRouterPolicyCreate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return "", err
+			}
+			entity := MapCreateEdgeRouterPolicyToModel(restEntity)
+			return batchCreate(ae.Managers.EdgeRouterPolicy.Create, entity, ctx)
+		},
+		update: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte) error {
+			restEntity := &rest_model.EdgeRouterPolicyUpdate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.EdgeRouterPolicy.Update(MapUpdateEdgeRouterPolicyToModel(id, restEntity), nil, ctx)
+		},
+		patch: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte, fields fields.UpdatedFields) error {
+			restEntity := &rest_model.EdgeRouterPolicyPatch{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.EdgeRouterPolicy.Update(MapPatchEdgeRouterPolicyToModel(id, restEntity), fields.FilterMaps("tags"), ctx)
+		},
+		delete: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string) error {
+			return ae.Managers.EdgeRouterPolicy.Delete(id, ctx)
+		},
+	}
+}
+
+func serviceEdgeRouterPolicyBatchHandler() *batchEntityHandler {
+	return &batchEntityHandler{
+		permission: "service-edge-router-policy",
+		create: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, body []byte) (string, error) {
+			restEntity := &rest_model.ServiceEdgeRouterPolicyCreate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return "", err
+			}
+			entity := MapCreateServiceEdgeRouterPolicyToModel(restEntity)
+			return batchCreate(ae.Managers.ServiceEdgeRouterPolicy.Create, entity, ctx)
+		},
+		update: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte) error {
+			restEntity := &rest_model.ServiceEdgeRouterPolicyUpdate{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.ServiceEdgeRouterPolicy.Update(MapUpdateServiceEdgeRouterPolicyToModel(id, restEntity), nil, ctx)
+		},
+		patch: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string, body []byte, fields fields.UpdatedFields) error {
+			restEntity := &rest_model.ServiceEdgeRouterPolicyPatch{}
+			if err := decodeBatchBody(body, restEntity); err != nil {
+				return err
+			}
+			return ae.Managers.ServiceEdgeRouterPolicy.Update(MapPatchServiceEdgeRouterPolicyToModel(id, restEntity), fields.FilterMaps("tags"), ctx)
+		},
+		delete: func(ae *env.AppEnv, rc *response.RequestContext, ctx *change.Context, id string) error {
+			return ae.Managers.ServiceEdgeRouterPolicy.Delete(id, ctx)
+		},
+	}
+}
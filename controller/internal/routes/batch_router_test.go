@@ -0,0 +1,74 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package routes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveBatchRefs(t *testing.T) {
+	refs := map[string]string{
+		"svc":  "abc123",
+		"host": "def456",
+	}
+
+	t.Run("ids are resolved", func(t *testing.T) {
+		req := require.New(t)
+		id, err := resolveBatchRefs("${svc}", refs)
+		req.NoError(err)
+		req.Equal("abc123", id)
+
+		id, err = resolveBatchRefs("plain-id", refs)
+		req.NoError(err)
+		req.Equal("plain-id", id)
+	})
+
+	t.Run("unknown refs are rejected", func(t *testing.T) {
+		req := require.New(t)
+		_, err := resolveBatchRefs("${missing}", refs)
+		req.Error(err)
+	})
+
+	t.Run("refs are resolved in nested body strings", func(t *testing.T) {
+		req := require.New(t)
+		body := json.RawMessage(`{"name":"dial","serviceRoles":["@${svc}"],"identityRoles":["@${host}","#all"],"semantic":"AnyOf","tags":{"src":"${svc}"},"count":1}`)
+		resolved, err := resolveBatchBodyRefs(body, refs)
+		req.NoError(err)
+
+		result := map[string]any{}
+		req.NoError(json.Unmarshal(resolved, &result))
+		req.Equal([]any{"@abc123"}, result["serviceRoles"])
+		req.Equal([]any{"@def456", "#all"}, result["identityRoles"])
+		req.Equal(map[string]any{"src": "abc123"}, result["tags"])
+		req.Equal(float64(1), result["count"])
+	})
+
+	t.Run("bodies without refs are passed through", func(t *testing.T) {
+		req := require.New(t)
+		body := json.RawMessage(`{"name":"test"}`)
+		resolved, err := resolveBatchBodyRefs(body, refs)
+		req.NoError(err)
+		req.Equal(string(body), string(resolved))
+
+		resolved, err = resolveBatchBodyRefs(nil, refs)
+		req.NoError(err)
+		req.Equal("{}", string(resolved))
+	})
+}
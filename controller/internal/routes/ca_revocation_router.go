@@ -0,0 +1,178 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/openziti/edge-api/rest_model"
+	"github.com/openziti/foundation/v2/errorz"
+	"github.com/openziti/ziti/v2/controller/apierror"
+	"github.com/openziti/ziti/v2/controller/env"
+	"github.com/openziti/ziti/v2/controller/model"
+	"github.com/openziti/ziti/v2/controller/permissions"
+	"github.com/openziti/ziti/v2/controller/response"
+	"github.com/openziti/ziti/v2/controller/webapis"
+)
+
+func init() {
+	r := NewCaRevocationRouter()
+	env.AddRouter(r)
+}
+
+// CaRevocationCheckDetail is the revocation check configuration of a third party CA
+type CaRevocationCheckDetail struct {
+	CaId                      string   `json:"caId"`
+	CrlEnabled                bool     `json:"crlEnabled"`
+	CrlUrls                   []string `json:"crlUrls"`
+	CrlRefreshIntervalSeconds int64    `json:"crlRefreshIntervalSeconds"`
+	OcspEnabled               bool     `json:"ocspEnabled"`
+	OcspUrl                   string   `json:"ocspUrl"`
+	FailMode                  string   `json:"failMode"`
+}
+
+// CaRevocationRouter manages the revocation check configuration of third party CAs. The CA is given by the caId
+// query parameter for GET and DELETE, and in the body for PUT.
+type CaRevocationRouter struct {
+	BasePath string
+}
+
+func NewCaRevocationRouter() *CaRevocationRouter {
+	return &CaRevocationRouter{
+		BasePath: "/cas/revocation-check",
+	}
+}
+
+func (r *CaRevocationRouter) Register(ae *env.AppEnv) {
+	ae.AddManagementApiHandler(webapis.ManagementRestApiBaseUrlLatest+r.BasePath, r.RevocationCheck)
+}
+
+func (r *CaRevocationRouter) RevocationCheck(ae *env.AppEnv, rc *response.RequestContext) {
+	switch rc.Request.Method {
+	case http.MethodGet:
+		if r.isAllowed(rc, permissions.Read) {
+			r.detail(ae, rc)
+		}
+	case http.MethodPut:
+		if r.isAllowed(rc, permissions.Update) {
+			r.update(ae, rc)
+		}
+	case http.MethodDelete:
+		if r.isAllowed(rc, permissions.Update) {
+			r.delete(ae, rc)
+		}
+	default:
+		rc.RespondWithApiError(apierror.NewMethodNotAllowed())
+	}
+}
+
+func (r *CaRevocationRouter) isAllowed(rc *response.RequestContext, action permissions.Action) bool {
+	rc.InitPermissionsContext(permissions.Management, "ca", action)
+	if permissions.DefaultManagementAccess().IsAllowed(rc) {
+		return true
+	}
+
+	if securityErr := rc.SecurityCtx.GetError(); securityErr != nil {
+		rc.RespondWithError(securityErr)
+	} else {
+		rc.RespondWithApiError(errorz.NewUnauthorized())
+	}
+	return false
+}
+
+func (r *CaRevocationRouter) detail(ae *env.AppEnv, rc *response.RequestContext) {
+	caId := rc.Request.URL.Query().Get("caId")
+	if caId == "" {
+		rc.RespondWithFieldError(errorz.NewFieldError("caId is required", "caId", caId))
+		return
+	}
+
+	ca, err := ae.Managers.Ca.Read(caId)
+	if err != nil {
+		rc.RespondWithError(err)
+		return
+	}
+
+	result := &CaRevocationCheckDetail{
+		CaId:    ca.Id,
+		CrlUrls: []string{},
+	}
+
+	if check := ca.RevocationCheck; check != nil {
+		result.CrlEnabled = check.CrlEnabled
+		result.CrlRefreshIntervalSeconds = int64(check.CrlRefreshInterval / time.Second)
+		result.OcspEnabled = check.OcspEnabled
+		result.OcspUrl = check.OcspUrl
+		result.FailMode = check.FailMode
+		if check.CrlUrls != nil {
+			result.CrlUrls = check.CrlUrls
+		}
+	}
+
+	rc.RespondWithOk(result, &rest_model.Meta{})
+}
+
+func (r *CaRevocationRouter) update(ae *env.AppEnv, rc *response.RequestContext) {
+	request := &CaRevocationCheckDetail{}
+	if err := json.Unmarshal(rc.Body, request); err != nil {
+		rc.RespondWithApiError(apierror.NewCouldNotParseBody(err))
+		return
+	}
+
+	if request.CaId == "" {
+		rc.RespondWithFieldError(errorz.NewFieldError("caId is required", "caId", request.CaId))
+		return
+	}
+
+	if request.CrlRefreshIntervalSeconds < 0 {
+		rc.RespondWithFieldError(errorz.NewFieldError("crlRefreshIntervalSeconds must not be negative", "crlRefreshIntervalSeconds", request.CrlRefreshIntervalSeconds))
+		return
+	}
+
+	check := &model.CaRevocationCheck{
+		CrlEnabled:         request.CrlEnabled,
+		CrlUrls:            request.CrlUrls,
+		CrlRefreshInterval: time.Duration(request.CrlRefreshIntervalSeconds) * time.Second,
+		OcspEnabled:        request.OcspEnabled,
+		OcspUrl:            request.OcspUrl,
+		FailMode:           request.FailMode,
+	}
+
+	if err := ae.Managers.Ca.UpdateRevocationCheck(request.CaId, check, rc.NewChangeContext()); err != nil {
+		rc.RespondWithError(err)
+		return
+	}
+
+	rc.RespondWithEmptyOk()
+}
+
+func (r *CaRevocationRouter) delete(ae *env.AppEnv, rc *response.RequestContext) {
+	caId := rc.Request.URL.Query().Get("caId")
+	if caId == "" {
+		rc.RespondWithFieldError(errorz.NewFieldError("caId is required", "caId", caId))
+		return
+	}
+
+	if err := ae.Managers.Ca.UpdateRevocationCheck(caId, nil, rc.NewChangeContext()); err != nil {
+		rc.RespondWithError(err)
+		return
+	}
+
+	rc.RespondWithEmptyOk()
+}
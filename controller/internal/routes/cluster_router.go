@@ -19,9 +19,13 @@ package routes
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	edgeRestModel "github.com/openziti/edge-api/rest_model"
 	"github.com/openziti/foundation/v2/errorz"
+	"github.com/openziti/foundation/v2/util"
 	"github.com/openziti/ziti/v2/common/pb/cmd_pb"
 	"github.com/openziti/ziti/v2/controller/apierror"
 	"github.com/openziti/ziti/v2/controller/env"
@@ -33,6 +37,9 @@ import (
 	"github.com/openziti/ziti/v2/controller/rest_server/operations/cluster"
 )
 
+// clusterStatusTimeout bounds how long the status request waits for peer controllers to report their state
+const clusterStatusTimeout = 5 * time.Second
+
 func init() {
 	r := NewClusterRouter()
 	env.AddRouter(r)
@@ -67,6 +74,10 @@ func (r *ClusterRouter) Register(ae *env.AppEnv) {
 			r.transferLeadership(ae, rc, params)
 		}, params.HTTPRequest, "", "", permissions.IsAdmin())
 	})
+
+	ae.FabricApi.ClusterClusterStatusHandler = cluster.ClusterStatusHandlerFunc(func(params cluster.ClusterStatusParams, _ any) middleware.Responder {
+		return ae.IsAllowed(r.status, params.HTTPRequest, "", "", permissions.IsAdmin())
+	})
 }
 
 func (r *ClusterRouter) getClusterController(ae *env.AppEnv) *raft.Controller {
@@ -113,6 +124,77 @@ func (r *ClusterRouter) listMembers(ae *env.AppEnv, rc *response.RequestContext)
 	}
 }
 
+// status reports per-member replication state and the cluster's quorum margin
+func (r *ClusterRouter) status(ae *env.AppEnv, rc *response.RequestContext) {
+	ClusterController := r.getClusterController(ae)
+	if ClusterController == nil {
+		rc.RespondWithApiError(apierror.NewNotRunningInHAModeError())
+		return
+	}
+
+	status, err := ClusterController.GetClusterStatus(clusterStatusTimeout)
+	if err != nil {
+		rc.RespondWithError(err)
+		return
+	}
+
+	rc.RespondWithOk(mapClusterStatusToRestModel(status), &edgeRestModel.Meta{})
+}
+
+func mapClusterStatusToRestModel(status *raft.ClusterStatus) *rest_model.ClusterStatus {
+	result := &rest_model.ClusterStatus{
+		ClusterID:   &status.ClusterId,
+		LeaderID:    &status.LeaderId,
+		ReadOnly:    &status.ReadOnly,
+		VersionSkew: &status.VersionSkew,
+		Members:     []*rest_model.ClusterMemberStatus{},
+	}
+
+	if quorum := status.Quorum; quorum != nil {
+		result.Quorum = &rest_model.ClusterQuorum{
+			Voters:            util.Ptr(int64(quorum.Voters)),
+			HealthyVoters:     util.Ptr(int64(quorum.HealthyVoters)),
+			Quorum:            util.Ptr(int64(quorum.Quorum)),
+			FailuresTolerable: util.Ptr(int64(quorum.FailuresTolerable)),
+			HasQuorum:         &quorum.HasQuorum,
+		}
+	}
+
+	for _, member := range status.Members {
+		memberStatus := &rest_model.ClusterMemberStatus{
+			ID:              &member.Id,
+			Address:         &member.Addr,
+			Voter:           &member.Voter,
+			Leader:          &member.Leader,
+			Version:         &member.Version,
+			Connected:       &member.Connected,
+			PreferredLeader: member.PreferredLeader,
+			Lag:             util.Ptr(int64(member.Lag)),
+			Error:           member.Error,
+		}
+
+		if raftStatus := member.Raft; raftStatus != nil {
+			memberStatus.Raft = &rest_model.ClusterMemberRaftStatus{
+				State:             &raftStatus.State,
+				AppliedIndex:      util.Ptr(int64(raftStatus.AppliedIndex)),
+				CommitIndex:       util.Ptr(int64(raftStatus.CommitIndex)),
+				LastLogIndex:      util.Ptr(int64(raftStatus.LastLogIndex)),
+				LastSnapshotIndex: util.Ptr(int64(raftStatus.LastSnapshotIndex)),
+			}
+			if raftStatus.LastSnapshotTime != nil {
+				memberStatus.Raft.LastSnapshotTime = strfmt.DateTime(*raftStatus.LastSnapshotTime)
+			}
+			if raftStatus.LastContact != nil {
+				memberStatus.Raft.LastContact = strfmt.DateTime(*raftStatus.LastContact)
+			}
+		}
+
+		result.Members = append(result.Members, memberStatus)
+	}
+
+	return result
+}
+
 func (r *ClusterRouter) addMember(ae *env.AppEnv, rc *response.RequestContext, params cluster.ClusterMemberAddParams) {
 	ClusterController := r.getClusterController(ae)
 	if ClusterController != nil {
@@ -113,7 +113,7 @@ func (r *ConfigRouter) Update(ae *env.AppEnv, rc *response.RequestContext, param
 		return
 	}
 
-	Update(rc, func(id string) error {
+	Update(ae, rc, func(id string) error {
 		model, err := MapUpdateConfigToModel(params.ID, params.Config)
 
 		if err != nil {
@@ -125,7 +125,7 @@ func (r *ConfigRouter) Update(ae *env.AppEnv, rc *response.RequestContext, param
 }
 
 func (r *ConfigRouter) Patch(ae *env.AppEnv, rc *response.RequestContext, params config.PatchConfigParams) {
-	Patch(rc, func(id string, fields fields.UpdatedFields) error {
+	Patch(ae, rc, func(id string, fields fields.UpdatedFields) error {
 		model, err := MapPatchConfigToModel(params.ID, params.Config)
 
 		if err != nil {
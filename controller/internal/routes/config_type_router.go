@@ -114,13 +114,13 @@ func (r *ConfigTypeRouter) Update(ae *env.AppEnv, rc *response.RequestContext, p
 		}
 	}
 
-	Update(rc, func(id string) error {
+	Update(ae, rc, func(id string) error {
 		return ae.Managers.ConfigType.Update(MapUpdateConfigTypeToModel(params.ID, params.ConfigType), nil, rc.NewChangeContext())
 	})
 }
 
 func (r *ConfigTypeRouter) Patch(ae *env.AppEnv, rc *response.RequestContext, params config.PatchConfigTypeParams) {
-	Patch(rc, func(id string, fields fields.UpdatedFields) error {
+	Patch(ae, rc, func(id string, fields fields.UpdatedFields) error {
 		if fields.IsUpdated(db.FieldConfigTypeSchema) && params.ConfigType.Schema != nil {
 			if _, ok := params.ConfigType.Schema.(map[string]interface{}); !ok {
 				return errorz.NewFieldApiError(errorz.NewFieldError("if present, schema must have object type", db.FieldConfigTypeSchema, params.ConfigType.Schema))
@@ -171,7 +171,7 @@ func (r *CurrentIdentityAuthenticatorRouter) Update(ae *env.AppEnv, rc *response
 		return
 	}
 
-	Update(rc, func(id string) error {
+	Update(ae, rc, func(id string) error {
 		return ae.Managers.Authenticator.UpdateSelf(MapUpdateAuthenticatorWithCurrentToModel(id, identity.Id, authenticator), rc.NewChangeContext())
 	})
 }
@@ -184,7 +184,7 @@ func (r *CurrentIdentityAuthenticatorRouter) Patch(ae *env.AppEnv, rc *response.
 		return
 	}
 
-	Patch(rc, func(id string, fields fields.UpdatedFields) error {
+	Patch(ae, rc, func(id string, fields fields.UpdatedFields) error {
 		return ae.Managers.Authenticator.PatchSelf(MapPatchAuthenticatorWithCurrentToModel(id, identity.Id, authenticator), fields.FilterMaps("tags"), rc.NewChangeContext())
 	})
 }
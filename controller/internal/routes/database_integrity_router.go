@@ -0,0 +1,212 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/edge-api/rest_model"
+	"github.com/openziti/foundation/v2/errorz"
+	"github.com/openziti/ziti/v2/controller/apierror"
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/env"
+	"github.com/openziti/ziti/v2/controller/permissions"
+	"github.com/openziti/ziti/v2/controller/response"
+	"github.com/openziti/ziti/v2/controller/webapis"
+)
+
+// MaxIntegrityReportIssues limits the number of issues kept in an integrity report
+const MaxIntegrityReportIssues = 10000
+
+func init() {
+	r := NewDatabaseIntegrityRouter()
+	env.AddRouter(r)
+}
+
+// IntegrityReport is the state of the most recent extended integrity check, and of the most recent fix request
+type IntegrityReport struct {
+	InProgress    bool                     `json:"inProgress"`
+	Fixing        bool                     `json:"fixing"`
+	StartTime     *time.Time               `json:"startTime,omitempty"`
+	EndTime       *time.Time               `json:"endTime,omitempty"`
+	Error         string                   `json:"error,omitempty"`
+	TooManyIssues bool                     `json:"tooManyIssues"`
+	Issues        []*db.IntegrityIssue     `json:"issues"`
+	FixResults    []*db.IntegrityFixResult `json:"fixResults,omitempty"`
+}
+
+// IntegrityFixRequest lists the integrity issues to fix
+type IntegrityFixRequest struct {
+	IssueIds []string `json:"issueIds"`
+}
+
+// DatabaseIntegrityRouter runs the extended database integrity checks in the background and allows the fix actions
+// of selected issues to be applied. Checks and fixes are restricted to admins. Checks only look at the local
+// database. Fixes are applied through a replicated command, so in an HA cluster every controller applies them.
+type DatabaseIntegrityRouter struct {
+	BasePath string
+	running  atomic.Bool
+	lock     sync.Mutex
+	report   IntegrityReport
+}
+
+func NewDatabaseIntegrityRouter() *DatabaseIntegrityRouter {
+	return &DatabaseIntegrityRouter{
+		BasePath: "/database/integrity-report",
+		report: IntegrityReport{
+			Issues: []*db.IntegrityIssue{},
+		},
+	}
+}
+
+func (r *DatabaseIntegrityRouter) Register(ae *env.AppEnv) {
+	ae.AddManagementApiHandler(webapis.ManagementRestApiBaseUrlLatest+r.BasePath, r.Report)
+	ae.AddManagementApiHandler(webapis.ManagementRestApiBaseUrlLatest+r.BasePath+"/fix", r.Fix)
+}
+
+func (r *DatabaseIntegrityRouter) isAdmin(rc *response.RequestContext) bool {
+	if permissions.IsAdmin().IsAllowed(rc) {
+		return true
+	}
+	if securityErr := rc.SecurityCtx.GetError(); securityErr != nil {
+		rc.RespondWithError(securityErr)
+	} else {
+		rc.RespondWithApiError(errorz.NewUnauthorized())
+	}
+	return false
+}
+
+// Report returns the current report on GET and starts a new check on POST
+func (r *DatabaseIntegrityRouter) Report(ae *env.AppEnv, rc *response.RequestContext) {
+	if !r.isAdmin(rc) {
+		return
+	}
+
+	switch rc.Request.Method {
+	case http.MethodGet:
+		r.lock.Lock()
+		report := r.report
+		r.lock.Unlock()
+		rc.RespondWithOk(report, &rest_model.Meta{})
+	case http.MethodPost:
+		if !r.running.CompareAndSwap(false, true) {
+			rc.RespondWithApiError(apierror.NewRateLimited())
+			return
+		}
+		r.start(false)
+		go r.run(ae, nil)
+		rc.Respond(&rest_model.Empty{Data: map[string]interface{}{}, Meta: &rest_model.Meta{}}, http.StatusAccepted)
+	default:
+		rc.RespondWithApiError(apierror.NewMethodNotAllowed())
+	}
+}
+
+// Fix applies the fix actions of the given issues, then re-runs the checks
+func (r *DatabaseIntegrityRouter) Fix(ae *env.AppEnv, rc *response.RequestContext) {
+	if !r.isAdmin(rc) {
+		return
+	}
+
+	if rc.Request.Method != http.MethodPost {
+		rc.RespondWithApiError(apierror.NewMethodNotAllowed())
+		return
+	}
+
+	request := &IntegrityFixRequest{}
+	if err := json.Unmarshal(rc.Body, request); err != nil {
+		rc.RespondWithApiError(apierror.NewCouldNotParseBody(err))
+		return
+	}
+
+	if len(request.IssueIds) == 0 {
+		rc.RespondWithFieldError(errorz.NewFieldError("at least one issue id is required", "issueIds", nil))
+		return
+	}
+
+	if !r.running.CompareAndSwap(false, true) {
+		rc.RespondWithApiError(apierror.NewRateLimited())
+		return
+	}
+
+	changeCtx := rc.NewChangeContext()
+	r.start(true)
+	go r.run(ae, func() ([]*db.IntegrityFixResult, error) {
+		return ae.Managers.Command.FixIntegrityIssues(request.IssueIds, changeCtx)
+	})
+	rc.Respond(&rest_model.Empty{Data: map[string]interface{}{}, Meta: &rest_model.Meta{}}, http.StatusAccepted)
+}
+
+func (r *DatabaseIntegrityRouter) start(fixing bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	r.report = IntegrityReport{
+		InProgress: true,
+		Fixing:     fixing,
+		StartTime:  &now,
+		Issues:     []*db.IntegrityIssue{},
+	}
+}
+
+func (r *DatabaseIntegrityRouter) run(ae *env.AppEnv, fix func() ([]*db.IntegrityFixResult, error)) {
+	var fixResults []*db.IntegrityFixResult
+	var issues []*db.IntegrityIssue
+	var tooManyIssues bool
+	var err error
+
+	defer func() {
+		r.lock.Lock()
+		now := time.Now()
+		r.report.InProgress = false
+		r.report.EndTime = &now
+		r.report.FixResults = fixResults
+		if issues != nil {
+			r.report.Issues = issues
+		}
+		r.report.TooManyIssues = tooManyIssues
+		if err != nil {
+			r.report.Error = err.Error()
+		}
+		r.lock.Unlock()
+		r.running.Store(false)
+	}()
+
+	if fix != nil {
+		if fixResults, err = fix(); err != nil {
+			pfxlog.Logger().WithError(err).Error("failed to fix data integrity issues")
+			return
+		}
+	}
+
+	ctx := change.New().SetSourceType("integrity.check").SetChangeAuthorType(change.AuthorTypeController).GetContext()
+	issues, tooManyIssues, err = ae.GetStores().CheckExtendedIntegrity(ae.GetDb(), ctx, MaxIntegrityReportIssues)
+	if err != nil {
+		pfxlog.Logger().WithError(err).Error("extended data integrity check failed")
+		return
+	}
+
+	for _, issue := range issues {
+		pfxlog.Logger().WithField("check", issue.Check).WithField("issueId", issue.Id).Warnf("data integrity issue: %s", issue.Description)
+	}
+}
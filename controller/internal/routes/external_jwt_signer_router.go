@@ -101,13 +101,13 @@ func (r *ExternalJwtSignerRouter) DeleteForManagement(ae *env.AppEnv, rc *respon
 }
 
 func (r *ExternalJwtSignerRouter) UpdateForManagement(ae *env.AppEnv, rc *response.RequestContext, params extJwtManagement.UpdateExternalJWTSignerParams) {
-	Update(rc, func(id string) error {
+	Update(ae, rc, func(id string) error {
 		return ae.Managers.ExternalJwtSigner.Update(MapUpdateExternalJwtSignerToModelForManagement(params.ID, params.ExternalJWTSigner), nil, rc.NewChangeContext())
 	})
 }
 
 func (r *ExternalJwtSignerRouter) PatchForManagement(ae *env.AppEnv, rc *response.RequestContext, params extJwtManagement.PatchExternalJWTSignerParams) {
-	Patch(rc, func(id string, patchFields fields.UpdatedFields) error {
+	Patch(ae, rc, func(id string, patchFields fields.UpdatedFields) error {
 
 		if patchFields.IsUpdated(db.FieldExternalJwtSignerCertPem) {
 			patchFields.AddField(db.FieldExternalJwtSignerCommonName)
@@ -96,13 +96,13 @@ func (r *FabricRouterRouter) Delete(ae *env.AppEnv, rc *response.RequestContext)
 }
 
 func (r *FabricRouterRouter) Update(ae *env.AppEnv, rc *response.RequestContext, params router.UpdateRouterParams) {
-	Update(rc, func(id string) error {
+	Update(ae, rc, func(id string) error {
 		return ae.Managers.Router.Update(MapUpdateFabricRouterToModel(params.ID, params.Router), nil, rc.NewChangeContext())
 	})
 }
 
 func (r *FabricRouterRouter) Patch(ae *env.AppEnv, rc *response.RequestContext, params router.PatchRouterParams) {
-	Patch(rc, func(id string, fields fields.UpdatedFields) error {
+	Patch(ae, rc, func(id string, fields fields.UpdatedFields) error {
 		return ae.Managers.Router.Update(MapPatchFabricRouterToModel(params.ID, params.Router), fields.FilterMaps("tags", db.FieldRouterCtrlChanListeners), rc.NewChangeContext())
 	})
 }
@@ -64,7 +64,7 @@ func (r *FabricServiceRouter) Register(ae *env.AppEnv) {
 
 	ae.FabricApi.ServicePatchServiceHandler = service.PatchServiceHandlerFunc(func(params service.PatchServiceParams, _ any) middleware.Responder {
 		return ae.IsAllowed(func(ae *env.AppEnv, rc *response.RequestContext) {
-			Patch(rc, func(id string, fields fields.UpdatedFields) error {
+			Patch(ae, rc, func(id string, fields fields.UpdatedFields) error {
 				return ae.Managers.Service.Update(
 					MapPatchFabricServiceToModel(params.ID, params.Service),
 					fields.FilterMaps("tags").MapField("maxIdleTimeMillis", "maxIdleTime"),
@@ -102,13 +102,13 @@ func (r *FabricServiceRouter) Delete(ae *env.AppEnv, rc *response.RequestContext
 }
 
 func (r *FabricServiceRouter) Update(ae *env.AppEnv, rc *response.RequestContext, params service.UpdateServiceParams) {
-	Update(rc, func(id string) error {
+	Update(ae, rc, func(id string) error {
 		return ae.Managers.Service.Update(MapUpdateFabricServiceToModel(params.ID, params.Service), nil, rc.NewChangeContext())
 	})
 }
 
 func (r *FabricServiceRouter) Patch(ae *env.AppEnv, rc *response.RequestContext, params service.PatchServiceParams) {
-	Patch(rc, func(id string, fields fields.UpdatedFields) error {
+	Patch(ae, rc, func(id string, fields fields.UpdatedFields) error {
 		return ae.Managers.Service.Update(MapPatchFabricServiceToModel(params.ID, params.Service), fields.FilterMaps("tags"), rc.NewChangeContext())
 	})
 }
@@ -235,14 +235,8 @@ func getIdentityTypeId(ae *env.AppEnv, identityType rest_model.IdentityType) str
 
 func (r *IdentityRouter) Create(ae *env.AppEnv, rc *response.RequestContext, params identity.CreateIdentityParams) {
 	Create(rc, rc, IdentityLinkFactory, func() (string, error) {
-		if !rc.HasPermission(permissions.AdminPermission) {
-			if len(ValueOrDefault(params.Identity.Permissions)) > 0 {
-				return "", nonAdminNotAllowedError(errors.New("non-admins may not create users with permissions"))
-			}
-
-			if ValueOrDefault(params.Identity.IsAdmin) {
-				return "", nonAdminNotAllowedError(errors.New("non-admins may not create admin users"))
-			}
+		if err := checkIdentityCreateAllowed(rc, params.Identity); err != nil {
+			return "", err
 		}
 
 		identityModel, enrollments := MapCreateIdentityToModel(params.Identity)
@@ -265,12 +259,9 @@ func (r *IdentityRouter) Delete(ae *env.AppEnv, rc *response.RequestContext) {
 			return
 		}
 
-		if entity, _ := ae.Managers.Identity.Read(id); entity != nil {
-			if entity.IsAdmin {
-				unauthorizedErr := nonAdminNotAllowedError(errors.New("non-admin may not delete admin identities"))
-				rc.RespondWithError(unauthorizedErr)
-				return
-			}
+		if err = checkIdentityDeleteAllowed(ae, rc, id); err != nil {
+			rc.RespondWithError(err)
+			return
 		}
 	}
 
@@ -278,9 +269,9 @@ func (r *IdentityRouter) Delete(ae *env.AppEnv, rc *response.RequestContext) {
 }
 
 func (r *IdentityRouter) Update(ae *env.AppEnv, rc *response.RequestContext, params identity.UpdateIdentityParams) {
-	Update(rc, func(id string) error {
-		if !rc.HasPermission(permissions.AdminPermission) {
-			return nonAdminNotAllowedError(errors.New("non-admins may not use the PUT method, use PATCH instead"))
+	Update(ae, rc, func(id string) error {
+		if err := checkIdentityUpdateAllowed(rc); err != nil {
+			return err
 		}
 
 		return ae.Managers.Identity.Update(MapUpdateIdentityToModel(params.ID, params.Identity, getIdentityTypeId(ae, *params.Identity.Type)), nil, rc.NewChangeContext())
@@ -288,26 +279,75 @@ func (r *IdentityRouter) Update(ae *env.AppEnv, rc *response.RequestContext, par
 }
 
 func (r *IdentityRouter) Patch(ae *env.AppEnv, rc *response.RequestContext, params identity.PatchIdentityParams) {
-	Patch(rc, func(id string, fields fields.UpdatedFields) error {
+	Patch(ae, rc, func(id string, fields fields.UpdatedFields) error {
 		fields = fields.FilterMaps(boltz.FieldTags, db.FieldIdentityAppData, db.FieldIdentityServiceHostingCosts, db.FieldIdentityServiceHostingPrecedences)
-		if !rc.HasPermission(permissions.AdminPermission) {
-			if entity, _ := ae.Managers.Identity.Read(id); entity != nil {
-				if entity.IsAdmin {
-					return nonAdminNotAllowedError(errors.New("non-admins may not modify admin identities"))
-				}
-			}
-
-			for _, field := range []string{db.FieldIdentityPermissions, db.FieldIdentityIsAdmin, db.FieldIdentityIsDefaultAdmin} {
-				if fields.IsUpdated(field) {
-					return nonAdminNotAllowedError(fmt.Errorf("non-admins may not modify the identity field '%s'", field))
-				}
-			}
+		if err := checkIdentityPatchAllowed(ae, rc, id, fields); err != nil {
+			return err
 		}
 
 		return ae.Managers.Identity.Update(MapPatchIdentityToModel(params.ID, params.Identity, getIdentityTypeId(ae, params.Identity.Type)), fields, rc.NewChangeContext())
 	})
 }
 
+// checkIdentityCreateAllowed prevents non-admins from creating admins or identities with permissions
+func checkIdentityCreateAllowed(rc *response.RequestContext, identity *rest_model.IdentityCreate) error {
+	if rc.HasPermission(permissions.AdminPermission) {
+		return nil
+	}
+
+	if len(ValueOrDefault(identity.Permissions)) > 0 {
+		return nonAdminNotAllowedError(errors.New("non-admins may not create users with permissions"))
+	}
+
+	if ValueOrDefault(identity.IsAdmin) {
+		return nonAdminNotAllowedError(errors.New("non-admins may not create admin users"))
+	}
+
+	return nil
+}
+
+func checkIdentityUpdateAllowed(rc *response.RequestContext) error {
+	if !rc.HasPermission(permissions.AdminPermission) {
+		return nonAdminNotAllowedError(errors.New("non-admins may not use the PUT method, use PATCH instead"))
+	}
+	return nil
+}
+
+// checkIdentityPatchAllowed prevents non-admins from modifying admins or changing identity permissions
+func checkIdentityPatchAllowed(ae *env.AppEnv, rc *response.RequestContext, id string, fields fields.UpdatedFields) error {
+	if rc.HasPermission(permissions.AdminPermission) {
+		return nil
+	}
+
+	if entity, _ := ae.Managers.Identity.Read(id); entity != nil {
+		if entity.IsAdmin {
+			return nonAdminNotAllowedError(errors.New("non-admins may not modify admin identities"))
+		}
+	}
+
+	for _, field := range []string{db.FieldIdentityPermissions, db.FieldIdentityIsAdmin, db.FieldIdentityIsDefaultAdmin} {
+		if fields.IsUpdated(field) {
+			return nonAdminNotAllowedError(fmt.Errorf("non-admins may not modify the identity field '%s'", field))
+		}
+	}
+
+	return nil
+}
+
+func checkIdentityDeleteAllowed(ae *env.AppEnv, rc *response.RequestContext, id string) error {
+	if rc.HasPermission(permissions.AdminPermission) {
+		return nil
+	}
+
+	if entity, _ := ae.Managers.Identity.Read(id); entity != nil {
+		if entity.IsAdmin {
+			return nonAdminNotAllowedError(errors.New("non-admin may not delete admin identities"))
+		}
+	}
+
+	return nil
+}
+
 func nonAdminNotAllowedError(cause error) error {
 	err := errorz.NewUnauthorized()
 	err.Cause = cause
@@ -388,7 +428,7 @@ func (r *IdentityRouter) listServiceConfigs(ae *env.AppEnv, rc *response.Request
 }
 
 func (r *IdentityRouter) assignServiceConfigs(ae *env.AppEnv, rc *response.RequestContext, params identity.AssociateIdentitysServiceConfigsParams) {
-	Update(rc, func(id string) error {
+	Update(ae, rc, func(id string) error {
 		var modelServiceConfigs []model.ServiceConfig
 		for _, serviceConfig := range params.ServiceConfigs {
 			modelServiceConfigs = append(modelServiceConfigs, MapServiceConfigToModel(*serviceConfig))
@@ -398,7 +438,7 @@ func (r *IdentityRouter) assignServiceConfigs(ae *env.AppEnv, rc *response.Reque
 }
 
 func (r *IdentityRouter) removeServiceConfigs(ae *env.AppEnv, rc *response.RequestContext, params identity.DisassociateIdentitysServiceConfigsParams) {
-	UpdateAllowEmptyBody(rc, func(id string) error {
+	UpdateAllowEmptyBody(ae, rc, func(id string) error {
 		var modelServiceConfigs []model.ServiceConfig
 		for _, serviceConfig := range params.ServiceConfigIDPairs {
 			modelServiceConfigs = append(modelServiceConfigs, MapServiceConfigToModel(*serviceConfig))
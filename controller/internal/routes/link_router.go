@@ -73,7 +73,7 @@ func (r *LinkRouter) Detail(ae *env.AppEnv, rc *response.RequestContext) {
 }
 
 func (r *LinkRouter) Patch(ae *env.AppEnv, rc *response.RequestContext, params link.PatchLinkParams) {
-	Patch(rc, func(id string, fields fields.UpdatedFields) error {
+	Patch(ae, rc, func(id string, fields fields.UpdatedFields) error {
 		n := ae.GetHostController().GetNetwork()
 		l, found := n.GetLink(id)
 		if !found {
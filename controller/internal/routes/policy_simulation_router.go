@@ -0,0 +1,96 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/openziti/edge-api/rest_model"
+	"github.com/openziti/foundation/v2/errorz"
+	"github.com/openziti/ziti/v2/controller/apierror"
+	"github.com/openziti/ziti/v2/controller/env"
+	"github.com/openziti/ziti/v2/controller/model"
+	"github.com/openziti/ziti/v2/controller/response"
+	"github.com/openziti/ziti/v2/controller/webapis"
+)
+
+func init() {
+	r := NewPolicySimulationRouter()
+	env.AddRouter(r)
+}
+
+// PolicySimulationRouter evaluates proposed changes without committing them. The request body has the same format
+// as a batch request. The operations are applied in a transaction which is rolled back, and the response reports the
+// service access, edge router access and service reachability which would be gained or lost.
+type PolicySimulationRouter struct {
+	BasePath    string
+	batchRouter *BatchRouter
+}
+
+func NewPolicySimulationRouter() *PolicySimulationRouter {
+	return &PolicySimulationRouter{
+		BasePath:    "/policy-advisor/simulate",
+		batchRouter: NewBatchRouter(),
+	}
+}
+
+func (r *PolicySimulationRouter) Register(ae *env.AppEnv) {
+	ae.AddManagementApiHandler(webapis.ManagementRestApiBaseUrlLatest+r.BasePath, r.Simulate)
+}
+
+type PolicySimulationResult struct {
+	Operations []*BatchOperationResult `json:"operations"`
+	*model.AdvisorSimulationResult
+}
+
+func (r *PolicySimulationRouter) Simulate(ae *env.AppEnv, rc *response.RequestContext) {
+	if rc.Request.Method != http.MethodPost {
+		rc.RespondWithApiError(apierror.NewMethodNotAllowed())
+		return
+	}
+
+	request := &BatchRequest{}
+	if err := json.Unmarshal(rc.Body, request); err != nil {
+		rc.RespondWithApiError(apierror.NewCouldNotParseBody(err))
+		return
+	}
+
+	if len(request.Operations) == 0 {
+		rc.RespondWithFieldError(errorz.NewFieldError("at least one operation is required", "operations", nil))
+		return
+	}
+
+	batch := ae.Managers.Command.NewBatch(rc.NewChangeContext())
+
+	results, commandOffsets, apiErr := r.batchRouter.record(ae, rc, batch, request.Operations)
+	if apiErr != nil {
+		rc.RespondWithApiError(apiErr)
+		return
+	}
+
+	simulation, err := ae.Managers.PolicyAdvisor.Simulate(batch)
+	if err != nil {
+		respondWithBatchError(rc, err, commandOffsets)
+		return
+	}
+
+	rc.RespondWithOk(&PolicySimulationResult{
+		Operations:              results,
+		AdvisorSimulationResult: simulation,
+	}, &rest_model.Meta{})
+}
@@ -0,0 +1,107 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package jwtsigner
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IsOpaqueKey returns true if the private key is only usable through crypto.Signer, such as keys held by a PKCS#11
+// token. JWT and JOSE libraries only sign with in-memory key types, so opaque keys need to be wrapped.
+func IsOpaqueKey(key crypto.PrivateKey) bool {
+	switch key.(type) {
+	case *ecdsa.PrivateKey, *rsa.PrivateKey, ed25519.PrivateKey:
+		return false
+	}
+	_, ok := key.(crypto.Signer)
+	return ok
+}
+
+// CryptoSignerMethod is a jwt.SigningMethod which signs through a crypto.Signer, producing the same signatures as
+// the wrapped ES* or RS* method. Verification is delegated to the wrapped method.
+type CryptoSignerMethod struct {
+	jwt.SigningMethod
+}
+
+// NewCryptoSignerMethod wraps the given signing method so that it signs with crypto.Signer keys
+func NewCryptoSignerMethod(sm jwt.SigningMethod) *CryptoSignerMethod {
+	return &CryptoSignerMethod{
+		SigningMethod: sm,
+	}
+}
+
+func (m *CryptoSignerMethod) Sign(signingString string, key interface{}) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, jwt.ErrInvalidKeyType
+	}
+	return SignPayload(signer, m.Alg(), []byte(signingString))
+}
+
+// SignPayload signs the payload with the given signer for the named JWS algorithm. ECDSA signatures are converted
+// from ASN.1 to the fixed size R || S encoding required by JWS.
+func SignPayload(signer crypto.Signer, alg string, payload []byte) ([]byte, error) {
+	var hash crypto.Hash
+	keySize := 0
+
+	switch sm := jwt.GetSigningMethod(alg).(type) {
+	case *jwt.SigningMethodECDSA:
+		hash = sm.Hash
+		keySize = sm.KeySize
+	case *jwt.SigningMethodRSA:
+		hash = sm.Hash
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm for crypto signer: %s", alg)
+	}
+
+	if !hash.Available() {
+		return nil, jwt.ErrHashUnavailable
+	}
+
+	hasher := hash.New()
+	hasher.Write(payload)
+
+	sig, err := signer.Sign(rand.Reader, hasher.Sum(nil), hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if keySize == 0 {
+		return sig, nil
+	}
+
+	var ecSig struct {
+		R, S *big.Int
+	}
+	if _, err = asn1.Unmarshal(sig, &ecSig); err != nil {
+		return nil, fmt.Errorf("unable to parse ECDSA signature (%w)", err)
+	}
+
+	out := make([]byte, 2*keySize)
+	ecSig.R.FillBytes(out[:keySize])
+	ecSig.S.FillBytes(out[keySize:])
+	return out, nil
+}
@@ -0,0 +1,89 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package jwtsigner
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// opaqueSigner hides the concrete key type, the same as a PKCS#11 backed key does
+type opaqueSigner struct {
+	signer crypto.Signer
+}
+
+func (s *opaqueSigner) Public() crypto.PublicKey {
+	return s.signer.Public()
+}
+
+func (s *opaqueSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.signer.Sign(rand, digest, opts)
+}
+
+func TestCryptoSignerMethod(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		key    crypto.Signer
+	}{
+		{name: "ES384", method: jwt.SigningMethodES384, key: ecKey},
+		{name: "RS256", method: jwt.SigningMethodRS256, key: rsaKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name+" tokens signed with an opaque key verify with the public key", func(t *testing.T) {
+			req := require.New(t)
+			key := &opaqueSigner{signer: test.key}
+			req.True(IsOpaqueKey(key))
+			req.False(IsOpaqueKey(test.key))
+
+			signer := New(test.method, key, "kid")
+			req.IsType(&CryptoSignerMethod{}, signer.SigningMethod())
+			req.Equal(test.method.Alg(), signer.SigningMethod().Alg())
+
+			token, err := signer.Generate(jwt.MapClaims{"sub": "test"})
+			req.NoError(err)
+
+			parsed, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) {
+				return test.key.Public(), nil
+			}, jwt.WithValidMethods([]string{test.method.Alg()}))
+			req.NoError(err)
+			req.True(parsed.Valid)
+			req.Equal("kid", parsed.Header["kid"])
+		})
+	}
+
+	t.Run("in-memory keys use the standard signing method", func(t *testing.T) {
+		req := require.New(t)
+		signer := New(jwt.SigningMethodES384, ecKey, "")
+		req.Equal(jwt.SigningMethodES384, signer.SigningMethod())
+	})
+}
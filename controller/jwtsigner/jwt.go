@@ -47,7 +47,12 @@ type SignerImpl struct {
 
 // New creates a new SignerImpl with the specified signing method, private key, and key ID.
 // The key ID is used for JWT key identification in multi-key scenarios.
+// Opaque keys, such as those held by a PKCS#11 token, sign through crypto.Signer.
 func New(sm jwt.SigningMethod, key crypto.PrivateKey, keyId string) *SignerImpl {
+	if IsOpaqueKey(key) {
+		sm = NewCryptoSignerMethod(sm)
+	}
+
 	return &SignerImpl{
 		signingMethod: sm,
 		key:           key,
@@ -82,10 +87,15 @@ func (j *SignerImpl) Generate(claims jwt.Claims) (string, error) {
 // TlsJwtSigner combines a JWT signer with its associated TLS certificate.
 // It wraps a jwtsigner.Signer and stores the certificate that was used to
 // create the signer, enabling JWT signing operations with certificate-based
-// key identification (kid).
+// key identification (kid). When signing key rotation is enabled, the signer
+// may instead use a rotated key, set with SetKey, while TlsCerts still holds
+// the controller's server certificate.
 type TlsJwtSigner struct {
 	Signer
 	TlsCerts *tls.Certificate
+
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
 }
 
 // Set configures the TlsJwtSigner with a new TLS certificate.
@@ -101,19 +111,44 @@ func (c *TlsJwtSigner) Set(cert *tls.Certificate) error {
 	}
 	kid := fmt.Sprintf("%x", sha1.Sum(cert.Certificate[0]))
 	c.Signer = New(signingMethod, c.TlsCerts.PrivateKey, kid)
+	c.privateKey = c.TlsCerts.PrivateKey
+	c.publicKey = cert.Leaf.PublicKey
+
+	return nil
+}
+
+// SetKey configures the TlsJwtSigner to sign with the given key instead of the key of
+// the TLS certificate. TlsCerts is left unchanged.
+func (c *TlsJwtSigner) SetKey(key crypto.Signer, kid string) error {
+	signingMethod, err := GetJwtSigningMethodForKey(key.Public())
+
+	if err != nil {
+		return err
+	}
+
+	c.Signer = New(signingMethod, key, kid)
+	c.privateKey = key
+	c.publicKey = key.Public()
 
 	return nil
 }
 
+// PrivateKey returns the private key tokens are currently signed with.
+func (c *TlsJwtSigner) PrivateKey() crypto.PrivateKey {
+	return c.privateKey
+}
+
+// PublicKey returns the public key tokens signed by this signer are verified with.
+func (c *TlsJwtSigner) PublicKey() crypto.PublicKey {
+	return c.publicKey
+}
+
 // GetJwtSigningMethod determines the appropriate JWT signing method based on the
 // certificate's public key type and parameters.
 // For ECDSA keys, it selects ES256, ES384, or ES512 based on the curve bit size.
 // For RSA keys, it defaults to RS256.
-// Panics if the certificate has an unsupported key type or ECDSA curve size.
+// Returns an error if the certificate has an unsupported key type or ECDSA curve size.
 func GetJwtSigningMethod(cert *tls.Certificate) (jwt.SigningMethod, error) {
-
-	var sm jwt.SigningMethod
-
 	if cert.Leaf == nil {
 		if len(cert.Certificate) == 0 {
 			return nil, fmt.Errorf("no certificates found")
@@ -126,9 +161,15 @@ func GetJwtSigningMethod(cert *tls.Certificate) (jwt.SigningMethod, error) {
 		}
 	}
 
-	switch cert.Leaf.PublicKey.(type) {
+	return GetJwtSigningMethodForKey(cert.Leaf.PublicKey)
+}
+
+// GetJwtSigningMethodForKey determines the appropriate JWT signing method for the given public key.
+func GetJwtSigningMethodForKey(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	var sm jwt.SigningMethod
+
+	switch key := publicKey.(type) {
 	case *ecdsa.PublicKey:
-		key := cert.Leaf.PublicKey.(*ecdsa.PublicKey)
 		switch key.Params().BitSize {
 		case jwt.SigningMethodES256.CurveBits:
 			sm = jwt.SigningMethodES256
@@ -0,0 +1,84 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package jwtsigner
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTlsJwtSigner_SetKey(t *testing.T) {
+	req := require.New(t)
+
+	certKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	req.NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ctrl"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, certKey.Public(), certKey)
+	req.NoError(err)
+
+	signer := &TlsJwtSigner{}
+	req.NoError(signer.Set(&tls.Certificate{Certificate: [][]byte{der}, PrivateKey: certKey}))
+	req.Equal(jwt.SigningMethodRS256, signer.SigningMethod())
+	req.Equal(certKey.Public(), signer.PublicKey())
+
+	rotatedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	req.NoError(err)
+
+	t.Run("tokens are signed with the rotated key", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(signer.SetKey(rotatedKey, "rotated"))
+		req.Equal(jwt.SigningMethodES256, signer.SigningMethod())
+		req.Equal("rotated", signer.KeyId())
+		req.Equal(rotatedKey, signer.PrivateKey())
+		req.Equal(der, signer.TlsCerts.Certificate[0])
+
+		token, err := signer.Generate(jwt.MapClaims{"sub": "test"})
+		req.NoError(err)
+
+		parsed, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) {
+			return rotatedKey.Public(), nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}))
+		req.NoError(err)
+		req.Equal("rotated", parsed.Header["kid"])
+	})
+
+	t.Run("unsupported keys leave the signer unchanged", func(t *testing.T) {
+		req := require.New(t)
+		p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+		req.NoError(err)
+
+		req.Error(signer.SetKey(p224Key, "p224"))
+		req.Equal("rotated", signer.KeyId())
+	})
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/openziti/ziti/v2/controller/db"
 	"github.com/openziti/ziti/v2/controller/fields"
 	"github.com/openziti/ziti/v2/controller/models"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -136,44 +137,7 @@ func (module *AuthModuleCert) Process(context AuthContext) (AuthResult, error) {
 
 	clientCert := certs[0]
 
-	trustCache := module.env.GetManagers().Ca.GetTrustCache()
-
-	trustCache.RLock()
-	defer trustCache.RUnlock()
-
-	var targetThirdPartyCa *Ca
-	chains, err := module.verifyClientCerts(certs, trustCache.staticFirstPartyRootPool)
-
-	if err != nil {
-		logger.WithError(err).Error("error verifying client certificate via static root pool, trying other pools")
-	}
-
-	if len(chains) == 0 {
-		logger.Debug("certificate validation failed via root only pool, trying other pools")
-		chains, err = module.verifyClientCerts(certs, trustCache.staticFirstPartyTrustAnchorPool)
-
-		if err != nil {
-			logger.WithError(err).Error("error verifying client certificate via static trust anchor pool, trying other pools")
-		}
-
-		if len(chains) != 0 {
-			//succeeded on augmented trust pool, client didn't supply a chain
-			bundle.ImproperClientCertChain = true
-		} else {
-			logger.Debug("certificate validation failed via root+intermediate pool, trying third party pool")
-			chains, err = module.verifyClientCerts(certs, trustCache.thirdPartyTrustAnchorPool)
-
-			if err == nil {
-				targetThirdPartyCa = getCaByChain(trustCache.activeThirdPartyCas, chains, module.env.GetFingerprintGenerator())
-
-				if len(chains) == 0 {
-					logger.Debug("certificate validation failed via third party pool")
-				}
-			} else {
-				logger.WithError(err).Error("error verifying client certificate via third party anchor pool")
-			}
-		}
-	}
+	chains, targetThirdPartyCa := module.verifyClientCertChains(certs, bundle, logger)
 
 	if len(chains) == 0 {
 		reason := "failed to verify client via all pools, no valid roots"
@@ -185,6 +149,18 @@ func (module *AuthModuleCert) Process(context AuthContext) (AuthResult, error) {
 		return nil, apierror.NewInvalidAuth()
 	}
 
+	if targetThirdPartyCa != nil && targetThirdPartyCa.RevocationCheck.IsEnabled() {
+		if err = module.env.GetManagers().Ca.GetRevocationChecker().CheckChain(targetThirdPartyCa.RevocationCheck, chains[0]); err != nil {
+			reason := fmt.Sprintf("client certificate failed revocation check: %v", err)
+			failEvent := module.NewAuthEventFailure(context, bundle, reason)
+			module.env.GetEventDispatcher().AcceptAuthenticationEvent(failEvent)
+
+			logger.WithField("caId", targetThirdPartyCa.Id).WithError(err).Error("client certificate failed revocation check")
+
+			return nil, apierror.NewInvalidAuth()
+		}
+	}
+
 	externalId := ""
 	if targetThirdPartyCa != nil {
 		externalId, err = targetThirdPartyCa.GetExternalId(clientCert)
@@ -325,6 +301,52 @@ func (module *AuthModuleCert) Process(context AuthContext) (AuthResult, error) {
 	}, nil
 }
 
+// verifyClientCertChains verifies the client certificates against the first party roots, then the first party trust
+// anchors and finally the third party CAs. It returns the valid chains, along with the third party CA the chains
+// resolved to, if any.
+func (module *AuthModuleCert) verifyClientCertChains(certs []*x509.Certificate, bundle *AuthBundle, logger *logrus.Entry) ([][]*x509.Certificate, *Ca) {
+	trustCache := module.env.GetManagers().Ca.GetTrustCache()
+
+	trustCache.RLock()
+	defer trustCache.RUnlock()
+
+	var targetThirdPartyCa *Ca
+	chains, err := module.verifyClientCerts(certs, trustCache.staticFirstPartyRootPool)
+
+	if err != nil {
+		logger.WithError(err).Error("error verifying client certificate via static root pool, trying other pools")
+	}
+
+	if len(chains) == 0 {
+		logger.Debug("certificate validation failed via root only pool, trying other pools")
+		chains, err = module.verifyClientCerts(certs, trustCache.staticFirstPartyTrustAnchorPool)
+
+		if err != nil {
+			logger.WithError(err).Error("error verifying client certificate via static trust anchor pool, trying other pools")
+		}
+
+		if len(chains) != 0 {
+			//succeeded on augmented trust pool, client didn't supply a chain
+			bundle.ImproperClientCertChain = true
+		} else {
+			logger.Debug("certificate validation failed via root+intermediate pool, trying third party pool")
+			chains, err = module.verifyClientCerts(certs, trustCache.thirdPartyTrustAnchorPool)
+
+			if err == nil {
+				targetThirdPartyCa = getCaByChain(trustCache.activeThirdPartyCas, chains, module.env.GetFingerprintGenerator())
+
+				if len(chains) == 0 {
+					logger.Debug("certificate validation failed via third party pool")
+				}
+			} else {
+				logger.WithError(err).Error("error verifying client certificate via third party anchor pool")
+			}
+		}
+	}
+
+	return chains, targetThirdPartyCa
+}
+
 func (module *AuthModuleCert) isEdgeRouter(clientCert *x509.Certificate) bool {
 
 	fingerprint := module.env.GetFingerprintGenerator().FromCert(clientCert)
@@ -55,7 +55,7 @@ type baseEntityManager[ME edgeEntity[PE], PE boltz.ExtEntity] struct {
 }
 
 func (self *baseEntityManager[ME, PE]) Dispatch(command command.Command) error {
-	return self.env.GetManagers().Dispatcher.Dispatch(command)
+	return self.env.GetManagers().Command.Dispatch(command)
 }
 
 func (self *baseEntityManager[ME, PE]) GetEntityTypeId() string {
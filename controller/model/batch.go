@@ -0,0 +1,174 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+
+	"github.com/openziti/ziti/v2/common/pb/cmd_pb"
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/command"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// Batch collects the commands generated by manager calls made with the batch's change context, instead of
+// dispatching them one at a time. Once all operations have been recorded, Dispatch applies them as a single
+// BatchCommand, so either all of them commit or none do.
+//
+// Only managers which dispatch through the base entity manager are batched. Callers are responsible for only
+// using the batch change context with those managers.
+type Batch struct {
+	manager  *CommandManager
+	ctx      *change.Context
+	commands []command.Command
+}
+
+// NewBatch starts a batch for the given change context. Each batch must be finished with either Dispatch or Discard.
+func (self *CommandManager) NewBatch(ctx *change.Context) *Batch {
+	result := &Batch{
+		manager: self,
+		ctx:     ctx,
+	}
+	self.batches.Store(ctx, result)
+	return result
+}
+
+// Dispatch sends the command to the command dispatcher, unless it belongs to an open batch, in which case it's
+// recorded in the batch
+func (self *CommandManager) Dispatch(cmd command.Command) error {
+	if ctx := cmd.GetChangeContext(); ctx != nil {
+		if val, found := self.batches.Load(ctx); found {
+			val.(*Batch).commands = append(val.(*Batch).commands, cmd)
+			return nil
+		}
+	}
+	return self.env.GetManagers().Dispatcher.Dispatch(cmd)
+}
+
+func (self *CommandManager) decodeBatchCommand(_ int32, data []byte) (command.Command, error) {
+	msg := &cmd_pb.BatchCommand{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+
+	result := &BatchCommand{
+		Context: change.FromProtoBuf(msg.Ctx),
+		Db:      self.env.GetDb(),
+	}
+
+	for idx, encoded := range msg.Commands {
+		cmd, err := self.Decoders.Decode(encoded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to decode batch operation %d", idx)
+		}
+		result.Commands = append(result.Commands, cmd)
+	}
+
+	return result, nil
+}
+
+func (self *Batch) ChangeContext() *change.Context {
+	return self.ctx
+}
+
+// Len returns the number of commands recorded so far
+func (self *Batch) Len() int {
+	return len(self.commands)
+}
+
+// Dispatch closes the batch and applies the recorded commands as a single command
+func (self *Batch) Dispatch() error {
+	self.Discard()
+	if len(self.commands) == 0 {
+		return nil
+	}
+	cmd := &BatchCommand{
+		Context:  self.ctx,
+		Db:       self.manager.env.GetDb(),
+		Commands: self.commands,
+	}
+	return self.manager.env.GetManagers().Dispatcher.Dispatch(cmd)
+}
+
+// Discard closes the batch without applying anything
+func (self *Batch) Discard() {
+	self.manager.batches.Delete(self.ctx)
+}
+
+// BatchError reports which command in a batch failed
+type BatchError struct {
+	Index int
+	Cause error
+}
+
+func (self *BatchError) Error() string {
+	return fmt.Sprintf("batch operation %d failed: %v", self.Index, self.Cause)
+}
+
+func (self *BatchError) Unwrap() error {
+	return self.Cause
+}
+
+// BatchCommand applies a list of commands, in order, in a single transaction. If any of them fail, the
+// transaction is rolled back and none of them are applied.
+type BatchCommand struct {
+	Context  *change.Context
+	Db       boltz.Db
+	Commands []command.Command
+}
+
+func (self *BatchCommand) Apply(ctx boltz.MutateContext) error {
+	return self.Db.Update(ctx, func(ctx boltz.MutateContext) error {
+		for idx, cmd := range self.Commands {
+			if err := cmd.Apply(ctx); err != nil {
+				return &BatchError{Index: idx, Cause: err}
+			}
+		}
+		return nil
+	})
+}
+
+func (self *BatchCommand) Validate() error {
+	for idx, cmd := range self.Commands {
+		if validatable, ok := cmd.(command.Validatable); ok {
+			if err := validatable.Validate(); err != nil {
+				return &BatchError{Index: idx, Cause: err}
+			}
+		}
+	}
+	return nil
+}
+
+func (self *BatchCommand) Encode() ([]byte, error) {
+	msg := &cmd_pb.BatchCommand{
+		Ctx: self.Context.ToProtoBuf(),
+	}
+	for idx, cmd := range self.Commands {
+		encoded, err := cmd.Encode()
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to encode batch operation %d", idx)
+		}
+		msg.Commands = append(msg.Commands, encoded)
+	}
+	return cmd_pb.EncodeProtobuf(msg)
+}
+
+func (self *BatchCommand) GetChangeContext() *change.Context {
+	return self.Context
+}
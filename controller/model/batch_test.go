@@ -0,0 +1,78 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	ctx := NewTestContext(t)
+	defer ctx.Cleanup()
+
+	managers := ctx.GetManagers()
+
+	t.Run("commands are applied together on dispatch", func(t *testing.T) {
+		req := require.New(t)
+
+		changeCtx := change.New()
+		batch := managers.Command.NewBatch(changeCtx)
+
+		first := &ConfigType{Name: "batch-one"}
+		second := &ConfigType{Name: "batch-two"}
+		req.NoError(managers.ConfigType.Create(first, changeCtx))
+		req.NoError(managers.ConfigType.Create(second, changeCtx))
+		req.Equal(2, batch.Len())
+
+		_, err := managers.ConfigType.Read(first.Id)
+		req.True(boltz.IsErrNotFoundErr(err))
+
+		req.NoError(batch.Dispatch())
+
+		for _, id := range []string{first.Id, second.Id} {
+			_, err = managers.ConfigType.Read(id)
+			req.NoError(err)
+		}
+
+		third := &ConfigType{Name: "batch-three"}
+		req.NoError(managers.ConfigType.Create(third, changeCtx))
+		_, err = managers.ConfigType.Read(third.Id)
+		req.NoError(err, "commands dispatched after the batch is closed should be applied immediately")
+	})
+
+	t.Run("a failed command rolls back the batch", func(t *testing.T) {
+		req := require.New(t)
+
+		changeCtx := change.New()
+		batch := managers.Command.NewBatch(changeCtx)
+
+		valid := &ConfigType{Name: "batch-four"}
+		duplicate := &ConfigType{Name: "batch-one"}
+		req.NoError(managers.ConfigType.Create(valid, changeCtx))
+		req.NoError(managers.ConfigType.Create(duplicate, changeCtx))
+
+		err := batch.Dispatch()
+		var batchErr *BatchError
+		req.ErrorAs(err, &batchErr)
+		req.Equal(1, batchErr.Index)
+
+		_, err = managers.ConfigType.Read(valid.Id)
+		req.True(boltz.IsErrNotFoundErr(err))
+	})
+
+	t.Run("discarded batches aren't applied", func(t *testing.T) {
+		req := require.New(t)
+
+		changeCtx := change.New()
+		batch := managers.Command.NewBatch(changeCtx)
+
+		discarded := &ConfigType{Name: "batch-five"}
+		req.NoError(managers.ConfigType.Create(discarded, changeCtx))
+		batch.Discard()
+
+		_, err := managers.ConfigType.Read(discarded.Id)
+		req.True(boltz.IsErrNotFoundErr(err))
+	})
+}
@@ -42,6 +42,7 @@ import (
 func NewCaManager(env Env) *CaManager {
 	manager := &CaManager{
 		baseEntityManager: newBaseEntityManager[*Ca, *db.Ca](env, env.GetStores().Ca),
+		revocationChecker: NewRevocationChecker(),
 	}
 	manager.impl = manager
 
@@ -197,7 +198,8 @@ func (self *TrustCache) VerifyClientCertCached(fingerprint string, clientCerts [
 
 type CaManager struct {
 	baseEntityManager[*Ca, *db.Ca]
-	cache TrustCache
+	cache             TrustCache
+	revocationChecker *RevocationChecker
 }
 
 func (self *CaManager) initCache() {
@@ -217,6 +219,12 @@ func (self *CaManager) GetTrustCache() *TrustCache {
 	return &self.cache
 }
 
+// GetRevocationChecker returns the checker used to verify that certificates issued by third party CAs haven't been
+// revoked
+func (self *CaManager) GetRevocationChecker() *RevocationChecker {
+	return self.revocationChecker
+}
+
 func (self *CaManager) RefreshActiveAuthCaCertCache() error {
 	self.cache.Lock()
 	defer self.cache.Unlock()
@@ -295,6 +303,10 @@ func (self *CaManager) NewModelEntity() *Ca {
 }
 
 func (self *CaManager) Create(entity *Ca, ctx *change.Context) error {
+	// defaults must be filled in before dispatch, as an encoded revocation check without a fail mode decodes as none
+	if err := validateRevocationCheck(entity.RevocationCheck); err != nil {
+		return err
+	}
 	return DispatchCreate[*Ca](self, entity, ctx)
 }
 
@@ -351,7 +363,9 @@ func (self *CaManager) IsUpdated(field string) bool {
 		strings.EqualFold(field, db.FieldCaIsAuthEnabled) ||
 		strings.EqualFold(field, db.FieldIdentityRoles) ||
 		strings.EqualFold(field, db.FieldCaIdentityNameFormat) ||
-		strings.HasPrefix(field, db.FieldCaExternalIdClaim+".")
+		strings.HasPrefix(field, db.FieldCaExternalIdClaim+".") ||
+		strings.EqualFold(field, db.FieldCaRevocationCheck) ||
+		strings.HasPrefix(field, db.FieldCaRevocationCheck+".")
 }
 
 func (self *CaManager) Verified(ca *Ca, ctx *change.Context) error {
@@ -362,6 +376,32 @@ func (self *CaManager) Verified(ca *Ca, ctx *change.Context) error {
 	return DispatchUpdate[*Ca](self, ca, checker, ctx)
 }
 
+// UpdateRevocationCheck sets the revocation check configuration of the CA. A nil configuration disables revocation
+// checking for the CA.
+func (self *CaManager) UpdateRevocationCheck(caId string, revocationCheck *CaRevocationCheck, ctx *change.Context) error {
+	ca, err := self.Read(caId)
+	if err != nil {
+		return err
+	}
+
+	// defaults must be filled in before dispatch, as an encoded revocation check without a fail mode decodes as none
+	if err = validateRevocationCheck(revocationCheck); err != nil {
+		return err
+	}
+
+	ca.RevocationCheck = revocationCheck
+	checker := fields.UpdatedFieldsMap{
+		db.FieldCaRevocationCheck:                          struct{}{},
+		db.FieldCaRevocationCheckCrlEnabled:                struct{}{},
+		db.FieldCaRevocationCheckCrlUrls:                   struct{}{},
+		db.FieldCaRevocationCheckCrlRefreshIntervalSeconds: struct{}{},
+		db.FieldCaRevocationCheckOcspEnabled:               struct{}{},
+		db.FieldCaRevocationCheckOcspUrl:                   struct{}{},
+		db.FieldCaRevocationCheckFailMode:                  struct{}{},
+	}
+	return DispatchUpdate[*Ca](self, ca, checker, ctx)
+}
+
 func (self *CaManager) Query(query string) (*CaListResult, error) {
 	result := &CaListResult{manager: self}
 	if err := self.ListWithHandler(query, result.collect); err != nil {
@@ -424,6 +464,15 @@ func (self *CaManager) Marshall(entity *Ca) ([]byte, error) {
 		ExternalIdClaim:           externalIdClaim,
 	}
 
+	if entity.RevocationCheck != nil {
+		msg.CrlCheckEnabled = entity.RevocationCheck.CrlEnabled
+		msg.CrlUrls = entity.RevocationCheck.CrlUrls
+		msg.CrlRefreshIntervalSeconds = int64(entity.RevocationCheck.CrlRefreshInterval / time.Second)
+		msg.OcspCheckEnabled = entity.RevocationCheck.OcspEnabled
+		msg.OcspUrl = entity.RevocationCheck.OcspUrl
+		msg.RevocationFailMode = entity.RevocationCheck.FailMode
+	}
+
 	return proto.Marshal(msg)
 }
 
@@ -445,6 +494,19 @@ func (self *CaManager) Unmarshall(bytes []byte) (*Ca, error) {
 		}
 	}
 
+	// a stored revocation check always has a fail mode, so it marks whether one was sent
+	var revocationCheck *CaRevocationCheck
+	if msg.RevocationFailMode != "" {
+		revocationCheck = &CaRevocationCheck{
+			CrlEnabled:         msg.CrlCheckEnabled,
+			CrlUrls:            msg.CrlUrls,
+			CrlRefreshInterval: time.Duration(msg.CrlRefreshIntervalSeconds) * time.Second,
+			OcspEnabled:        msg.OcspCheckEnabled,
+			OcspUrl:            msg.OcspUrl,
+			FailMode:           msg.RevocationFailMode,
+		}
+	}
+
 	return &Ca{
 		BaseEntity: models.BaseEntity{
 			Id:   msg.Id,
@@ -461,10 +523,14 @@ func (self *CaManager) Unmarshall(bytes []byte) (*Ca, error) {
 		IdentityRoles:             msg.IdentityRoles,
 		IdentityNameFormat:        msg.IdentityNameFormat,
 		ExternalIdClaim:           externalIdClaim,
+		RevocationCheck:           revocationCheck,
 	}, nil
 }
 
 func (self *CaManager) onMutate(_ *db.Ca) {
+	// revocation sources may have changed, so start from fresh revocation information
+	self.revocationChecker.ClearCache()
+
 	err := self.RefreshActiveAuthCaCertCache()
 
 	if err != nil {
@@ -20,6 +20,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/openziti/foundation/v2/errorz"
 	"github.com/openziti/x509-claims/x509claims"
@@ -46,6 +47,7 @@ type Ca struct {
 	IdentityRoles             []string
 	IdentityNameFormat        string
 	ExternalIdClaim           *ExternalIdClaim
+	RevocationCheck           *CaRevocationCheck
 }
 
 type ExternalIdClaim struct {
@@ -57,6 +59,50 @@ type ExternalIdClaim struct {
 	Index           int64
 }
 
+// CaRevocationCheck configures revocation checking for certificates issued by a third party CA. CRLs are loaded
+// from the configured URLs, or from the CRL distribution points of the certificates being checked. Configured URLs
+// may be http, https or file URLs, or absolute paths, while distribution points are only loaded over http or https,
+// as they come from the certificates being checked. OCSP requests go
+// to the configured responder, or to the responders listed in the certificates being checked. When the revocation
+// status can't be determined, authentication fails in HARD fail mode and is allowed in SOFT fail mode.
+type CaRevocationCheck struct {
+	CrlEnabled         bool
+	CrlUrls            []string
+	CrlRefreshInterval time.Duration
+	OcspEnabled        bool
+	OcspUrl            string
+	FailMode           string
+}
+
+func (self *CaRevocationCheck) IsEnabled() bool {
+	return self != nil && (self.CrlEnabled || self.OcspEnabled)
+}
+
+func (self *CaRevocationCheck) IsHardFail() bool {
+	return self != nil && self.FailMode == db.RevocationFailModeHard
+}
+
+func (self *CaRevocationCheck) GetCrlRefreshInterval() time.Duration {
+	if self.CrlRefreshInterval <= 0 {
+		return DefaultCrlRefreshInterval
+	}
+	return self.CrlRefreshInterval
+}
+
+func (self *CaRevocationCheck) toBolt() *db.RevocationCheck {
+	if self == nil {
+		return nil
+	}
+	return &db.RevocationCheck{
+		CrlEnabled:                self.CrlEnabled,
+		CrlUrls:                   self.CrlUrls,
+		CrlRefreshIntervalSeconds: int64(self.CrlRefreshInterval / time.Second),
+		OcspEnabled:               self.OcspEnabled,
+		OcspUrl:                   self.OcspUrl,
+		FailMode:                  self.FailMode,
+	}
+}
+
 type ExternalIdFieldType string
 
 func (entity *Ca) fillFrom(_ Env, _ *bbolt.Tx, boltCa *db.Ca) error {
@@ -82,6 +128,17 @@ func (entity *Ca) fillFrom(_ Env, _ *bbolt.Tx, boltCa *db.Ca) error {
 		entity.ExternalIdClaim.ParserCriteria = boltCa.ExternalIdClaim.ParserCriteria
 	}
 
+	if boltCa.RevocationCheck != nil {
+		entity.RevocationCheck = &CaRevocationCheck{
+			CrlEnabled:         boltCa.RevocationCheck.CrlEnabled,
+			CrlUrls:            boltCa.RevocationCheck.CrlUrls,
+			CrlRefreshInterval: time.Duration(boltCa.RevocationCheck.CrlRefreshIntervalSeconds) * time.Second,
+			OcspEnabled:        boltCa.RevocationCheck.OcspEnabled,
+			OcspUrl:            boltCa.RevocationCheck.OcspUrl,
+			FailMode:           boltCa.RevocationCheck.FailMode,
+		}
+	}
+
 	return nil
 }
 
@@ -94,6 +151,10 @@ func (entity *Ca) toBoltEntityForCreate(tx *bbolt.Tx, env Env) (*db.Ca, error) {
 		return nil, err
 	}
 
+	if err := validateRevocationCheck(entity.RevocationCheck); err != nil {
+		return nil, err
+	}
+
 	var fp string
 
 	if entity.CertPem != "" {
@@ -152,6 +213,7 @@ func (entity *Ca) toBoltEntityForCreate(tx *bbolt.Tx, env Env) (*db.Ca, error) {
 		IsOttCaEnrollmentEnabled:  entity.IsOttCaEnrollmentEnabled,
 		IdentityRoles:             entity.IdentityRoles,
 		IdentityNameFormat:        entity.IdentityNameFormat,
+		RevocationCheck:           entity.RevocationCheck.toBolt(),
 	}
 
 	if entity.ExternalIdClaim != nil {
@@ -182,6 +244,11 @@ func (entity *Ca) toBoltEntityForUpdate(tx *bbolt.Tx, env Env, checker boltz.Fie
 		return nil, err
 	}
 
+	revocationCheck, err := entity.effectiveRevocationCheck(tx, env, checker)
+	if err != nil {
+		return nil, err
+	}
+
 	boltEntity := &db.Ca{
 		BaseExtEntity:             *boltz.NewExtEntity(entity.Id, entity.Tags),
 		Name:                      entity.Name,
@@ -191,6 +258,7 @@ func (entity *Ca) toBoltEntityForUpdate(tx *bbolt.Tx, env Env, checker boltz.Fie
 		IsVerified:                entity.IsVerified,
 		IdentityRoles:             entity.IdentityRoles,
 		IdentityNameFormat:        entity.IdentityNameFormat,
+		RevocationCheck:           revocationCheck,
 	}
 
 	if entity.ExternalIdClaim != nil {
@@ -207,6 +275,24 @@ func (entity *Ca) toBoltEntityForUpdate(tx *bbolt.Tx, env Env, checker boltz.Fie
 	return boltEntity, nil
 }
 
+// effectiveRevocationCheck returns the revocation check to store for an update. The revocation check is managed on
+// its own, so updates which don't name it, including full replaces from clients which don't know about it, keep the
+// stored configuration.
+func (entity *Ca) effectiveRevocationCheck(tx *bbolt.Tx, env Env, checker boltz.FieldChecker) (*db.RevocationCheck, error) {
+	if checker != nil && checker.IsUpdated(db.FieldCaRevocationCheck) {
+		if err := validateRevocationCheck(entity.RevocationCheck); err != nil {
+			return nil, err
+		}
+		return entity.RevocationCheck.toBolt(), nil
+	}
+
+	existing, err := env.GetStores().Ca.LoadById(tx, entity.Id)
+	if err != nil {
+		return nil, err
+	}
+	return existing.RevocationCheck, nil
+}
+
 // caExternalIdClaimFields are the persisted externalIdClaim subfields, used to detect which
 // subfields a partial update touches.
 var caExternalIdClaimFields = []string{
@@ -313,6 +399,43 @@ func validateExternalIdClaim(claim *ExternalIdClaim) error {
 	return nil
 }
 
+// validateRevocationCheck rejects revocation check configurations which can't be used to check certificates. An
+// empty fail mode defaults to SOFT.
+func validateRevocationCheck(check *CaRevocationCheck) error {
+	if check == nil {
+		return nil
+	}
+
+	switch check.FailMode {
+	case "":
+		check.FailMode = db.RevocationFailModeSoft
+	case db.RevocationFailModeSoft, db.RevocationFailModeHard:
+	default:
+		return apierror.NewBadRequestFieldError(*errorz.NewFieldError("fail mode must be SOFT or HARD", "revocationCheck.failMode", check.FailMode))
+	}
+
+	if check.CrlRefreshInterval != 0 && check.CrlRefreshInterval < MinCrlRefreshInterval {
+		return apierror.NewBadRequestFieldError(*errorz.NewFieldError(fmt.Sprintf("crl refresh interval must be at least %s", MinCrlRefreshInterval),
+			"revocationCheck.crlRefreshIntervalSeconds", int64(check.CrlRefreshInterval/time.Second)))
+	}
+
+	for _, crlUrl := range check.CrlUrls {
+		if _, isFile := getCrlFilePath(crlUrl); !isFile && !isNetworkUrl(crlUrl) {
+			return apierror.NewBadRequestFieldError(*errorz.NewFieldError("crl urls must be http, https or file urls, or absolute paths",
+				"revocationCheck.crlUrls", crlUrl))
+		}
+	}
+
+	if check.OcspUrl != "" {
+		parsed, err := url.Parse(check.OcspUrl)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return apierror.NewBadRequestFieldError(*errorz.NewFieldError("ocsp url must be an http or https url", "revocationCheck.ocspUrl", check.OcspUrl))
+		}
+	}
+
+	return nil
+}
+
 // GetExternalId will attempt to retrieve a string claim from a x509 Certificate based on
 // location, matching, and parsing of various x509 Certificate fields.
 func (entity *Ca) GetExternalId(cert *x509.Certificate) (string, error) {
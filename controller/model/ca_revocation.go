@@ -0,0 +1,466 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/michaelquigley/pfxlog"
+	"golang.org/x/crypto/ocsp"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	DefaultCrlRefreshInterval = time.Hour
+	MinCrlRefreshInterval     = time.Minute
+
+	// DefaultOcspCacheDuration is how long an OCSP response is cached if the responder doesn't say when the next
+	// update will be available
+	DefaultOcspCacheDuration = time.Hour
+
+	revocationFetchTimeout = 5 * time.Second
+	maxCrlSize             = 64 * 1024 * 1024
+	maxOcspResponseSize    = 1024 * 1024
+	maxOcspCacheEntries    = 100_000
+)
+
+type RevocationStatus int
+
+const (
+	RevocationStatusUnknown RevocationStatus = iota
+	RevocationStatusGood
+	RevocationStatusRevoked
+)
+
+func (self RevocationStatus) String() string {
+	switch self {
+	case RevocationStatusGood:
+		return "good"
+	case RevocationStatusRevoked:
+		return "revoked"
+	default:
+		return "unknown"
+	}
+}
+
+// RevocationChecker checks the revocation status of certificates issued by third party CAs. CRLs are cached by
+// source until they're due for a refresh, and concurrent loads of the same CRL share a single fetch. OCSP responses
+// are cached until the responder's next update, the same way a stapled response would be, and the raw responses are
+// kept so they can be handed out again.
+type RevocationChecker struct {
+	httpClient    *http.Client
+	crlLock       sync.Mutex
+	crls          map[string]*cachedCrl
+	crlLoads      singleflight.Group
+	ocspResponses *ttlcache.Cache[string, *CachedOcspResponse]
+	initOnce      sync.Once
+}
+
+type cachedCrl struct {
+	list        *x509.RevocationList
+	revoked     map[string]struct{}
+	nextRefresh time.Time
+}
+
+// CachedOcspResponse is a verified OCSP response for a single certificate
+type CachedOcspResponse struct {
+	Raw      []byte
+	Response *ocsp.Response
+}
+
+func NewRevocationChecker() *RevocationChecker {
+	return &RevocationChecker{
+		httpClient: &http.Client{Timeout: revocationFetchTimeout},
+		crls:       map[string]*cachedCrl{},
+	}
+}
+
+func (self *RevocationChecker) init() {
+	self.ocspResponses = ttlcache.New[string, *CachedOcspResponse](
+		ttlcache.WithTTL[string, *CachedOcspResponse](DefaultOcspCacheDuration),
+		ttlcache.WithCapacity[string, *CachedOcspResponse](maxOcspCacheEntries),
+	)
+	go self.ocspResponses.Start()
+}
+
+// CheckChain checks every certificate in the chain, except the last, against the certificate which issued it. An
+// error is returned if a certificate has been revoked, or if its status can't be determined and the check is
+// configured to fail hard.
+func (self *RevocationChecker) CheckChain(check *CaRevocationCheck, chain []*x509.Certificate) error {
+	if !check.IsEnabled() {
+		return nil
+	}
+
+	for i := 0; i < len(chain)-1; i++ {
+		cert, issuer := chain[i], chain[i+1]
+		status, err := self.GetStatus(check, cert, issuer)
+
+		switch status {
+		case RevocationStatusRevoked:
+			return fmt.Errorf("certificate with serial %s issued by [%s] has been revoked", cert.SerialNumber, issuer.Subject)
+		case RevocationStatusUnknown:
+			if check.IsHardFail() {
+				return fmt.Errorf("unable to determine revocation status of certificate with serial %s issued by [%s] (%w)",
+					cert.SerialNumber, issuer.Subject, err)
+			}
+			pfxlog.Logger().WithError(err).
+				WithField("serial", cert.SerialNumber.String()).
+				WithField("issuer", issuer.Subject.String()).
+				Warn("unable to determine certificate revocation status, allowing as revocation check fail mode is soft")
+		}
+	}
+
+	return nil
+}
+
+// GetStatus returns the revocation status of the certificate. OCSP is consulted first, if enabled, as it's usually
+// more current. If it doesn't give an answer and CRLs are enabled, the CRLs are checked. If the status is unknown, the
+// returned error explains why.
+func (self *RevocationChecker) GetStatus(check *CaRevocationCheck, cert, issuer *x509.Certificate) (RevocationStatus, error) {
+	var errs []error
+
+	if check.OcspEnabled {
+		status, err := self.getOcspStatus(check, cert, issuer)
+		if status != RevocationStatusUnknown {
+			return status, nil
+		}
+		errs = append(errs, err)
+	}
+
+	if check.CrlEnabled {
+		status, err := self.getCrlStatus(check, cert, issuer)
+		if status != RevocationStatusUnknown {
+			return status, nil
+		}
+		errs = append(errs, err)
+	}
+
+	return RevocationStatusUnknown, errors.Join(errs...)
+}
+
+func (self *RevocationChecker) getCrlStatus(check *CaRevocationCheck, cert, issuer *x509.Certificate) (RevocationStatus, error) {
+	crlUrls := check.CrlUrls
+	fromCert := len(crlUrls) == 0
+	if fromCert {
+		crlUrls = cert.CRLDistributionPoints
+	}
+
+	if len(crlUrls) == 0 {
+		return RevocationStatusUnknown, errors.New("no CRL urls configured and certificate has no CRL distribution points")
+	}
+
+	var errs []error
+	for _, crlUrl := range crlUrls {
+		// distribution points come from client certificates, so only network sources are loaded for them, never
+		// local files. Local files may only be configured by an admin.
+		if fromCert && !isNetworkUrl(crlUrl) {
+			errs = append(errs, fmt.Errorf("unsupported CRL distribution point [%s], only http and https are allowed", crlUrl))
+			continue
+		}
+
+		crl, err := self.getCrl(check, crlUrl)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// configured CRLs may be for other issuers in the chain, so only use those signed by this issuer
+		if err = crl.list.CheckSignatureFrom(issuer); err != nil {
+			errs = append(errs, fmt.Errorf("CRL from %s is not signed by [%s] (%w)", crlUrl, issuer.Subject, err))
+			continue
+		}
+
+		if _, revoked := crl.revoked[cert.SerialNumber.String()]; revoked {
+			return RevocationStatusRevoked, nil
+		}
+		return RevocationStatusGood, nil
+	}
+
+	return RevocationStatusUnknown, errors.Join(errs...)
+}
+
+// getCrl returns the cached CRL for the given url, loading it if it isn't cached or is due for a refresh. If a refresh
+// fails, the cached CRL continues to be used until its next update time has passed.
+func (self *RevocationChecker) getCrl(check *CaRevocationCheck, crlUrl string) (*cachedCrl, error) {
+	now := time.Now()
+
+	self.crlLock.Lock()
+	current := self.crls[crlUrl]
+	self.crlLock.Unlock()
+
+	if current != nil && now.Before(current.nextRefresh) {
+		return current, nil
+	}
+
+	// a CRL falling due is usually noticed by many authentications at once, so they share one load
+	result, err, _ := self.crlLoads.Do(crlUrl, func() (interface{}, error) {
+		// another load may have finished between the cache check and this one starting
+		self.crlLock.Lock()
+		latest := self.crls[crlUrl]
+		self.crlLock.Unlock()
+
+		if latest != nil && time.Now().Before(latest.nextRefresh) {
+			return latest, nil
+		}
+		return self.refreshCrl(check, crlUrl, latest)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*cachedCrl), nil
+}
+
+// refreshCrl loads the CRL and caches it. If the load fails, current, the previously cached CRL, is returned for as
+// long as it's valid.
+func (self *RevocationChecker) refreshCrl(check *CaRevocationCheck, crlUrl string, current *cachedCrl) (*cachedCrl, error) {
+	now := time.Now()
+
+	list, err := self.loadCrl(crlUrl)
+	if err != nil {
+		if current != nil && (current.list.NextUpdate.IsZero() || now.Before(current.list.NextUpdate)) {
+			pfxlog.Logger().WithError(err).WithField("url", crlUrl).Warn("unable to refresh CRL, using cached CRL")
+
+			// don't retry the refresh on every authentication while the source is unavailable
+			retry := *current
+			retry.nextRefresh = now.Add(MinCrlRefreshInterval)
+			self.crlLock.Lock()
+			self.crls[crlUrl] = &retry
+			self.crlLock.Unlock()
+
+			return current, nil
+		}
+		return nil, fmt.Errorf("unable to load CRL from %s (%w)", crlUrl, err)
+	}
+
+	if !list.NextUpdate.IsZero() && now.After(list.NextUpdate) {
+		return nil, fmt.Errorf("CRL from %s expired at %s", crlUrl, list.NextUpdate.Format(time.RFC3339))
+	}
+
+	result := &cachedCrl{
+		list:        list,
+		revoked:     map[string]struct{}{},
+		nextRefresh: now.Add(check.GetCrlRefreshInterval()),
+	}
+
+	if !list.NextUpdate.IsZero() && list.NextUpdate.Before(result.nextRefresh) {
+		result.nextRefresh = list.NextUpdate
+	}
+
+	for _, entry := range list.RevokedCertificateEntries {
+		result.revoked[entry.SerialNumber.String()] = struct{}{}
+	}
+
+	self.crlLock.Lock()
+	self.crls[crlUrl] = result
+	self.crlLock.Unlock()
+
+	pfxlog.Logger().WithField("url", crlUrl).
+		WithField("revoked", len(result.revoked)).
+		WithField("nextRefresh", result.nextRefresh).
+		Debug("loaded CRL")
+
+	return result, nil
+}
+
+// isNetworkUrl returns true if the url is an http or https url
+func isNetworkUrl(rawUrl string) bool {
+	parsed, err := url.Parse(rawUrl)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https")
+}
+
+// getCrlFilePath returns the local path of a CRL source given as a file url or as an absolute path. The returned bool
+// is false if the source isn't a local file.
+func getCrlFilePath(crlUrl string) (string, bool) {
+	if filepath.IsAbs(crlUrl) {
+		return crlUrl, true
+	}
+
+	parsed, err := url.Parse(crlUrl)
+	if err != nil || parsed.Scheme != "file" || parsed.Path == "" || (parsed.Host != "" && parsed.Host != "localhost") {
+		return "", false
+	}
+	return parsed.Path, true
+}
+
+func (self *RevocationChecker) loadCrl(crlUrl string) (*x509.RevocationList, error) {
+	var data []byte
+	var err error
+
+	if path, isFile := getCrlFilePath(crlUrl); isFile {
+		data, err = readCrlFile(path)
+	} else if isNetworkUrl(crlUrl) {
+		data, err = self.fetchCrl(crlUrl)
+	} else {
+		return nil, fmt.Errorf("unsupported CRL source [%s]", crlUrl)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// CRLs are usually DER encoded, but some servers publish PEM encoded CRLs
+	if block, _ := pem.Decode(data); block != nil && block.Type == "X509 CRL" {
+		data = block.Bytes
+	}
+
+	return x509.ParseRevocationList(data)
+}
+
+func (self *RevocationChecker) fetchCrl(crlUrl string) ([]byte, error) {
+	resp, err := self.httpClient.Get(crlUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxCrlSize))
+}
+
+func readCrlFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	return io.ReadAll(io.LimitReader(file, maxCrlSize))
+}
+
+func (self *RevocationChecker) getOcspStatus(check *CaRevocationCheck, cert, issuer *x509.Certificate) (RevocationStatus, error) {
+	response, err := self.GetOcspResponse(check, cert, issuer)
+	if err != nil {
+		return RevocationStatusUnknown, err
+	}
+
+	switch response.Response.Status {
+	case ocsp.Good:
+		return RevocationStatusGood, nil
+	case ocsp.Revoked:
+		return RevocationStatusRevoked, nil
+	default:
+		return RevocationStatusUnknown, errors.New("OCSP responder returned status unknown")
+	}
+}
+
+// GetOcspResponse returns a verified OCSP response for the certificate, from the cache if a current response is
+// available
+func (self *RevocationChecker) GetOcspResponse(check *CaRevocationCheck, cert, issuer *x509.Certificate) (*CachedOcspResponse, error) {
+	self.initOnce.Do(self.init)
+
+	issuerHash := sha256.Sum256(issuer.Raw)
+	key := hex.EncodeToString(issuerHash[:]) + "/" + cert.SerialNumber.String()
+
+	if item := self.ocspResponses.Get(key); item != nil {
+		return item.Value(), nil
+	}
+
+	servers := cert.OCSPServer
+	if check.OcspUrl != "" {
+		servers = []string{check.OcspUrl}
+	}
+
+	if len(servers) == 0 {
+		return nil, errors.New("no OCSP url configured and certificate has no OCSP servers")
+	}
+
+	request, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create OCSP request (%w)", err)
+	}
+
+	var errs []error
+	for _, server := range servers {
+		result, err := self.queryOcsp(server, request, cert, issuer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("OCSP request to %s failed (%w)", server, err))
+			continue
+		}
+
+		ttl := DefaultOcspCacheDuration
+		if !result.Response.NextUpdate.IsZero() {
+			ttl = time.Until(result.Response.NextUpdate)
+		}
+
+		if ttl > 0 {
+			self.ocspResponses.Set(key, result, ttl)
+		}
+
+		return result, nil
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func (self *RevocationChecker) queryOcsp(server string, request []byte, cert, issuer *x509.Certificate) (*CachedOcspResponse, error) {
+	resp, err := self.httpClient.Post(server, "application/ocsp-request", bytes.NewReader(request))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxOcspResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// verifies the response was signed by the issuer, or by a responder the issuer delegated to
+	response, err := ocsp.ParseResponseForCert(raw, cert, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.NextUpdate.IsZero() && time.Now().After(response.NextUpdate) {
+		return nil, fmt.Errorf("OCSP response expired at %s", response.NextUpdate.Format(time.RFC3339))
+	}
+
+	return &CachedOcspResponse{
+		Raw:      raw,
+		Response: response,
+	}, nil
+}
+
+// ClearCache drops all cached CRLs and OCSP responses, so the next checks load current revocation information
+func (self *RevocationChecker) ClearCache() {
+	self.crlLock.Lock()
+	self.crls = map[string]*cachedCrl{}
+	self.crlLock.Unlock()
+
+	self.initOnce.Do(self.init)
+	self.ocspResponses.DeleteAll()
+}
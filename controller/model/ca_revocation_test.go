@@ -0,0 +1,299 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func newTestCrl(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, revokedSerials ...int64) []byte {
+	t.Helper()
+	req := require.New(t)
+
+	list := &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Minute),
+		NextUpdate: time.Now().Add(time.Hour),
+	}
+	for _, serial := range revokedSerials {
+		list.RevokedCertificateEntries = append(list.RevokedCertificateEntries, x509.RevocationListEntry{
+			SerialNumber:   big.NewInt(serial),
+			RevocationTime: time.Now().Add(-time.Minute),
+		})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, list, ca, caKey)
+	req.NoError(err)
+	return der
+}
+
+// serveTestCrl publishes a CRL over http and returns its url
+func serveTestCrl(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, revokedSerials ...int64) string {
+	t.Helper()
+	der := newTestCrl(t, ca, caKey, revokedSerials...)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(der)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/ca.crl"
+}
+
+func TestRevocationCheckerCrl(t *testing.T) {
+	ca, caKey := newTestCA(t, "third-party-ca")
+	leaf := newTestLeaf(t, ca, caKey, "device")
+	otherCa, otherCaKey := newTestCA(t, "other-ca")
+
+	t.Run("revoked certificate fails", func(t *testing.T) {
+		req := require.New(t)
+		check := &CaRevocationCheck{CrlEnabled: true, CrlUrls: []string{serveTestCrl(t, ca, caKey, 2)}, FailMode: db.RevocationFailModeSoft}
+
+		checker := NewRevocationChecker()
+		status, err := checker.GetStatus(check, leaf, ca)
+		req.NoError(err)
+		req.Equal(RevocationStatusRevoked, status)
+		req.Error(checker.CheckChain(check, []*x509.Certificate{leaf, ca}))
+	})
+
+	t.Run("certificate not on the CRL passes", func(t *testing.T) {
+		req := require.New(t)
+		check := &CaRevocationCheck{CrlEnabled: true, CrlUrls: []string{serveTestCrl(t, ca, caKey, 10)}, FailMode: db.RevocationFailModeHard}
+
+		checker := NewRevocationChecker()
+		status, err := checker.GetStatus(check, leaf, ca)
+		req.NoError(err)
+		req.Equal(RevocationStatusGood, status)
+		req.NoError(checker.CheckChain(check, []*x509.Certificate{leaf, ca}))
+	})
+
+	t.Run("CRL from another issuer is ignored", func(t *testing.T) {
+		req := require.New(t)
+		check := &CaRevocationCheck{CrlEnabled: true, CrlUrls: []string{serveTestCrl(t, otherCa, otherCaKey, 2)}}
+
+		status, err := NewRevocationChecker().GetStatus(check, leaf, ca)
+		req.Error(err)
+		req.Equal(RevocationStatusUnknown, status)
+	})
+
+	t.Run("unavailable CRL depends on fail mode", func(t *testing.T) {
+		req := require.New(t)
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		missing := server.URL + "/missing.crl"
+
+		soft := &CaRevocationCheck{CrlEnabled: true, CrlUrls: []string{missing}, FailMode: db.RevocationFailModeSoft}
+		req.NoError(NewRevocationChecker().CheckChain(soft, []*x509.Certificate{leaf, ca}))
+
+		hard := &CaRevocationCheck{CrlEnabled: true, CrlUrls: []string{missing}, FailMode: db.RevocationFailModeHard}
+		req.Error(NewRevocationChecker().CheckChain(hard, []*x509.Certificate{leaf, ca}))
+	})
+}
+
+func TestRevocationCheckerCrlLoads(t *testing.T) {
+	ca, caKey := newTestCA(t, "third-party-ca")
+	leaf := newTestLeaf(t, ca, caKey, "device")
+	der := newTestCrl(t, ca, caKey)
+
+	t.Run("concurrent checks share one load", func(t *testing.T) {
+		req := require.New(t)
+
+		var requests atomic.Int32
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			<-release
+			_, _ = w.Write(der)
+		}))
+		defer server.Close()
+
+		check := &CaRevocationCheck{CrlEnabled: true, CrlUrls: []string{server.URL}, FailMode: db.RevocationFailModeHard}
+		checker := NewRevocationChecker()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- checker.CheckChain(check, []*x509.Certificate{leaf, ca})
+			}()
+		}
+
+		// give the checks time to queue up behind the first load
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			req.NoError(err)
+		}
+		req.Equal(int32(1), requests.Load())
+	})
+
+	t.Run("configured CRL files are loaded", func(t *testing.T) {
+		req := require.New(t)
+		revoked := newTestCrl(t, ca, caKey, 2)
+		path := filepath.Join(t.TempDir(), "ca.crl")
+		req.NoError(os.WriteFile(path, revoked, 0600))
+
+		for _, crlUrl := range []string{"file://" + path, path} {
+			check := &CaRevocationCheck{CrlEnabled: true, CrlUrls: []string{crlUrl}, FailMode: db.RevocationFailModeHard}
+			req.NoError(validateRevocationCheck(check))
+
+			checker := NewRevocationChecker()
+			status, err := checker.GetStatus(check, leaf, ca)
+			req.NoError(err)
+			req.Equal(RevocationStatusRevoked, status)
+			req.Error(checker.CheckChain(check, []*x509.Certificate{leaf, ca}))
+		}
+	})
+
+	t.Run("file distribution points from certificates are not loaded", func(t *testing.T) {
+		req := require.New(t)
+		path := filepath.Join(t.TempDir(), "ca.crl")
+		req.NoError(os.WriteFile(path, der, 0600))
+
+		cdpLeaf := *leaf
+		cdpLeaf.CRLDistributionPoints = []string{"file://" + path}
+
+		check := &CaRevocationCheck{CrlEnabled: true, FailMode: db.RevocationFailModeHard}
+		status, err := NewRevocationChecker().GetStatus(check, &cdpLeaf, ca)
+		req.ErrorContains(err, "only http and https are allowed")
+		req.Equal(RevocationStatusUnknown, status)
+	})
+
+	t.Run("unsupported configured sources are rejected", func(t *testing.T) {
+		req := require.New(t)
+		for _, crlUrl := range []string{"ldap://ldap.example.com/cn=ca", "relative/ca.crl", "file://remote-host/ca.crl"} {
+			check := &CaRevocationCheck{CrlEnabled: true, CrlUrls: []string{crlUrl}}
+			req.Error(validateRevocationCheck(check), crlUrl)
+		}
+	})
+}
+
+func TestCaManagerUpdateRevocationCheck(t *testing.T) {
+	ctx := NewTestContext(t)
+	defer ctx.Cleanup()
+
+	// the test context encodes and decodes commands, as happens when running in a cluster
+	ca, _ := newTestCA(t, "third-party-ca")
+	entity := &Ca{
+		Name:    "third-party-ca",
+		CertPem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})),
+	}
+	ctx.NoError(ctx.managers.Ca.Create(entity, change.New()))
+
+	t.Run("fail mode defaults to soft", func(t *testing.T) {
+		req := require.New(t)
+		check := &CaRevocationCheck{CrlEnabled: true, CrlUrls: []string{"https://pki.example.com/ca.crl"}}
+		req.NoError(ctx.managers.Ca.UpdateRevocationCheck(entity.Id, check, change.New()))
+
+		updated, err := ctx.managers.Ca.Read(entity.Id)
+		req.NoError(err)
+		req.NotNil(updated.RevocationCheck, "revocation check without a fail mode must survive command encoding")
+		req.True(updated.RevocationCheck.CrlEnabled)
+		req.Equal(db.RevocationFailModeSoft, updated.RevocationCheck.FailMode)
+	})
+
+	t.Run("invalid configuration is rejected before dispatch", func(t *testing.T) {
+		req := require.New(t)
+		check := &CaRevocationCheck{CrlEnabled: true, CrlUrls: []string{"ldap://ldap.example.com/cn=ca"}}
+		req.Error(ctx.managers.Ca.UpdateRevocationCheck(entity.Id, check, change.New()))
+
+		updated, err := ctx.managers.Ca.Read(entity.Id)
+		req.NoError(err)
+		req.NotNil(updated.RevocationCheck, "a rejected update must leave the existing configuration in place")
+	})
+
+	t.Run("nil removes revocation checking", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(ctx.managers.Ca.UpdateRevocationCheck(entity.Id, nil, change.New()))
+
+		updated, err := ctx.managers.Ca.Read(entity.Id)
+		req.NoError(err)
+		req.Nil(updated.RevocationCheck)
+	})
+}
+
+func TestRevocationCheckerOcsp(t *testing.T) {
+	req := require.New(t)
+
+	ca, caKey := newTestCA(t, "third-party-ca")
+	leaf := newTestLeaf(t, ca, caKey, "device")
+
+	requests := 0
+	responseStatus := ocsp.Revoked
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, err := io.ReadAll(r.Body)
+		req.NoError(err)
+
+		ocspReq, err := ocsp.ParseRequest(body)
+		req.NoError(err)
+
+		resp, err := ocsp.CreateResponse(ca, ca, ocsp.Response{
+			Status:       responseStatus,
+			SerialNumber: ocspReq.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+			RevokedAt:    time.Now().Add(-time.Minute),
+		}, crypto.Signer(caKey))
+		req.NoError(err)
+
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+
+	check := &CaRevocationCheck{OcspEnabled: true, OcspUrl: server.URL, FailMode: db.RevocationFailModeHard}
+	checker := NewRevocationChecker()
+
+	status, err := checker.GetStatus(check, leaf, ca)
+	req.NoError(err)
+	req.Equal(RevocationStatusRevoked, status)
+
+	// the response is cached until its next update
+	responseStatus = ocsp.Good
+	status, err = checker.GetStatus(check, leaf, ca)
+	req.NoError(err)
+	req.Equal(RevocationStatusRevoked, status)
+	req.Equal(1, requests)
+
+	checker.ClearCache()
+	status, err = checker.GetStatus(check, leaf, ca)
+	req.NoError(err)
+	req.Equal(RevocationStatusGood, status)
+	req.Equal(2, requests)
+}
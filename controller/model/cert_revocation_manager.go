@@ -0,0 +1,276 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/storage/ast"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"go.etcd.io/bbolt"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// IssuedCrlValidity is how long a published CRL is valid for. It is regenerated after half that time, or as soon
+	// as the set of revoked certificates changes.
+	IssuedCrlValidity = time.Hour
+
+	// IssuedOcspValidity is how long clients may cache an OCSP response for a network issued certificate
+	IssuedOcspValidity = 5 * time.Minute
+)
+
+// CertRevocationManager publishes the revocation status of certificates issued by the controller's enrollment
+// signer, both as a CRL and as OCSP responses. The revoked certificates are recorded by the db layer whenever an
+// authenticator or edge router certificate is removed or replaced, and while an identity is disabled.
+type CertRevocationManager struct {
+	env Env
+
+	crlLock  sync.Mutex
+	crl      *IssuedCrl
+	crlDirty atomic.Bool
+}
+
+// IssuedCrl is a signed, DER encoded CRL for the network issued certificates
+type IssuedCrl struct {
+	Der        []byte
+	ThisUpdate time.Time
+	NextUpdate time.Time
+	signerRaw  []byte
+}
+
+func NewCertRevocationManager(env Env) *CertRevocationManager {
+	result := &CertRevocationManager{
+		env: env,
+	}
+
+	env.GetStores().RevokedCertificate.AddEntityIdListener(func(string) {
+		result.crlDirty.Store(true)
+	}, boltz.EntityCreated, boltz.EntityUpdated, boltz.EntityDeleted)
+
+	return result
+}
+
+func (self *CertRevocationManager) getSigner() (*x509.Certificate, crypto.Signer, error) {
+	cfg := self.env.GetConfig()
+	if cfg == nil || cfg.Edge == nil || cfg.Edge.Enrollment.SigningCert == nil {
+		return nil, nil, errors.New("no enrollment signing certificate configured")
+	}
+
+	tlsCert := cfg.Edge.Enrollment.SigningCert.Cert()
+	if tlsCert == nil || tlsCert.Leaf == nil {
+		return nil, nil, errors.New("enrollment signing certificate not loaded")
+	}
+
+	signer, ok := tlsCert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("enrollment signing key of type %T can not sign", tlsCert.PrivateKey)
+	}
+
+	return tlsCert.Leaf, signer, nil
+}
+
+// isPublished returns true if the revoked certificate is still valid and was issued by the given signer.
+// Certificates from a previous signer are not listed, as they're not trusted by anyone relying on this one.
+func (self *CertRevocationManager) isPublished(revoked *db.RevokedCertificate, signerCert *x509.Certificate, now time.Time) bool {
+	if !revoked.ExpiresAt.After(now) {
+		return false
+	}
+	if revoked.AuthorityKeyId == "" || len(signerCert.SubjectKeyId) == 0 {
+		return true
+	}
+	return revoked.AuthorityKeyId == hex.EncodeToString(signerCert.SubjectKeyId)
+}
+
+// GetCrl returns the current CRL, regenerating it if revocations have changed since it was signed, the signer has
+// changed or half of its validity has passed.
+func (self *CertRevocationManager) GetCrl() (*IssuedCrl, error) {
+	signerCert, signer, err := self.getSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	self.crlLock.Lock()
+	defer self.crlLock.Unlock()
+
+	now := time.Now()
+	if crl := self.crl; crl != nil && !self.crlDirty.Load() && bytes.Equal(crl.signerRaw, signerCert.Raw) &&
+		now.Before(crl.ThisUpdate.Add(IssuedCrlValidity/2)) {
+		return crl, nil
+	}
+
+	// clear before reading, so changes made while the CRL is being built trigger another rebuild
+	self.crlDirty.Store(false)
+
+	var entries []x509.RevocationListEntry
+	err = self.env.GetDb().View(func(tx *bbolt.Tx) error {
+		store := self.env.GetStores().RevokedCertificate
+		for cursor := store.IterateIds(tx, ast.BoolNodeTrue); cursor.IsValid(); cursor.Next() {
+			revoked, err := store.LoadById(tx, string(cursor.Current()))
+			if err != nil {
+				return err
+			}
+			if !self.isPublished(revoked, signerCert, now) {
+				continue
+			}
+
+			serial, ok := new(big.Int).SetString(revoked.Id, 16)
+			if !ok {
+				continue
+			}
+
+			entries = append(entries, x509.RevocationListEntry{
+				SerialNumber:   serial,
+				RevocationTime: revoked.RevokedAt,
+				ReasonCode:     revoked.Reason,
+			})
+		}
+		return nil
+	})
+
+	if err != nil {
+		self.crlDirty.Store(true)
+		return nil, err
+	}
+
+	template := &x509.RevocationList{
+		// CRL numbers must increase monotonically, including across controllers, so time based numbering is used
+		Number:                    big.NewInt(now.UnixMilli()),
+		ThisUpdate:                now,
+		NextUpdate:                now.Add(IssuedCrlValidity),
+		RevokedCertificateEntries: entries,
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, template, signerCert, signer)
+	if err != nil {
+		self.crlDirty.Store(true)
+		return nil, fmt.Errorf("unable to sign CRL (%w)", err)
+	}
+
+	self.crl = &IssuedCrl{
+		Der:        der,
+		ThisUpdate: template.ThisUpdate,
+		NextUpdate: template.NextUpdate,
+		signerRaw:  signerCert.Raw,
+	}
+
+	return self.crl, nil
+}
+
+// GetStatus returns the revocation entry for the given serial number, or nil if the certificate isn't revoked
+func (self *CertRevocationManager) GetStatus(serial *big.Int) (*db.RevokedCertificate, error) {
+	signerCert, _, err := self.getSigner()
+	if err != nil {
+		return nil, err
+	}
+	return self.getStatus(serial, signerCert)
+}
+
+func (self *CertRevocationManager) getStatus(serial *big.Int, signerCert *x509.Certificate) (*db.RevokedCertificate, error) {
+	var result *db.RevokedCertificate
+	err := self.env.GetDb().View(func(tx *bbolt.Tx) error {
+		revoked, found, err := self.env.GetStores().RevokedCertificate.FindById(tx, db.SerialToRevokedCertificateId(serial))
+		if err != nil {
+			return err
+		}
+		if found && self.isPublished(revoked, signerCert, time.Now()) {
+			result = revoked
+		}
+		return nil
+	})
+	return result, err
+}
+
+// CreateOcspResponse answers the given DER encoded OCSP request. Failures to process the request are reported as
+// OCSP error responses, so the returned bytes are always a valid response. Serial numbers without a revocation
+// entry are reported as good, the same as a CRL based responder would.
+func (self *CertRevocationManager) CreateOcspResponse(request []byte) ([]byte, error) {
+	ocspRequest, err := ocsp.ParseRequest(request)
+	if err != nil {
+		return ocsp.MalformedRequestErrorResponse, nil
+	}
+
+	signerCert, signer, err := self.getSigner()
+	if err != nil {
+		return ocsp.InternalErrorErrorResponse, err
+	}
+
+	if !isOcspRequestForIssuer(ocspRequest, signerCert) {
+		return ocsp.UnauthorizedErrorResponse, nil
+	}
+
+	revoked, err := self.getStatus(ocspRequest.SerialNumber, signerCert)
+	if err != nil {
+		return ocsp.InternalErrorErrorResponse, err
+	}
+
+	now := time.Now()
+	template := ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: ocspRequest.SerialNumber,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(IssuedOcspValidity),
+	}
+
+	if revoked != nil {
+		template.Status = ocsp.Revoked
+		template.RevokedAt = revoked.RevokedAt
+		template.RevocationReason = revoked.Reason
+	}
+
+	response, err := ocsp.CreateResponse(signerCert, signerCert, template, signer)
+	if err != nil {
+		return ocsp.InternalErrorErrorResponse, fmt.Errorf("unable to sign OCSP response (%w)", err)
+	}
+	return response, nil
+}
+
+// isOcspRequestForIssuer checks the issuer name and key hashes of the request against the given issuer
+func isOcspRequestForIssuer(request *ocsp.Request, issuer *x509.Certificate) bool {
+	if !request.HashAlgorithm.Available() {
+		return false
+	}
+
+	var publicKeyInfo struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &publicKeyInfo); err != nil {
+		return false
+	}
+
+	nameHash := request.HashAlgorithm.New()
+	nameHash.Write(issuer.RawSubject)
+
+	keyHash := request.HashAlgorithm.New()
+	keyHash.Write(publicKeyInfo.PublicKey.RightAlign())
+
+	return bytes.Equal(nameHash.Sum(nil), request.IssuerNameHash) && bytes.Equal(keyHash.Sum(nil), request.IssuerKeyHash)
+}
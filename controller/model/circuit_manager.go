@@ -75,7 +75,7 @@ func (self *Circuit) HasRouter(routerId string) bool {
 	if self == nil || self.Path == nil {
 		return false
 	}
-	for _, node := range self.Path.Nodes {
+	for _, node := range self.Path.AllNodes() {
 		if node.Id == routerId {
 			return true
 		}
@@ -19,6 +19,7 @@ package model
 import (
 	"errors"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	"github.com/michaelquigley/pfxlog"
@@ -90,12 +91,14 @@ type CommandManager struct {
 	backgroundDelayThreshold time.Duration
 	droppedEntries           metrics.Meter
 	backgroundWorkTimer      metrics.Timer
+	batches                  sync.Map
 }
 
 func (self *CommandManager) registerGenericCommands() {
 	self.Decoders.RegisterF(int32(cmd_pb.CommandType_CreateEntityType), self.decodeCreateEntityCommand)
 	self.Decoders.RegisterF(int32(cmd_pb.CommandType_UpdateEntityType), self.decodeUpdateEntityCommand)
 	self.Decoders.RegisterF(int32(cmd_pb.CommandType_DeleteEntityType), self.decodeDeleteEntityCommand)
+	self.Decoders.RegisterF(int32(cmd_pb.CommandType_BatchType), self.decodeBatchCommand)
 }
 
 func (self *CommandManager) decodeCreateEntityCommand(_ int32, data []byte) (command.Command, error) {
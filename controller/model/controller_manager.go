@@ -34,6 +34,9 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// singleControllerId is the id of the controller built by buildSelfController in non-HA deployments
+const singleControllerId = "single-controller"
+
 func NewControllerManager(env Env) *ControllerManager {
 	manager := &ControllerManager{
 		baseEntityManager: newBaseEntityManager[*Controller, *db.Controller](env, env.GetStores().Controller),
@@ -122,7 +125,7 @@ func (self *ControllerManager) buildSelfController() (*Controller, error) {
 
 	return &Controller{
 		BaseEntity: models.BaseEntity{
-			Id: "single-controller",
+			Id: singleControllerId,
 		},
 		Name:        cert.Subject.CommonName,
 		CertPem:     nfpem.EncodeToString(cert),
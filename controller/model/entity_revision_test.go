@@ -0,0 +1,72 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/openziti/ziti/v2/common/eid"
+	"github.com/openziti/ziti/v2/controller/apierror"
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/models"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityRevisionPreconditions(t *testing.T) {
+	ctx := NewTestContext(t)
+	defer ctx.Cleanup()
+
+	// the test context encodes and decodes commands, so the If-Match must survive dispatch as it does in a cluster
+	identity := ctx.requireNewIdentity(false)
+
+	readTag := func(req *require.Assertions) string {
+		current, err := ctx.managers.Identity.Read(identity.Id)
+		req.NoError(err)
+		return boltz.EntityTag(current.Revision)
+	}
+
+	t.Run("updates with the current tag succeed", func(t *testing.T) {
+		req := require.New(t)
+		req.Equal(`"1"`, readTag(req))
+
+		identity.Name = eid.New()
+		req.NoError(ctx.managers.Identity.Update(identity, nil, change.New().SetIfMatch(identity.Id, `"1"`)))
+		req.Equal(`"2"`, readTag(req))
+	})
+
+	t.Run("updates with a stale tag fail with precondition failed", func(t *testing.T) {
+		req := require.New(t)
+		identity.Name = eid.New()
+		err := ctx.managers.Identity.Update(identity, nil, change.New().SetIfMatch(identity.Id, `"1"`))
+		req.Error(err)
+
+		apiErr := models.ToApiError(err)
+		req.Equal(http.StatusPreconditionFailed, apiErr.Status)
+		req.Equal(apierror.PreconditionFailedCode, apiErr.AppCode)
+		req.Equal(`"2"`, readTag(req))
+	})
+
+	t.Run("deletes are conditional too", func(t *testing.T) {
+		req := require.New(t)
+		err := ctx.managers.Identity.Delete(identity.Id, change.New().SetIfMatch(identity.Id, `"1"`))
+		req.Equal(http.StatusPreconditionFailed, models.ToApiError(err).Status)
+
+		req.NoError(ctx.managers.Identity.Delete(identity.Id, change.New().SetIfMatch(identity.Id, `"2"`)))
+	})
+}
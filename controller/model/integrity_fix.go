@@ -0,0 +1,86 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"github.com/openziti/ziti/v2/common/pb/cmd_pb"
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+)
+
+// FixIntegrityIssues applies the fix actions of the data integrity issues with the given ids. The fixes are
+// dispatched as a command, so in an HA cluster every controller re-runs the checks against its copy of the data
+// model and applies the same fixes. The results are taken from the local data model before the command is
+// dispatched.
+func (self *CommandManager) FixIntegrityIssues(issueIds []string, ctx *change.Context) ([]*db.IntegrityFixResult, error) {
+	results, err := self.env.GetStores().GetIntegrityFixResults(self.env.GetDb(), ctx.GetContext(), issueIds)
+	if err != nil {
+		return nil, err
+	}
+
+	fixable := false
+	for _, result := range results {
+		fixable = fixable || result.Fixed
+	}
+
+	if !fixable {
+		return results, nil
+	}
+
+	cmd := &FixIntegrityIssuesCommand{
+		Context:  ctx,
+		Env:      self.env,
+		IssueIds: issueIds,
+	}
+
+	if err = self.Dispatch(cmd); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// FixIntegrityIssuesCommand applies the fix actions of the data integrity issues with the given ids
+type FixIntegrityIssuesCommand struct {
+	Context  *change.Context
+	Env      Env
+	IssueIds []string
+}
+
+func (self *FixIntegrityIssuesCommand) Apply(ctx boltz.MutateContext) error {
+	_, err := self.Env.GetStores().FixIntegrityIssues(self.Env.GetDb(), ctx, self.IssueIds)
+	return err
+}
+
+func (self *FixIntegrityIssuesCommand) Encode() ([]byte, error) {
+	return cmd_pb.EncodeProtobuf(&cmd_pb.FixIntegrityIssuesCommand{
+		IssueIds: self.IssueIds,
+		Ctx:      self.Context.ToProtoBuf(),
+	})
+}
+
+func (self *FixIntegrityIssuesCommand) Decode(env Env, msg *cmd_pb.FixIntegrityIssuesCommand) error {
+	self.Env = env
+	self.IssueIds = msg.IssueIds
+	self.Context = change.FromProtoBuf(msg.Ctx)
+	return nil
+}
+
+func (self *FixIntegrityIssuesCommand) GetChangeContext() *change.Context {
+	return self.Context
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+func TestFixIntegrityIssues(t *testing.T) {
+	ctx := NewTestContext(t)
+	defer ctx.Cleanup()
+
+	identity := ctx.requireNewIdentity(false)
+	service := ctx.requireNewService()
+	policy := ctx.requireNewServicePolicy(db.PolicyTypeDialName, ss("@"+identity.Id), ss("@"+service.Id))
+
+	identityLinks := ctx.GetStores().ServicePolicy.GetLinkCollection(db.EntityTypeIdentities)
+
+	policyIssues := func(req *require.Assertions) []*db.IntegrityIssue {
+		issues, _, err := ctx.GetStores().CheckExtendedIntegrity(ctx.GetDb(), change.New().GetContext(), 0)
+		req.NoError(err)
+
+		var result []*db.IntegrityIssue
+		for _, issue := range issues {
+			if issue.Check == db.IntegrityCheckPolicyRoles && issue.EntityId == policy.Id {
+				result = append(result, issue)
+			}
+		}
+		return result
+	}
+
+	t.Run("fixes are applied through a command", func(t *testing.T) {
+		req := require.New(t)
+
+		req.NoError(ctx.GetDb().Update(change.New().NewMutateContext(), func(mutateCtx boltz.MutateContext) error {
+			return identityLinks.RemoveLinks(mutateCtx.Tx(), policy.Id, identity.Id)
+		}))
+
+		issues := policyIssues(req)
+		req.Len(issues, 1)
+
+		results, err := ctx.GetManagers().Command.FixIntegrityIssues([]string{issues[0].Id, "not-an-issue"}, change.New())
+		req.NoError(err)
+		req.Len(results, 2)
+		req.True(results[0].Fixed, results[0].Error)
+		req.Equal(issues[0].FixAction.Id, results[0].FixActionId)
+		req.False(results[1].Fixed)
+		req.NotEmpty(results[1].Error)
+
+		req.Empty(policyIssues(req))
+		req.NoError(ctx.GetDb().View(func(tx *bbolt.Tx) error {
+			req.Contains(identityLinks.GetLinks(tx, policy.Id), identity.Id)
+			return nil
+		}))
+	})
+
+	t.Run("nothing is dispatched when no issues can be fixed", func(t *testing.T) {
+		req := require.New(t)
+
+		results, err := ctx.GetManagers().Command.FixIntegrityIssues([]string{"not-an-issue"}, change.New())
+		req.NoError(err)
+		req.Len(results, 1)
+		req.False(results[0].Fixed)
+	})
+}
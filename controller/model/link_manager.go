@@ -233,12 +233,21 @@ func (self *LinkManager) ConnectedNeighborsOfRouter(router *Router) []*Router {
 }
 
 func (self *LinkManager) LeastExpensiveLink(a, b *Router) (*Link, bool) {
+	return self.LeastExpensiveLinkExcluding(a, b, nil)
+}
+
+// LeastExpensiveLinkExcluding returns the least expensive usable link between the two routers, ignoring any links
+// whose ids are in the excluded set
+func (self *LinkManager) LeastExpensiveLinkExcluding(a, b *Router, excluded map[string]struct{}) (*Link, bool) {
 	var selected *Link
 	var cost int64 = math.MaxInt64
 
 	linksByRouter := a.routerLinks.GetLinksByRouter()
 	links := linksByRouter[b.Id]
 	for _, link := range links {
+		if _, skip := excluded[link.Id]; skip {
+			continue
+		}
 		if link.IsUsable() {
 			linkCost := link.GetCost()
 			if link.DstId == b.Id {
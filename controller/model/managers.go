@@ -50,6 +50,7 @@ type Managers struct {
 	ApiSession              *ApiSessionManager
 	ApiSessionCertificate   *ApiSessionCertificateManager
 	Ca                      *CaManager
+	CertRevocation          *CertRevocationManager
 	Config                  *ConfigManager
 	ConfigType              *ConfigTypeManager
 	Controller              *ControllerManager
@@ -65,6 +66,7 @@ type Managers struct {
 	Revocation              *RevocationManager
 	TransitRouter           *TransitRouterManager
 	Session                 *SessionManager
+	SigningKey              *SigningKeyManager
 	Authenticator           *AuthenticatorManager
 	Enrollment              *EnrollmentManager
 	PostureCheck            *PostureCheckManager
@@ -98,6 +100,7 @@ func (managers *Managers) Init(env Env) *Managers {
 	managers.Authenticator = NewAuthenticatorManager(env)
 	managers.AuthPolicy = NewAuthPolicyManager(env)
 	managers.Ca = NewCaManager(env)
+	managers.CertRevocation = NewCertRevocationManager(env)
 	managers.Config = NewConfigManager(env)
 	managers.ConfigType = NewConfigTypeManager(env)
 	managers.Controller = NewControllerManager(env)
@@ -113,6 +116,7 @@ func (managers *Managers) Init(env Env) *Managers {
 	managers.ServiceEdgeRouterPolicy = NewServiceEdgeRouterPolicyManager(env)
 	managers.ServicePolicy = NewServicePolicyManager(env)
 	managers.Session = NewSessionManager(env)
+	managers.SigningKey = NewSigningKeyManager(env)
 	managers.TransitRouter = NewTransitRouterManager(env)
 	managers.PostureCheck = NewPostureCheckManager(env)
 	managers.PostureCheckType = NewPostureCheckTypeManager(env)
@@ -120,6 +124,7 @@ func (managers *Managers) Init(env Env) *Managers {
 	managers.Mfa = NewMfaManager(env)
 
 	RegisterCommand(env, &CreateEdgeTerminatorCmd{}, &edge_cmd_pb.CreateEdgeTerminatorCommand{})
+	RegisterCommand(env, &FixIntegrityIssuesCommand{}, &cmd_pb.FixIntegrityIssuesCommand{})
 	managers.Command.registerGenericCommands()
 
 	return managers
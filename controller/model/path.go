@@ -29,6 +29,13 @@ type Path struct {
 	InitiatorRemoteAddr  string
 	TerminatorLocalAddr  string
 	TerminatorRemoteAddr string
+	// Secondary is an additional, link-disjoint path between the same endpoint routers, used by multipath
+	// circuits. It shares the ingress and egress ids of the primary path. Nil for single path circuits.
+	Secondary *Path
+	// MultipathMode is the mode used to distribute traffic across the primary and secondary paths. It's set for
+	// circuits of multipath services even when no secondary path is currently available, so that one can be added
+	// when the path is next updated.
+	MultipathMode string
 }
 
 func (self *Path) IsValid() bool {
@@ -58,10 +65,49 @@ func (self *Path) String() string {
 		out += fmt.Sprintf("->[l/%s]", self.Links[i].Id)
 		out += fmt.Sprintf("->[r/%s]", self.Nodes[i+1].Id)
 	}
+	if self.IsMultipath() {
+		out += fmt.Sprintf(" + %s (%s)", self.Secondary.String(), self.MultipathMode)
+	}
 	return out
 }
 
+// IsMultipath returns true if the path has a secondary path
+func (self *Path) IsMultipath() bool {
+	return self != nil && self.Secondary != nil
+}
+
+// AllNodes returns every router on the primary and secondary paths, without duplicates. The egress router is
+// always last, matching the ordering of Nodes for single path circuits.
+func (self *Path) AllNodes() []*Router {
+	if !self.IsMultipath() || len(self.Nodes) == 0 {
+		return self.Nodes
+	}
+
+	seen := map[string]struct{}{}
+	var result []*Router
+	add := func(nodes []*Router) {
+		for _, r := range nodes {
+			if _, found := seen[r.Id]; !found {
+				seen[r.Id] = struct{}{}
+				result = append(result, r)
+			}
+		}
+	}
+
+	egress := self.EgressRouter()
+	seen[egress.Id] = struct{}{}
+	add(self.Nodes)
+	add(self.Secondary.Nodes)
+	return append(result, egress)
+}
+
 func (self *Path) EqualPath(other *Path) bool {
+	if self.IsMultipath() != other.IsMultipath() {
+		return false
+	}
+	if self.IsMultipath() && !self.Secondary.EqualPath(other.Secondary) {
+		return false
+	}
 	if len(self.Nodes) != len(other.Nodes) {
 		return false
 	}
@@ -96,5 +142,5 @@ func (self *Path) UsesLink(l *Link) bool {
 			}
 		}
 	}
-	return false
+	return self.IsMultipath() && self.Secondary.UsesLink(l)
 }
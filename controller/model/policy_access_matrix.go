@@ -0,0 +1,206 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"sort"
+
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/storage/ast"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"go.etcd.io/bbolt"
+)
+
+type AdvisorMatrixEdgeRouter struct {
+	AdvisorEntityRef
+	IsOnline bool `json:"isOnline"`
+}
+
+// AdvisorAccessMatrixEntry describes the effective access an identity has to a service
+type AdvisorAccessMatrixEntry struct {
+	Identity          AdvisorEntityRef           `json:"identity"`
+	Service           AdvisorEntityRef           `json:"service"`
+	Dial              bool                       `json:"dial"`
+	Bind              bool                       `json:"bind"`
+	DialPostureChecks []AdvisorEntityRef         `json:"dialPostureChecks"`
+	BindPostureChecks []AdvisorEntityRef         `json:"bindPostureChecks"`
+	EdgeRouters       []*AdvisorMatrixEdgeRouter `json:"edgeRouters"`
+}
+
+type matrixServicePolicy struct {
+	policyType    string
+	services      idSet
+	postureChecks []string
+}
+
+// AccessMatrix reports, for every identity and service matching the given filters, the effective dial and bind
+// permissions, the posture checks which apply to them and the edge routers the identity and service have in common.
+// Only identity/service pairs with dial or bind access are included. Empty filters match everything.
+func (advisor *PolicyAdvisor) AccessMatrix(identityFilter, serviceFilter string) ([]*AdvisorAccessMatrixEntry, error) {
+	stores := advisor.env.GetStores()
+
+	identityPredicate, err := advisor.parseMatrixFilter(stores.Identity, identityFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	servicePredicate, err := advisor.parseMatrixFilter(stores.Service, serviceFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*AdvisorAccessMatrixEntry
+
+	err = advisor.env.GetDb().View(func(tx *bbolt.Tx) error {
+		names := map[string]string{}
+		ref := func(id string, store boltz.Store) AdvisorEntityRef {
+			name, found := names[id]
+			if !found {
+				if val := boltz.FieldToString(store.GetSymbol(db.FieldName).Eval(tx, []byte(id))); val != nil {
+					name = *val
+				}
+				names[id] = name
+			}
+			return AdvisorEntityRef{Id: id, Name: name}
+		}
+
+		services := idSet{}
+		for cursor := stores.Service.IterateIds(tx, servicePredicate); cursor.IsValid(); cursor.Next() {
+			services[string(cursor.Current())] = struct{}{}
+		}
+
+		serviceEdgeRouters := map[string]idSet{}
+		policies := map[string]*matrixServicePolicy{}
+		policyTypeSymbol := stores.ServicePolicy.GetSymbol(db.FieldServicePolicyType)
+
+		for cursor := stores.Identity.IterateIds(tx, identityPredicate); cursor.IsValid(); cursor.Next() {
+			identityId := string(cursor.Current())
+			dial := newIdSet(stores.Identity.GetRelatedEntitiesIdList(tx, identityId, db.FieldIdentityDialServices))
+			bind := newIdSet(stores.Identity.GetRelatedEntitiesIdList(tx, identityId, db.FieldIdentityBindServices))
+
+			var accessible []string
+			for serviceId := range services {
+				_, canDial := dial[serviceId]
+				_, canBind := bind[serviceId]
+				if canDial || canBind {
+					accessible = append(accessible, serviceId)
+				}
+			}
+
+			if len(accessible) == 0 {
+				continue
+			}
+
+			var identityPolicies []*matrixServicePolicy
+			for _, policyId := range stores.Identity.GetRelatedEntitiesIdList(tx, identityId, db.EntityTypeServicePolicies) {
+				policy, found := policies[policyId]
+				if !found {
+					policy = &matrixServicePolicy{
+						policyType:    db.PolicyTypeDialName,
+						services:      newIdSet(stores.ServicePolicy.GetRelatedEntitiesIdList(tx, policyId, db.EntityTypeServices)),
+						postureChecks: stores.ServicePolicy.GetRelatedEntitiesIdList(tx, policyId, db.EntityTypePostureChecks),
+					}
+					if fieldType, val := policyTypeSymbol.Eval(tx, []byte(policyId)); fieldType == boltz.TypeString {
+						policy.policyType = string(val)
+					}
+					policies[policyId] = policy
+				}
+				identityPolicies = append(identityPolicies, policy)
+			}
+
+			identityEdgeRouters := newIdSet(stores.Identity.GetRelatedEntitiesIdList(tx, identityId, db.EntityTypeRouters))
+
+			for _, serviceId := range accessible {
+				_, canDial := dial[serviceId]
+				_, canBind := bind[serviceId]
+
+				entry := &AdvisorAccessMatrixEntry{
+					Identity: ref(identityId, stores.Identity),
+					Service:  ref(serviceId, stores.Service),
+					Dial:     canDial,
+					Bind:     canBind,
+				}
+
+				dialChecks, bindChecks := idSet{}, idSet{}
+				for _, policy := range identityPolicies {
+					if _, found := policy.services[serviceId]; !found {
+						continue
+					}
+					checks := dialChecks
+					if policy.policyType == db.PolicyTypeBindName {
+						checks = bindChecks
+					}
+					for _, checkId := range policy.postureChecks {
+						checks[checkId] = struct{}{}
+					}
+				}
+
+				for _, checkId := range sortedIds(dialChecks) {
+					entry.DialPostureChecks = append(entry.DialPostureChecks, ref(checkId, stores.PostureCheck))
+				}
+
+				for _, checkId := range sortedIds(bindChecks) {
+					entry.BindPostureChecks = append(entry.BindPostureChecks, ref(checkId, stores.PostureCheck))
+				}
+
+				edgeRouters, found := serviceEdgeRouters[serviceId]
+				if !found {
+					edgeRouters = newIdSet(stores.Service.GetRelatedEntitiesIdList(tx, serviceId, db.FieldEdgeRouters))
+					serviceEdgeRouters[serviceId] = edgeRouters
+				}
+
+				for _, edgeRouterId := range sortedIds(edgeRouters) {
+					if _, found = identityEdgeRouters[edgeRouterId]; found {
+						entry.EdgeRouters = append(entry.EdgeRouters, &AdvisorMatrixEdgeRouter{
+							AdvisorEntityRef: ref(edgeRouterId, stores.Router),
+							IsOnline:         advisor.env.IsEdgeRouterOnline(edgeRouterId),
+						})
+					}
+				}
+
+				result = append(result, entry)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Identity.Name != result[j].Identity.Name {
+			return result[i].Identity.Name < result[j].Identity.Name
+		}
+		return result[i].Service.Name < result[j].Service.Name
+	})
+
+	return result, nil
+}
+
+func (advisor *PolicyAdvisor) parseMatrixFilter(store boltz.Store, filter string) (ast.BoolNode, error) {
+	if filter == "" {
+		return ast.BoolNodeTrue, nil
+	}
+
+	query, err := ast.Parse(store, filter)
+	if err != nil {
+		return nil, err
+	}
+	return query.GetPredicate(), nil
+}
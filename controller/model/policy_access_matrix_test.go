@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyAccessMatrix(t *testing.T) {
+	ctx := NewTestContext(t)
+	defer ctx.Cleanup()
+	ctx.Init()
+
+	managers := ctx.GetManagers()
+
+	edgeRouter := ctx.requireNewEdgeRouter()
+	dialer := ctx.requireNewIdentity(false)
+	host := ctx.requireNewIdentity(false)
+	outsider := ctx.requireNewIdentity(false)
+	service := ctx.requireNewService()
+	otherService := ctx.requireNewService()
+
+	ctx.requireNewServicePolicy(db.PolicyTypeDialName, ss("@"+dialer.Id), ss("@"+service.Id, "@"+otherService.Id))
+	ctx.requireNewServicePolicy(db.PolicyTypeBindName, ss("@"+host.Id), ss("@"+service.Id))
+	ctx.requireNewEdgeRouterPolicy(ss("@"+dialer.Id), ss("@"+edgeRouter.Id))
+	ctx.requireNewServiceNewEdgeRouterPolicy(ss("@"+service.Id), ss("@"+edgeRouter.Id))
+
+	t.Run("pairs with access are reported", func(t *testing.T) {
+		req := require.New(t)
+
+		entries, err := managers.PolicyAdvisor.AccessMatrix("", "")
+		req.NoError(err)
+
+		byPair := map[string]*AdvisorAccessMatrixEntry{}
+		for _, entry := range entries {
+			byPair[entry.Identity.Id+"/"+entry.Service.Id] = entry
+			req.NotEqual(outsider.Id, entry.Identity.Id)
+		}
+		req.Len(byPair, 3)
+
+		entry := byPair[dialer.Id+"/"+service.Id]
+		req.NotNil(entry)
+		req.True(entry.Dial)
+		req.False(entry.Bind)
+		req.Equal(dialer.Name, entry.Identity.Name)
+		req.Len(entry.EdgeRouters, 1)
+		req.Equal(edgeRouter.Id, entry.EdgeRouters[0].Id)
+
+		entry = byPair[dialer.Id+"/"+otherService.Id]
+		req.NotNil(entry)
+		req.Empty(entry.EdgeRouters)
+
+		entry = byPair[host.Id+"/"+service.Id]
+		req.NotNil(entry)
+		req.True(entry.Bind)
+		req.False(entry.Dial)
+		req.Empty(entry.EdgeRouters)
+	})
+
+	t.Run("filters limit the report", func(t *testing.T) {
+		req := require.New(t)
+
+		entries, err := managers.PolicyAdvisor.AccessMatrix(fmt.Sprintf(`name = "%v"`, dialer.Name), fmt.Sprintf(`name = "%v"`, otherService.Name))
+		req.NoError(err)
+		req.Len(entries, 1)
+		req.Equal(dialer.Id, entries[0].Identity.Id)
+		req.Equal(otherService.Id, entries[0].Service.Id)
+	})
+
+	t.Run("invalid filters are rejected", func(t *testing.T) {
+		req := require.New(t)
+		_, err := managers.PolicyAdvisor.AccessMatrix("not a filter", "")
+		req.Error(err)
+	})
+}
@@ -0,0 +1,312 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/openziti/ziti/v2/controller/command"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/storage/ast"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	AdvisorChangeGained = "gained"
+	AdvisorChangeLost   = "lost"
+)
+
+// errSimulationComplete is returned from the simulation transaction to make sure it's rolled back
+var errSimulationComplete = errors.New("policy simulation complete")
+
+type AdvisorEntityRef struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AdvisorServiceAccessChange reports a dial or bind permission which an identity would gain or lose
+type AdvisorServiceAccessChange struct {
+	Identity   AdvisorEntityRef `json:"identity"`
+	Service    AdvisorEntityRef `json:"service"`
+	PolicyType string           `json:"policyType"`
+	Change     string           `json:"change"`
+}
+
+// AdvisorEdgeRouterAccessChange reports an edge router which an identity would gain or lose access to
+type AdvisorEdgeRouterAccessChange struct {
+	Identity   AdvisorEntityRef `json:"identity"`
+	EdgeRouter AdvisorEntityRef `json:"edgeRouter"`
+	Change     string           `json:"change"`
+}
+
+// AdvisorServiceEdgeRouterChange reports an edge router which a service would gain or lose access to
+type AdvisorServiceEdgeRouterChange struct {
+	Service    AdvisorEntityRef `json:"service"`
+	EdgeRouter AdvisorEntityRef `json:"edgeRouter"`
+	Change     string           `json:"change"`
+}
+
+// AdvisorServiceReachabilityChange reports an identity which would gain or lose the ability to reach a service it
+// has dial or bind access to. A service is reachable if the identity and service have at least one edge router in
+// common.
+type AdvisorServiceReachabilityChange struct {
+	Identity AdvisorEntityRef `json:"identity"`
+	Service  AdvisorEntityRef `json:"service"`
+	Change   string           `json:"change"`
+}
+
+type AdvisorSimulationResult struct {
+	ServiceAccess      []*AdvisorServiceAccessChange       `json:"serviceAccess"`
+	EdgeRouterAccess   []*AdvisorEdgeRouterAccessChange    `json:"edgeRouterAccess"`
+	ServiceEdgeRouters []*AdvisorServiceEdgeRouterChange   `json:"serviceEdgeRouters"`
+	Reachability       []*AdvisorServiceReachabilityChange `json:"reachability"`
+}
+
+// IsEmpty returns true if the simulated changes wouldn't change any access
+func (self *AdvisorSimulationResult) IsEmpty() bool {
+	return len(self.ServiceAccess) == 0 && len(self.EdgeRouterAccess) == 0 &&
+		len(self.ServiceEdgeRouters) == 0 && len(self.Reachability) == 0
+}
+
+// Simulate closes the batch and applies its commands to the local datastore, in a transaction which is always
+// rolled back. It reports the dial and bind permissions, edge router access and service reachability which would be
+// gained or lost across the network if the batch were dispatched. Posture checks are not evaluated.
+//
+// Because the simulation uses a write transaction, other writes to the local datastore wait for it to finish.
+func (advisor *PolicyAdvisor) Simulate(batch *Batch) (*AdvisorSimulationResult, error) {
+	batch.Discard()
+
+	var before, after *policySnapshot
+
+	ctx := batch.ChangeContext().NewMutateContext()
+	err := advisor.env.GetDb().Update(ctx, func(ctx boltz.MutateContext) error {
+		before = advisor.snapshot(ctx.Tx())
+		for idx, cmd := range batch.commands {
+			if validatable, ok := cmd.(command.Validatable); ok {
+				if err := validatable.Validate(); err != nil {
+					return &BatchError{Index: idx, Cause: err}
+				}
+			}
+			if err := cmd.Apply(ctx); err != nil {
+				return &BatchError{Index: idx, Cause: err}
+			}
+		}
+		after = advisor.snapshot(ctx.Tx())
+		return errSimulationComplete
+	})
+
+	if !errors.Is(err, errSimulationComplete) {
+		return nil, err
+	}
+
+	return before.diff(after), nil
+}
+
+type idSet map[string]struct{}
+
+func newIdSet(ids []string) idSet {
+	result := idSet{}
+	for _, id := range ids {
+		result[id] = struct{}{}
+	}
+	return result
+}
+
+func (self idSet) intersects(other idSet) bool {
+	for id := range self {
+		if _, found := other[id]; found {
+			return true
+		}
+	}
+	return false
+}
+
+type identityAccess struct {
+	dial        idSet
+	bind        idSet
+	edgeRouters idSet
+}
+
+// policySnapshot holds the denormalized policy links between identities, services and edge routers at a point in
+// time, along with the names of the linked entities
+type policySnapshot struct {
+	names              map[string]string
+	identities         map[string]*identityAccess
+	serviceEdgeRouters map[string]idSet
+}
+
+func (advisor *PolicyAdvisor) snapshot(tx *bbolt.Tx) *policySnapshot {
+	stores := advisor.env.GetStores()
+
+	result := &policySnapshot{
+		names:              map[string]string{},
+		identities:         map[string]*identityAccess{},
+		serviceEdgeRouters: map[string]idSet{},
+	}
+
+	identityName := stores.Identity.GetSymbol(db.FieldName)
+	for cursor := stores.Identity.IterateIds(tx, ast.BoolNodeTrue); cursor.IsValid(); cursor.Next() {
+		id := string(cursor.Current())
+		result.addName(id, boltz.FieldToString(identityName.Eval(tx, cursor.Current())))
+		result.identities[id] = &identityAccess{
+			dial:        newIdSet(stores.Identity.GetRelatedEntitiesIdList(tx, id, db.FieldIdentityDialServices)),
+			bind:        newIdSet(stores.Identity.GetRelatedEntitiesIdList(tx, id, db.FieldIdentityBindServices)),
+			edgeRouters: newIdSet(stores.Identity.GetRelatedEntitiesIdList(tx, id, db.EntityTypeRouters)),
+		}
+	}
+
+	serviceName := stores.Service.GetSymbol(db.FieldName)
+	for cursor := stores.Service.IterateIds(tx, ast.BoolNodeTrue); cursor.IsValid(); cursor.Next() {
+		id := string(cursor.Current())
+		result.addName(id, boltz.FieldToString(serviceName.Eval(tx, cursor.Current())))
+		result.serviceEdgeRouters[id] = newIdSet(stores.Service.GetRelatedEntitiesIdList(tx, id, db.FieldEdgeRouters))
+	}
+
+	routerName := stores.Router.GetSymbol(db.FieldName)
+	for cursor := stores.EdgeRouter.IterateIds(tx, ast.BoolNodeTrue); cursor.IsValid(); cursor.Next() {
+		result.addName(string(cursor.Current()), boltz.FieldToString(routerName.Eval(tx, cursor.Current())))
+	}
+
+	return result
+}
+
+func (self *policySnapshot) addName(id string, name *string) {
+	if name != nil {
+		self.names[id] = *name
+	}
+}
+
+// reachable returns the services the identity has dial or bind access to, and shares at least one edge router with
+func (self *policySnapshot) reachable(access *identityAccess) idSet {
+	result := idSet{}
+	for _, services := range []idSet{access.dial, access.bind} {
+		for serviceId := range services {
+			if access.edgeRouters.intersects(self.serviceEdgeRouters[serviceId]) {
+				result[serviceId] = struct{}{}
+			}
+		}
+	}
+	return result
+}
+
+func (self *policySnapshot) diff(after *policySnapshot) *AdvisorSimulationResult {
+	names := map[string]string{}
+	for id, name := range self.names {
+		names[id] = name
+	}
+	for id, name := range after.names {
+		names[id] = name
+	}
+
+	ref := func(id string) AdvisorEntityRef {
+		return AdvisorEntityRef{Id: id, Name: names[id]}
+	}
+
+	emptyAccess := &identityAccess{}
+	result := &AdvisorSimulationResult{}
+
+	for _, identityId := range unionKeys(self.identities, after.identities) {
+		beforeAccess, afterAccess := self.identities[identityId], after.identities[identityId]
+		if beforeAccess == nil {
+			beforeAccess = emptyAccess
+		}
+		if afterAccess == nil {
+			afterAccess = emptyAccess
+		}
+
+		diffIdSets(beforeAccess.dial, afterAccess.dial, func(serviceId, change string) {
+			result.ServiceAccess = append(result.ServiceAccess, &AdvisorServiceAccessChange{
+				Identity:   ref(identityId),
+				Service:    ref(serviceId),
+				PolicyType: db.PolicyTypeDialName,
+				Change:     change,
+			})
+		})
+
+		diffIdSets(beforeAccess.bind, afterAccess.bind, func(serviceId, change string) {
+			result.ServiceAccess = append(result.ServiceAccess, &AdvisorServiceAccessChange{
+				Identity:   ref(identityId),
+				Service:    ref(serviceId),
+				PolicyType: db.PolicyTypeBindName,
+				Change:     change,
+			})
+		})
+
+		diffIdSets(beforeAccess.edgeRouters, afterAccess.edgeRouters, func(edgeRouterId, change string) {
+			result.EdgeRouterAccess = append(result.EdgeRouterAccess, &AdvisorEdgeRouterAccessChange{
+				Identity:   ref(identityId),
+				EdgeRouter: ref(edgeRouterId),
+				Change:     change,
+			})
+		})
+
+		diffIdSets(self.reachable(beforeAccess), after.reachable(afterAccess), func(serviceId, change string) {
+			result.Reachability = append(result.Reachability, &AdvisorServiceReachabilityChange{
+				Identity: ref(identityId),
+				Service:  ref(serviceId),
+				Change:   change,
+			})
+		})
+	}
+
+	for _, serviceId := range unionKeys(self.serviceEdgeRouters, after.serviceEdgeRouters) {
+		diffIdSets(self.serviceEdgeRouters[serviceId], after.serviceEdgeRouters[serviceId], func(edgeRouterId, change string) {
+			result.ServiceEdgeRouters = append(result.ServiceEdgeRouters, &AdvisorServiceEdgeRouterChange{
+				Service:    ref(serviceId),
+				EdgeRouter: ref(edgeRouterId),
+				Change:     change,
+			})
+		})
+	}
+
+	return result
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	set := idSet{}
+	for k := range a {
+		set[k] = struct{}{}
+	}
+	for k := range b {
+		set[k] = struct{}{}
+	}
+	return sortedIds(set)
+}
+
+func sortedIds(set idSet) []string {
+	result := make([]string, 0, len(set))
+	for id := range set {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// diffIdSets calls f, in id order, for each id which was lost or gained between before and after
+func diffIdSets(before, after idSet, f func(id, change string)) {
+	for _, id := range unionKeys(before, after) {
+		_, inBefore := before[id]
+		_, inAfter := after[id]
+		if inBefore && !inAfter {
+			f(id, AdvisorChangeLost)
+		} else if !inBefore && inAfter {
+			f(id, AdvisorChangeGained)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/openziti/ziti/v2/common/eid"
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicySimulation(t *testing.T) {
+	ctx := NewTestContext(t)
+	defer ctx.Cleanup()
+	ctx.Init()
+
+	managers := ctx.GetManagers()
+
+	edgeRouter := ctx.requireNewEdgeRouter()
+	identity := ctx.requireNewIdentity(false)
+	service := ctx.requireNewService()
+	erp := ctx.requireNewEdgeRouterPolicy(ss("@"+identity.Id), ss("@"+edgeRouter.Id))
+	ctx.requireNewServiceNewEdgeRouterPolicy(ss("@"+service.Id), ss("@"+edgeRouter.Id))
+
+	simulate := func(f func(changeCtx *change.Context) error) (*AdvisorSimulationResult, error) {
+		changeCtx := change.New()
+		batch := managers.Command.NewBatch(changeCtx)
+		if err := f(changeCtx); err != nil {
+			batch.Discard()
+			return nil, err
+		}
+		return managers.PolicyAdvisor.Simulate(batch)
+	}
+
+	t.Run("gained access is reported and not committed", func(t *testing.T) {
+		req := require.New(t)
+
+		policy := &ServicePolicy{
+			Name:          eid.New(),
+			Semantic:      db.SemanticAllOf,
+			IdentityRoles: ss("@" + identity.Id),
+			ServiceRoles:  ss("@" + service.Id),
+			PolicyType:    db.PolicyTypeDialName,
+		}
+
+		result, err := simulate(func(changeCtx *change.Context) error {
+			return managers.ServicePolicy.Create(policy, changeCtx)
+		})
+		req.NoError(err)
+
+		req.Len(result.ServiceAccess, 1)
+		req.Equal(identity.Id, result.ServiceAccess[0].Identity.Id)
+		req.Equal(identity.Name, result.ServiceAccess[0].Identity.Name)
+		req.Equal(service.Id, result.ServiceAccess[0].Service.Id)
+		req.Equal(db.PolicyTypeDialName, result.ServiceAccess[0].PolicyType)
+		req.Equal(AdvisorChangeGained, result.ServiceAccess[0].Change)
+
+		req.Len(result.Reachability, 1)
+		req.Equal(AdvisorChangeGained, result.Reachability[0].Change)
+		req.Empty(result.EdgeRouterAccess)
+		req.Empty(result.ServiceEdgeRouters)
+
+		_, err = managers.ServicePolicy.Read(policy.Id)
+		req.True(boltz.IsErrNotFoundErr(err))
+	})
+
+	t.Run("lost access is reported", func(t *testing.T) {
+		req := require.New(t)
+
+		ctx.requireNewServicePolicy(db.PolicyTypeBindName, ss("@"+identity.Id), ss("@"+service.Id))
+
+		result, err := simulate(func(changeCtx *change.Context) error {
+			return managers.EdgeRouterPolicy.Delete(erp.Id, changeCtx)
+		})
+		req.NoError(err)
+
+		req.Empty(result.ServiceAccess)
+		req.Len(result.EdgeRouterAccess, 1)
+		req.Equal(edgeRouter.Id, result.EdgeRouterAccess[0].EdgeRouter.Id)
+		req.Equal(AdvisorChangeLost, result.EdgeRouterAccess[0].Change)
+
+		req.Len(result.Reachability, 1)
+		req.Equal(service.Id, result.Reachability[0].Service.Id)
+		req.Equal(AdvisorChangeLost, result.Reachability[0].Change)
+
+		_, err = managers.EdgeRouterPolicy.Read(erp.Id)
+		req.NoError(err)
+	})
+
+	t.Run("unchanged access isn't reported", func(t *testing.T) {
+		req := require.New(t)
+
+		result, err := simulate(func(changeCtx *change.Context) error {
+			return managers.ConfigType.Create(&ConfigType{Name: eid.New()}, changeCtx)
+		})
+		req.NoError(err)
+		req.True(result.IsEmpty())
+	})
+
+	t.Run("failed commands are reported", func(t *testing.T) {
+		req := require.New(t)
+
+		_, err := simulate(func(changeCtx *change.Context) error {
+			return managers.EdgeService.Create(&EdgeService{Name: service.Name}, changeCtx)
+		})
+
+		var batchErr *BatchError
+		req.ErrorAs(err, &batchErr)
+		req.Equal(0, batchErr.Index)
+	})
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/openziti/foundation/v2/versions"
 	"github.com/openziti/ziti/v2/common/capabilities"
 	"github.com/openziti/ziti/v2/common/ctrlchan"
+	"github.com/openziti/ziti/v2/common/locality"
 	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
 	"github.com/openziti/ziti/v2/controller/db"
 	"github.com/openziti/ziti/v2/controller/models"
@@ -54,7 +55,10 @@ type Router struct {
 	// VersionInfo is reported in the router's hello and is not persisted, so it is only populated on the
 	// instance built for a control-channel connection. It is nil on an instance loaded from the database.
 	// Read it from GetConnected rather than from whatever instance is to hand.
-	VersionInfo       *versions.VersionInfo
+	VersionInfo *versions.VersionInfo
+	// Locality is also reported in the router's hello, and like VersionInfo is only populated on the instance
+	// built for a control-channel connection.
+	Locality          locality.Locality
 	routerLinks       RouterLinks
 	Cost              uint16
 	NoTraversal       bool
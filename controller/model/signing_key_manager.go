@@ -0,0 +1,443 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/command"
+	"github.com/openziti/ziti/v2/controller/config"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/event"
+	"github.com/openziti/ziti/v2/controller/fields"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// SigningKeyAlgorithm is the JWS algorithm rotated signing keys are generated for
+	SigningKeyAlgorithm = "ES256"
+
+	signingKeyFileExt = ".key"
+)
+
+func NewSigningKeyManager(env Env) *SigningKeyManager {
+	manager := &SigningKeyManager{
+		baseEntityManager: newBaseEntityManager[*SigningKey, *db.SigningKey](env, env.GetStores().SigningKey),
+	}
+	manager.impl = manager
+
+	RegisterManagerDecoder[*SigningKey](env, manager)
+
+	return manager
+}
+
+// ActiveSigningKey is the rotated key a controller currently signs JWTs with
+type ActiveSigningKey struct {
+	Kid    string
+	Signer crypto.Signer
+}
+
+// SigningKeyManager tracks the rotating JWT signing keys of all controllers and rotates the keys owned by this
+// controller. Only public keys are stored, so they replicate to peer controllers and routers for verification. The
+// private keys are kept on disk by the controller which generated them.
+type SigningKeyManager struct {
+	baseEntityManager[*SigningKey, *db.SigningKey]
+	activeKey  atomic.Pointer[ActiveSigningKey]
+	rotateLock sync.Mutex
+}
+
+func (self *SigningKeyManager) Create(entity *SigningKey, ctx *change.Context) error {
+	return DispatchCreate[*SigningKey](self, entity, ctx)
+}
+
+func (self *SigningKeyManager) ApplyCreate(cmd *command.CreateEntityCommand[*SigningKey], ctx boltz.MutateContext) error {
+	_, err := self.createEntity(cmd.Entity, ctx)
+	return err
+}
+
+func (self *SigningKeyManager) Update(entity *SigningKey, checker fields.UpdatedFields, ctx *change.Context) error {
+	return DispatchUpdate[*SigningKey](self, entity, checker, ctx)
+}
+
+func (self *SigningKeyManager) ApplyUpdate(cmd *command.UpdateEntityCommand[*SigningKey], ctx boltz.MutateContext) error {
+	return self.updateEntity(cmd.Entity, cmd.UpdatedFields, ctx)
+}
+
+func (self *SigningKeyManager) NewModelEntity() *SigningKey {
+	return &SigningKey{}
+}
+
+func (self *SigningKeyManager) Read(id string) (*SigningKey, error) {
+	modelEntity := &SigningKey{}
+	if err := self.readEntity(id, modelEntity); err != nil {
+		return nil, err
+	}
+	return modelEntity, nil
+}
+
+// ReadAll returns the signing keys of all controllers
+func (self *SigningKeyManager) ReadAll() ([]*SigningKey, error) {
+	result, err := self.BaseList("true limit none")
+	if err != nil {
+		return nil, err
+	}
+	return result.GetEntities(), nil
+}
+
+func (self *SigningKeyManager) readForController(controllerId string) ([]*SigningKey, error) {
+	result, err := self.BaseList(fmt.Sprintf(`%s = "%s" limit none`, db.FieldSigningKeyControllerId, controllerId))
+	if err != nil {
+		return nil, err
+	}
+	return result.GetEntities(), nil
+}
+
+// Marshall encodes signing keys as JSON. They only hold strings and timestamps, so there's no need for a
+// dedicated protobuf message.
+func (self *SigningKeyManager) Marshall(entity *SigningKey) ([]byte, error) {
+	return json.Marshal(entity)
+}
+
+func (self *SigningKeyManager) Unmarshall(bytes []byte) (*SigningKey, error) {
+	result := &SigningKey{}
+	if err := json.Unmarshal(bytes, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetActiveKey returns the rotated key this controller signs JWTs with, or nil if rotation isn't enabled or no key
+// has been activated yet, in which case JWTs are signed with the key of the server certificate.
+func (self *SigningKeyManager) GetActiveKey() *ActiveSigningKey {
+	return self.activeKey.Load()
+}
+
+// LoadActiveKey loads the private key of this controller's active signing key, so that tokens are signed with it
+// from startup, rather than from the first rotation check.
+func (self *SigningKeyManager) LoadActiveKey(cfg *config.SigningKeyRotation) error {
+	keys, err := self.readForController(self.env.GetId())
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if key.State == db.SigningKeyStateActive {
+			return self.loadActiveKey(self.getKeyDir(cfg), key)
+		}
+	}
+
+	return nil
+}
+
+// Rotate moves this controller's signing keys through one step of scheduled rotation. A new key is published
+// PublishLead before the active key has been in use for the rotation interval. Once the new key activates, the
+// previous key is retired and kept for verification until the retention period passes, after which it's removed.
+func (self *SigningKeyManager) Rotate(cfg *config.SigningKeyRotation, now time.Time, ctx *change.Context) error {
+	self.rotateLock.Lock()
+	defer self.rotateLock.Unlock()
+
+	dir := self.getKeyDir(cfg)
+
+	keys, err := self.readForController(self.env.GetId())
+	if err != nil {
+		return err
+	}
+
+	var active, next *SigningKey
+	var errList []error
+
+	for _, key := range keys {
+		switch key.State {
+		case db.SigningKeyStateActive:
+			active = key
+		case db.SigningKeyStateNext:
+			next = key
+		case db.SigningKeyStateRetired:
+			if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+				if err = self.removeKey(dir, key, ctx); err != nil {
+					errList = append(errList, err)
+				}
+			}
+		}
+	}
+
+	replaceActive := active == nil || active.ActivatedAt == nil ||
+		!active.ActivatedAt.Add(cfg.Interval-cfg.PublishLead).After(now)
+
+	if active == nil {
+		self.activeKey.Store(nil)
+	} else if err = self.loadActiveKey(dir, active); err != nil {
+		// without the private key, tokens are signed with the server certificate until the replacement activates
+		self.activeKey.Store(nil)
+		self.emitEvent(event.SigningKeyFailed, active, err)
+		errList = append(errList, err)
+		replaceActive = true
+	}
+
+	if next == nil && replaceActive {
+		if next, err = self.generateKey(dir, now.Add(cfg.PublishLead), ctx); err != nil {
+			self.emitEvent(event.SigningKeyFailed, nil, err)
+			return errors.Join(append(errList, err)...)
+		}
+	}
+
+	if next != nil && !next.ActivatesAt.After(now) {
+		if err = self.activateKey(dir, next, active, self.getRetention(cfg), now, ctx); err != nil {
+			self.emitEvent(event.SigningKeyFailed, next, err)
+			errList = append(errList, err)
+		}
+	}
+
+	return errors.Join(errList...)
+}
+
+func (self *SigningKeyManager) getKeyDir(cfg *config.SigningKeyRotation) string {
+	if cfg.Dir != "" {
+		return cfg.Dir
+	}
+
+	var dbPath string
+	_ = self.env.GetDb().View(func(tx *bbolt.Tx) error {
+		dbPath = tx.DB().Path()
+		return nil
+	})
+	return filepath.Join(filepath.Dir(dbPath), "signing-keys")
+}
+
+// getRetention returns how long a retired key is kept. Tokens signed with it must keep verifying until they
+// expire, so it's never shorter than the longest lived OIDC token.
+func (self *SigningKeyManager) getRetention(cfg *config.SigningKeyRotation) time.Duration {
+	retention := cfg.Retention
+	if edgeConfig := self.env.GetConfig().Edge; edgeConfig != nil {
+		retention = max(retention, edgeConfig.Oidc.MaxTokenDuration())
+	}
+	return retention
+}
+
+func (self *SigningKeyManager) loadActiveKey(dir string, key *SigningKey) error {
+	if current := self.activeKey.Load(); current != nil && current.Kid == key.Id {
+		return nil
+	}
+
+	signer, err := readSigningKeyFile(dir, key.Id)
+	if err != nil {
+		return err
+	}
+
+	self.activeKey.Store(&ActiveSigningKey{
+		Kid:    key.Id,
+		Signer: signer,
+	})
+
+	pfxlog.Logger().WithField("kid", key.Id).Info("loaded active JWT signing key")
+	return nil
+}
+
+func (self *SigningKeyManager) generateKey(dir string, activatesAt time.Time, ctx *change.Context) (*SigningKey, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate signing key (%w)", err)
+	}
+
+	publicDer, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal signing key public key (%w)", err)
+	}
+
+	kid := fmt.Sprintf("%x", sha1.Sum(publicDer))
+
+	if err = writeSigningKeyFile(dir, kid, privateKey); err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{
+		ControllerId: self.env.GetId(),
+		PublicKey:    string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer})),
+		Algorithm:    SigningKeyAlgorithm,
+		State:        db.SigningKeyStateNext,
+		ActivatesAt:  activatesAt,
+	}
+	key.Id = kid
+
+	if err = self.Create(key, ctx); err != nil {
+		_ = os.Remove(signingKeyFilePath(dir, kid))
+		return nil, fmt.Errorf("unable to store signing key %s (%w)", kid, err)
+	}
+
+	pfxlog.Logger().WithField("kid", kid).WithField("activatesAt", activatesAt).Info("published new JWT signing key")
+	self.emitEvent(event.SigningKeyPublished, key, nil)
+
+	return key, nil
+}
+
+// activateKey starts signing with next. The previous key is retired first, so that a failure leaves this controller
+// signing with the previous key, or its server certificate, rather than with two active keys.
+func (self *SigningKeyManager) activateKey(dir string, next, previous *SigningKey, retention time.Duration, now time.Time, ctx *change.Context) error {
+	signer, err := readSigningKeyFile(dir, next.Id)
+	if err != nil {
+		// the key can never be activated, remove it so a replacement is generated
+		if deleteErr := self.Delete(next.Id, ctx); deleteErr != nil {
+			return errors.Join(err, deleteErr)
+		}
+		return err
+	}
+
+	if previous != nil {
+		expiresAt := now.Add(retention)
+		previous.State = db.SigningKeyStateRetired
+		previous.ExpiresAt = &expiresAt
+
+		checker := fields.UpdatedFieldsMap{
+			db.FieldSigningKeyState:     struct{}{},
+			db.FieldSigningKeyExpiresAt: struct{}{},
+		}
+		if err = self.Update(previous, checker, ctx); err != nil {
+			return fmt.Errorf("unable to retire signing key %s (%w)", previous.Id, err)
+		}
+		self.emitEvent(event.SigningKeyRetired, previous, nil)
+	}
+
+	next.State = db.SigningKeyStateActive
+	next.ActivatedAt = &now
+
+	checker := fields.UpdatedFieldsMap{
+		db.FieldSigningKeyState:       struct{}{},
+		db.FieldSigningKeyActivatedAt: struct{}{},
+	}
+	if err = self.Update(next, checker, ctx); err != nil {
+		return fmt.Errorf("unable to activate signing key %s (%w)", next.Id, err)
+	}
+
+	self.activeKey.Store(&ActiveSigningKey{
+		Kid:    next.Id,
+		Signer: signer,
+	})
+
+	pfxlog.Logger().WithField("kid", next.Id).Info("activated new JWT signing key")
+	self.emitEvent(event.SigningKeyActivated, next, nil)
+
+	return nil
+}
+
+func (self *SigningKeyManager) removeKey(dir string, key *SigningKey, ctx *change.Context) error {
+	if err := self.Delete(key.Id, ctx); err != nil {
+		return fmt.Errorf("unable to remove signing key %s (%w)", key.Id, err)
+	}
+
+	if err := os.Remove(signingKeyFilePath(dir, key.Id)); err != nil && !os.IsNotExist(err) {
+		pfxlog.Logger().WithError(err).WithField("kid", key.Id).Warn("unable to remove signing key file")
+	}
+
+	pfxlog.Logger().WithField("kid", key.Id).Info("removed expired JWT signing key")
+	self.emitEvent(event.SigningKeyRemoved, key, nil)
+
+	return nil
+}
+
+func (self *SigningKeyManager) emitEvent(eventType event.SigningKeyEventType, key *SigningKey, err error) {
+	evt := &event.SigningKeyEvent{
+		Namespace:  event.SigningKeyEventNS,
+		EventType:  eventType,
+		EventSrcId: self.env.GetId(),
+		Timestamp:  time.Now(),
+	}
+
+	if key != nil {
+		evt.Kid = key.Id
+		evt.Algorithm = key.Algorithm
+		if key.State == db.SigningKeyStateNext {
+			activatesAt := key.ActivatesAt
+			evt.ActivatesAt = &activatesAt
+		}
+		evt.ExpiresAt = key.ExpiresAt
+	}
+
+	if err != nil {
+		evt.Error = err.Error()
+	}
+
+	self.env.GetEventDispatcher().AcceptSigningKeyEvent(evt)
+}
+
+func signingKeyFilePath(dir, kid string) string {
+	return filepath.Join(dir, kid+signingKeyFileExt)
+}
+
+func writeSigningKeyFile(dir, kid string, key *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("unable to marshal signing key (%w)", err)
+	}
+
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("unable to create signing key directory %s (%w)", dir, err)
+	}
+
+	path := signingKeyFilePath(dir, kid)
+	if err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return fmt.Errorf("unable to write signing key %s (%w)", path, err)
+	}
+
+	return nil
+}
+
+func readSigningKeyFile(dir, kid string) (crypto.Signer, error) {
+	path := signingKeyFilePath(dir, kid)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read signing key %s (%w)", path, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", path)
+	}
+
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse signing key %s (%w)", path, err)
+	}
+
+	publicDer, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal signing key %s public key (%w)", path, err)
+	}
+
+	if fmt.Sprintf("%x", sha1.Sum(publicDer)) != kid {
+		return nil, fmt.Errorf("signing key %s doesn't match key id %s", path, kid)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,202 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/config"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/event"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningKeyFiles(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	publicDer, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	kid := fmt.Sprintf("%x", sha1.Sum(publicDer))
+
+	t.Run("keys are written with owner only permissions and read back", func(t *testing.T) {
+		req := require.New(t)
+		dir := t.TempDir()
+
+		req.NoError(writeSigningKeyFile(dir, kid, key))
+
+		info, err := os.Stat(signingKeyFilePath(dir, kid))
+		req.NoError(err)
+		req.Equal(os.FileMode(0600), info.Mode().Perm())
+
+		signer, err := readSigningKeyFile(dir, kid)
+		req.NoError(err)
+		req.True(key.PublicKey.Equal(signer.Public()))
+	})
+
+	t.Run("keys which don't match the key id are rejected", func(t *testing.T) {
+		req := require.New(t)
+		dir := t.TempDir()
+
+		req.NoError(writeSigningKeyFile(dir, kid, key))
+		req.NoError(os.Rename(signingKeyFilePath(dir, kid), signingKeyFilePath(dir, "other")))
+
+		_, err := readSigningKeyFile(dir, "other")
+		req.ErrorContains(err, "doesn't match key id")
+	})
+
+	t.Run("missing keys are reported", func(t *testing.T) {
+		req := require.New(t)
+		_, err := readSigningKeyFile(t.TempDir(), kid)
+		req.Error(err)
+	})
+}
+
+type signingKeyEventRecorder struct {
+	event.DispatcherMock
+	events []*event.SigningKeyEvent
+}
+
+func (self *signingKeyEventRecorder) AcceptSigningKeyEvent(evt *event.SigningKeyEvent) {
+	self.events = append(self.events, evt)
+}
+
+func (self *signingKeyEventRecorder) last() *event.SigningKeyEvent {
+	if len(self.events) == 0 {
+		return nil
+	}
+	return self.events[len(self.events)-1]
+}
+
+func TestSigningKeyRotation(t *testing.T) {
+	req := require.New(t)
+	ctx := NewTestContext(t)
+	defer ctx.Cleanup()
+
+	recorder := &signingKeyEventRecorder{}
+	ctx.eventDispatcher = recorder
+
+	// retired keys have to outlive the longest lived OIDC token, even with a shorter configured retention
+	ctx.config.Edge.Oidc.AccessTokenDuration = 30 * time.Minute
+	ctx.config.Edge.Oidc.RefreshTokenDuration = 48 * time.Hour
+
+	cfg := &config.SigningKeyRotation{
+		Enabled:     true,
+		Interval:    30 * 24 * time.Hour,
+		PublishLead: 24 * time.Hour,
+		Retention:   time.Hour,
+		Dir:         t.TempDir(),
+	}
+
+	manager := ctx.managers.SigningKey
+	changeCtx := change.New()
+
+	keysByState := func() map[string][]*SigningKey {
+		keys, err := manager.readForController(ctx.GetId())
+		req.NoError(err)
+		result := map[string][]*SigningKey{}
+		for _, key := range keys {
+			result[key.State] = append(result[key.State], key)
+		}
+		return result
+	}
+
+	now := time.Now()
+
+	// the first run publishes a key, which isn't used until the publish lead has passed
+	req.NoError(manager.Rotate(cfg, now, changeCtx))
+	keys := keysByState()
+	req.Len(keys[db.SigningKeyStateNext], 1)
+	req.Empty(keys[db.SigningKeyStateActive])
+	first := keys[db.SigningKeyStateNext][0]
+	req.True(first.ActivatesAt.Equal(now.Add(cfg.PublishLead)))
+	req.Nil(manager.GetActiveKey())
+	req.Equal(event.SigningKeyPublished, recorder.last().EventType)
+	req.FileExists(signingKeyFilePath(cfg.Dir, first.Id))
+
+	// nothing changes before the key is due
+	now = now.Add(cfg.PublishLead - time.Minute)
+	req.NoError(manager.Rotate(cfg, now, changeCtx))
+	req.Nil(manager.GetActiveKey())
+	req.Len(keysByState()[db.SigningKeyStateNext], 1)
+
+	// once due, the key is activated and used for signing
+	now = now.Add(time.Minute)
+	req.NoError(manager.Rotate(cfg, now, changeCtx))
+	keys = keysByState()
+	req.Empty(keys[db.SigningKeyStateNext])
+	req.Len(keys[db.SigningKeyStateActive], 1)
+	req.Equal(first.Id, keys[db.SigningKeyStateActive][0].Id)
+	req.NotNil(manager.GetActiveKey())
+	req.Equal(first.Id, manager.GetActiveKey().Kid)
+	req.Equal(event.SigningKeyActivated, recorder.last().EventType)
+
+	// the replacement is published the publish lead before the active key has been in use for the interval
+	now = now.Add(cfg.Interval - cfg.PublishLead)
+	req.NoError(manager.Rotate(cfg, now, changeCtx))
+	keys = keysByState()
+	req.Len(keys[db.SigningKeyStateNext], 1)
+	second := keys[db.SigningKeyStateNext][0]
+	req.NotEqual(first.Id, second.Id)
+	req.Equal(first.Id, manager.GetActiveKey().Kid)
+	req.Equal(event.SigningKeyPublished, recorder.last().EventType)
+
+	// activating the replacement retires the previous key, keeping it for the longest OIDC token duration
+	now = now.Add(cfg.PublishLead)
+	req.NoError(manager.Rotate(cfg, now, changeCtx))
+	keys = keysByState()
+	req.Len(keys[db.SigningKeyStateActive], 1)
+	req.Equal(second.Id, keys[db.SigningKeyStateActive][0].Id)
+	req.Equal(second.Id, manager.GetActiveKey().Kid)
+	req.Len(keys[db.SigningKeyStateRetired], 1)
+	retired := keys[db.SigningKeyStateRetired][0]
+	req.Equal(first.Id, retired.Id)
+	req.NotNil(retired.ExpiresAt)
+	req.True(retired.ExpiresAt.Equal(now.Add(ctx.config.Edge.Oidc.MaxTokenDuration())))
+
+	var eventTypes []event.SigningKeyEventType
+	for _, evt := range recorder.events[len(recorder.events)-2:] {
+		eventTypes = append(eventTypes, evt.EventType)
+	}
+	req.Equal([]event.SigningKeyEventType{event.SigningKeyRetired, event.SigningKeyActivated}, eventTypes)
+
+	// retired keys are kept for verification until they expire
+	expiresAt := *retired.ExpiresAt
+	now = expiresAt.Add(-time.Minute)
+	req.NoError(manager.Rotate(cfg, now, changeCtx))
+	req.Len(keysByState()[db.SigningKeyStateRetired], 1)
+	req.FileExists(signingKeyFilePath(cfg.Dir, first.Id))
+
+	// and are then removed, along with their private key
+	now = expiresAt
+	req.NoError(manager.Rotate(cfg, now, changeCtx))
+	keys = keysByState()
+	req.Empty(keys[db.SigningKeyStateRetired])
+	req.Len(keys[db.SigningKeyStateActive], 1)
+	req.NoFileExists(signingKeyFilePath(cfg.Dir, first.Id))
+	req.Equal(event.SigningKeyRemoved, recorder.last().EventType)
+	req.Equal(first.Id, recorder.last().Kid)
+}
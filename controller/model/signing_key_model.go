@@ -0,0 +1,82 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package model
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/models"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"go.etcd.io/bbolt"
+)
+
+type SigningKey struct {
+	models.BaseEntity
+	ControllerId string     `json:"controllerId"`
+	PublicKey    string     `json:"publicKey"`
+	Algorithm    string     `json:"algorithm"`
+	State        string     `json:"state"`
+	ActivatesAt  time.Time  `json:"activatesAt"`
+	ActivatedAt  *time.Time `json:"activatedAt,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+}
+
+func (entity *SigningKey) toBoltEntityForUpdate(tx *bbolt.Tx, env Env, _ boltz.FieldChecker) (*db.SigningKey, error) {
+	return entity.toBoltEntityForCreate(tx, env)
+}
+
+func (entity *SigningKey) fillFrom(_ Env, _ *bbolt.Tx, boltSigningKey *db.SigningKey) error {
+	entity.FillCommon(boltSigningKey)
+	entity.ControllerId = boltSigningKey.ControllerId
+	entity.PublicKey = boltSigningKey.PublicKey
+	entity.Algorithm = boltSigningKey.Algorithm
+	entity.State = boltSigningKey.State
+	entity.ActivatesAt = boltSigningKey.ActivatesAt
+	entity.ActivatedAt = boltSigningKey.ActivatedAt
+	entity.ExpiresAt = boltSigningKey.ExpiresAt
+
+	return nil
+}
+
+func (entity *SigningKey) toBoltEntityForCreate(*bbolt.Tx, Env) (*db.SigningKey, error) {
+	boltEntity := &db.SigningKey{
+		BaseExtEntity: *boltz.NewExtEntity(entity.Id, entity.Tags),
+		ControllerId:  entity.ControllerId,
+		PublicKey:     entity.PublicKey,
+		Algorithm:     entity.Algorithm,
+		State:         entity.State,
+		ActivatesAt:   entity.ActivatesAt,
+		ActivatedAt:   entity.ActivatedAt,
+		ExpiresAt:     entity.ExpiresAt,
+	}
+
+	return boltEntity, nil
+}
+
+// GetPublicKey decodes the PEM encoded public key
+func (entity *SigningKey) GetPublicKey() (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(entity.PublicKey))
+	if block == nil {
+		return nil, errors.New("signing key public key is not PEM encoded")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
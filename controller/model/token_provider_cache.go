@@ -107,6 +107,7 @@ func (a *TokenIssuerCache) onControllerCreate(controller *db.Controller) {
 			Chain:  certs},
 		kid:              fmt.Sprintf("%x", sha1.Sum(cert.Raw)),
 		controllerIssuer: "",
+		signingKeys:      a.env.GetManagers().SigningKey,
 	}
 	a.controllerIssuers.Set(controller.Id, controllerTokenIssuer)
 }
@@ -156,6 +157,7 @@ func (a *TokenIssuerCache) addControllerIssuer(controller *Controller) {
 		},
 		kid:              fmt.Sprintf("%x", sha1.Sum(cert.Raw)),
 		controllerIssuer: "",
+		signingKeys:      a.env.GetManagers().SigningKey,
 	}
 	a.controllerIssuers.Set(controller.Id, controllerTokenIssuer)
 }
@@ -998,6 +1000,7 @@ type ControllerTokenIssuer struct {
 	pubKey           common.IssuerPublicKey
 	kid              string
 	controllerIssuer string
+	signingKeys      *SigningKeyManager
 }
 
 // GetKids returns the single key ID derived from the controller's TLS certificate fingerprint.
@@ -1026,11 +1029,22 @@ func (o *ControllerTokenIssuer) IsEnabled() bool {
 	return true
 }
 
+// PubKeyByKid returns the public key of the controller's TLS certificate, or of one of the controller's rotated
+// signing keys.
 func (o *ControllerTokenIssuer) PubKeyByKid(kid string) (common.IssuerPublicKey, bool) {
 	if kid == o.kid {
 		return o.pubKey, true
 	}
 
+	if o.signingKeys != nil {
+		signingKey, _ := o.signingKeys.Read(kid)
+		if signingKey != nil && (signingKey.ControllerId == o.controllerId || o.controllerId == singleControllerId) {
+			if pubKey, err := signingKey.GetPublicKey(); err == nil {
+				return common.IssuerPublicKey{PubKey: pubKey}, true
+			}
+		}
+	}
+
 	return common.IssuerPublicKey{}, false
 }
 
@@ -66,6 +66,11 @@ type Entity interface {
 	IsSystemEntity() bool
 }
 
+// Versioned entities have a revision, which is incremented every time they are updated
+type Versioned interface {
+	GetRevision() int64
+}
+
 type NameIndexedStore interface {
 	boltz.Store
 	GetNameIndex() boltz.ReadIndex
@@ -77,6 +82,7 @@ type BaseEntity struct {
 	UpdatedAt time.Time
 	Tags      map[string]interface{}
 	IsSystem  bool
+	Revision  int64
 }
 
 func (entity *BaseEntity) GetId() string {
@@ -103,12 +109,17 @@ func (entity *BaseEntity) IsSystemEntity() bool {
 	return entity.IsSystem
 }
 
+func (entity *BaseEntity) GetRevision() int64 {
+	return entity.Revision
+}
+
 func (entity *BaseEntity) FillCommon(boltEntity boltz.ExtEntity) {
 	entity.Id = boltEntity.GetId()
 	entity.CreatedAt = boltEntity.GetCreatedAt()
 	entity.UpdatedAt = boltEntity.GetUpdatedAt()
 	entity.Tags = boltEntity.GetTags()
 	entity.IsSystem = boltEntity.IsSystemEntity()
+	entity.Revision = boltEntity.GetRevision()
 }
 
 func (entity *BaseEntity) ToBoltBaseExtEntity() *boltz.BaseExtEntity {
@@ -24,6 +24,11 @@ func ToApiErrorWithDefault(err error, f func(err error) *errorz.ApiError) *error
 		return result
 	}
 
+	var rme *boltz.RevisionMismatchError
+	if errors.As(err, &rme) {
+		return apierror.NewPreconditionFailed(rme)
+	}
+
 	var fe *errorz.FieldError
 	if errors.As(err, &fe) {
 		return errorz.NewFieldApiError(fe)
@@ -42,6 +42,15 @@ func (network *Network) fillCircuitPath(e *event.CircuitEvent, path *model.Path)
 	e.Path.TerminatorLocalAddr = path.TerminatorLocalAddr
 	e.Path.TerminatorRemoteAddr = path.TerminatorRemoteAddr
 	e.LinkCount = len(path.Links)
+	if path.IsMultipath() {
+		for _, r := range path.Secondary.Nodes {
+			e.Path.SecondaryNodes = append(e.Path.SecondaryNodes, r.Id)
+		}
+		for _, l := range path.Secondary.Links {
+			e.Path.SecondaryLinks = append(e.Path.SecondaryLinks, l.Id)
+		}
+	}
+	e.Path.MultipathMode = path.MultipathMode
 }
 
 func (network *Network) CircuitEvent(eventType event.CircuitEventType, circuit *model.Circuit, creationTimespan *time.Duration) {
@@ -98,6 +107,7 @@ const (
 	CircuitFailureRouterErrPortNotAllowed          CircuitFailureCause = "ROUTER_ERR_PORT_NOT_ALLOWED"
 	CircuitFailureRouterErrInvalidLinkDest         CircuitFailureCause = "ROUTER_ERR_INVALID_LINK_DESTINATION"
 	CircuitFailureRouterErrResourcesNotAvailable   CircuitFailureCause = "ROUTER_ERR_RESOURCES_NOT_AVAILABLE"
+	CircuitFailureQuotaExceeded                    CircuitFailureCause = "CIRCUIT_QUOTA_EXCEEDED"
 )
 
 type CircuitError interface {
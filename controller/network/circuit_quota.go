@@ -0,0 +1,215 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openziti/ziti/v2/common/alert"
+	"github.com/openziti/ziti/v2/controller/event"
+	"github.com/openziti/ziti/v2/controller/model"
+)
+
+// circuitQuotaAlertInterval limits how often a quota alert is emitted for a given client or service, so that a
+// client hammering the controller doesn't also flood the event stream
+const circuitQuotaAlertInterval = time.Minute
+
+const (
+	circuitQuotaClientIdentity = "identity"
+	circuitQuotaClientRouter   = "router"
+)
+
+// circuitQuotaClient identifies who a circuit is counted against. Circuits originated by a router, such as those
+// from tunneler enabled edge routers, carry the router id as their client id and are counted separately from
+// circuits dialed by identities.
+type circuitQuotaClient struct {
+	entityType string
+	id         string
+}
+
+type circuitQuotaKey struct {
+	client    circuitQuotaClient
+	serviceId string
+}
+
+type circuitQuotaViolation struct {
+	entityType string
+	entityId   string
+	limit      uint32
+}
+
+func (self *circuitQuotaViolation) Error() string {
+	return fmt.Sprintf("%s %s has reached its limit of %d concurrent circuits", self.entityType, self.entityId, self.limit)
+}
+
+// circuitQuotaTracker keeps counts of concurrent circuits per client and per service. Circuits are tracked by
+// id so that releasing a circuit is idempotent and doesn't depend on the circuit still carrying the same tags.
+// Counts only cover circuits created through this controller.
+type circuitQuotaTracker struct {
+	lock          sync.Mutex
+	circuits      map[string]circuitQuotaKey
+	clientCounts  map[circuitQuotaClient]uint32
+	serviceCounts map[string]uint32
+	lastAlert     map[string]time.Time
+}
+
+func newCircuitQuotaTracker() *circuitQuotaTracker {
+	return &circuitQuotaTracker{
+		circuits:      map[string]circuitQuotaKey{},
+		clientCounts:  map[circuitQuotaClient]uint32{},
+		serviceCounts: map[string]uint32{},
+		lastAlert:     map[string]time.Time{},
+	}
+}
+
+// acquire records the circuit against the client and service counts, unless doing so would exceed either limit.
+// A limit of zero means unlimited. A client with an empty id is not counted.
+func (self *circuitQuotaTracker) acquire(circuitId string, client circuitQuotaClient, clientLimit uint32, serviceId string, serviceLimit uint32) *circuitQuotaViolation {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if client.id != "" && clientLimit > 0 && self.clientCounts[client] >= clientLimit {
+		return &circuitQuotaViolation{entityType: client.entityType, entityId: client.id, limit: clientLimit}
+	}
+
+	if serviceLimit > 0 && self.serviceCounts[serviceId] >= serviceLimit {
+		return &circuitQuotaViolation{entityType: "service", entityId: serviceId, limit: serviceLimit}
+	}
+
+	if client.id != "" {
+		self.clientCounts[client]++
+	}
+	self.serviceCounts[serviceId]++
+	self.circuits[circuitId] = circuitQuotaKey{client: client, serviceId: serviceId}
+	return nil
+}
+
+func (self *circuitQuotaTracker) release(circuitId string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	key, found := self.circuits[circuitId]
+	if !found {
+		return
+	}
+	delete(self.circuits, circuitId)
+
+	if key.client.id != "" {
+		decrementCount(self.clientCounts, key.client)
+	}
+	decrementCount(self.serviceCounts, key.serviceId)
+}
+
+// shouldAlert returns true if an alert hasn't been emitted for the given violation in the last alert interval
+func (self *circuitQuotaTracker) shouldAlert(violation *circuitQuotaViolation, now time.Time) bool {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	alertKey := violation.entityType + "/" + violation.entityId
+	if last, found := self.lastAlert[alertKey]; found && now.Sub(last) < circuitQuotaAlertInterval {
+		return false
+	}
+	self.lastAlert[alertKey] = now
+
+	for k, v := range self.lastAlert {
+		if now.Sub(v) >= circuitQuotaAlertInterval {
+			delete(self.lastAlert, k)
+		}
+	}
+	return true
+}
+
+func decrementCount[K comparable](m map[K]uint32, key K) {
+	if count := m[key]; count > 1 {
+		m[key] = count - 1
+	} else {
+		delete(m, key)
+	}
+}
+
+// getCircuitQuotaClient works out who a circuit should be counted against. Router originated circuits use the
+// source router's id as their client id.
+func getCircuitQuotaClient(params model.CreateCircuitParams) circuitQuotaClient {
+	clientId := params.GetCircuitTags(nil)["clientId"]
+	if sourceRouter := params.GetSourceRouter(); clientId != "" && sourceRouter != nil && sourceRouter.Id == clientId {
+		return circuitQuotaClient{entityType: circuitQuotaClientRouter, id: clientId}
+	}
+	return circuitQuotaClient{entityType: circuitQuotaClientIdentity, id: clientId}
+}
+
+func (network *Network) acquireCircuitQuota(circuitId string, params model.CreateCircuitParams, serviceId string) CircuitError {
+	quotas := &network.options.CircuitQuotas
+	if !quotas.IsEnabled() {
+		return nil
+	}
+
+	client := getCircuitQuotaClient(params)
+
+	var clientLimit uint32
+	if client.entityType == circuitQuotaClientRouter {
+		clientLimit = quotas.GetRouterLimit(client.id)
+	} else {
+		clientLimit = quotas.GetIdentityLimit(client.id, func() string {
+			if client.id == "" {
+				return ""
+			}
+			identity, err := network.Identity.Read(client.id)
+			if err != nil {
+				return ""
+			}
+			return identity.IdentityTypeId
+		})
+	}
+	serviceLimit := quotas.GetServiceLimit(serviceId)
+
+	violation := network.circuitQuotas.acquire(circuitId, client, clientLimit, serviceId, serviceLimit)
+	if violation == nil {
+		return nil
+	}
+
+	now := time.Now()
+	if network.circuitQuotas.shouldAlert(violation, now) {
+		relatedEntities := map[string]string{
+			"service": serviceId,
+		}
+		if client.id != "" {
+			relatedEntities[client.entityType] = client.id
+		}
+
+		network.eventDispatcher.AcceptAlertEvent(&event.AlertEvent{
+			Namespace:       event.AlertEventNS,
+			EventSrcId:      network.GetAppId(),
+			Timestamp:       now,
+			AlertSourceType: event.AlertSourceTypeController,
+			AlertSourceId:   network.GetAppId(),
+			Severity:        alert.SeverityWarning,
+			Message:         "circuit quota exceeded",
+			Details:         []string{violation.Error()},
+			RelatedEntities: relatedEntities,
+		})
+	}
+
+	return newCircuitErrWrap(CircuitFailureQuotaExceeded, violation)
+}
+
+func (network *Network) releaseCircuitQuota(circuitId string) {
+	if network.options.CircuitQuotas.IsEnabled() {
+		network.circuitQuotas.release(circuitId)
+	}
+}
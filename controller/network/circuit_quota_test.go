@@ -0,0 +1,120 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openziti/ziti/v2/controller/model"
+	"github.com/openziti/ziti/v2/controller/xt"
+	"github.com/stretchr/testify/require"
+)
+
+func quotaIdentity(id string) circuitQuotaClient {
+	return circuitQuotaClient{entityType: circuitQuotaClientIdentity, id: id}
+}
+
+func quotaRouter(id string) circuitQuotaClient {
+	return circuitQuotaClient{entityType: circuitQuotaClientRouter, id: id}
+}
+
+func TestCircuitQuotaTracker(t *testing.T) {
+	t.Run("identity limit is enforced and released", func(t *testing.T) {
+		req := require.New(t)
+		tracker := newCircuitQuotaTracker()
+
+		req.Nil(tracker.acquire("c1", quotaIdentity("i1"), 2, "s1", 0))
+		req.Nil(tracker.acquire("c2", quotaIdentity("i1"), 2, "s1", 0))
+
+		violation := tracker.acquire("c3", quotaIdentity("i1"), 2, "s1", 0)
+		req.NotNil(violation)
+		req.Equal("identity", violation.entityType)
+		req.Equal("i1", violation.entityId)
+
+		// other identities aren't affected
+		req.Nil(tracker.acquire("c4", quotaIdentity("i2"), 2, "s1", 0))
+
+		tracker.release("c1")
+		tracker.release("c1")
+		req.Nil(tracker.acquire("c3", quotaIdentity("i1"), 2, "s1", 0))
+		req.Equal(uint32(2), tracker.clientCounts[quotaIdentity("i1")])
+	})
+
+	t.Run("router circuits are counted separately from identity circuits", func(t *testing.T) {
+		req := require.New(t)
+		tracker := newCircuitQuotaTracker()
+
+		req.Nil(tracker.acquire("c1", quotaRouter("r1"), 1, "s1", 0))
+		req.Nil(tracker.acquire("c2", quotaIdentity("r1"), 1, "s1", 0))
+
+		violation := tracker.acquire("c3", quotaRouter("r1"), 1, "s1", 0)
+		req.NotNil(violation)
+		req.Equal("router", violation.entityType)
+		req.Equal("r1", violation.entityId)
+
+		tracker.release("c1")
+		req.Nil(tracker.acquire("c3", quotaRouter("r1"), 1, "s1", 0))
+	})
+
+	t.Run("service limit is enforced", func(t *testing.T) {
+		req := require.New(t)
+		tracker := newCircuitQuotaTracker()
+
+		req.Nil(tracker.acquire("c1", quotaIdentity("i1"), 0, "s1", 1))
+		violation := tracker.acquire("c2", quotaIdentity("i2"), 0, "s1", 1)
+		req.NotNil(violation)
+		req.Equal("service", violation.entityType)
+
+		// a failed acquire must not leave a count behind
+		req.Equal(uint32(0), tracker.clientCounts[quotaIdentity("i2")])
+
+		tracker.release("c1")
+		req.Empty(tracker.serviceCounts)
+		req.Empty(tracker.circuits)
+	})
+
+	t.Run("alerts are rate limited per entity", func(t *testing.T) {
+		req := require.New(t)
+		tracker := newCircuitQuotaTracker()
+		now := time.Now()
+
+		v1 := &circuitQuotaViolation{entityType: "identity", entityId: "i1", limit: 1}
+		v2 := &circuitQuotaViolation{entityType: "service", entityId: "s1", limit: 1}
+
+		req.True(tracker.shouldAlert(v1, now))
+		req.False(tracker.shouldAlert(v1, now.Add(time.Second)))
+		req.True(tracker.shouldAlert(v2, now.Add(time.Second)))
+		req.True(tracker.shouldAlert(v1, now.Add(circuitQuotaAlertInterval)))
+	})
+}
+
+type quotaTestCircuitParams struct {
+	testCreateCircuitParams
+	clientId string
+}
+
+func (self quotaTestCircuitParams) GetCircuitTags(xt.CostedTerminator) map[string]string {
+	return map[string]string{"clientId": self.clientId}
+}
+
+func TestGetCircuitQuotaClient(t *testing.T) {
+	router := &model.Router{}
+	router.Id = "r1"
+
+	t.Run("circuits dialed by identities are counted against the identity", func(t *testing.T) {
+		req := require.New(t)
+		params := quotaTestCircuitParams{testCreateCircuitParams: testCreateCircuitParams{router: router}, clientId: "i1"}
+		req.Equal(quotaIdentity("i1"), getCircuitQuotaClient(params))
+	})
+
+	t.Run("circuits originated by the source router are counted against the router", func(t *testing.T) {
+		req := require.New(t)
+		params := quotaTestCircuitParams{testCreateCircuitParams: testCreateCircuitParams{router: router}, clientId: "r1"}
+		req.Equal(quotaRouter("r1"), getCircuitQuotaClient(params))
+	})
+
+	t.Run("circuits without a client id aren't counted against a client", func(t *testing.T) {
+		req := require.New(t)
+		params := quotaTestCircuitParams{testCreateCircuitParams: testCreateCircuitParams{router: router}}
+		req.Equal(quotaIdentity(""), getCircuitQuotaClient(params))
+	})
+}
@@ -0,0 +1,279 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package network
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/ziti/v2/controller/config"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/event"
+	"go.etcd.io/bbolt"
+)
+
+const dbBackupMarker = "-backup-"
+
+// RunDbBackups makes scheduled backups of the controller database until the network is shut down. Each backup is
+// optionally verified and compressed, and old backups are removed according to the configured retention policy.
+func (network *Network) RunDbBackups(cfg *config.DbBackupConfig) {
+	log := pfxlog.Logger().WithField("dir", network.getDbBackupDir(cfg))
+
+	for {
+		next := cfg.NextBackupTime(time.Now())
+		log.WithField("next", next).Info("scheduled next database backup")
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			network.BackupDatabase(cfg)
+		case <-network.closeNotify:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// BackupDatabase makes a single backup of the controller database using the given backup configuration, then prunes
+// old backups. The outcome is reported as a backup event.
+func (network *Network) BackupDatabase(cfg *config.DbBackupConfig) {
+	start := time.Now()
+	evt := &event.BackupEvent{
+		Namespace: event.BackupEventNS,
+		Timestamp: start,
+	}
+
+	err := network.backupDatabase(cfg, evt)
+	evt.Duration = time.Since(start)
+
+	log := pfxlog.Logger().WithField("path", evt.Path).WithField("duration", evt.Duration)
+	if err != nil {
+		evt.EventType = event.BackupFailed
+		evt.Error = err.Error()
+		log.WithError(err).Error("database backup failed")
+	} else {
+		evt.EventType = event.BackupCompleted
+		log.WithField("size", evt.Size).WithField("raftIndex", evt.RaftIndex).Info("database backup completed")
+	}
+	network.eventDispatcher.AcceptBackupEvent(evt)
+
+	network.pruneDbBackups(cfg)
+}
+
+func (network *Network) getDbPath() string {
+	var path string
+	_ = network.GetDb().View(func(tx *bbolt.Tx) error {
+		path = tx.DB().Path()
+		return nil
+	})
+	return path
+}
+
+func (network *Network) getDbBackupDir(cfg *config.DbBackupConfig) string {
+	if cfg.Dir != "" {
+		return cfg.Dir
+	}
+	return filepath.Join(filepath.Dir(network.getDbPath()), "backups")
+}
+
+func (network *Network) getDbBackupPrefix() string {
+	return filepath.Base(network.getDbPath()) + dbBackupMarker
+}
+
+func (network *Network) backupDatabase(cfg *config.DbBackupConfig, evt *event.BackupEvent) error {
+	dir := network.getDbBackupDir(cfg)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("unable to create backup directory %s (%w)", dir, err)
+	}
+
+	path := filepath.Join(dir, network.getDbBackupPrefix()+time.Now().Format("20060102-150405"))
+	evt.Path = path
+
+	err := network.GetDb().View(func(tx *bbolt.Tx) error {
+		evt.RaftIndex = db.LoadCurrentRaftIndex(tx)
+		var err error
+		path, _, err = network.GetDb().SnapshotInTx(tx, path)
+		return err
+	})
+
+	if err != nil {
+		removeDbBackup(evt.Path)
+		return err
+	}
+	evt.Path = path
+
+	if cfg.Verify {
+		if err = verifyDbBackup(path); err != nil {
+			removeDbBackup(path)
+			return fmt.Errorf("backup failed integrity check (%w)", err)
+		}
+		evt.Verified = true
+	}
+
+	if cfg.Compress {
+		compressedPath := path + ".gz"
+		if err = compressDbBackup(path, compressedPath); err != nil {
+			removeDbBackup(compressedPath)
+			removeDbBackup(path)
+			return fmt.Errorf("unable to compress backup (%w)", err)
+		}
+		removeDbBackup(path)
+		evt.Path = compressedPath
+		evt.Compressed = true
+	}
+
+	info, err := os.Stat(evt.Path)
+	if err != nil {
+		return err
+	}
+	evt.Size = info.Size()
+
+	return nil
+}
+
+func (network *Network) pruneDbBackups(cfg *config.DbBackupConfig) {
+	if cfg.RetentionCount == 0 && cfg.RetentionAge == 0 {
+		return
+	}
+
+	dir := network.getDbBackupDir(cfg)
+	log := pfxlog.Logger().WithField("dir", dir)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.WithError(err).Error("unable to list database backups for pruning")
+		return
+	}
+
+	type backupFile struct {
+		path    string
+		modTime time.Time
+		size    int64
+	}
+
+	prefix := network.getDbBackupPrefix()
+	var backups []*backupFile
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			log.WithError(err).WithField("file", entry.Name()).Error("unable to stat database backup")
+			continue
+		}
+		backups = append(backups, &backupFile{
+			path:    filepath.Join(dir, entry.Name()),
+			modTime: info.ModTime(),
+			size:    info.Size(),
+		})
+	}
+
+	// newest first
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+
+	now := time.Now()
+	for idx, backup := range backups {
+		expired := cfg.RetentionAge > 0 && now.Sub(backup.modTime) > cfg.RetentionAge
+		overCount := cfg.RetentionCount > 0 && idx >= cfg.RetentionCount
+		if !expired && !overCount {
+			continue
+		}
+
+		if err = os.Remove(backup.path); err != nil {
+			log.WithError(err).WithField("path", backup.path).Error("unable to remove old database backup")
+			continue
+		}
+
+		log.WithField("path", backup.path).Info("removed old database backup")
+		network.eventDispatcher.AcceptBackupEvent(&event.BackupEvent{
+			Namespace:  event.BackupEventNS,
+			EventType:  event.BackupPruned,
+			Timestamp:  now,
+			Path:       backup.path,
+			Size:       backup.size,
+			Compressed: strings.HasSuffix(backup.path, ".gz"),
+		})
+	}
+}
+
+// verifyDbBackup opens the backup read-only and checks the consistency of all pages
+func verifyDbBackup(path string) error {
+	backupDb, err := bbolt.Open(path, 0600, &bbolt.Options{ReadOnly: true, Timeout: time.Second})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = backupDb.Close()
+	}()
+
+	return backupDb.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(db.RootBucket)) == nil {
+			return fmt.Errorf("backup is missing the %s bucket", db.RootBucket)
+		}
+
+		// the check channel has to be drained, otherwise the check goroutine never finishes
+		var firstErr error
+		for err := range tx.Check() {
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	})
+}
+
+func compressDbBackup(path, compressedPath string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	dst, err := os.OpenFile(compressedPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(dst)
+	if _, err = io.Copy(writer, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+func removeDbBackup(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		pfxlog.Logger().WithError(err).WithField("path", path).Error("unable to remove database backup file")
+	}
+}
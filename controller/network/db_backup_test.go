@@ -0,0 +1,251 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package network
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openziti/ziti/v2/controller/config"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/event"
+	"github.com/openziti/ziti/v2/controller/model"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+type backupEventRecorder struct {
+	event.DispatcherMock
+	events []*event.BackupEvent
+}
+
+func (self *backupEventRecorder) AcceptBackupEvent(evt *event.BackupEvent) {
+	self.events = append(self.events, evt)
+}
+
+func (self *backupEventRecorder) ofType(eventType event.BackupEventType) []*event.BackupEvent {
+	var result []*event.BackupEvent
+	for _, evt := range self.events {
+		if evt.EventType == eventType {
+			result = append(result, evt)
+		}
+	}
+	return result
+}
+
+func newBackupTestNetwork(t *testing.T) (*Network, *backupEventRecorder) {
+	ctx := model.NewTestContext(t)
+	t.Cleanup(ctx.Cleanup)
+
+	testConfig := newTestConfig(ctx)
+	t.Cleanup(func() { close(testConfig.closeNotify) })
+
+	network, err := NewNetwork(testConfig, ctx)
+	require.NoError(t, err)
+
+	recorder := &backupEventRecorder{}
+	network.eventDispatcher = recorder
+	return network, recorder
+}
+
+func TestBackupDatabase(t *testing.T) {
+	t.Run("verified and compressed backups can be restored", func(t *testing.T) {
+		req := require.New(t)
+		network, recorder := newBackupTestNetwork(t)
+
+		network.BackupDatabase(&config.DbBackupConfig{
+			Dir:      t.TempDir(),
+			Verify:   true,
+			Compress: true,
+		})
+
+		completed := recorder.ofType(event.BackupCompleted)
+		req.Len(completed, 1)
+		evt := completed[0]
+		req.True(evt.Verified)
+		req.True(evt.Compressed)
+		req.True(strings.HasSuffix(evt.Path, ".gz"))
+		req.True(evt.Size > 0)
+
+		// only the compressed backup is kept
+		_, err := os.Stat(strings.TrimSuffix(evt.Path, ".gz"))
+		req.True(os.IsNotExist(err))
+
+		restoredPath := filepath.Join(t.TempDir(), "restored.db")
+		req.NoError(decompressDbBackup(evt.Path, restoredPath))
+		req.NoError(verifyDbBackup(restoredPath))
+	})
+
+	t.Run("backups failing verification are reported and removed", func(t *testing.T) {
+		req := require.New(t)
+		network, recorder := newBackupTestNetwork(t)
+
+		// without the root bucket the backup can't be used to restore a controller
+		err := network.GetDb().Update(nil, func(ctx boltz.MutateContext) error {
+			return ctx.Tx().DeleteBucket([]byte(db.RootBucket))
+		})
+		req.NoError(err)
+
+		dir := t.TempDir()
+		network.BackupDatabase(&config.DbBackupConfig{
+			Dir:    dir,
+			Verify: true,
+		})
+
+		req.Empty(recorder.ofType(event.BackupCompleted))
+		failed := recorder.ofType(event.BackupFailed)
+		req.Len(failed, 1)
+		req.Contains(failed[0].Error, "integrity check")
+		req.False(failed[0].Verified)
+
+		entries, err := os.ReadDir(dir)
+		req.NoError(err)
+		req.Empty(entries)
+	})
+}
+
+func TestVerifyDbBackup(t *testing.T) {
+	t.Run("a database without the root bucket fails", func(t *testing.T) {
+		req := require.New(t)
+		path := filepath.Join(t.TempDir(), "empty.db")
+		emptyDb, err := bbolt.Open(path, 0600, nil)
+		req.NoError(err)
+		req.NoError(emptyDb.Close())
+
+		req.ErrorContains(verifyDbBackup(path), db.RootBucket)
+	})
+
+	t.Run("a file which isn't a database fails", func(t *testing.T) {
+		req := require.New(t)
+		path := filepath.Join(t.TempDir(), "garbage.db")
+		req.NoError(os.WriteFile(path, []byte(strings.Repeat("not a bolt db", 1000)), 0600))
+
+		req.Error(verifyDbBackup(path))
+	})
+}
+
+func TestPruneDbBackups(t *testing.T) {
+	// createBackups writes fake backups which are the given ages old, returning their paths
+	createBackups := func(t *testing.T, network *Network, dir string, ages ...time.Duration) []string {
+		req := require.New(t)
+		var result []string
+		for idx, age := range ages {
+			path := filepath.Join(dir, network.getDbBackupPrefix()+time.Now().Add(-age).Format("20060102-150405"))
+			if idx%2 == 1 {
+				path += ".gz"
+			}
+			req.NoError(os.WriteFile(path, []byte("backup"), 0600))
+			modTime := time.Now().Add(-age)
+			req.NoError(os.Chtimes(path, modTime, modTime))
+			result = append(result, path)
+		}
+		return result
+	}
+
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	t.Run("backups over the retention count are removed oldest first", func(t *testing.T) {
+		req := require.New(t)
+		network, recorder := newBackupTestNetwork(t)
+		dir := t.TempDir()
+
+		backups := createBackups(t, network, dir, time.Hour, 2*time.Hour, 3*time.Hour, 4*time.Hour)
+		unrelated := filepath.Join(dir, "unrelated.db")
+		req.NoError(os.WriteFile(unrelated, []byte("other"), 0600))
+		req.NoError(os.Chtimes(unrelated, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour)))
+
+		network.pruneDbBackups(&config.DbBackupConfig{Dir: dir, RetentionCount: 2})
+
+		req.True(exists(backups[0]))
+		req.True(exists(backups[1]))
+		req.False(exists(backups[2]))
+		req.False(exists(backups[3]))
+		req.True(exists(unrelated))
+
+		pruned := recorder.ofType(event.BackupPruned)
+		req.Len(pruned, 2)
+		req.Equal(backups[2], pruned[0].Path)
+		req.False(pruned[0].Compressed)
+		req.Equal(backups[3], pruned[1].Path)
+		req.True(pruned[1].Compressed)
+	})
+
+	t.Run("backups older than the retention age are removed", func(t *testing.T) {
+		req := require.New(t)
+		network, recorder := newBackupTestNetwork(t)
+		dir := t.TempDir()
+
+		backups := createBackups(t, network, dir, time.Hour, 25*time.Hour, 49*time.Hour)
+
+		network.pruneDbBackups(&config.DbBackupConfig{Dir: dir, RetentionAge: 24 * time.Hour})
+
+		req.True(exists(backups[0]))
+		req.False(exists(backups[1]))
+		req.False(exists(backups[2]))
+		req.Len(recorder.ofType(event.BackupPruned), 2)
+	})
+
+	t.Run("nothing is removed without a retention policy", func(t *testing.T) {
+		req := require.New(t)
+		network, recorder := newBackupTestNetwork(t)
+		dir := t.TempDir()
+
+		backups := createBackups(t, network, dir, time.Hour, 100*time.Hour)
+
+		network.pruneDbBackups(&config.DbBackupConfig{Dir: dir})
+
+		for _, backup := range backups {
+			req.True(exists(backup))
+		}
+		req.Empty(recorder.events)
+	})
+}
+
+func decompressDbBackup(compressedPath, path string) error {
+	src, err := os.Open(compressedPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	reader, err := gzip.NewReader(src)
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(dst, reader); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
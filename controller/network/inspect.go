@@ -198,6 +198,10 @@ func (ctx *inspectRequestContext) InspectLocal(name string) {
 			}
 			ctx.handleLocalJsonResponse(name, members)
 		}
+	} else if lc == inspect.ClusterMemberStatusKey {
+		if raftController, ok := ctx.network.Dispatcher.(*raft.Controller); ok {
+			ctx.handleLocalJsonResponse(name, raftController.GetRaftStatus())
+		}
 	} else if lc == inspect.PeerDialerKey {
 		if raftController, ok := ctx.network.Dispatcher.(*raft.Controller); ok {
 			matched, val, err := raftController.InspectPeerDialer(name)
@@ -118,6 +118,8 @@ type Network struct {
 	RouterMessaging     *RouterMessaging
 	inspectionTargets   concurrenz.CopyOnWriteSlice[InspectTarget]
 	ctrlDialerValidator CtrlDialerValidator
+	circuitQuotas       *circuitQuotaTracker
+	linkLoads           *linkLoadTracker
 }
 
 func NewNetwork(config Config, env model.Env) (*Network, error) {
@@ -154,7 +156,9 @@ func NewNetwork(config Config, env model.Env) (*Network, error) {
 		serviceInvalidTerminatorCounter:           serviceEventMetrics.IntervalCounter("service.dial.terminator.invalid", time.Minute),
 		serviceMisconfiguredTerminatorCounter:     serviceEventMetrics.IntervalCounter("service.dial.terminator.misconfigured", time.Minute),
 
-		config: config,
+		config:        config,
+		circuitQuotas: newCircuitQuotaTracker(),
+		linkLoads:     newLinkLoadTracker(),
 	}
 
 	env.GetManagers().Command.Decoders.RegisterF(int32(cmd_pb.CommandType_SyncSnapshot), network.decodeSyncSnapshotCommand)
@@ -706,9 +710,18 @@ func (network *Network) CreateCircuit(params model.CreateCircuitParams) (*model.
 	defer func() {
 		if removeReserved {
 			network.Circuit.Remove(circuit)
+			network.releaseCircuitQuota(circuitId)
 		}
 	}()
 
+	// Enforce concurrent circuit limits before doing any path selection or routing work
+	if quotaErr := network.acquireCircuitQuota(circuitId, params, serviceId); quotaErr != nil {
+		logger.WithError(quotaErr).Warn("circuit quota exceeded")
+		network.CircuitFailedEvent(circuitId, params, startTime, nil, nil, quotaErr.Cause())
+		network.ServiceDialOtherError(serviceId)
+		return circuit, quotaErr
+	}
+
 	attempt := uint32(0)
 	allCleanups := make(map[string]struct{})
 	rs := network.newRouteSender(circuitId)
@@ -741,6 +754,12 @@ func (network *Network) CreateCircuit(params model.CreateCircuitParams) (*model.
 			return circuit, pathErr
 		}
 
+		network.balancePath(path)
+
+		if mode := network.options.Multipath.GetServiceMode(svc.Id); mode != "" {
+			network.addSecondaryPath(path, mode)
+		}
+
 		circuit.Path = path
 
 		// get circuit tags
@@ -751,12 +770,13 @@ func (network *Network) CreateCircuit(params model.CreateCircuitParams) (*model.
 		// 4a: Create Route Messages
 		rms := network.CreateRouteMessages(path, attempt, circuitId, terminator, deadline)
 		rms[len(rms)-1].Egress.PeerData = clientId.Data
+		routeTags := getRouteTags(tags, path)
 		for _, msg := range rms {
 			msg.Context = &ctrl_pb.Context{
 				Fields:      ctx.GetStringFields(),
 				ChannelMask: ctx.GetChannelsMask(),
 			}
-			msg.Tags = tags
+			msg.Tags = routeTags
 		}
 
 		// 5: Routing
@@ -794,7 +814,7 @@ func (network *Network) CreateCircuit(params model.CreateCircuitParams) (*model.
 
 		// 5.a: Unroute Abandoned Routers (from Previous Attempts)
 		usedRouters := make(map[string]struct{})
-		for _, r := range path.Nodes {
+		for _, r := range path.AllNodes() {
 			usedRouters[r.Id] = struct{}{}
 		}
 		cleanupCount := 0
@@ -835,6 +855,7 @@ func (network *Network) CreateCircuit(params model.CreateCircuitParams) (*model.
 		circuit.UpdatedAt = now
 
 		removeReserved = false // circuit is finalized, don't remove on defer
+		network.updateCircuitLinkLoads(circuit)
 		creationTimespan := time.Since(startTime)
 		network.CircuitEvent(event.CircuitCreated, circuit, &creationTimespan)
 		strategy.NotifyEvent(xt.NewDialSucceeded(terminator))
@@ -974,7 +995,7 @@ func (network *Network) RemoveCircuit(circuitId string, now bool) error {
 	log := pfxlog.Logger().WithField("circuitId", circuitId)
 
 	if circuit, found := network.Circuit.Get(circuitId); found {
-		for _, r := range circuit.Path.Nodes {
+		for _, r := range circuit.Path.AllNodes() {
 			err := sendUnroute(r, circuit.Id, now)
 			if err != nil {
 				log.Errorf("error sending unroute to [r/%s] (%s)", r.Id, err)
@@ -982,6 +1003,8 @@ func (network *Network) RemoveCircuit(circuitId string, now bool) error {
 		}
 
 		network.Circuit.Remove(circuit)
+		network.releaseCircuitQuota(circuit.Id)
+		network.removeCircuitLinkLoads(circuit.Id)
 		network.CircuitEvent(event.CircuitDeleted, circuit, nil)
 
 		if svc, err := network.Service.Read(circuit.ServiceId); err == nil {
@@ -1025,9 +1048,13 @@ func (network *Network) CreatePath(srcR, dstR *model.Router) (*model.Path, error
 }
 
 func (network *Network) setLinks(path *model.Path) error {
+	return network.setLinksExcluding(path, nil)
+}
+
+func (network *Network) setLinksExcluding(path *model.Path, excluded map[string]struct{}) error {
 	if len(path.Nodes) > 1 {
 		for i := 0; i < len(path.Nodes)-1; i++ {
-			if link, found := network.Link.LeastExpensiveLink(path.Nodes[i], path.Nodes[i+1]); found {
+			if link, found := network.Link.LeastExpensiveLinkExcluding(path.Nodes[i], path.Nodes[i+1], excluded); found {
 				path.Links = append(path.Links, link)
 			} else {
 				return fmt.Errorf("no link from r/%v to r/%v", path.Nodes[i].Id, path.Nodes[i+1].Id)
@@ -1229,12 +1256,14 @@ func (network *Network) rerouteCircuit(circuit *model.Circuit, deadline time.Tim
 		if cq, err := network.UpdatePath(circuit.Path); err == nil {
 			circuit.Path = cq
 			circuit.UpdatedAt = time.Now()
+			network.updateCircuitLinkLoads(circuit)
 
 			rms := network.CreateRouteMessages(cq, SmartRerouteAttempt, circuit.Id, circuit.Terminator, deadline)
 
-			for i := 0; i < len(cq.Nodes); i++ {
-				if _, err := sendRoute(cq.Nodes[i], rms[i], network.options.RouteTimeout); err != nil {
-					log.WithError(err).Errorf("error sending route to [r/%s]", cq.Nodes[i].Id)
+			nodes := cq.AllNodes()
+			for i := 0; i < len(nodes); i++ {
+				if _, err := sendRoute(nodes[i], rms[i], network.options.RouteTimeout); err != nil {
+					log.WithError(err).Errorf("error sending route to [r/%s]", nodes[i].Id)
 					return err
 				}
 			}
@@ -1263,13 +1292,15 @@ func (network *Network) smartReroute(circuit *model.Circuit, cq *model.Path, dea
 		oldPath := circuit.Path
 		circuit.Path = cq
 		circuit.UpdatedAt = time.Now()
+		network.updateCircuitLinkLoads(circuit)
 
 		rms := network.CreateRouteMessages(cq, SmartRerouteAttempt, circuit.Id, circuit.Terminator, deadline)
 
-		for i := 0; i < len(cq.Nodes); i++ {
-			if _, err := sendRoute(cq.Nodes[i], rms[i], network.options.RouteTimeout); err != nil {
+		nodes := cq.AllNodes()
+		for i := 0; i < len(nodes); i++ {
+			if _, err := sendRoute(nodes[i], rms[i], network.options.RouteTimeout); err != nil {
 				retry = true
-				log.WithField("routerId", cq.Nodes[i].Id).WithError(err).Error("error sending smart route update to router")
+				log.WithField("routerId", nodes[i].Id).WithError(err).Error("error sending smart route update to router")
 				break
 			}
 		}
@@ -1367,12 +1398,13 @@ func sendUnroute(r *model.Router, circuitId string, now bool) error {
 // unrouteRemovedPathNodes sends Unroute to any nodes that were in the old path but are not in the new path.
 // This handles the case where a circuit reroute changes intermediate transit nodes.
 func (network *Network) unrouteRemovedPathNodes(log *logrus.Entry, circuitId string, oldPath, newPath *model.Path) {
-	newNodeIds := make(map[string]struct{}, len(newPath.Nodes))
-	for _, r := range newPath.Nodes {
+	newNodes := newPath.AllNodes()
+	newNodeIds := make(map[string]struct{}, len(newNodes))
+	for _, r := range newNodes {
 		newNodeIds[r.Id] = struct{}{}
 	}
 
-	for _, r := range oldPath.Nodes {
+	for _, r := range oldPath.AllNodes() {
 		if _, ok := newNodeIds[r.Id]; !ok {
 			if cr := network.GetConnectedRouter(r.Id); cr != nil {
 				if err := sendUnroute(cr, circuitId, true); err != nil {
@@ -5,6 +5,9 @@ import (
 	"math"
 	"time"
 
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/ziti/v2/common/capabilities"
+	"github.com/openziti/ziti/v2/common/ctrl_msg"
 	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
 	"github.com/openziti/ziti/v2/controller/idgen"
 	"github.com/openziti/ziti/v2/controller/model"
@@ -12,7 +15,53 @@ import (
 	"github.com/pkg/errors"
 )
 
+// CreateRouteMessages returns the route messages for the given path, one per router, ordered to match
+// path.AllNodes(). For multipath circuits the forwards for both paths are merged into a single message per router.
 func (network *Network) CreateRouteMessages(path *model.Path, attempt uint32, circuitId string, terminator xt.Terminator, deadline time.Time) []*ctrl_pb.Route {
+	routeMessages := network.createPathRouteMessages(path, attempt, circuitId, terminator, deadline)
+	if !path.IsMultipath() {
+		return routeMessages
+	}
+
+	secondaryMessages := network.createPathRouteMessages(path.Secondary, attempt, circuitId, terminator, deadline)
+
+	byRouter := map[string]*ctrl_pb.Route{}
+	for i, r := range path.Nodes {
+		byRouter[r.Id] = routeMessages[i]
+	}
+	for i, r := range path.Secondary.Nodes {
+		secondaryMsg := secondaryMessages[i]
+		if routeMsg, found := byRouter[r.Id]; found {
+			routeMsg.Forwards = append(routeMsg.Forwards, secondaryMsg.Forwards...)
+		} else {
+			byRouter[r.Id] = secondaryMsg
+		}
+	}
+
+	var result []*ctrl_pb.Route
+	for _, r := range path.AllNodes() {
+		routeMsg := byRouter[r.Id]
+		routeMsg.Tags = getRouteTags(nil, path)
+		result = append(result, routeMsg)
+	}
+	return result
+}
+
+// getRouteTags returns the tags to send with route messages for the given path. The circuit tags are copied if the
+// multipath tag needs to be added, as they're shared with the circuit.
+func getRouteTags(tags map[string]string, path *model.Path) map[string]string {
+	if !path.IsMultipath() {
+		return tags
+	}
+	result := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		result[k] = v
+	}
+	result[ctrl_msg.MultipathRouteTag] = path.MultipathMode
+	return result
+}
+
+func (network *Network) createPathRouteMessages(path *model.Path, attempt uint32, circuitId string, terminator xt.Terminator, deadline time.Time) []*ctrl_pb.Route {
 	var routeMessages []*ctrl_pb.Route
 	remainingTime := time.Until(deadline)
 	if len(path.Links) == 0 {
@@ -136,10 +185,70 @@ func (network *Network) UpdatePath(path *model.Path) (*model.Path, error) {
 	if err := network.setLinks(path2); err != nil {
 		return nil, err
 	}
+
+	if path.MultipathMode != "" {
+		network.addSecondaryPath(path2, path.MultipathMode)
+	}
 	return path2, nil
 }
 
+// addSecondaryPath sets the multipath mode on the path and, if one is available, a secondary path which shares
+// none of the primary path's links. If no secondary path can be found, the circuit continues over a single path.
+func (network *Network) addSecondaryPath(path *model.Path, mode string) {
+	path.MultipathMode = mode
+	path.Secondary = nil
+
+	log := pfxlog.Logger().WithField("path", path.String())
+	if len(path.Links) == 0 {
+		log.Debug("not adding secondary path, path is local to a single router")
+		return
+	}
+
+	for _, r := range path.Nodes {
+		if !r.HasCapability(capabilities.RouterMultipath) {
+			log.WithField("routerId", r.Id).Warn("not adding secondary path, router on primary path doesn't support multipath")
+			return
+		}
+	}
+
+	excluded := map[string]struct{}{}
+	for _, l := range path.Links {
+		excluded[l.Id] = struct{}{}
+	}
+
+	// routers which don't support multipath can't be used by the secondary path either
+	excludedRouters := map[string]struct{}{}
+	for _, r := range network.Router.AllConnected() {
+		if !r.HasCapability(capabilities.RouterMultipath) {
+			excludedRouters[r.Id] = struct{}{}
+		}
+	}
+
+	nodes, _, err := network.shortestPathExcluding(path.Nodes[0], path.EgressRouter(), excluded, excludedRouters)
+	if err != nil {
+		log.WithError(err).Warn("no link-disjoint secondary path available, using single path")
+		return
+	}
+
+	secondary := &model.Path{
+		Nodes:     nodes,
+		IngressId: path.IngressId,
+		EgressId:  path.EgressId,
+	}
+	if err = network.setLinksExcluding(secondary, excluded); err != nil {
+		log.WithError(err).Warn("no link-disjoint secondary path available, using single path")
+		return
+	}
+	path.Secondary = secondary
+}
+
 func (network *Network) shortestPath(srcR *model.Router, dstR *model.Router) ([]*model.Router, int64, error) {
+	return network.shortestPathExcluding(srcR, dstR, nil, nil)
+}
+
+// shortestPathExcluding finds the least expensive path between the two routers which doesn't use any of the
+// links whose ids are in the excluded set, or traverse any of the routers whose ids are in the excluded routers set
+func (network *Network) shortestPathExcluding(srcR *model.Router, dstR *model.Router, excluded map[string]struct{}, excludedRouters map[string]struct{}) ([]*model.Router, int64, error) {
 	if srcR == nil || dstR == nil {
 		return nil, 0, errors.New("not routable (!srcR||!dstR)")
 	}
@@ -179,9 +288,12 @@ func (network *Network) shortestPath(srcR *model.Router, dstR *model.Router) ([]
 
 		neighbors := network.Link.ConnectedNeighborsOfRouter(u)
 		for _, r := range neighbors {
+			if _, skip := excludedRouters[r.Id]; skip {
+				continue
+			}
 			if _, found := unvisited[r]; found {
 				var cost int64 = math.MaxInt32 + 1
-				if l, found := network.Link.LeastExpensiveLink(r, u); found {
+				if l, found := network.Link.LeastExpensiveLinkExcluding(r, u, excluded); found {
 					if !r.NoTraversal || r == srcR || r == dstR {
 						cost = l.GetCost() + int64(max(r.Cost, minRouterCost))
 					}
@@ -0,0 +1,238 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package network
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/ziti/v2/controller/model"
+)
+
+// linkLoadTracker keeps counts of the circuits using each link. Circuits are tracked by id so that updating a
+// circuit's path replaces its previous contribution rather than adding to it.
+type linkLoadTracker struct {
+	lock       sync.Mutex
+	circuits   map[string][]string
+	linkCounts map[string]uint32
+}
+
+func newLinkLoadTracker() *linkLoadTracker {
+	return &linkLoadTracker{
+		circuits:   map[string][]string{},
+		linkCounts: map[string]uint32{},
+	}
+}
+
+func (self *linkLoadTracker) update(circuitId string, path *model.Path) {
+	var linkIds []string
+	for _, l := range path.Links {
+		linkIds = append(linkIds, l.Id)
+	}
+	if path.IsMultipath() {
+		for _, l := range path.Secondary.Links {
+			linkIds = append(linkIds, l.Id)
+		}
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.removeUnlocked(circuitId)
+	for _, linkId := range linkIds {
+		self.linkCounts[linkId]++
+	}
+	self.circuits[circuitId] = linkIds
+}
+
+func (self *linkLoadTracker) remove(circuitId string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.removeUnlocked(circuitId)
+}
+
+func (self *linkLoadTracker) removeUnlocked(circuitId string) {
+	for _, linkId := range self.circuits[circuitId] {
+		decrementCount(self.linkCounts, linkId)
+	}
+	delete(self.circuits, circuitId)
+}
+
+func (self *linkLoadTracker) getCount(linkId string) uint32 {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return self.linkCounts[linkId]
+}
+
+// kShortestPaths returns up to k loop-free paths between the two routers, cheapest first, using Yen's algorithm.
+// Paths are distinguished by the links they use, so parallel links between the same pair of routers yield
+// separate paths.
+func (network *Network) kShortestPaths(srcR, dstR *model.Router, k int) []*model.Path {
+	minRouterCost := network.options.MinRouterCost
+
+	nodes, _, err := network.shortestPath(srcR, dstR)
+	if err != nil {
+		return nil
+	}
+	first := &model.Path{Nodes: nodes}
+	if err = network.setLinks(first); err != nil {
+		return nil
+	}
+
+	result := []*model.Path{first}
+	var candidates []*model.Path
+
+	for len(result) < k {
+		prev := result[len(result)-1]
+		for i := 0; i < len(prev.Links); i++ {
+			spurNode := prev.Nodes[i]
+			rootNodes := prev.Nodes[:i+1]
+			rootLinks := prev.Links[:i]
+
+			excludedLinks := map[string]struct{}{}
+			for _, p := range result {
+				if len(p.Links) > i && sameLinks(p.Links[:i], rootLinks) {
+					excludedLinks[p.Links[i].Id] = struct{}{}
+				}
+			}
+
+			excludedRouters := map[string]struct{}{}
+			for _, r := range rootNodes[:i] {
+				excludedRouters[r.Id] = struct{}{}
+			}
+
+			spurNodes, _, err := network.shortestPathExcluding(spurNode, dstR, excludedLinks, excludedRouters)
+			if err != nil {
+				continue
+			}
+			spur := &model.Path{Nodes: spurNodes}
+			if err = network.setLinksExcluding(spur, excludedLinks); err != nil {
+				continue
+			}
+
+			candidate := &model.Path{
+				Nodes: append(append([]*model.Router{}, rootNodes[:i]...), spur.Nodes...),
+				Links: append(append([]*model.Link{}, rootLinks...), spur.Links...),
+			}
+			if !containsPath(result, candidate) && !containsPath(candidates, candidate) {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Cost(minRouterCost) < candidates[j].Cost(minRouterCost)
+		})
+		result = append(result, candidates[0])
+		candidates = candidates[1:]
+	}
+
+	return result
+}
+
+// balancePath moves the path onto the least loaded of the near-equal-cost candidate paths between its endpoint
+// routers. Load is measured per link as circuits relative to link capacity, and a path is as loaded as its busiest
+// link. Ties go to the cheaper path.
+func (network *Network) balancePath(path *model.Path) {
+	options := &network.options.PathSelection
+	if !options.IsEnabled() || len(path.Links) == 0 {
+		return
+	}
+
+	candidates := network.kShortestPaths(path.Nodes[0], path.EgressRouter(), int(options.Candidates))
+	if len(candidates) < 2 {
+		return
+	}
+
+	minRouterCost := network.options.MinRouterCost
+	bestCost := candidates[0].Cost(minRouterCost)
+
+	var selected *model.Path
+	var selectedLoad float64
+	for _, candidate := range candidates {
+		if !options.IsNearEqual(candidate.Cost(minRouterCost), bestCost) {
+			break
+		}
+		load := network.getPathLoad(candidate)
+		if selected == nil || load < selectedLoad {
+			selected = candidate
+			selectedLoad = load
+		}
+	}
+
+	pfxlog.Logger().WithField("path", selected.String()).
+		WithField("candidates", len(candidates)).
+		Debug("selected least loaded near-equal-cost path")
+
+	path.Nodes = selected.Nodes
+	path.Links = selected.Links
+}
+
+// getPathLoad returns the load the path would have with one more circuit on it, which is the highest ratio of
+// circuits to capacity across its links
+func (network *Network) getPathLoad(path *model.Path) float64 {
+	options := &network.options.PathSelection
+	var result float64
+	for _, l := range path.Links {
+		capacity := options.GetLinkCapacity(l.Protocol)
+		if capacity == 0 {
+			capacity = 1
+		}
+		load := float64(network.linkLoads.getCount(l.Id)+1) / float64(capacity)
+		if load > result {
+			result = load
+		}
+	}
+	return result
+}
+
+func (network *Network) updateCircuitLinkLoads(circuit *model.Circuit) {
+	if network.options.PathSelection.IsEnabled() {
+		network.linkLoads.update(circuit.Id, circuit.Path)
+	}
+}
+
+func (network *Network) removeCircuitLinkLoads(circuitId string) {
+	if network.options.PathSelection.IsEnabled() {
+		network.linkLoads.remove(circuitId)
+	}
+}
+
+func sameLinks(a, b []*model.Link) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Id != b[i].Id {
+			return false
+		}
+	}
+	return true
+}
+
+func containsPath(paths []*model.Path, path *model.Path) bool {
+	for _, p := range paths {
+		if sameLinks(p.Links, path.Links) {
+			return true
+		}
+	}
+	return false
+}
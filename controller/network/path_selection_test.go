@@ -0,0 +1,150 @@
+package network
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openziti/transport/v2/tcp"
+	"github.com/openziti/ziti/v2/controller/model"
+	"github.com/stretchr/testify/require"
+)
+
+type pathSelectionTest struct {
+	network *Network
+	r0, r1  *model.Router
+	l0, l1  *model.Link
+	l2, l3  *model.Link
+}
+
+func newPathSelectionTest(t *testing.T, ctx *model.TestContext, config *testConfig) *pathSelectionTest {
+	req := require.New(t)
+	network, err := NewNetwork(config, ctx)
+	req.NoError(err)
+
+	transportAddr, err := tcp.AddressParser{}.Parse("tcp:0.0.0.0:0")
+	req.NoError(err)
+
+	r0 := model.NewRouterForTest("r0", "", transportAddr, nil, 0, false)
+	network.Router.MarkConnected(r0)
+
+	r1 := model.NewRouterForTest("r1", "", transportAddr, nil, 0, false)
+	network.Router.MarkConnected(r1)
+
+	r2 := model.NewRouterForTest("r2", "", transportAddr, nil, 0, false)
+	network.Router.MarkConnected(r2)
+
+	// two parallel links between r0 and r1, and a more expensive path through r2
+	result := &pathSelectionTest{
+		network: network,
+		r0:      r0,
+		r1:      r1,
+		l0:      model.NewTestLink("l0", r0, r1),
+		l1:      model.NewTestLink("l1", r0, r1),
+		l2:      model.NewTestLink("l2", r0, r2),
+		l3:      model.NewTestLink("l3", r2, r1),
+	}
+
+	for _, l := range []*model.Link{result.l0, result.l1, result.l2, result.l3} {
+		l.SetState(model.Connected)
+		network.Link.Add(l)
+	}
+
+	return result
+}
+
+func (self *pathSelectionTest) createCircuits(t *testing.T, count int) map[string]int {
+	req := require.New(t)
+	result := map[string]int{}
+	for i := 0; i < count; i++ {
+		path, err := self.network.CreatePath(self.r0, self.r1)
+		req.NoError(err)
+		self.network.balancePath(path)
+		req.Len(path.Links, 1)
+		result[path.Links[0].Id]++
+		self.network.linkLoads.update(fmt.Sprintf("c%d", i), path)
+	}
+	return result
+}
+
+func TestKShortestPaths(t *testing.T) {
+	ctx := model.NewTestContext(t)
+	defer ctx.Cleanup()
+
+	config := newTestConfig(ctx)
+	defer close(config.closeNotify)
+
+	req := require.New(t)
+	test := newPathSelectionTest(t, ctx, config)
+
+	paths := test.network.kShortestPaths(test.r0, test.r1, 5)
+	req.Len(paths, 3)
+
+	var direct []string
+	for _, p := range paths[:2] {
+		req.Len(p.Links, 1)
+		direct = append(direct, p.Links[0].Id)
+	}
+	req.ElementsMatch([]string{test.l0.Id, test.l1.Id}, direct)
+	req.Equal([]*model.Link{test.l2, test.l3}, paths[2].Links)
+
+	paths = test.network.kShortestPaths(test.r0, test.r1, 1)
+	req.Len(paths, 1)
+}
+
+func TestBalancePath(t *testing.T) {
+	t.Run("circuits are spread across near-equal-cost links", func(t *testing.T) {
+		ctx := model.NewTestContext(t)
+		defer ctx.Cleanup()
+
+		config := newTestConfig(ctx)
+		defer close(config.closeNotify)
+		config.options.PathSelection.Candidates = 3
+
+		req := require.New(t)
+		test := newPathSelectionTest(t, ctx, config)
+
+		counts := test.createCircuits(t, 4)
+		req.Equal(2, counts[test.l0.Id])
+		req.Equal(2, counts[test.l1.Id])
+		req.Equal(0, counts[test.l2.Id])
+
+		test.network.linkLoads.remove("c0")
+		test.network.linkLoads.remove("c1")
+		test.network.linkLoads.remove("c2")
+		test.network.linkLoads.remove("c3")
+		req.Equal(uint32(0), test.network.linkLoads.getCount(test.l0.Id))
+		req.Equal(uint32(0), test.network.linkLoads.getCount(test.l1.Id))
+	})
+
+	t.Run("circuits are weighted by link capacity", func(t *testing.T) {
+		ctx := model.NewTestContext(t)
+		defer ctx.Cleanup()
+
+		config := newTestConfig(ctx)
+		defer close(config.closeNotify)
+		config.options.PathSelection.Candidates = 3
+		config.options.PathSelection.LinkCapacities = map[string]uint32{"fast": 4}
+
+		req := require.New(t)
+		test := newPathSelectionTest(t, ctx, config)
+		test.l1.Protocol = "fast"
+
+		counts := test.createCircuits(t, 3)
+		req.Equal(0, counts[test.l0.Id])
+		req.Equal(3, counts[test.l1.Id])
+	})
+
+	t.Run("balancing is disabled by default", func(t *testing.T) {
+		ctx := model.NewTestContext(t)
+		defer ctx.Cleanup()
+
+		config := newTestConfig(ctx)
+		defer close(config.closeNotify)
+
+		req := require.New(t)
+		test := newPathSelectionTest(t, ctx, config)
+
+		counts := test.createCircuits(t, 4)
+		req.Len(counts, 1)
+	})
+}
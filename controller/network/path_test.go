@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/openziti/ziti/v2/common/capabilities"
 	config2 "github.com/openziti/ziti/v2/controller/config"
 	"github.com/openziti/ziti/v2/controller/model"
 
@@ -607,3 +608,111 @@ func newPathTestLink(network *Network, id string, srcR, destR *model.Router) *mo
 	network.Link.Add(l)
 	return l
 }
+
+func TestMultipathPath(t *testing.T) {
+	ctx := model.NewTestContext(t)
+	defer ctx.Cleanup()
+
+	config := newTestConfig(ctx)
+	defer close(config.closeNotify)
+
+	network, err := NewNetwork(config, ctx)
+	assert.Nil(t, err)
+
+	addr := "tcp:0.0.0.0:0"
+	transportAddr, err := tcp.AddressParser{}.Parse(addr)
+	assert.Nil(t, err)
+
+	r0 := model.NewRouterForTest("r0", "", transportAddr, nil, 0, false)
+	r0.Capabilities = capabilities.NewMask(capabilities.RouterMultipath)
+	network.Router.MarkConnected(r0)
+
+	r1 := model.NewRouterForTest("r1", "", transportAddr, nil, 0, false)
+	r1.Capabilities = capabilities.NewMask(capabilities.RouterMultipath)
+	network.Router.MarkConnected(r1)
+
+	r2 := model.NewRouterForTest("r2", "", transportAddr, nil, 0, false)
+	r2.Capabilities = capabilities.NewMask(capabilities.RouterMultipath)
+	network.Router.MarkConnected(r2)
+
+	l0 := model.NewTestLink("l0", r0, r1)
+	l0.SetState(model.Connected)
+	network.Link.Add(l0)
+
+	l1 := model.NewTestLink("l1", r0, r2)
+	l1.SetState(model.Connected)
+	network.Link.Add(l1)
+
+	l2 := model.NewTestLink("l2", r2, r1)
+	l2.SetState(model.Connected)
+	network.Link.Add(l2)
+
+	req := require.New(t)
+
+	path, err := network.CreatePath(r0, r1)
+	req.NoError(err)
+	req.Equal([]*model.Link{l0}, path.Links)
+
+	network.addSecondaryPath(path, "duplicate")
+	req.True(path.IsMultipath())
+	req.Equal([]*model.Router{r0, r2, r1}, path.Secondary.Nodes)
+	req.Equal([]*model.Link{l1, l2}, path.Secondary.Links)
+	req.Equal(path.IngressId, path.Secondary.IngressId)
+	req.Equal(path.EgressId, path.Secondary.EgressId)
+	req.True(path.UsesLink(l2))
+	req.Equal([]*model.Router{r0, r2, r1}, path.AllNodes())
+
+	terminator := &model.Terminator{Address: addr, Binding: "transport"}
+	routeMessages := network.CreateRouteMessages(path, 0, "s0", terminator, time.Now().Add(config2.DefaultOptionsRouteTimeout))
+	req.Equal(3, len(routeMessages))
+
+	for _, rm := range routeMessages {
+		req.Equal("duplicate", rm.Tags["multipath"])
+	}
+
+	// ingress fans out over both links
+	rm0 := routeMessages[0]
+	req.Nil(rm0.Egress)
+	req.Equal(4, len(rm0.Forwards))
+	var ingressDsts []string
+	for _, fwd := range rm0.Forwards {
+		if fwd.SrcAddress == path.IngressId {
+			ingressDsts = append(ingressDsts, fwd.DstAddress)
+		}
+	}
+	req.Equal([]string{l0.Id, l1.Id}, ingressDsts)
+
+	// transit router only on the secondary path
+	rm1 := routeMessages[1]
+	req.Nil(rm1.Egress)
+	req.Equal(2, len(rm1.Forwards))
+
+	// egress has a single egress definition and fans out over both links
+	rm2 := routeMessages[2]
+	req.NotNil(rm2.Egress)
+	req.Equal(path.EgressId, rm2.Egress.Address)
+	req.Equal(4, len(rm2.Forwards))
+
+	// routers which don't advertise multipath support aren't sent a second path
+	r2.Capabilities = capabilities.NewMask[capabilities.RouterCapability]()
+	network.addSecondaryPath(path, "duplicate")
+	req.False(path.IsMultipath())
+	req.Equal("duplicate", path.MultipathMode)
+
+	r2.Capabilities = capabilities.NewMask(capabilities.RouterMultipath)
+	r1.Capabilities = nil
+	network.addSecondaryPath(path, "duplicate")
+	req.False(path.IsMultipath())
+
+	r1.Capabilities = capabilities.NewMask(capabilities.RouterMultipath)
+	network.addSecondaryPath(path, "duplicate")
+	req.True(path.IsMultipath())
+
+	// with the only alternate link gone, the circuit falls back to a single path but keeps its mode
+	network.Link.Remove(l2)
+	updated, err := network.UpdatePath(path)
+	req.NoError(err)
+	req.False(updated.IsMultipath())
+	req.Equal("duplicate", updated.MultipathMode)
+	req.False(updated.EqualPath(path))
+}
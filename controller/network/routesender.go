@@ -83,8 +83,9 @@ func (self *routeSender) route(attempt uint32, path *model.Path, routeMsgs []*ct
 	logger := pfxlog.ChannelLogger(logcontext.EstablishPath).Wire(ctx)
 
 	// send route messages
-	for i := 0; i < len(path.Nodes); i++ {
-		r := path.Nodes[i]
+	nodes := path.AllNodes()
+	for i := 0; i < len(nodes); i++ {
+		r := nodes[i]
 		msg := routeMsgs[i]
 		logger.Debugf("sending route message to [r/%s] for attempt [#%d]", r.Id, msg.Attempt)
 		go self.sendRoute(r, msg, ctx)
@@ -226,7 +227,7 @@ func (self *routeSender) sendRoute(r *model.Router, routeMsg *ctrl_pb.Route, ctx
 
 func (self *routeSender) cleanups(path *model.Path) map[string]struct{} {
 	cleanups := make(map[string]struct{})
-	for _, r := range path.Nodes {
+	for _, r := range path.AllNodes() {
 		success, found := self.attendance[r.Id]
 		if found && success {
 			cleanups[r.Id] = struct{}{}
@@ -105,7 +105,13 @@ func (network *Network) getRerouteCandidates() []*newCircuitPath {
 				newCost := updatedPath.Cost(minRouterCost)
 				costDelta := oldCost - newCost
 				log.Tracef("old cost: %v, new cost: %v, delta: %v", oldCost, newCost, costDelta)
-				if count < ceiling && pathChanged && costDelta >= int64(network.options.Smart.MinCostDelta) {
+				// a multipath circuit which lost its secondary path is always worth rerouting once one is available again
+				secondaryRestored := updatedPath.IsMultipath() && !circuit.Path.IsMultipath()
+				// circuits spread across near-equal-cost paths are left where they are, otherwise smart routing
+				// would pull them all back onto the cheapest path
+				balanced := network.options.PathSelection.IsEnabled() && network.options.PathSelection.IsNearEqual(oldCost, newCost)
+				cheaper := costDelta >= int64(network.options.Smart.MinCostDelta) && !balanced
+				if count < ceiling && pathChanged && (cheaper || secondaryRestored) {
 					count++
 					candidates = append(candidates, &newCircuitPath{
 						circuit: circuit,
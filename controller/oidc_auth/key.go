@@ -24,6 +24,8 @@ import (
 
 	"github.com/go-jose/go-jose/v4"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/openziti/ziti/v2/controller/jwtsigner"
+	"github.com/openziti/ziti/v2/controller/model"
 )
 
 // key implements op.Key and represents a private key
@@ -46,8 +48,12 @@ func (s *key) SignatureAlgorithm() jose.SignatureAlgorithm {
 	return s.algorithm
 }
 
-// Key returns the private key for the key pair.
+// Key returns the private key for the key pair. Opaque keys, such as those held by a PKCS#11 token, are returned
+// as a jose.OpaqueSigner, as go-jose can't sign with them directly.
 func (s *key) Key() interface{} {
+	if jwtsigner.IsOpaqueKey(s.privateKey) {
+		return &opaqueKey{key: s}
+	}
 	return s.privateKey
 }
 
@@ -55,6 +61,31 @@ func (s *key) ID() string {
 	return s.id
 }
 
+// opaqueKey implements jose.OpaqueSigner for keys which can only be used through crypto.Signer
+type opaqueKey struct {
+	key *key
+}
+
+func (s *opaqueKey) Public() *jose.JSONWebKey {
+	return &jose.JSONWebKey{
+		Key:       s.key.publicKey,
+		KeyID:     s.key.id,
+		Algorithm: string(s.key.algorithm),
+		Use:       s.key.Use(),
+	}
+}
+
+func (s *opaqueKey) Algs() []jose.SignatureAlgorithm {
+	return []jose.SignatureAlgorithm{s.key.algorithm}
+}
+
+func (s *opaqueKey) SignPayload(payload []byte, alg jose.SignatureAlgorithm) ([]byte, error) {
+	if alg != s.key.algorithm {
+		return nil, jose.ErrUnsupportedAlgorithm
+	}
+	return jwtsigner.SignPayload(s.key.privateKey.(crypto.Signer), string(alg), payload)
+}
+
 // pubKey implements op.Key and represents a public key
 type pubKey struct {
 	key
@@ -82,6 +113,22 @@ func newKeyFromCert(cert *x509.Certificate, kid string) *pubKey {
 	}
 }
 
+// newKeyFromSigningKey will create a new PubKey from a rotated signing key
+func newKeyFromSigningKey(signingKey *model.SigningKey) *pubKey {
+	publicKey, err := signingKey.GetPublicKey()
+	if err != nil {
+		return nil
+	}
+
+	return &pubKey{
+		key{
+			id:        signingKey.Id,
+			algorithm: jose.SignatureAlgorithm(signingKey.Algorithm),
+			publicKey: publicKey,
+		},
+	}
+}
+
 // getSigningMethod determines the jwt.SigningMethod necessary for certificate
 func getSigningMethod(cert *x509.Certificate) jwt.SigningMethod {
 	switch pubKey := cert.PublicKey.(type) {
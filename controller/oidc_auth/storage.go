@@ -980,12 +980,43 @@ func (s *HybridStorage) RevokeToken(_ context.Context, tokenIDOrToken string, _
 
 // SigningKey implements the op.Storage interface
 func (s *HybridStorage) SigningKey(_ context.Context) (op.SigningKey, error) {
-	return &s.signingKey, nil
+	return s.currentSigningKey(), nil
+}
+
+// currentSigningKey returns this controller's active rotated signing key if there is one, otherwise the key of
+// the server certificate
+func (s *HybridStorage) currentSigningKey() *key {
+	managers := s.env.GetManagers()
+	if managers == nil || managers.SigningKey == nil {
+		return &s.signingKey
+	}
+
+	activeKey := managers.SigningKey.GetActiveKey()
+	if activeKey == nil {
+		return &s.signingKey
+	}
+
+	signingMethod, err := jwtsigner.GetJwtSigningMethodForKey(activeKey.Signer.Public())
+	if err != nil {
+		pfxlog.Logger().WithError(err).WithField("kid", activeKey.Kid).Error("unable to use active signing key, signing with server certificate")
+		return &s.signingKey
+	}
+
+	return &key{
+		id:         activeKey.Kid,
+		algorithm:  jose.SignatureAlgorithm(signingMethod.Alg()),
+		privateKey: activeKey.Signer,
+		publicKey:  activeKey.Signer.Public(),
+	}
 }
 
 // SignatureAlgorithms implements the op.Storage interface
 func (s *HybridStorage) SignatureAlgorithms(context.Context) ([]jose.SignatureAlgorithm, error) {
-	return []jose.SignatureAlgorithm{s.signingKey.Algorithm()}, nil
+	result := []jose.SignatureAlgorithm{s.signingKey.Algorithm()}
+	if current := s.currentSigningKey(); current.Algorithm() != s.signingKey.Algorithm() {
+		result = append(result, current.Algorithm())
+	}
+	return result, nil
 }
 
 // KeySet implements the op.Storage interface
@@ -1025,6 +1056,26 @@ func (s *HybridStorage) KeySet(_ context.Context) ([]op.Key, error) {
 		}
 	})
 
+	//rotated signing keys of all controllers, including the next keys which aren't used for signing yet
+	if managers := s.env.GetManagers(); managers != nil && managers.SigningKey != nil {
+		signingKeys, err := managers.SigningKey.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, signingKey := range signingKeys {
+			if s.IsTokenRevoked(signingKey.Id) {
+				continue
+			}
+
+			if newKey := newKeyFromSigningKey(signingKey); newKey != nil {
+				result = append(result, newKey)
+			} else {
+				pfxlog.Logger().WithField("kid", signingKey.Id).Error("could not convert signing key to JWKS key")
+			}
+		}
+	}
+
 	return result, nil
 }
 
@@ -0,0 +1,286 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/channel/v5"
+	"github.com/openziti/channel/v5/protobufs"
+	"github.com/openziti/ziti/v2/common/alert"
+	"github.com/openziti/ziti/v2/common/inspect"
+	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
+	"github.com/openziti/ziti/v2/controller/event"
+	"github.com/pkg/errors"
+)
+
+// ClusterStatus is a consolidated view of cluster health, combining membership, each member's replication state
+// and the quorum margin.
+type ClusterStatus struct {
+	ClusterId   string               `json:"clusterId"`
+	LeaderId    string               `json:"leaderId"`
+	ReadOnly    bool                 `json:"isReadOnly"`
+	VersionSkew bool                 `json:"versionSkew"`
+	Quorum      *event.ClusterQuorum `json:"quorum"`
+	Members     []*MemberStatus      `json:"members"`
+}
+
+// MemberStatus describes a cluster member along with its replication state. Raft is nil if the member couldn't be
+// reached, in which case Error describes why.
+type MemberStatus struct {
+	Member
+	Raft  *inspect.ClusterMemberRaftStatus `json:"raft,omitempty"`
+	Lag   uint64                           `json:"lag"`
+	Error string                           `json:"error,omitempty"`
+}
+
+// GetRaftStatus returns the local controller's view of its raft log and state machine
+func (self *Controller) GetRaftStatus() *inspect.ClusterMemberRaftStatus {
+	r := self.GetRaft()
+
+	result := &inspect.ClusterMemberRaftStatus{
+		Id:           self.env.GetId().Token,
+		State:        r.State().String(),
+		AppliedIndex: r.AppliedIndex(),
+		CommitIndex:  r.CommitIndex(),
+		LastLogIndex: r.LastIndex(),
+	}
+
+	if lastContact := r.LastContact(); !lastContact.IsZero() {
+		result.LastContact = &lastContact
+	}
+
+	if self.snapshotStore != nil {
+		snapshots, err := self.snapshotStore.List()
+		if err != nil {
+			pfxlog.Logger().WithError(err).Error("unable to list raft snapshots")
+		} else if len(snapshots) > 0 {
+			result.LastSnapshotIndex = snapshots[0].Index
+			result.LastSnapshotTime = snapshotTime(snapshots[0].ID)
+		}
+	}
+
+	return result
+}
+
+// snapshotTime extracts the creation time from a file snapshot id, which has the form term-index-unixMillis
+func snapshotTime(id string) *time.Time {
+	parts := strings.Split(id, "-")
+	if len(parts) != 3 {
+		return nil
+	}
+	millis, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil
+	}
+	result := time.UnixMilli(millis)
+	return &result
+}
+
+// GetClusterStatus collects the replication state of every member. Remote members are asked for their state over
+// the mesh, waiting at most timeout for them to answer.
+func (self *Controller) GetClusterStatus(timeout time.Duration) (*ClusterStatus, error) {
+	members, err := self.ListMembers()
+	if err != nil {
+		return nil, err
+	}
+
+	_, leaderId := self.GetRaft().LeaderWithID()
+
+	result := &ClusterStatus{
+		ClusterId: self.GetClusterId(),
+		LeaderId:  string(leaderId),
+		ReadOnly:  self.Mesh.IsReadOnly(),
+	}
+
+	peers := self.Mesh.GetPeers()
+	wg := sync.WaitGroup{}
+
+	for _, member := range members {
+		status := &MemberStatus{Member: *member}
+		result.Members = append(result.Members, status)
+
+		if member.IsSelf {
+			status.Raft = self.GetRaftStatus()
+			continue
+		}
+
+		peer, found := peers[member.Addr]
+		if !found || peer.Channel == nil {
+			status.Error = "not connected"
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			raftStatus, peerErr := getPeerRaftStatus(peer.Channel, timeout)
+			if peerErr != nil {
+				status.Error = peerErr.Error()
+			}
+			status.Raft = raftStatus
+		}()
+	}
+
+	wg.Wait()
+
+	result.Quorum, result.VersionSkew = summarizeMembers(result.Members)
+
+	return result, nil
+}
+
+// summarizeMembers fills in each member's lag behind the highest known commit index and works out the quorum
+// margin and whether reachable members are running different versions
+func summarizeMembers(members []*MemberStatus) (*event.ClusterQuorum, bool) {
+	var commitIndex uint64
+	for _, member := range members {
+		if member.Raft != nil && member.Raft.CommitIndex > commitIndex {
+			commitIndex = member.Raft.CommitIndex
+		}
+	}
+
+	voters := 0
+	healthyVoters := 0
+	versions := map[string]struct{}{}
+
+	for _, member := range members {
+		if member.Raft != nil {
+			if member.Raft.AppliedIndex < commitIndex {
+				member.Lag = commitIndex - member.Raft.AppliedIndex
+			}
+			versions[member.Version] = struct{}{}
+		}
+
+		if member.Voter {
+			voters++
+			if member.Raft != nil {
+				healthyVoters++
+			}
+		}
+	}
+
+	return event.NewClusterQuorum(voters, healthyVoters), len(versions) > 1
+}
+
+// countVoters returns the number of voting members and how many of them are healthy. A voter is unhealthy when
+// heartbeats to it are failing and the leader last heard from it more than contactTimeout ago.
+func countVoters(servers []raft.Server, failedHeartbeats map[raft.ServerID]time.Time, now time.Time, contactTimeout time.Duration) (int, int) {
+	voters := 0
+	healthyVoters := 0
+	for _, srv := range servers {
+		if srv.Suffrage != raft.Voter {
+			continue
+		}
+		voters++
+		if lastContact, failing := failedHeartbeats[srv.ID]; !failing || now.Sub(lastContact) <= contactTimeout {
+			healthyVoters++
+		}
+	}
+	return voters, healthyVoters
+}
+
+func getPeerRaftStatus(ch channel.Sender, timeout time.Duration) (*inspect.ClusterMemberRaftStatus, error) {
+	request := &ctrl_pb.InspectRequest{RequestedValues: []string{inspect.ClusterMemberStatusKey}}
+	resp := &ctrl_pb.InspectResponse{}
+	respMsg, err := protobufs.MarshalTyped(request).WithTimeout(timeout).SendForReply(ch)
+	if err = protobufs.TypedResponse(resp).Unmarshall(respMsg, err); err != nil {
+		return nil, err
+	}
+
+	for _, val := range resp.Values {
+		if val.Name == inspect.ClusterMemberStatusKey {
+			result := &inspect.ClusterMemberRaftStatus{}
+			if err = json.Unmarshal([]byte(val.Value), result); err != nil {
+				return nil, errors.Wrap(err, "unable to parse member raft status")
+			}
+			return result, nil
+		}
+	}
+
+	if len(resp.Errors) > 0 {
+		return nil, errors.New(strings.Join(resp.Errors, ", "))
+	}
+
+	return nil, errors.New("member did not report raft status")
+}
+
+// checkQuorumMargin is run periodically on the leader. It emits an alert and a quorum.at_risk cluster event when
+// losing one more voting member would lose quorum, and a quorum.restored event once there's margin again. Voting
+// members are counted as healthy unless raft has been failing to heartbeat them for longer than the heartbeat
+// timeout, so a member which is connected but not replicating still counts against the margin.
+func (self *Controller) checkQuorumMargin(eventState *clusterEventState) {
+	if !self.isLeader.Load() {
+		eventState.quorumAtRisk = false
+		clear(eventState.failedHeartbeats)
+		return
+	}
+
+	configFuture := self.GetRaft().GetConfiguration()
+	if err := configFuture.Error(); err != nil {
+		pfxlog.Logger().WithError(err).Error("unable to get raft configuration to check quorum margin")
+		return
+	}
+
+	contactTimeout := self.GetRaft().ReloadableConfig().HeartbeatTimeout
+	voters, healthyVoters := countVoters(configFuture.Configuration().Servers, eventState.failedHeartbeats, time.Now(), contactTimeout)
+
+	quorum := event.NewClusterQuorum(voters, healthyVoters)
+	atRisk := quorum.IsAtRisk()
+
+	if atRisk == eventState.quorumAtRisk {
+		return
+	}
+	eventState.quorumAtRisk = atRisk
+
+	log := pfxlog.Logger().
+		WithField("voters", quorum.Voters).
+		WithField("healthyVoters", quorum.HealthyVoters).
+		WithField("quorum", quorum.Quorum)
+
+	eventType := event.ClusterQuorumRestored
+	if atRisk {
+		eventType = event.ClusterQuorumAtRisk
+		log.Warn("cluster quorum at risk, losing another voting member will lose quorum")
+
+		srcId := self.env.GetId().Token
+		self.env.GetEventDispatcher().AcceptAlertEvent(&event.AlertEvent{
+			Namespace:       event.AlertEventNS,
+			EventSrcId:      srcId,
+			Timestamp:       time.Now(),
+			AlertSourceType: event.AlertSourceTypeController,
+			AlertSourceId:   srcId,
+			Severity:        alert.SeverityWarning,
+			Message:         "cluster quorum at risk",
+			Details: []string{
+				fmt.Sprintf("%d of %d voting members are reachable, %d are required for quorum", quorum.HealthyVoters, quorum.Voters, quorum.Quorum),
+			},
+		})
+	} else {
+		log.Info("cluster quorum margin restored")
+	}
+
+	clusterEvent := event.NewClusterEvent(eventType)
+	clusterEvent.Quorum = quorum
+	self.env.GetEventDispatcher().AcceptClusterEvent(clusterEvent)
+}
@@ -0,0 +1,175 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package raft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/openziti/ziti/v2/common/inspect"
+	"github.com/openziti/ziti/v2/controller/event"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeMembers(t *testing.T) {
+	newMember := func(id string, voter bool, version string, applied, commit uint64) *MemberStatus {
+		return &MemberStatus{
+			Member: Member{Id: id, Voter: voter, Version: version},
+			Raft:   &inspect.ClusterMemberRaftStatus{Id: id, AppliedIndex: applied, CommitIndex: commit},
+		}
+	}
+
+	unreachable := func(id string, voter bool) *MemberStatus {
+		return &MemberStatus{
+			Member: Member{Id: id, Voter: voter, Version: "<not connected>"},
+			Error:  "not connected",
+		}
+	}
+
+	t.Run("lag is measured against the highest commit index", func(t *testing.T) {
+		req := require.New(t)
+		members := []*MemberStatus{
+			newMember("ctrl1", true, "v1.0.0", 100, 100),
+			newMember("ctrl2", true, "v1.0.0", 90, 95),
+			newMember("ctrl3", true, "v1.0.0", 100, 100),
+		}
+
+		quorum, skew := summarizeMembers(members)
+		req.False(skew)
+		req.Equal(uint64(0), members[0].Lag)
+		req.Equal(uint64(10), members[1].Lag)
+		req.Equal(uint64(0), members[2].Lag)
+		req.Equal(&event.ClusterQuorum{Voters: 3, HealthyVoters: 3, Quorum: 2, FailuresTolerable: 1, HasQuorum: true}, quorum)
+	})
+
+	t.Run("unreachable voters reduce the failures tolerable", func(t *testing.T) {
+		req := require.New(t)
+		members := []*MemberStatus{
+			newMember("ctrl1", true, "v1.0.0", 100, 100),
+			newMember("ctrl2", true, "v1.0.0", 100, 100),
+			unreachable("ctrl3", true),
+		}
+
+		quorum, skew := summarizeMembers(members)
+		req.False(skew)
+		req.Equal(0, quorum.FailuresTolerable)
+		req.True(quorum.HasQuorum)
+		req.True(quorum.IsAtRisk())
+	})
+
+	t.Run("non-voters don't count towards quorum", func(t *testing.T) {
+		req := require.New(t)
+		members := []*MemberStatus{
+			newMember("ctrl1", true, "v1.0.0", 100, 100),
+			newMember("ctrl2", true, "v1.0.0", 100, 100),
+			newMember("ctrl3", true, "v1.0.0", 100, 100),
+			newMember("ctrl4", false, "v1.0.0", 100, 100),
+			unreachable("ctrl5", false),
+		}
+
+		quorum, _ := summarizeMembers(members)
+		req.Equal(3, quorum.Voters)
+		req.Equal(1, quorum.FailuresTolerable)
+	})
+
+	t.Run("different versions on reachable members are reported as skew", func(t *testing.T) {
+		req := require.New(t)
+		members := []*MemberStatus{
+			newMember("ctrl1", true, "v1.0.0", 100, 100),
+			newMember("ctrl2", true, "v1.1.0", 100, 100),
+			unreachable("ctrl3", true),
+		}
+
+		_, skew := summarizeMembers(members)
+		req.True(skew)
+	})
+}
+
+func TestCountVoters(t *testing.T) {
+	servers := []raft.Server{
+		{ID: "ctrl1", Suffrage: raft.Voter},
+		{ID: "ctrl2", Suffrage: raft.Voter},
+		{ID: "ctrl3", Suffrage: raft.Voter},
+		{ID: "ctrl4", Suffrage: raft.Nonvoter},
+	}
+	now := time.Now()
+	timeout := time.Second
+
+	t.Run("voters without heartbeat failures are healthy", func(t *testing.T) {
+		req := require.New(t)
+		voters, healthy := countVoters(servers, map[raft.ServerID]time.Time{}, now, timeout)
+		req.Equal(3, voters)
+		req.Equal(3, healthy)
+	})
+
+	t.Run("voters heard from within the timeout are healthy", func(t *testing.T) {
+		req := require.New(t)
+		failed := map[raft.ServerID]time.Time{"ctrl2": now.Add(-timeout / 2)}
+		_, healthy := countVoters(servers, failed, now, timeout)
+		req.Equal(3, healthy)
+	})
+
+	t.Run("voters not heard from within the timeout are unhealthy", func(t *testing.T) {
+		req := require.New(t)
+		failed := map[raft.ServerID]time.Time{
+			"ctrl2": now.Add(-2 * timeout),
+			"ctrl3": {},
+			"ctrl4": now.Add(-2 * timeout),
+		}
+		voters, healthy := countVoters(servers, failed, now, timeout)
+		req.Equal(3, voters)
+		req.Equal(1, healthy)
+	})
+}
+
+func TestClusterQuorum(t *testing.T) {
+	tests := []struct {
+		voters            int
+		healthyVoters     int
+		quorum            int
+		failuresTolerable int
+		hasQuorum         bool
+		atRisk            bool
+	}{
+		{voters: 1, healthyVoters: 1, quorum: 1, failuresTolerable: 0, hasQuorum: true},
+		{voters: 2, healthyVoters: 2, quorum: 2, failuresTolerable: 0, hasQuorum: true, atRisk: true},
+		{voters: 3, healthyVoters: 3, quorum: 2, failuresTolerable: 1, hasQuorum: true},
+		{voters: 3, healthyVoters: 1, quorum: 2, failuresTolerable: 0, hasQuorum: false, atRisk: true},
+		{voters: 5, healthyVoters: 4, quorum: 3, failuresTolerable: 1, hasQuorum: true},
+	}
+
+	for _, test := range tests {
+		req := require.New(t)
+		quorum := event.NewClusterQuorum(test.voters, test.healthyVoters)
+		req.Equal(test.quorum, quorum.Quorum)
+		req.Equal(test.failuresTolerable, quorum.FailuresTolerable)
+		req.Equal(test.hasQuorum, quorum.HasQuorum)
+		req.Equal(test.atRisk, quorum.IsAtRisk())
+	}
+}
+
+func TestSnapshotTime(t *testing.T) {
+	req := require.New(t)
+
+	ts := snapshotTime("3-1200-1700000000123")
+	req.NotNil(ts)
+	req.True(time.UnixMilli(1700000000123).Equal(*ts))
+
+	req.Nil(snapshotTime("not-a-snapshot"))
+	req.Nil(snapshotTime("3-1200-abc"))
+}
@@ -138,32 +138,35 @@ func (self *Controller) HandleAddPeerAsLeader(req *cmd_pb.AddPeerRequest) error
 		return errors.Wrap(err, "failed to get raft configuration")
 	}
 
-	id := peerId
-	addr := peerAddr
+	change, err := planMemberChange(configFuture.Configuration().Servers, peerId, peerAddr, req.IsVoter)
+	if err != nil {
+		return err
+	}
 
-	for _, srv := range configFuture.Configuration().Servers {
-		// If a node already exists with either the joining node's ID or address,
-		// that node may need to be removed from the config first.
-		if srv.ID == id || srv.Address == addr {
-			// However, if *both* the ID and the address are the same, then nothing -- not even
-			// a join operation -- is needed.
-			if srv.ID == id && srv.Address == addr && ((srv.Suffrage == raft.Voter) == req.IsVoter) {
-				logrus.Infof("node %s at %s already member of cluster matching request, ignoring join request", id, addr)
-				return nil
-			}
+	log := logrus.WithField("id", peerId).WithField("addr", peerAddr).WithField("voter", req.IsVoter)
 
-			future := r.RemoveServer(srv.ID, 0, 0)
-			if err := future.Error(); err != nil {
-				return errors.Wrapf(err, "error removing existing node %s at %s", id, addr)
-			}
+	if change.noop {
+		log.Info("node already member of cluster matching request, ignoring join request")
+		return nil
+	}
+
+	for _, srv := range change.remove {
+		future := r.RemoveServer(srv.ID, 0, 0)
+		if err := future.Error(); err != nil {
+			return errors.Wrapf(err, "error removing existing node %s at %s", srv.ID, srv.Address)
 		}
 	}
 
 	var f raft.IndexFuture
-	if req.IsVoter {
-		f = r.AddVoter(id, addr, 0, 0)
+	if change.demote {
+		// keeps replicating to the node, it just no longer counts towards quorum
+		log.Info("demoting cluster member to non-voter")
+		f = r.DemoteVoter(peerId, 0, 0)
+	} else if req.IsVoter {
+		// promotes the node in place if it's already a non-voter
+		f = r.AddVoter(peerId, peerAddr, 0, 0)
 	} else {
-		f = r.AddNonvoter(id, addr, 0, 0)
+		f = r.AddNonvoter(peerId, peerAddr, 0, 0)
 	}
 
 	if err := f.Error(); err != nil {
@@ -173,6 +176,55 @@ func (self *Controller) HandleAddPeerAsLeader(req *cmd_pb.AddPeerRequest) error
 	return nil
 }
 
+// memberChange describes how to get a node into the cluster with the requested suffrage
+type memberChange struct {
+	// noop is set when the node is already a member with the requested address and suffrage
+	noop bool
+
+	// remove holds existing members which conflict with the node's id or address
+	remove []raft.Server
+
+	// demote is set when the node is already a voter and should become a non-voter
+	demote bool
+}
+
+// planMemberChange works out how to add the node with the given id and address to the cluster. If a member already
+// exists with both the id and the address, its suffrage is changed in place, so a non-voter can be promoted or a voter
+// demoted without it having to catch up on the log again. Demoting the last voter is refused, as the cluster would be
+// left without a quorum.
+func planMemberChange(servers []raft.Server, id raft.ServerID, addr raft.ServerAddress, isVoter bool) (*memberChange, error) {
+	result := &memberChange{}
+	voters := 0
+
+	for _, srv := range servers {
+		if srv.Suffrage == raft.Voter {
+			voters++
+		}
+	}
+
+	for _, srv := range servers {
+		if srv.ID == id && srv.Address == addr {
+			isCurrentVoter := srv.Suffrage == raft.Voter
+			if isCurrentVoter == isVoter {
+				result.noop = true
+				return result, nil
+			}
+			if isCurrentVoter {
+				if voters <= 1 {
+					return nil, errors.Errorf("node %s is the only voting member of the cluster and can't be made a non-voter", id)
+				}
+				result.demote = true
+			}
+		} else if srv.ID == id || srv.Address == addr {
+			// If a node already exists with either the joining node's ID or address,
+			// that node needs to be removed from the config first.
+			result.remove = append(result.remove, srv)
+		}
+	}
+
+	return result, nil
+}
+
 func (self *Controller) HandleRemovePeerAsLeader(req *cmd_pb.RemovePeerRequest) error {
 	r := self.GetRaft()
 
@@ -0,0 +1,88 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package raft
+
+import (
+	"testing"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanMemberChange(t *testing.T) {
+	servers := []raft.Server{
+		{ID: "ctrl1", Address: "tls:ctrl1:1280", Suffrage: raft.Voter},
+		{ID: "ctrl2", Address: "tls:ctrl2:1280", Suffrage: raft.Voter},
+		{ID: "ctrl3", Address: "tls:ctrl3:1280", Suffrage: raft.Nonvoter},
+	}
+
+	t.Run("new members are added without changes to existing members", func(t *testing.T) {
+		req := require.New(t)
+		change, err := planMemberChange(servers, "ctrl4", "tls:ctrl4:1280", false)
+		req.NoError(err)
+		req.False(change.noop)
+		req.False(change.demote)
+		req.Empty(change.remove)
+	})
+
+	t.Run("members matching the request are left alone", func(t *testing.T) {
+		req := require.New(t)
+		change, err := planMemberChange(servers, "ctrl3", "tls:ctrl3:1280", false)
+		req.NoError(err)
+		req.True(change.noop)
+
+		change, err = planMemberChange(servers, "ctrl1", "tls:ctrl1:1280", true)
+		req.NoError(err)
+		req.True(change.noop)
+	})
+
+	t.Run("voters are demoted in place", func(t *testing.T) {
+		req := require.New(t)
+		change, err := planMemberChange(servers, "ctrl2", "tls:ctrl2:1280", false)
+		req.NoError(err)
+		req.False(change.noop)
+		req.True(change.demote)
+		req.Empty(change.remove)
+	})
+
+	t.Run("non-voters are promoted in place", func(t *testing.T) {
+		req := require.New(t)
+		change, err := planMemberChange(servers, "ctrl3", "tls:ctrl3:1280", true)
+		req.NoError(err)
+		req.False(change.noop)
+		req.False(change.demote)
+		req.Empty(change.remove)
+	})
+
+	t.Run("members with a conflicting id or address are removed first", func(t *testing.T) {
+		req := require.New(t)
+		change, err := planMemberChange(servers, "ctrl3", "tls:ctrl3-new:1280", false)
+		req.NoError(err)
+		req.Len(change.remove, 1)
+		req.Equal(raft.ServerID("ctrl3"), change.remove[0].ID)
+	})
+
+	t.Run("the last voter can't be demoted", func(t *testing.T) {
+		req := require.New(t)
+		single := []raft.Server{
+			{ID: "ctrl1", Address: "tls:ctrl1:1280", Suffrage: raft.Voter},
+			{ID: "ctrl3", Address: "tls:ctrl3:1280", Suffrage: raft.Nonvoter},
+		}
+		_, err := planMemberChange(single, "ctrl1", "tls:ctrl1:1280", false)
+		req.ErrorContains(err, "only voting member")
+	})
+}
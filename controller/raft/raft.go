@@ -151,6 +151,7 @@ type Controller struct {
 	Raft                       *raft.Raft
 	Fsm                        *BoltDbFsm
 	raftStore                  *raftboltdb.BoltStore
+	snapshotStore              raft.SnapshotStore
 	bootstrapped               atomic.Bool
 	clusterLock                sync.Mutex
 	indexTracker               IndexTracker
@@ -639,6 +640,7 @@ func (self *Controller) Init() error {
 		logrus.WithField("snapshotDir", snapshotsDir).WithError(err).Errorf("failed to initialize raft snapshot store in: '%v'", snapshotsDir)
 		return err
 	}
+	self.snapshotStore = snapshotStore
 
 	helloHeaderProviders := self.env.GetHelloHeaderProviders()
 
@@ -673,9 +675,11 @@ func (self *Controller) Init() error {
 	}
 
 	r.RegisterObserver(raft.NewObserver(self.clusterEvents, true, func(o *raft.Observation) bool {
-		_, isRaftState := o.Data.(raft.RaftState)
-		_, isLeaderState := o.Data.(raft.LeaderObservation)
-		return isRaftState || isLeaderState
+		switch o.Data.(type) {
+		case raft.RaftState, raft.LeaderObservation, raft.FailedHeartbeatObservation, raft.ResumedHeartbeatObservation:
+			return true
+		}
+		return false
 	}))
 
 	rc := r.ReloadableConfig()
@@ -718,6 +722,9 @@ func (self *Controller) setupPreferredLeaderTransfer() {
 
 	if self.Config.PreferredLeader {
 		log.Info("this controller is configured as a preferred leader")
+		if self.IsBootstrapped() && !self.isVoter(raft.ServerID(self.env.GetId().Token)) {
+			log.Warn("this controller is a non-voting member of the cluster and can't become leader, preferred leader setting has no effect")
+		}
 		return // Preferred leaders keep leadership, nothing to do
 	}
 
@@ -753,6 +760,21 @@ func (self *Controller) setupPreferredLeaderTransfer() {
 	}
 }
 
+// isVoter returns true if the given server is a voting member of the cluster. Non-voting members replicate the log
+// and serve reads, but don't count towards quorum and can't become leader.
+func (self *Controller) isVoter(id raft.ServerID) bool {
+	configFuture := self.GetRaft().GetConfiguration()
+	if err := configFuture.Error(); err != nil {
+		return false
+	}
+	for _, srv := range configFuture.Configuration().Servers {
+		if srv.ID == id {
+			return srv.Suffrage == raft.Voter
+		}
+	}
+	return false
+}
+
 func (self *Controller) attemptTransferToPreferredLeader() {
 	select {
 	case <-self.env.GetCloseNotify():
@@ -774,6 +796,10 @@ func (self *Controller) transferToPreferredLeader() {
 	for _, peer := range peers {
 		log.Infof("transfer check: peer %s preferred=%v", peer.Id, peer.PreferredLeader)
 		if peer.PreferredLeader {
+			if !self.isVoter(peer.Id) {
+				log.WithField("targetPeer", peer.Id).Info("preferred leader is a non-voting member, skipping")
+				continue
+			}
 			log.WithField("targetPeer", peer.Id).Info("transferring leadership to preferred leader")
 			req := &cmd_pb.TransferLeadershipRequest{
 				Id: string(peer.Id),
@@ -872,6 +898,10 @@ type clusterEventState struct {
 	noLeaderAt     time.Time
 	warningEmitted bool
 	leaderId       string
+	quorumAtRisk   bool
+
+	// failedHeartbeats holds the last contact time of followers the leader is failing to heartbeat
+	failedHeartbeats map[raft.ServerID]time.Time
 }
 
 func (self *Controller) eventLoop() {
@@ -882,6 +912,8 @@ func (self *Controller) eventLoop() {
 		hasLeader:   leaderAddr != "",
 		noLeaderAt:  time.Now(),
 		leaderId:    string(leaderId),
+
+		failedHeartbeats: map[raft.ServerID]time.Time{},
 	}
 
 	if eventState.hasLeader {
@@ -903,6 +935,7 @@ func (self *Controller) eventLoop() {
 					Warn("cluster running without leader for longer than configured threshold")
 				eventState.warningEmitted = true
 			}
+			self.checkQuorumMargin(eventState)
 		}
 	}
 }
@@ -934,6 +967,14 @@ func (self *Controller) processRaftObservation(observation raft.Observation, eve
 		}
 	}
 
+	if failed, ok := observation.Data.(raft.FailedHeartbeatObservation); ok {
+		eventState.failedHeartbeats[failed.PeerID] = failed.LastContact
+	}
+
+	if resumed, ok := observation.Data.(raft.ResumedHeartbeatObservation); ok {
+		delete(eventState.failedHeartbeats, resumed.PeerID)
+	}
+
 	if leaderState, ok := observation.Data.(raft.LeaderObservation); ok {
 		if leaderState.LeaderAddr == "" {
 			if eventState.hasLeader {
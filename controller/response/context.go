@@ -183,6 +183,14 @@ func (rc *RequestContext) NewChangeContextForIdentity(identity *model.Identity)
 	if rc.Request.Form.Has("traceId") {
 		changeCtx.SetTraceId(rc.Request.Form.Get("traceId"))
 	}
+
+	if ifMatch := rc.Request.Header.Get(IfMatchHeader); ifMatch != "" && ifMatch != "*" && rc.entityId != "" {
+		switch rc.Request.Method {
+		case http.MethodPut, http.MethodPatch, http.MethodDelete:
+			changeCtx.SetIfMatch(rc.entityId, ifMatch)
+		}
+	}
+
 	return changeCtx
 }
 
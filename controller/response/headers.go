@@ -22,12 +22,15 @@ import (
 
 	"github.com/openziti/foundation/v2/errorz"
 	"github.com/openziti/ziti/v2/common/build"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
 )
 
 const (
 	ApiSessionExpirationSecondsHeader = "expiration-seconds"
 	ApiSessionExpiresAtHeader         = "expires-at"
 	ServerHeader                      = "server"
+	ETagHeader                        = "ETag"
+	IfMatchHeader                     = "If-Match"
 )
 
 // AddHeaders sets standard response headers for every API response, including the server
@@ -41,6 +44,12 @@ func AddHeaders(rc *RequestContext) {
 	AddApiSessionHeaders(rc)
 }
 
+// AddEntityTagHeader sets the ETag header to the entity tag for the given entity revision. Clients may send the
+// value back in an If-Match header to make updates and deletes conditional on the entity being unchanged.
+func AddEntityTagHeader(rc *RequestContext, revision int64) {
+	rc.ResponseWriter.Header().Set(ETagHeader, boltz.EntityTag(revision))
+}
+
 // AddApiSessionHeaders writes API-session lifetime headers when a resolved session is
 // available, and appends WWW-Authenticate or other structured error headers from any
 // MFA or session-level errors so that clients can determine the next required action.
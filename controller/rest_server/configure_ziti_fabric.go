@@ -125,6 +125,14 @@ func configureAPI(api *operations.ZitiFabricAPI) http.Handler {
 			return middleware.NotImplemented("operation cluster.ClusterMemberRemove has not yet been implemented")
 		})
 	}
+	if api.ClusterClusterStatusHandler == nil {
+		api.ClusterClusterStatusHandler = cluster.ClusterStatusHandlerFunc(func(params cluster.ClusterStatusParams, principal any) middleware.Responder {
+			_ = params
+			_ = principal
+
+			return middleware.NotImplemented("operation cluster.ClusterStatus has not yet been implemented")
+		})
+	}
 	if api.ClusterClusterTransferLeadershipHandler == nil {
 		api.ClusterClusterTransferLeadershipHandler = cluster.ClusterTransferLeadershipHandlerFunc(func(params cluster.ClusterTransferLeadershipParams, principal any) middleware.Responder {
 			_ = params
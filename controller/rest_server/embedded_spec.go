@@ -271,6 +271,30 @@ func init() {
         }
       }
     },
+    "/cluster/status": {
+      "get": {
+        "description": "Returns the health of the cluster, including each member's replication state and how many voting members can be lost without losing quorum. Requires admin access.",
+        "tags": [
+          "Cluster"
+        ],
+        "summary": "Returns the health of the cluster",
+        "operationId": "clusterStatus",
+        "responses": {
+          "200": {
+            "$ref": "#/responses/clusterStatusResponse"
+          },
+          "400": {
+            "$ref": "#/responses/badRequestResponse"
+          },
+          "401": {
+            "$ref": "#/responses/unauthorizedResponse"
+          },
+          "429": {
+            "$ref": "#/responses/rateLimitedResponse"
+          }
+        }
+      }
+    },
     "/cluster/transfer-leadership": {
       "post": {
         "description": "Attempts to transfer leadership to a different member of the cluster. Requires admin access.",
@@ -1493,6 +1517,41 @@ func init() {
         }
       }
     },
+    "clusterMemberRaftStatus": {
+      "type": "object",
+      "required": [
+        "state",
+        "appliedIndex",
+        "commitIndex",
+        "lastLogIndex",
+        "lastSnapshotIndex"
+      ],
+      "properties": {
+        "appliedIndex": {
+          "type": "integer"
+        },
+        "commitIndex": {
+          "type": "integer"
+        },
+        "lastContact": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "lastLogIndex": {
+          "type": "integer"
+        },
+        "lastSnapshotIndex": {
+          "type": "integer"
+        },
+        "lastSnapshotTime": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "state": {
+          "type": "string"
+        }
+      }
+    },
     "clusterMemberRemove": {
       "type": "object",
       "required": [
@@ -1504,6 +1563,126 @@ func init() {
         }
       }
     },
+    "clusterMemberStatus": {
+      "type": "object",
+      "required": [
+        "id",
+        "address",
+        "voter",
+        "leader",
+        "version",
+        "connected",
+        "lag"
+      ],
+      "properties": {
+        "address": {
+          "type": "string"
+        },
+        "connected": {
+          "type": "boolean"
+        },
+        "error": {
+          "type": "string"
+        },
+        "id": {
+          "type": "string"
+        },
+        "lag": {
+          "type": "integer"
+        },
+        "leader": {
+          "type": "boolean"
+        },
+        "preferredLeader": {
+          "type": "boolean"
+        },
+        "raft": {
+          "$ref": "#/definitions/clusterMemberRaftStatus"
+        },
+        "version": {
+          "type": "string"
+        },
+        "voter": {
+          "type": "boolean"
+        }
+      }
+    },
+    "clusterQuorum": {
+      "type": "object",
+      "required": [
+        "voters",
+        "healthyVoters",
+        "quorum",
+        "failuresTolerable",
+        "hasQuorum"
+      ],
+      "properties": {
+        "failuresTolerable": {
+          "type": "integer"
+        },
+        "hasQuorum": {
+          "type": "boolean"
+        },
+        "healthyVoters": {
+          "type": "integer"
+        },
+        "quorum": {
+          "type": "integer"
+        },
+        "voters": {
+          "type": "integer"
+        }
+      }
+    },
+    "clusterStatus": {
+      "type": "object",
+      "required": [
+        "clusterId",
+        "leaderId",
+        "readOnly",
+        "versionSkew",
+        "quorum",
+        "members"
+      ],
+      "properties": {
+        "clusterId": {
+          "type": "string"
+        },
+        "leaderId": {
+          "type": "string"
+        },
+        "members": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/clusterMemberStatus"
+          }
+        },
+        "quorum": {
+          "$ref": "#/definitions/clusterQuorum"
+        },
+        "readOnly": {
+          "type": "boolean"
+        },
+        "versionSkew": {
+          "type": "boolean"
+        }
+      }
+    },
+    "clusterStatusEnvelope": {
+      "type": "object",
+      "required": [
+        "meta",
+        "data"
+      ],
+      "properties": {
+        "data": {
+          "$ref": "#/definitions/clusterStatus"
+        },
+        "meta": {
+          "$ref": "#/definitions/meta"
+        }
+      }
+    },
     "clusterTransferLeadership": {
       "type": "object",
       "properties": {
@@ -2764,6 +2943,12 @@ func init() {
         "$ref": "#/definitions/clusterMemberListResponse"
       }
     },
+    "clusterStatusResponse": {
+      "description": "A response to a cluster status request",
+      "schema": {
+        "$ref": "#/definitions/clusterStatusEnvelope"
+      }
+    },
     "createResponse": {
       "description": "The create request was successful and the resource has been added at the following location",
       "schema": {
@@ -3721,6 +3906,108 @@ func init() {
         }
       }
     },
+    "/cluster/status": {
+      "get": {
+        "description": "Returns the health of the cluster, including each member's replication state and how many voting members can be lost without losing quorum. Requires admin access.",
+        "tags": [
+          "Cluster"
+        ],
+        "summary": "Returns the health of the cluster",
+        "operationId": "clusterStatus",
+        "responses": {
+          "200": {
+            "description": "A response to a cluster status request",
+            "schema": {
+              "$ref": "#/definitions/clusterStatusEnvelope"
+            }
+          },
+          "400": {
+            "description": "The supplied request contains invalid fields or could not be parsed (json and non-json bodies). The error's code, message, and cause fields can be inspected for further information",
+            "schema": {
+              "$ref": "#/definitions/apiErrorEnvelope"
+            },
+            "examples": {
+              "application/json": {
+                "error": {
+                  "args": {
+                    "urlVars": {}
+                  },
+                  "cause": {
+                    "details": {
+                      "context": "(root)",
+                      "field": "(root)",
+                      "property": "fooField3"
+                    },
+                    "field": "(root)",
+                    "message": "(root): fooField3 is required",
+                    "type": "required",
+                    "value": {
+                      "fooField": "abc",
+                      "fooField2": "def"
+                    }
+                  },
+                  "causeMessage": "schema validation failed",
+                  "code": "COULD_NOT_VALIDATE",
+                  "message": "The supplied request contains an invalid document",
+                  "requestId": "ac6766d6-3a09-44b3-8d8a-1b541d97fdd9"
+                },
+                "meta": {
+                  "apiEnrollmentVersion": "0.0.1",
+                  "apiVersion": "0.0.1"
+                }
+              }
+            }
+          },
+          "401": {
+            "description": "The currently supplied session does not have the correct access rights to request this resource",
+            "schema": {
+              "$ref": "#/definitions/apiErrorEnvelope"
+            },
+            "examples": {
+              "application/json": {
+                "error": {
+                  "args": {
+                    "urlVars": {}
+                  },
+                  "cause": "",
+                  "causeMessage": "",
+                  "code": "UNAUTHORIZED",
+                  "message": "The request could not be completed. The session is not authorized or the credentials are invalid",
+                  "requestId": "0bfe7a04-9229-4b7a-812c-9eb3cc0eac0f"
+                },
+                "meta": {
+                  "apiEnrollmentVersion": "0.0.1",
+                  "apiVersion": "0.0.1"
+                }
+              }
+            }
+          },
+          "429": {
+            "description": "The resource requested is rate limited and the rate limit has been exceeded",
+            "schema": {
+              "$ref": "#/definitions/apiErrorEnvelope"
+            },
+            "examples": {
+              "application/json": {
+                "error": {
+                  "args": {
+                    "urlVars": {}
+                  },
+                  "causeMessage": "you have hit a rate limit in the requested operation",
+                  "code": "RATE_LIMITED",
+                  "message": "The resource is rate limited and the rate limit has been exceeded. Please try again later",
+                  "requestId": "270908d6-f2ef-4577-b973-67bec18ae376"
+                },
+                "meta": {
+                  "apiEnrollmentVersion": "0.0.1",
+                  "apiVersion": "0.0.1"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
     "/cluster/transfer-leadership": {
       "post": {
         "description": "Attempts to transfer leadership to a different member of the cluster. Requires admin access.",
@@ -7366,6 +7653,41 @@ func init() {
         }
       }
     },
+    "clusterMemberRaftStatus": {
+      "type": "object",
+      "required": [
+        "state",
+        "appliedIndex",
+        "commitIndex",
+        "lastLogIndex",
+        "lastSnapshotIndex"
+      ],
+      "properties": {
+        "appliedIndex": {
+          "type": "integer"
+        },
+        "commitIndex": {
+          "type": "integer"
+        },
+        "lastContact": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "lastLogIndex": {
+          "type": "integer"
+        },
+        "lastSnapshotIndex": {
+          "type": "integer"
+        },
+        "lastSnapshotTime": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "state": {
+          "type": "string"
+        }
+      }
+    },
     "clusterMemberRemove": {
       "type": "object",
       "required": [
@@ -7377,6 +7699,126 @@ func init() {
         }
       }
     },
+    "clusterMemberStatus": {
+      "type": "object",
+      "required": [
+        "id",
+        "address",
+        "voter",
+        "leader",
+        "version",
+        "connected",
+        "lag"
+      ],
+      "properties": {
+        "address": {
+          "type": "string"
+        },
+        "connected": {
+          "type": "boolean"
+        },
+        "error": {
+          "type": "string"
+        },
+        "id": {
+          "type": "string"
+        },
+        "lag": {
+          "type": "integer"
+        },
+        "leader": {
+          "type": "boolean"
+        },
+        "preferredLeader": {
+          "type": "boolean"
+        },
+        "raft": {
+          "$ref": "#/definitions/clusterMemberRaftStatus"
+        },
+        "version": {
+          "type": "string"
+        },
+        "voter": {
+          "type": "boolean"
+        }
+      }
+    },
+    "clusterQuorum": {
+      "type": "object",
+      "required": [
+        "voters",
+        "healthyVoters",
+        "quorum",
+        "failuresTolerable",
+        "hasQuorum"
+      ],
+      "properties": {
+        "failuresTolerable": {
+          "type": "integer"
+        },
+        "hasQuorum": {
+          "type": "boolean"
+        },
+        "healthyVoters": {
+          "type": "integer"
+        },
+        "quorum": {
+          "type": "integer"
+        },
+        "voters": {
+          "type": "integer"
+        }
+      }
+    },
+    "clusterStatus": {
+      "type": "object",
+      "required": [
+        "clusterId",
+        "leaderId",
+        "readOnly",
+        "versionSkew",
+        "quorum",
+        "members"
+      ],
+      "properties": {
+        "clusterId": {
+          "type": "string"
+        },
+        "leaderId": {
+          "type": "string"
+        },
+        "members": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/clusterMemberStatus"
+          }
+        },
+        "quorum": {
+          "$ref": "#/definitions/clusterQuorum"
+        },
+        "readOnly": {
+          "type": "boolean"
+        },
+        "versionSkew": {
+          "type": "boolean"
+        }
+      }
+    },
+    "clusterStatusEnvelope": {
+      "type": "object",
+      "required": [
+        "meta",
+        "data"
+      ],
+      "properties": {
+        "data": {
+          "$ref": "#/definitions/clusterStatus"
+        },
+        "meta": {
+          "$ref": "#/definitions/meta"
+        }
+      }
+    },
     "clusterTransferLeadership": {
       "type": "object",
       "properties": {
@@ -8644,6 +9086,12 @@ func init() {
         "$ref": "#/definitions/clusterMemberListResponse"
       }
     },
+    "clusterStatusResponse": {
+      "description": "A response to a cluster status request",
+      "schema": {
+        "$ref": "#/definitions/clusterStatusEnvelope"
+      }
+    },
     "createResponse": {
       "description": "The create request was successful and the resource has been added at the following location",
       "schema": {
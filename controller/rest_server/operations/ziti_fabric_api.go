@@ -103,6 +103,13 @@ func NewZitiFabricAPI(spec *loads.Document) *ZitiFabricAPI {
 			return middleware.NotImplemented("operation cluster.ClusterMemberRemove has not yet been implemented")
 		}),
 
+		ClusterClusterStatusHandler: cluster.ClusterStatusHandlerFunc(func(params cluster.ClusterStatusParams, principal any) middleware.Responder {
+			_ = params
+			_ = principal
+
+			return middleware.NotImplemented("operation cluster.ClusterStatus has not yet been implemented")
+		}),
+
 		ClusterClusterTransferLeadershipHandler: cluster.ClusterTransferLeadershipHandlerFunc(func(params cluster.ClusterTransferLeadershipParams, principal any) middleware.Responder {
 			_ = params
 			_ = principal
@@ -403,6 +410,8 @@ type ZitiFabricAPI struct {
 	ClusterClusterMemberAddHandler cluster.ClusterMemberAddHandler
 	// ClusterClusterMemberRemoveHandler sets the operation handler for the cluster member remove operation
 	ClusterClusterMemberRemoveHandler cluster.ClusterMemberRemoveHandler
+	// ClusterClusterStatusHandler sets the operation handler for the cluster status operation
+	ClusterClusterStatusHandler cluster.ClusterStatusHandler
 	// ClusterClusterTransferLeadershipHandler sets the operation handler for the cluster transfer leadership operation
 	ClusterClusterTransferLeadershipHandler cluster.ClusterTransferLeadershipHandler
 	// DatabaseCreateDatabaseSnapshotHandler sets the operation handler for the create database snapshot operation
@@ -565,6 +574,9 @@ func (o *ZitiFabricAPI) Validate() error {
 	if o.ClusterClusterMemberRemoveHandler == nil {
 		unregistered = append(unregistered, "cluster.ClusterMemberRemoveHandler")
 	}
+	if o.ClusterClusterStatusHandler == nil {
+		unregistered = append(unregistered, "cluster.ClusterStatusHandler")
+	}
 	if o.ClusterClusterTransferLeadershipHandler == nil {
 		unregistered = append(unregistered, "cluster.ClusterTransferLeadershipHandler")
 	}
@@ -775,6 +787,10 @@ func (o *ZitiFabricAPI) initHandlerCache() {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/cluster/list-members"] = cluster.NewClusterListMembers(o.context, o.ClusterClusterListMembersHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/cluster/status"] = cluster.NewClusterStatus(o.context, o.ClusterClusterStatusHandler)
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
@@ -52,6 +52,8 @@ const (
 	policyAppWanFreq            = 1 * time.Second
 	policySessionFreq           = 5 * time.Second
 	policyRevocationFreqDefault = 1 * time.Minute
+
+	policySigningKeyRotationFreq = 1 * time.Minute
 )
 
 func NewController(host env.HostController) (*Controller, error) {
@@ -216,6 +218,20 @@ func (c *Controller) Initialize() {
 			Errorf("could not add revocation enforcer")
 	}
 
+	if rotationConfig := &c.config.SigningKeyRotation; rotationConfig.Enabled {
+		if err := c.AppEnv.GetManagers().SigningKey.LoadActiveKey(rotationConfig); err != nil {
+			log.WithError(err).Error("could not load active JWT signing key, signing with server certificate until the next rotation")
+		}
+
+		signingKeyRotator := policy.NewSigningKeyRotator(c.AppEnv, rotationConfig, policySigningKeyRotationFreq)
+		if err := c.policyEngine.AddOperation(signingKeyRotator); err != nil {
+			log.WithField("cause", err).
+				WithField("enforcerName", signingKeyRotator.GetName()).
+				WithField("enforcerId", signingKeyRotator.GetId()).
+				Errorf("could not add signing key rotator")
+		}
+	}
+
 	if err := c.AppEnv.GetStores().EventualEventer.Start(c.AppEnv.GetHostController().GetCloseNotifyChannel()); err != nil {
 		log.WithError(err).Panic("could not start EventualEventer")
 	}
@@ -34,6 +34,7 @@ const (
 	FieldUpdatedAt      = "updatedAt"
 	FieldTags           = "tags"
 	FieldIsSystemEntity = "isSystem"
+	FieldRevision       = "revision"
 )
 
 type EntityEventType byte
@@ -342,6 +343,7 @@ type ExtEntity interface {
 	GetUpdatedAt() time.Time
 	GetTags() map[string]interface{}
 	IsSystemEntity() bool
+	GetRevision() int64
 
 	SetCreatedAt(createdAt time.Time)
 	SetUpdatedAt(updatedAt time.Time)
@@ -367,6 +369,7 @@ type BaseExtEntity struct {
 	UpdatedAt time.Time              `json:"updatedAt"`
 	Tags      map[string]interface{} `json:"tags"`
 	IsSystem  bool                   `json:"isSystem"`
+	Revision  int64                  `json:"revision"`
 	Migrate   bool                   `json:"-"`
 }
 
@@ -394,6 +397,12 @@ func (entity *BaseExtEntity) IsSystemEntity() bool {
 	return entity.IsSystem
 }
 
+// GetRevision returns the entity revision, which is incremented on every update. Entities created before revisions
+// were tracked start at 0.
+func (entity *BaseExtEntity) GetRevision() int64 {
+	return entity.Revision
+}
+
 func (entity *BaseExtEntity) SetCreatedAt(createdAt time.Time) {
 	entity.CreatedAt = createdAt
 }
@@ -411,6 +420,7 @@ func (entity *BaseExtEntity) LoadBaseValues(bucket *TypedBucket) {
 	entity.UpdatedAt = bucket.GetTimeOrError(FieldUpdatedAt)
 	entity.Tags = bucket.GetMap(FieldTags)
 	entity.IsSystem = bucket.GetBoolWithDefault(FieldIsSystemEntity, false)
+	entity.Revision = bucket.GetInt64WithDefault(FieldRevision, 0)
 }
 
 func (entity *BaseExtEntity) SetBaseValues(ctx *PersistContext) {
@@ -426,9 +436,11 @@ func (entity *BaseExtEntity) CreateBaseValues(ctx *PersistContext) {
 	if entity.Migrate {
 		ctx.Bucket.SetTimeP(FieldCreatedAt, &entity.CreatedAt, nil)
 		ctx.Bucket.SetTimeP(FieldUpdatedAt, &entity.UpdatedAt, nil)
+		ctx.Bucket.SetInt64(FieldRevision, entity.Revision, nil)
 	} else {
 		ctx.Bucket.SetTimeP(FieldCreatedAt, &now, nil)
 		ctx.Bucket.SetTimeP(FieldUpdatedAt, &now, nil)
+		ctx.Bucket.SetInt64(FieldRevision, 1, nil)
 	}
 	ctx.Bucket.PutMap(FieldTags, entity.Tags, nil, false)
 	if entity.IsSystem {
@@ -439,5 +451,6 @@ func (entity *BaseExtEntity) CreateBaseValues(ctx *PersistContext) {
 func (entity *BaseExtEntity) UpdateBaseValues(ctx *PersistContext) {
 	now := time.Now()
 	ctx.Bucket.SetTimeP(FieldUpdatedAt, &now, nil)
+	ctx.Bucket.SetInt64(FieldRevision, ctx.Bucket.GetInt64WithDefault(FieldRevision, 0)+1, nil)
 	ctx.Bucket.PutMap(FieldTags, entity.Tags, ctx.FieldChecker, false)
 }
@@ -0,0 +1,105 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package boltz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type expectedEntityTagsKeyType struct{}
+
+var expectedEntityTagsKey = expectedEntityTagsKeyType{}
+
+type expectedEntityTags struct {
+	id   string
+	tags []string
+}
+
+// EntityTag returns the entity tag, suitable for use as an HTTP ETag, for the given entity revision
+func EntityTag(revision int64) string {
+	return `"` + strconv.FormatInt(revision, 10) + `"`
+}
+
+// ParseEntityTags splits a comma separated list of entity tags, such as an If-Match header value
+func ParseEntityTags(val string) []string {
+	var result []string
+	for _, tag := range strings.Split(val, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// WithExpectedEntityTags returns a context which makes updates and deletes of the entity with the given id fail
+// with a RevisionMismatchError, unless the entity's current entity tag is one of the given tags. Weak tags never
+// match.
+func WithExpectedEntityTags(ctx context.Context, id string, tags []string) context.Context {
+	return context.WithValue(ctx, expectedEntityTagsKey, &expectedEntityTags{
+		id:   id,
+		tags: tags,
+	})
+}
+
+func checkRevision(ctx MutateContext, entityType, id string, bucket *TypedBucket) error {
+	if ctx.Context() == nil {
+		return nil
+	}
+
+	expected, ok := ctx.Context().Value(expectedEntityTagsKey).(*expectedEntityTags)
+	if !ok || expected.id != id {
+		return nil
+	}
+
+	revision := bucket.GetInt64WithDefault(FieldRevision, 0)
+	current := EntityTag(revision)
+	for _, tag := range expected.tags {
+		if tag == current {
+			return nil
+		}
+	}
+
+	return &RevisionMismatchError{
+		EntityType: entityType,
+		Id:         id,
+		Expected:   expected.tags,
+		Revision:   revision,
+	}
+}
+
+// RevisionMismatchError is returned when an entity is modified with an expected revision which doesn't match its
+// current revision, usually because someone else modified it in the meantime
+type RevisionMismatchError struct {
+	EntityType string
+	Id         string
+	Expected   []string
+	Revision   int64
+}
+
+func (err *RevisionMismatchError) Error() string {
+	return fmt.Sprintf("%v with id %v has revision %v, which does not match expected %v",
+		err.EntityType, err.Id, EntityTag(err.Revision), strings.Join(err.Expected, ", "))
+}
+
+func IsRevisionMismatchError(err error) bool {
+	var target *RevisionMismatchError
+	return errors.As(err, &target)
+}
@@ -0,0 +1,170 @@
+/*
+	Copyright NetFoundry, Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package boltz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+func TestEntityTags(t *testing.T) {
+	req := require.New(t)
+	req.Equal(`"42"`, EntityTag(42))
+	req.Equal([]string{`"1"`, `W/"2"`, `"3"`}, ParseEntityTags(` "1", W/"2",,"3" `))
+	req.Empty(ParseEntityTags(""))
+}
+
+func TestRevisionChecks(t *testing.T) {
+	test := &crudTest{}
+	test.Assertions = require.New(t)
+	test.init(false)
+	defer test.cleanup()
+
+	employee := &Employee{
+		Id:   uuid.NewString(),
+		Name: uuid.NewString(),
+	}
+
+	err := test.db.Update(func(tx *bbolt.Tx) error {
+		ctx := newTestMutateContext(tx)
+		if err := test.empStore.Create(ctx, employee); err != nil {
+			return err
+		}
+		test.empStore.GetEntityBucket(tx, []byte(employee.Id)).SetInt64(FieldRevision, 3, nil)
+		return nil
+	})
+	test.NoError(err)
+
+	mutateWithTags := func(tags string, f func(ctx MutateContext) error) error {
+		return test.db.Update(func(tx *bbolt.Tx) error {
+			ctx := NewTxMutateContext(WithExpectedEntityTags(context.Background(), employee.Id, ParseEntityTags(tags)), tx)
+			return f(ctx)
+		})
+	}
+
+	update := func(ctx MutateContext) error {
+		return test.empStore.Update(ctx, employee, nil)
+	}
+
+	t.Run("stale tags are rejected", func(t *testing.T) {
+		test.switchTestContext(t)
+		err = mutateWithTags(`"2"`, update)
+		test.True(IsRevisionMismatchError(err))
+
+		var mismatchErr *RevisionMismatchError
+		test.ErrorAs(err, &mismatchErr)
+		test.Equal(int64(3), mismatchErr.Revision)
+	})
+
+	t.Run("weak tags never match", func(t *testing.T) {
+		test.switchTestContext(t)
+		err = mutateWithTags(`W/"3"`, update)
+		test.True(IsRevisionMismatchError(err))
+	})
+
+	t.Run("matching tags are accepted", func(t *testing.T) {
+		test.switchTestContext(t)
+		test.NoError(mutateWithTags(`"1", "3"`, update))
+	})
+
+	t.Run("tags for other entities are ignored", func(t *testing.T) {
+		test.switchTestContext(t)
+		err = test.db.Update(func(tx *bbolt.Tx) error {
+			ctx := NewTxMutateContext(WithExpectedEntityTags(context.Background(), "other", []string{`"9"`}), tx)
+			return test.empStore.Update(ctx, employee, nil)
+		})
+		test.NoError(err)
+	})
+
+	t.Run("deletes are checked", func(t *testing.T) {
+		test.switchTestContext(t)
+		deleteEmployee := func(ctx MutateContext) error {
+			return test.empStore.DeleteById(ctx, employee.Id)
+		}
+
+		test.True(IsRevisionMismatchError(mutateWithTags(`"4"`, deleteEmployee)))
+		test.NoError(mutateWithTags(`"3"`, deleteEmployee))
+	})
+}
+
+func TestExtEntityRevisions(t *testing.T) {
+	test := &systemEntitiesTest{}
+	test.Assertions = require.New(t)
+	test.init()
+	defer test.cleanup()
+
+	entity := test.newFoo()
+
+	readTag := func() string {
+		var tag string
+		test.NoError(test.db.View(func(tx *bbolt.Tx) error {
+			current, found, err := test.fooStore.FindById(tx, entity.Id)
+			test.True(found)
+			tag = EntityTag(current.GetRevision())
+			return err
+		}))
+		return tag
+	}
+
+	updateWithTags := func(tags string) error {
+		return test.db.Update(func(tx *bbolt.Tx) error {
+			ctx := NewTxMutateContext(WithExpectedEntityTags(context.Background(), entity.Id, ParseEntityTags(tags)), tx)
+			entity.Name = uuid.NewString()
+			return test.fooStore.Update(ctx, entity, nil)
+		})
+	}
+
+	test.NoError(test.db.Update(func(tx *bbolt.Tx) error {
+		return test.fooStore.Create(newTestMutateContext(tx), entity)
+	}))
+
+	t.Run("create stores the first revision", func(t *testing.T) {
+		test.Assertions = require.New(t)
+		test.Equal(`"1"`, readTag())
+	})
+
+	t.Run("every update increments the revision", func(t *testing.T) {
+		test.Assertions = require.New(t)
+		test.NoError(test.db.Update(func(tx *bbolt.Tx) error {
+			return test.fooStore.Update(newTestMutateContext(tx), entity, nil)
+		}))
+		test.Equal(`"2"`, readTag())
+
+		// partial updates increment it too, even though the revision isn't one of the updated fields
+		test.NoError(test.db.Update(func(tx *bbolt.Tx) error {
+			return test.fooStore.Update(newTestMutateContext(tx), entity, MapFieldChecker{fieldName: struct{}{}})
+		}))
+		test.Equal(`"3"`, readTag())
+	})
+
+	t.Run("a stale tag is rejected", func(t *testing.T) {
+		test.Assertions = require.New(t)
+		err := updateWithTags(`"2"`)
+		test.True(IsRevisionMismatchError(err))
+		test.Equal(`"3"`, readTag(), "a rejected update must not change the revision")
+	})
+
+	t.Run("the current tag is accepted", func(t *testing.T) {
+		test.Assertions = require.New(t)
+		test.NoError(updateWithTags(readTag()))
+		test.Equal(`"4"`, readTag())
+	})
+}
@@ -266,6 +266,10 @@ func (store *BaseStore[E]) Update(ctx MutateContext, entity E, checker FieldChec
 		return store.entityNotFoundF(entity.GetId())
 	}
 
+	if err = checkRevision(ctx, store.GetSingularEntityType(), entity.GetId(), bucket); err != nil {
+		return err
+	}
+
 	changeFlow := &EntityChangeState[E]{
 		EventId:      uuid.NewString(),
 		ChangeType:   EntityUpdated,
@@ -410,6 +414,12 @@ func (store *BaseStore[E]) DeleteById(ctx MutateContext, id string) error {
 		return store.entityNotFoundF(id)
 	}
 
+	if entityBucket := store.GetEntityBucket(ctx.Tx(), []byte(id)); entityBucket != nil {
+		if err = checkRevision(ctx, store.GetSingularEntityType(), id, entityBucket); err != nil {
+			return err
+		}
+	}
+
 	hasChildren := false
 	var changeFlows = []entityChangeFlow{nil}
 	for _, handler := range store.childStoreStrategies {
@@ -452,6 +462,34 @@ func (store *BaseStore[E]) DeleteById(ctx MutateContext, id string) error {
 	return nil
 }
 
+// DeleteDanglingById removes the record of an entity which can't be removed with DeleteById, because it references
+// entities which no longer exist. Index and link entries referencing the entity are left behind, and should be cleaned
+// up with CheckIntegrity. Entity delete events are fired as they would be for DeleteById.
+func (store *BaseStore[E]) DeleteDanglingById(ctx MutateContext, id string) error {
+	changeFlow := &EntityChangeState[E]{
+		EventId:    uuid.NewString(),
+		ChangeType: EntityDeleted,
+		EntityId:   id,
+		store:      store,
+	}
+
+	found, err := changeFlow.init(ctx)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return store.entityNotFoundF(id)
+	}
+
+	bucket := store.GetEntitiesBucket(ctx.Tx())
+	bucket.DeleteEntity(id)
+	if bucket.Err != nil {
+		return bucket.Err
+	}
+
+	return changeFlow.fireEvents()
+}
+
 func (store *BaseStore[E]) DeleteWhere(ctx MutateContext, query string) error {
 	ids, _, err := store.QueryIds(ctx.Tx(), query)
 	if err != nil {
@@ -312,6 +312,7 @@ func (store *BaseStore[E]) AddExtEntitySymbols() {
 	store.AddMapSymbol(FieldTags, ast.NodeTypeAnyType, FieldTags)
 	store.MakeSymbolPublic(FieldTags)
 	store.AddSymbol(FieldIsSystemEntity, ast.NodeTypeBool)
+	store.AddSymbol(FieldRevision, ast.NodeTypeInt64)
 }
 
 func (store *BaseStore[E]) NewScanner(sort []ast.SortField) Scanner {
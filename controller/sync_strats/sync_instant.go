@@ -22,6 +22,7 @@ import (
 	"crypto/tls"
 	"encoding/binary"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"math"
 	"math/big"
@@ -252,6 +253,15 @@ func (strategy *InstantStrategy) Initialize(logSize uint64, bufferSize uint) err
 	}
 	strategy.ae.GetStores().Revocation.AddEntityConstraint(revocationHandler)
 
+	//signing key create/delete/update
+	signingKeyHandler := &constraintToIndexedEvents[*db.SigningKey]{
+		indexProvider: strategy.indexProvider,
+		createHandler: strategy.SigningKeyCreate,
+		updateHandler: strategy.SigningKeyUpdate,
+		deleteHandler: strategy.SigningKeyDelete,
+	}
+	strategy.ae.GetStores().SigningKey.AddEntityConstraint(signingKeyHandler)
+
 	controllerHandler := &constraintToIndexedEvents[*db.Controller]{
 		indexProvider: strategy.indexProvider,
 		createHandler: strategy.ControllerCreate,
@@ -1078,6 +1088,28 @@ func (strategy *InstantStrategy) BuildPublicKeys(tx *bbolt.Tx, rdm *common.Route
 		rdm.HandlePublicKeyEvent(newEvent, newModel)
 	}
 
+	for cursor := strategy.ae.GetStores().SigningKey.IterateIds(tx, ast.BoolNodeTrue); cursor.IsValid(); cursor.Next() {
+		signingKey, err := strategy.ae.GetStores().SigningKey.LoadById(tx, string(cursor.Current()))
+
+		if err != nil {
+			return err
+		}
+
+		publicKey := newSigningKeyPublicKey(signingKey)
+
+		if publicKey == nil {
+			continue
+		}
+
+		newModel := &edge_ctrl_pb.DataState_Event_PublicKey{PublicKey: publicKey}
+		newEvent := &edge_ctrl_pb.DataState_Event{
+			Action:      edge_ctrl_pb.DataState_Create,
+			Model:       newModel,
+			IsSynthetic: true,
+		}
+		rdm.HandlePublicKeyEvent(newEvent, newModel)
+	}
+
 	return nil
 }
 
@@ -1745,6 +1777,17 @@ func newPublicKey(data []byte, format edge_ctrl_pb.DataState_PublicKey_Format, u
 	}
 }
 
+// newSigningKeyPublicKey converts a rotated JWT signing key to a public key used for JWT validation. The kid is the
+// SHA-1 of the DER encoded public key, which matches the signing key's id.
+func newSigningKeyPublicKey(signingKey *db.SigningKey) *edge_ctrl_pb.DataState_PublicKey {
+	block, _ := pem.Decode([]byte(signingKey.PublicKey))
+	if block == nil {
+		pfxlog.Logger().WithField("kid", signingKey.Id).Error("could not decode signing key public key PEM")
+		return nil
+	}
+	return newPublicKey(block.Bytes, edge_ctrl_pb.DataState_PublicKey_PKIXPublicKey, []edge_ctrl_pb.DataState_PublicKey_Usage{edge_ctrl_pb.DataState_PublicKey_JWTValidation})
+}
+
 func newPostureCheckById(tx *bbolt.Tx, ae *env.AppEnv, id string) (*edge_ctrl_pb.DataState_PostureCheck, error) {
 	postureModel, err := ae.GetStores().PostureCheck.LoadById(tx, id)
 
@@ -2054,6 +2097,24 @@ func (strategy *InstantStrategy) RevocationDelete(index uint64, revocation *db.R
 	strategy.handleRevocation(index, edge_ctrl_pb.DataState_Delete, revocation)
 }
 
+func (strategy *InstantStrategy) SigningKeyCreate(index uint64, signingKey *db.SigningKey) {
+	if publicKey := newSigningKeyPublicKey(signingKey); publicKey != nil {
+		strategy.handlePublicKey(index, edge_ctrl_pb.DataState_Create, publicKey)
+	}
+}
+
+func (strategy *InstantStrategy) SigningKeyUpdate(index uint64, signingKey *db.SigningKey) {
+	if publicKey := newSigningKeyPublicKey(signingKey); publicKey != nil {
+		strategy.handlePublicKey(index, edge_ctrl_pb.DataState_Create, publicKey)
+	}
+}
+
+func (strategy *InstantStrategy) SigningKeyDelete(index uint64, signingKey *db.SigningKey) {
+	if publicKey := newSigningKeyPublicKey(signingKey); publicKey != nil {
+		strategy.handlePublicKey(index, edge_ctrl_pb.DataState_Delete, publicKey)
+	}
+}
+
 func (strategy *InstantStrategy) handlePublicKey(index uint64, action edge_ctrl_pb.DataState_Action, publicKey *edge_ctrl_pb.DataState_PublicKey) {
 	strategy.addToChangeSet(index, &edge_ctrl_pb.DataState_Event{
 		Action:      action,
@@ -0,0 +1,352 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package webapis
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/fullsailor/pkcs7"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/foundation/v2/errorz"
+	nfpem "github.com/openziti/foundation/v2/pem"
+	"github.com/openziti/xweb/v3"
+	"github.com/openziti/ziti/v2/controller/change"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/env"
+	"github.com/openziti/ziti/v2/controller/model"
+)
+
+const (
+	EstCaCertsPath        = EstApiBaseUrl + "/cacerts"
+	EstSimpleEnrollPath   = EstApiBaseUrl + "/simpleenroll"
+	EstSimpleReEnrollPath = EstApiBaseUrl + "/simplereenroll"
+
+	// maxEstRequestSize is well above the size of a base64 encoded CSR
+	maxEstRequestSize = 64 * 1024
+
+	estEnrollmentMethodClaim = "em"
+)
+
+var _ xweb.ApiHandlerFactory = &EstApiFactory{}
+
+// EstApiFactory creates handlers for an RFC 7030 Enrollment over Secure Transport server. EST requests are mapped
+// onto the existing flows: simpleenroll with a bearer token runs an ott, ottca or token (ext-jwt) enrollment, and
+// simplereenroll, or simpleenroll with a network issued client certificate, extends the certificate authenticator
+// that certificate belongs to.
+type EstApiFactory struct {
+	appEnv *env.AppEnv
+}
+
+func (factory *EstApiFactory) Validate(*xweb.InstanceConfig) error {
+	return nil
+}
+
+func NewEstApiFactory(appEnv *env.AppEnv) *EstApiFactory {
+	return &EstApiFactory{
+		appEnv: appEnv,
+	}
+}
+
+func (factory *EstApiFactory) Binding() string {
+	return EstApiBinding
+}
+
+func (factory *EstApiFactory) New(_ *xweb.ServerConfig, options map[interface{}]interface{}) (xweb.ApiHandler, error) {
+	return NewEstApiHandler(factory.appEnv, options), nil
+}
+
+func NewEstApiHandler(appEnv *env.AppEnv, options map[interface{}]interface{}) *EstApiHandler {
+	return &EstApiHandler{
+		appEnv:  appEnv,
+		options: options,
+	}
+}
+
+type EstApiHandler struct {
+	appEnv  *env.AppEnv
+	options map[interface{}]interface{}
+}
+
+func (h *EstApiHandler) Binding() string {
+	return EstApiBinding
+}
+
+func (h *EstApiHandler) Options() map[interface{}]interface{} {
+	return h.options
+}
+
+func (h *EstApiHandler) RootPath() string {
+	return EstApiBaseUrl
+}
+
+func (h *EstApiHandler) IsHandler(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, h.RootPath()+"/")
+}
+
+func (h *EstApiHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case EstCaCertsPath:
+		if r.Method != http.MethodGet {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		h.caCerts(rw)
+	case EstSimpleEnrollPath, EstSimpleReEnrollPath:
+		if r.Method != http.MethodPost {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		h.enroll(rw, r)
+	default:
+		// csrattrs, serverkeygen and CA labels aren't supported. RFC 7030 allows a 404 for all of them.
+		rw.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (h *EstApiHandler) caCerts(rw http.ResponseWriter) {
+	certs := nfpem.PemBytesToCertificates(h.appEnv.GetConfig().Edge.CaPems())
+	h.respondWithCerts(rw, certs)
+}
+
+func (h *EstApiHandler) enroll(rw http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEstRequestSize))
+	if err != nil {
+		h.respondWithError(rw, errorz.NewCouldNotValidate(err))
+		return
+	}
+
+	csrPem, err := parseEstCsr(body)
+	if err != nil {
+		h.respondWithError(rw, errorz.NewCouldNotValidate(err))
+		return
+	}
+
+	changeCtx := change.New().SetSourceType(change.SourceTypeEst).
+		SetSourceAuth("edge").
+		SetSourceMethod(r.URL.Path).
+		SetSourceLocal(r.Host).
+		SetSourceRemote(r.RemoteAddr).
+		SetChangeAuthorType(change.AuthorTypeUnattributed)
+
+	var peerCerts []*x509.Certificate
+	if r.TLS != nil {
+		peerCerts = r.TLS.PeerCertificates
+	}
+
+	var certs []*x509.Certificate
+
+	if token := bearerToken(r); token != "" && r.URL.Path == EstSimpleEnrollPath {
+		certs, err = h.enrollWithToken(r, token, csrPem, peerCerts, changeCtx)
+	} else if len(peerCerts) > 0 {
+		certs, err = h.extendCert(peerCerts, csrPem, changeCtx)
+	} else {
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="ziti"`)
+		err = errorz.NewUnauthorized()
+	}
+
+	if err != nil {
+		h.respondWithError(rw, err)
+		return
+	}
+
+	h.respondWithCerts(rw, certs)
+}
+
+// enrollWithToken runs an enrollment for a one time token JWT issued by the controller, or an external JWT accepted
+// by a token issuer with cert enrollment enabled. The enrollment JWT isn't verified here, as it's only used to
+// locate the enrollment by its jti, exactly as the ziti enroller presents it.
+func (h *EstApiHandler) enrollWithToken(r *http.Request, token string, csrPem []byte, peerCerts []*x509.Certificate, changeCtx *change.Context) ([]*x509.Certificate, error) {
+	enrollContext := &model.EnrollmentContextHttp{
+		Data: &model.EnrollmentData{
+			ClientCsrPem: csrPem,
+		},
+		Certs:         peerCerts,
+		Method:        db.MethodEnrollToken,
+		ChangeContext: changeCtx,
+	}
+	enrollContext.SetHeadersFromRequest(r)
+
+	if method, jti := parseEnrollmentJwt(token); method != "" {
+		if method != db.MethodEnrollOtt && method != db.MethodEnrollOttCa {
+			return nil, errorz.NewCouldNotValidate(errors.New("enrollment method " + method + " is not supported over EST"))
+		}
+		enrollContext.Method = method
+		enrollContext.Token = jti
+	}
+
+	result, err := h.appEnv.GetManagers().Enrollment.Enroll(enrollContext)
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil || result.Authenticator == nil || result.Authenticator.ToCert() == nil {
+		return nil, errorz.NewUnauthorized()
+	}
+
+	return nfpem.PemStringToCertificates(result.Authenticator.ToCert().Pem), nil
+}
+
+// extendCert issues a new certificate for the network issued certificate authenticator the client authenticated
+// with. The new certificate replaces the old one right away: the CSR proves possession of the new key, and EST has
+// no second round trip in which the client could present the new certificate for verification.
+func (h *EstApiHandler) extendCert(peerCerts []*x509.Certificate, csrPem []byte, changeCtx *change.Context) ([]*x509.Certificate, error) {
+	managers := h.appEnv.GetManagers()
+
+	fingerprint := h.appEnv.GetFingerprintGenerator().FromCert(peerCerts[0])
+	authenticator, err := managers.Authenticator.ReadByFingerprint(fingerprint)
+	if err != nil || authenticator == nil {
+		return nil, errorz.NewUnauthorized()
+	}
+
+	identity, err := managers.Identity.Read(authenticator.IdentityId)
+	if err != nil || identity == nil || identity.Disabled {
+		return nil, errorz.NewUnauthorized()
+	}
+
+	changeCtx.SetChangeAuthorType(change.AuthorTypeIdentity).
+		SetChangeAuthorId(identity.Id).
+		SetChangeAuthorName(identity.Name)
+
+	chainPem, err := managers.Authenticator.ExtendCertForIdentity(identity.Id, authenticator.Id, peerCerts, string(csrPem), changeCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	certs := nfpem.PemBytesToCertificates(chainPem)
+	if len(certs) == 0 {
+		return nil, errorz.NewUnhandled(errors.New("extended certificate chain contained no certificates"))
+	}
+
+	leafPem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certs[0].Raw}))
+	if err = managers.Authenticator.VerifyExtendCertForIdentity(true, "", identity.Id, authenticator.Id, leafPem, changeCtx); err != nil {
+		return nil, err
+	}
+
+	return certs, nil
+}
+
+// respondWithCerts writes the certificates as a base64 encoded, degenerate PKCS#7 certs-only structure
+func (h *EstApiHandler) respondWithCerts(rw http.ResponseWriter, certs []*x509.Certificate) {
+	var data []byte
+	for _, cert := range certs {
+		data = append(data, cert.Raw...)
+	}
+
+	p7, err := pkcs7.DegenerateCertificate(data)
+	if err != nil {
+		pfxlog.Logger().WithError(err).Error("unexpected issue creating pkcs7 degenerate")
+		h.respondWithError(rw, errorz.NewUnhandled(err))
+		return
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(p7)
+	out := &bytes.Buffer{}
+	for len(encoded) > 64 {
+		out.WriteString(encoded[:64])
+		out.WriteString("\n")
+		encoded = encoded[64:]
+	}
+	out.WriteString(encoded)
+
+	rw.Header().Set("Content-Type", "application/pkcs7-mime; smime-type=certs-only")
+	rw.Header().Set("Content-Transfer-Encoding", "base64")
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(out.Bytes())
+}
+
+func (h *EstApiHandler) respondWithError(rw http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	message := "internal error"
+
+	var apiErr *errorz.ApiError
+	if errors.As(err, &apiErr) {
+		if apiErr.Status != 0 {
+			status = apiErr.Status
+		}
+		message = apiErr.Message
+		for name, values := range apiErr.Headers {
+			for _, value := range values {
+				rw.Header().Add(name, value)
+			}
+		}
+	}
+
+	if status >= http.StatusInternalServerError {
+		pfxlog.Logger().WithError(err).Error("EST request failed")
+	}
+
+	rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	rw.WriteHeader(status)
+	_, _ = rw.Write([]byte(message))
+}
+
+// parseEstCsr accepts a base64 encoded DER PKCS#10 request, as specified by RFC 7030, or a PEM encoded one, and
+// returns it PEM encoded for the enrollment flows
+func parseEstCsr(body []byte) ([]byte, error) {
+	body = bytes.TrimSpace(body)
+
+	if block, _ := pem.Decode(body); block != nil {
+		if _, err := x509.ParseCertificateRequest(block.Bytes); err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: block.Bytes}), nil
+	}
+
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(body)), ""))
+	if err != nil {
+		return nil, errors.New("request body is not a base64 encoded PKCS#10 certificate request")
+	}
+
+	if _, err = x509.ParseCertificateRequest(der); err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// parseEnrollmentJwt returns the enrollment method and token of a controller issued enrollment JWT. Both are empty
+// for other JWTs, such as those from external token issuers.
+func parseEnrollmentJwt(token string) (string, string) {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return "", ""
+	}
+
+	method, _ := claims[estEnrollmentMethodClaim].(string)
+	jti, _ := claims["jti"].(string)
+	if method == "" || jti == "" {
+		return "", ""
+	}
+
+	return method, jti
+}
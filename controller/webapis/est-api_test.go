@@ -0,0 +1,151 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package webapis
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/stretchr/testify/require"
+)
+
+func estMkCsrDer(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "est"}}, key)
+	require.NoError(t, err)
+	return der
+}
+
+func estMkJwt(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func TestParseEstCsr(t *testing.T) {
+	der := estMkCsrDer(t)
+
+	t.Run("base64 DER with line breaks is accepted", func(t *testing.T) {
+		req := require.New(t)
+		encoded := base64.StdEncoding.EncodeToString(der)
+		var wrapped strings.Builder
+		for len(encoded) > 64 {
+			wrapped.WriteString(encoded[:64] + "\r\n")
+			encoded = encoded[64:]
+		}
+		wrapped.WriteString(encoded + "\n")
+
+		result, err := parseEstCsr([]byte(wrapped.String()))
+		req.NoError(err)
+		block, _ := pem.Decode(result)
+		req.NotNil(block)
+		req.Equal("CERTIFICATE REQUEST", block.Type)
+		req.Equal(der, block.Bytes)
+	})
+
+	t.Run("PEM is accepted", func(t *testing.T) {
+		req := require.New(t)
+		result, err := parseEstCsr(pem.EncodeToMemory(&pem.Block{Type: "NEW CERTIFICATE REQUEST", Bytes: der}))
+		req.NoError(err)
+		block, _ := pem.Decode(result)
+		req.NotNil(block)
+		req.Equal("CERTIFICATE REQUEST", block.Type)
+		req.Equal(der, block.Bytes)
+	})
+
+	t.Run("invalid bodies are rejected", func(t *testing.T) {
+		req := require.New(t)
+		_, err := parseEstCsr([]byte("not base64!"))
+		req.Error(err)
+
+		_, err = parseEstCsr([]byte(base64.StdEncoding.EncodeToString([]byte("not a csr"))))
+		req.Error(err)
+	})
+}
+
+func TestParseEnrollmentJwt(t *testing.T) {
+	t.Run("enrollment JWTs return their method and token", func(t *testing.T) {
+		req := require.New(t)
+		method, jti := parseEnrollmentJwt(estMkJwt(t, jwt.MapClaims{"em": db.MethodEnrollOtt, "jti": "abc"}))
+		req.Equal(db.MethodEnrollOtt, method)
+		req.Equal("abc", jti)
+	})
+
+	t.Run("other JWTs return nothing", func(t *testing.T) {
+		req := require.New(t)
+		method, jti := parseEnrollmentJwt(estMkJwt(t, jwt.MapClaims{"sub": "someone", "jti": "abc"}))
+		req.Empty(method)
+		req.Empty(jti)
+
+		method, jti = parseEnrollmentJwt("not.a.jwt")
+		req.Empty(method)
+		req.Empty(jti)
+	})
+}
+
+func TestEstApiHandler_Routing(t *testing.T) {
+	h := NewEstApiHandler(nil, nil)
+
+	t.Run("only paths below the EST root are handled", func(t *testing.T) {
+		req := require.New(t)
+		req.True(h.IsHandler(httptest.NewRequest(http.MethodGet, EstCaCertsPath, nil)))
+		req.True(h.IsHandler(httptest.NewRequest(http.MethodPost, EstSimpleEnrollPath, nil)))
+		req.False(h.IsHandler(httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)))
+	})
+
+	t.Run("unsupported operations and methods are rejected", func(t *testing.T) {
+		req := require.New(t)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EstApiBaseUrl+"/csrattrs", nil))
+		req.Equal(http.StatusNotFound, rec.Code)
+
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, EstSimpleEnrollPath, nil))
+		req.Equal(http.StatusMethodNotAllowed, rec.Code)
+
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, EstCaCertsPath, nil))
+		req.Equal(http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("enrollment without credentials is unauthorized", func(t *testing.T) {
+		req := require.New(t)
+		body := base64.StdEncoding.EncodeToString(estMkCsrDer(t))
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, EstSimpleEnrollPath, strings.NewReader(body)))
+		req.Equal(http.StatusUnauthorized, rec.Code)
+		req.NotEmpty(rec.Header().Get("WWW-Authenticate"))
+	})
+}
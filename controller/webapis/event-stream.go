@@ -0,0 +1,394 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package webapis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/foundation/v2/errorz"
+	"github.com/openziti/ziti/v2/common"
+	"github.com/openziti/ziti/v2/common/eid"
+	"github.com/openziti/ziti/v2/controller/api"
+	"github.com/openziti/ziti/v2/controller/env"
+	"github.com/openziti/ziti/v2/controller/event"
+	"github.com/openziti/ziti/v2/controller/permissions"
+	"github.com/openziti/ziti/v2/controller/response"
+)
+
+const (
+	ManagementEventStreamPath = ManagementRestApiBaseUrlLatest + "/events/stream"
+
+	eventStreamBufferSize      = 256
+	eventStreamKeepAlivePeriod = 30 * time.Second
+)
+
+// eventStreamPermissions maps entity change event entity types to the permission which grants read access to them.
+// Entity types without an entry are only streamed to admins.
+var eventStreamPermissions = map[string]string{
+	"apiSessions":               permissions.Ops,
+	"authPolicies":              "auth-policy",
+	"cas":                       "ca",
+	"configs":                   "config",
+	"configTypes":               "config-type",
+	"controllers":               "controllers",
+	"edgeRouterPolicies":        "edge-router-policy",
+	"edgeRouters":               "router",
+	"enrollments":               "enrollment",
+	"externalJwtSigners":        "external-jwt-signer",
+	"identities":                "identity",
+	"postureChecks":             "posture-check",
+	"routers":                   "router",
+	"serviceEdgeRouterPolicies": "service-edge-router-policy",
+	"servicePolicies":           "service-policy",
+	"services":                  "service",
+	"sessions":                  permissions.Ops,
+	"terminators":               "terminator",
+}
+
+// eventStreamNamespaces maps the event namespaces which may be streamed to the permission required to read them.
+// Entity change events are checked per entity type.
+var eventStreamNamespaces = map[string]string{
+	event.EntityChangeEventNS: "",
+	event.CircuitEventNS:      permissions.Ops,
+	event.TerminatorEventNS:   "terminator",
+}
+
+// eventStreamFilter decides which events are sent to a single event stream client. It is built when the stream is
+// opened and rebuilt on each keepalive, so permissions are resolved up front rather than per event.
+type eventStreamFilter struct {
+	namespaces  map[string]struct{}
+	entityTypes map[string]struct{}
+	ids         map[string]struct{}
+	isAdmin     bool
+	readable    map[string]bool
+}
+
+// newEventStreamFilter builds a filter from the stream query parameters. Each of types, entityType and id may be
+// given multiple times or as comma separated lists. canRead reports whether the caller may read entities guarded
+// by the given permission, with the empty permission meaning admin access. Explicitly requested event types or
+// entity types the caller may not read are rejected. Without an explicit entity type, entity change events are
+// silently limited to the readable types.
+func newEventStreamFilter(query url.Values, canRead func(permission string) bool) (*eventStreamFilter, error) {
+	result := &eventStreamFilter{
+		namespaces:  queryValueSet(query, "types"),
+		entityTypes: queryValueSet(query, "entityType"),
+		ids:         queryValueSet(query, "id"),
+		isAdmin:     canRead(""),
+		readable:    map[string]bool{},
+	}
+
+	for _, permission := range eventStreamPermissions {
+		if _, found := result.readable[permission]; !found {
+			result.readable[permission] = result.isAdmin || canRead(permission)
+		}
+	}
+
+	namespaceReadable := func(namespace string) bool {
+		if namespace == event.EntityChangeEventNS {
+			for _, readable := range result.readable {
+				if readable {
+					return true
+				}
+			}
+			return result.isAdmin
+		}
+		return result.isAdmin || canRead(eventStreamNamespaces[namespace])
+	}
+
+	if len(result.namespaces) == 0 {
+		for namespace := range eventStreamNamespaces {
+			if namespaceReadable(namespace) {
+				result.namespaces[namespace] = struct{}{}
+			}
+		}
+		if len(result.namespaces) == 0 {
+			return nil, errorz.NewUnauthorized()
+		}
+	}
+
+	for namespace := range result.namespaces {
+		if _, found := eventStreamNamespaces[namespace]; !found {
+			return nil, errorz.NewFieldApiError(errorz.NewFieldError("unsupported event type", "types", namespace))
+		}
+		if !namespaceReadable(namespace) {
+			return nil, errorz.NewUnauthorized()
+		}
+	}
+
+	for entityType := range result.entityTypes {
+		if !result.canReadEntityType(entityType) {
+			return nil, errorz.NewUnauthorized()
+		}
+	}
+
+	return result, nil
+}
+
+func queryValueSet(query url.Values, key string) map[string]struct{} {
+	result := map[string]struct{}{}
+	for _, value := range query[key] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				result[v] = struct{}{}
+			}
+		}
+	}
+	return result
+}
+
+func (self *eventStreamFilter) canReadEntityType(entityType string) bool {
+	if self.isAdmin {
+		return true
+	}
+	permission, found := eventStreamPermissions[entityType]
+	return found && self.readable[permission]
+}
+
+func (self *eventStreamFilter) hasNamespace(namespace string) bool {
+	_, found := self.namespaces[namespace]
+	return found
+}
+
+func (self *eventStreamFilter) matchesIds(ids ...string) bool {
+	if len(self.ids) == 0 {
+		return true
+	}
+	for _, id := range ids {
+		if _, found := self.ids[id]; found && id != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *eventStreamFilter) acceptEntityChange(evt *event.EntityChangeEvent) bool {
+	if !self.hasNamespace(event.EntityChangeEventNS) || !self.canReadEntityType(evt.EntityType) {
+		return false
+	}
+	if len(self.entityTypes) > 0 {
+		if _, found := self.entityTypes[evt.EntityType]; !found {
+			return false
+		}
+	}
+	return self.matchesIds(entityStateId(evt.InitialState), entityStateId(evt.FinalState))
+}
+
+func (self *eventStreamFilter) acceptCircuit(evt *event.CircuitEvent) bool {
+	return self.hasNamespace(event.CircuitEventNS) &&
+		self.matchesIds(evt.CircuitId, evt.ServiceId, evt.TerminatorId, evt.ClientId)
+}
+
+func (self *eventStreamFilter) acceptTerminator(evt *event.TerminatorEvent) bool {
+	return self.hasNamespace(event.TerminatorEventNS) &&
+		self.matchesIds(evt.TerminatorId, evt.ServiceId, evt.RouterId, evt.HostId)
+}
+
+func entityStateId(state any) string {
+	if entity, ok := state.(interface{ GetId() string }); ok {
+		return entity.GetId()
+	}
+	return ""
+}
+
+type streamedEvent struct {
+	namespace string
+	event     any
+}
+
+// eventStream receives events from the dispatcher and hands them off to the http handler goroutine. The dispatcher
+// delivers some events synchronously, so a client which can't keep up is disconnected instead of blocking it.
+type eventStream struct {
+	filter     atomic.Pointer[eventStreamFilter]
+	events     chan *streamedEvent
+	overflowed chan struct{}
+}
+
+func (self *eventStream) accept(namespace string, evt any) {
+	select {
+	case self.events <- &streamedEvent{namespace: namespace, event: evt}:
+	default:
+		select {
+		case self.overflowed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (self *eventStream) AcceptEntityChangeEvent(evt *event.EntityChangeEvent) {
+	if self.filter.Load().acceptEntityChange(evt) {
+		self.accept(event.EntityChangeEventNS, evt)
+	}
+}
+
+func (self *eventStream) AcceptCircuitEvent(evt *event.CircuitEvent) {
+	if self.filter.Load().acceptCircuit(evt) {
+		self.accept(event.CircuitEventNS, evt)
+	}
+}
+
+func (self *eventStream) AcceptTerminatorEvent(evt *event.TerminatorEvent) {
+	if self.filter.Load().acceptTerminator(evt) {
+		self.accept(event.TerminatorEventNS, evt)
+	}
+}
+
+// newEventStreamHandler returns a handler which streams entity change, circuit and terminator events to management
+// API clients as server-sent events. It isn't wrapped in the API timeout handler, as streams are long-lived.
+func newEventStreamHandler(ae *env.AppEnv) http.Handler {
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set(ZitiInstanceId, ae.InstanceId)
+
+		rc, err := ae.CreateRequestContext(rw, r)
+		if err != nil {
+			env.WriteHttpError(rw, err)
+			return
+		}
+
+		api.AddRequestContextToHttpContext(r, rc)
+		response.AddHeaders(rc)
+
+		filter, err := authorizeEventStream(rc)
+		if err != nil {
+			rc.RespondWithError(err)
+			return
+		}
+
+		// the API session is resolved again from the request's tokens, so a deleted or revoked session, a disabled
+		// identity or removed permissions are picked up while the stream is open
+		reauthorize := func() (*eventStreamFilter, error) {
+			securityTokenCtx, err := common.NewSecurityTokenCtx(r, ae.TokenIssuerCache)
+			if err != nil {
+				return nil, err
+			}
+			return authorizeEventStream(&response.RequestContext{
+				Id:             eid.New(),
+				ResponseWriter: rw,
+				Request:        r,
+				StartTime:      time.Now(),
+				SecurityCtx:    env.NewSecurityCtx(securityTokenCtx, ae),
+			})
+		}
+
+		serveEventStream(ae.GetEventDispatcher(), filter, reauthorize, eventStreamKeepAlivePeriod, rw, r)
+	})
+
+	return api.WrapCorsHandler(handler)
+}
+
+func authorizeEventStream(rc *response.RequestContext) (*eventStreamFilter, error) {
+	return newEventStreamFilter(rc.Request.URL.Query(), func(permission string) bool {
+		if permission == "" {
+			return permissions.IsAdmin().IsAllowed(rc) || permissions.HasAdminReadOnlyAccess().IsAllowed(rc)
+		}
+		rc.InitPermissionsContext(permissions.Management, permission, permissions.Read)
+		return permissions.DefaultManagementAccess().IsAllowed(rc)
+	})
+}
+
+// serveEventStream writes events accepted by filter to rw until the client goes away. On each keepalive the stream is
+// reauthorized, and closed if the caller is no longer allowed to read it.
+func serveEventStream(dispatcher event.Dispatcher, filter *eventStreamFilter, reauthorize func() (*eventStreamFilter, error),
+	keepAlivePeriod time.Duration, rw http.ResponseWriter, r *http.Request) {
+	log := pfxlog.Logger().WithField("remote", r.RemoteAddr)
+
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	stream := &eventStream{
+		events:     make(chan *streamedEvent, eventStreamBufferSize),
+		overflowed: make(chan struct{}, 1),
+	}
+	stream.filter.Store(filter)
+
+	dispatcher.AddEntityChangeEventHandler(stream)
+	dispatcher.AddCircuitEventHandler(stream)
+	dispatcher.AddTerminatorEventHandler(stream)
+
+	defer func() {
+		dispatcher.RemoveEntityChangeEventHandler(stream)
+		dispatcher.RemoveCircuitEventHandler(stream)
+		dispatcher.RemoveTerminatorEventHandler(stream)
+		log.Debug("management event stream closed")
+	}()
+
+	// the server's write timeout is sized for ordinary requests and would end the stream shortly after it starts
+	if err := http.NewResponseController(rw).SetWriteDeadline(time.Time{}); err != nil {
+		log.WithError(err).Warn("unable to clear write deadline, server write timeout may close the event stream")
+	}
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	log.Debug("management event stream opened")
+
+	keepAlive := time.NewTicker(keepAlivePeriod)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case evt := <-stream.events:
+			if err := writeStreamedEvent(rw, evt); err != nil {
+				log.WithError(err).Debug("unable to write to management event stream")
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			updatedFilter, err := reauthorize()
+			if err != nil {
+				log.WithError(err).Debug("management event stream no longer authorized, closing stream")
+				_, _ = fmt.Fprint(rw, "event: error\ndata: {\"error\":\"api session is no longer valid or authorized\"}\n\n")
+				flusher.Flush()
+				return
+			}
+			stream.filter.Store(updatedFilter)
+
+			if _, err = fmt.Fprint(rw, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-stream.overflowed:
+			log.Warn("management event stream client not keeping up, closing stream")
+			_, _ = fmt.Fprint(rw, "event: error\ndata: {\"error\":\"event buffer overflow, events were dropped\"}\n\n")
+			flusher.Flush()
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeStreamedEvent(rw http.ResponseWriter, evt *streamedEvent) error {
+	buf, err := json.Marshal(evt.event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(rw, "event: %s\ndata: %s\n\n", evt.namespace, buf)
+	return err
+}
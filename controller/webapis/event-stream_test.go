@@ -0,0 +1,246 @@
+package webapis
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openziti/foundation/v2/errorz"
+	"github.com/openziti/ziti/v2/controller/event"
+	"github.com/openziti/ziti/v2/controller/permissions"
+	"github.com/stretchr/testify/require"
+)
+
+type testStreamEntity struct {
+	id string
+}
+
+func (self *testStreamEntity) GetId() string {
+	return self.id
+}
+
+func grantPermissions(granted ...string) func(string) bool {
+	return func(permission string) bool {
+		for _, p := range granted {
+			if p == permission {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestEventStreamFilter(t *testing.T) {
+	identityChange := &event.EntityChangeEvent{EntityType: "identities", FinalState: &testStreamEntity{id: "id1"}}
+	serviceChange := &event.EntityChangeEvent{EntityType: "services", InitialState: &testStreamEntity{id: "svc1"}}
+	circuit := &event.CircuitEvent{CircuitId: "c1", ServiceId: "svc1"}
+	terminator := &event.TerminatorEvent{TerminatorId: "t1", ServiceId: "svc2"}
+
+	t.Run("admin receives everything", func(t *testing.T) {
+		req := require.New(t)
+		filter, err := newEventStreamFilter(url.Values{}, grantPermissions(""))
+		req.NoError(err)
+		req.True(filter.acceptEntityChange(identityChange))
+		req.True(filter.acceptEntityChange(&event.EntityChangeEvent{EntityType: "authenticators"}))
+		req.True(filter.acceptCircuit(circuit))
+		req.True(filter.acceptTerminator(terminator))
+	})
+
+	t.Run("unfiltered streams are limited to readable entity types", func(t *testing.T) {
+		req := require.New(t)
+		filter, err := newEventStreamFilter(url.Values{}, grantPermissions("service"))
+		req.NoError(err)
+		req.True(filter.acceptEntityChange(serviceChange))
+		req.False(filter.acceptEntityChange(identityChange))
+		req.False(filter.acceptEntityChange(&event.EntityChangeEvent{EntityType: "authenticators"}))
+		req.False(filter.acceptCircuit(circuit))
+		req.False(filter.acceptTerminator(terminator))
+	})
+
+	t.Run("filters by type, entity type and id", func(t *testing.T) {
+		req := require.New(t)
+		query := url.Values{
+			"types":      {"entityChange,circuit"},
+			"entityType": {"services"},
+			"id":         {"svc1"},
+		}
+		filter, err := newEventStreamFilter(query, grantPermissions(""))
+		req.NoError(err)
+		req.True(filter.acceptEntityChange(serviceChange))
+		req.False(filter.acceptEntityChange(identityChange))
+		req.True(filter.acceptCircuit(circuit))
+		req.False(filter.acceptTerminator(&event.TerminatorEvent{TerminatorId: "t1", ServiceId: "svc1"}))
+	})
+
+	t.Run("requests for unreadable events are rejected", func(t *testing.T) {
+		req := require.New(t)
+		_, err := newEventStreamFilter(url.Values{"types": {"circuit"}}, grantPermissions("service"))
+		req.Error(err)
+
+		_, err = newEventStreamFilter(url.Values{"entityType": {"identities"}}, grantPermissions("service"))
+		req.Error(err)
+
+		_, err = newEventStreamFilter(url.Values{}, grantPermissions())
+		req.Error(err)
+
+		filter, err := newEventStreamFilter(url.Values{"types": {"circuit"}}, grantPermissions(permissions.Ops))
+		req.NoError(err)
+		req.True(filter.acceptCircuit(circuit))
+	})
+
+	t.Run("unknown event types are rejected", func(t *testing.T) {
+		req := require.New(t)
+		_, err := newEventStreamFilter(url.Values{"types": {"metrics"}}, grantPermissions(""))
+		req.Error(err)
+	})
+}
+
+// testStreamDispatcher records the handlers a stream registers. Only the methods used by streams are implemented.
+type testStreamDispatcher struct {
+	event.Dispatcher
+	entityChangeHandlers chan event.EntityChangeEventHandler
+}
+
+func (self *testStreamDispatcher) AddEntityChangeEventHandler(handler event.EntityChangeEventHandler) {
+	self.entityChangeHandlers <- handler
+}
+
+func (self *testStreamDispatcher) RemoveEntityChangeEventHandler(event.EntityChangeEventHandler) {}
+
+func (self *testStreamDispatcher) AddCircuitEventHandler(event.CircuitEventHandler) {}
+
+func (self *testStreamDispatcher) RemoveCircuitEventHandler(event.CircuitEventHandler) {}
+
+func (self *testStreamDispatcher) AddTerminatorEventHandler(event.TerminatorEventHandler) {}
+
+func (self *testStreamDispatcher) RemoveTerminatorEventHandler(event.TerminatorEventHandler) {}
+
+func TestEventStreamOutlivesServerTimeouts(t *testing.T) {
+	req := require.New(t)
+
+	filter, err := newEventStreamFilter(url.Values{}, grantPermissions(""))
+	req.NoError(err)
+
+	dispatcher := &testStreamDispatcher{entityChangeHandlers: make(chan event.EntityChangeEventHandler, 1)}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		serveEventStream(dispatcher, filter, func() (*eventStreamFilter, error) {
+			return filter, nil
+		}, eventStreamKeepAlivePeriod, rw, r)
+	}))
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	req.NoError(err)
+	defer func() { _ = resp.Body.Close() }()
+	req.Equal(http.StatusOK, resp.StatusCode)
+
+	var handler event.EntityChangeEventHandler
+	select {
+	case handler = <-dispatcher.entityChangeHandlers:
+	case <-time.After(time.Second):
+		req.Fail("stream did not register for entity change events")
+	}
+
+	// wait until well past the write timeout before sending anything
+	time.Sleep(500 * time.Millisecond)
+	handler.AcceptEntityChangeEvent(&event.EntityChangeEvent{EntityType: "identities", FinalState: &testStreamEntity{id: "id1"}})
+
+	lines := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(resp.Body).ReadString('\n')
+		lines <- line
+	}()
+
+	select {
+	case line := <-lines:
+		req.Equal("event: "+event.EntityChangeEventNS, strings.TrimSpace(line))
+	case <-time.After(2 * time.Second):
+		req.Fail("no event received")
+	}
+}
+
+func TestEventStreamClosedWhenNoLongerAuthorized(t *testing.T) {
+	req := require.New(t)
+
+	filter, err := newEventStreamFilter(url.Values{}, grantPermissions(""))
+	req.NoError(err)
+
+	var sessionDeleted atomic.Bool
+	reauthorize := func() (*eventStreamFilter, error) {
+		if sessionDeleted.Load() {
+			return nil, errorz.NewUnauthorized()
+		}
+		// the caller lost admin, but may still read identities
+		return newEventStreamFilter(url.Values{}, grantPermissions("identity"))
+	}
+
+	dispatcher := &testStreamDispatcher{entityChangeHandlers: make(chan event.EntityChangeEventHandler, 1)}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		serveEventStream(dispatcher, filter, reauthorize, 50*time.Millisecond, rw, r)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	req.NoError(err)
+	defer func() { _ = resp.Body.Close() }()
+	req.Equal(http.StatusOK, resp.StatusCode)
+
+	var handler event.EntityChangeEventHandler
+	select {
+	case handler = <-dispatcher.entityChangeHandlers:
+	case <-time.After(time.Second):
+		req.Fail("stream did not register for entity change events")
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readUntil := func(prefix string) string {
+		lines := make(chan string, 1)
+		go func() {
+			var result strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				result.WriteString(line)
+				if err != nil || strings.HasPrefix(line, prefix) {
+					lines <- result.String()
+					return
+				}
+			}
+		}()
+
+		select {
+		case data := <-lines:
+			return data
+		case <-time.After(2 * time.Second):
+			req.Fail("stream did not send " + prefix)
+			return ""
+		}
+	}
+
+	// the keepalive is written after the filter is replaced
+	readUntil(": keepalive")
+
+	handler.AcceptEntityChangeEvent(&event.EntityChangeEvent{EntityType: "services", FinalState: &testStreamEntity{id: "s1"}})
+	handler.AcceptEntityChangeEvent(&event.EntityChangeEvent{EntityType: "identities", FinalState: &testStreamEntity{id: "id1"}})
+
+	data := readUntil("event: ")
+	req.True(strings.HasSuffix(data, "event: "+event.EntityChangeEventNS+"\n"))
+
+	sessionDeleted.Store(true)
+
+	data = readUntil("data: {\"error\"")
+	req.NotContains(data, "event: "+event.EntityChangeEventNS, "events for types which are no longer readable should not be streamed")
+	req.True(strings.HasSuffix(data, "event: error\ndata: {\"error\":\"api session is no longer valid or authorized\"}\n"))
+
+	_, err = reader.ReadString('\n')
+	req.NoError(err)
+	_, err = reader.ReadString('\n')
+	req.ErrorIs(err, io.EOF, "stream should be closed")
+}
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-openapi/runtime"
 	"github.com/openziti/edge-api/rest_management_api_client"
 	"github.com/openziti/edge-api/rest_management_api_server"
 	"github.com/openziti/xweb/v3"
@@ -75,9 +76,10 @@ func (factory ManagementApiFactory) New(_ *xweb.ServerConfig, options map[interf
 }
 
 type ManagementApiHandler struct {
-	handler http.Handler
-	appEnv  *env.AppEnv
-	options map[interface{}]interface{}
+	handler            http.Handler
+	eventStreamHandler http.Handler
+	appEnv             *env.AppEnv
+	options            map[interface{}]interface{}
 }
 
 func (managementApi ManagementApiHandler) Binding() string {
@@ -97,7 +99,11 @@ func (managementApi ManagementApiHandler) IsHandler(r *http.Request) bool {
 }
 
 func (managementApi ManagementApiHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	managementApi.handler.ServeHTTP(writer, request)
+	if request.URL.Path == ManagementEventStreamPath {
+		managementApi.eventStreamHandler.ServeHTTP(writer, request)
+	} else {
+		managementApi.handler.ServeHTTP(writer, request)
+	}
 }
 
 func NewManagementApiHandler(ae *env.AppEnv, options map[interface{}]interface{}) (*ManagementApiHandler, error) {
@@ -107,6 +113,7 @@ func NewManagementApiHandler(ae *env.AppEnv, options map[interface{}]interface{}
 	}
 
 	managementApi.handler = managementApi.newHandler(ae)
+	managementApi.eventStreamHandler = newEventStreamHandler(ae)
 
 	return managementApi, nil
 }
@@ -146,6 +153,12 @@ func (managementApi ManagementApiHandler) newHandler(ae *env.AppEnv) http.Handle
 		//after request context is filled so that api session is present for session expiration headers
 		response.AddHeaders(rc)
 
+		if handler, found := ae.ManagementApiHandlers[r.URL.Path]; found {
+			rc.SetProducer(runtime.JSONProducer())
+			handler(ae, rc)
+			return
+		}
+
 		innerManagementHandler.ServeHTTP(rw, r)
 	})
 
@@ -0,0 +1,176 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package webapis
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/michaelquigley/pfxlog"
+	"github.com/openziti/xweb/v3"
+	"github.com/openziti/ziti/v2/controller/env"
+	"github.com/openziti/ziti/v2/controller/model"
+)
+
+const (
+	PkiApiCrlPath  = PkiApiBaseUrl + "/crl"
+	PkiApiOcspPath = PkiApiBaseUrl + "/ocsp"
+
+	// maxOcspRequestSize is well above the size of a single certificate request
+	maxOcspRequestSize = 64 * 1024
+)
+
+var _ xweb.ApiHandlerFactory = &PkiApiFactory{}
+
+// PkiApiFactory creates handlers which publish the revocation status of the certificates the controller issued
+// during enrollment, as a CRL and through an OCSP responder. Both are unauthenticated, as relying parties need to
+// reach them without a network identity.
+type PkiApiFactory struct {
+	appEnv *env.AppEnv
+}
+
+func (factory *PkiApiFactory) Validate(*xweb.InstanceConfig) error {
+	return nil
+}
+
+func NewPkiApiFactory(appEnv *env.AppEnv) *PkiApiFactory {
+	return &PkiApiFactory{
+		appEnv: appEnv,
+	}
+}
+
+func (factory *PkiApiFactory) Binding() string {
+	return PkiApiBinding
+}
+
+func (factory *PkiApiFactory) New(_ *xweb.ServerConfig, options map[interface{}]interface{}) (xweb.ApiHandler, error) {
+	return NewPkiApiHandler(factory.appEnv, options), nil
+}
+
+func NewPkiApiHandler(appEnv *env.AppEnv, options map[interface{}]interface{}) *PkiApiHandler {
+	return &PkiApiHandler{
+		appEnv:  appEnv,
+		options: options,
+	}
+}
+
+type PkiApiHandler struct {
+	appEnv  *env.AppEnv
+	options map[interface{}]interface{}
+}
+
+func (h *PkiApiHandler) Binding() string {
+	return PkiApiBinding
+}
+
+func (h *PkiApiHandler) Options() map[interface{}]interface{} {
+	return h.options
+}
+
+func (h *PkiApiHandler) RootPath() string {
+	return PkiApiBaseUrl
+}
+
+func (h *PkiApiHandler) IsHandler(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, h.RootPath()+"/")
+}
+
+func (h *PkiApiHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == PkiApiCrlPath:
+		h.serveCrl(rw, r)
+	case r.URL.Path == PkiApiOcspPath || strings.HasPrefix(r.URL.Path, PkiApiOcspPath+"/"):
+		h.serveOcsp(rw, r)
+	default:
+		rw.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// serveCrl returns the CRL DER encoded, or PEM encoded if requested with ?format=pem
+func (h *PkiApiHandler) serveCrl(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	crl, err := h.appEnv.GetManagers().CertRevocation.GetCrl()
+	if err != nil {
+		pfxlog.Logger().WithError(err).Error("unable to generate CRL")
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body := crl.Der
+	contentType := "application/pkix-crl"
+	if r.URL.Query().Get("format") == "pem" {
+		body = pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crl.Der})
+		contentType = "application/x-pem-file"
+	}
+
+	rw.Header().Set("Content-Type", contentType)
+	rw.Header().Set("Last-Modified", crl.ThisUpdate.UTC().Format(http.TimeFormat))
+	rw.Header().Set("Expires", crl.NextUpdate.UTC().Format(http.TimeFormat))
+	rw.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int64(time.Until(crl.NextUpdate).Seconds())))
+	rw.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodGet {
+		_, _ = rw.Write(body)
+	}
+}
+
+// serveOcsp handles OCSP requests, either POSTed or base64 encoded in the path of a GET, as described in RFC 6960
+// appendix A.1
+func (h *PkiApiHandler) serveOcsp(rw http.ResponseWriter, r *http.Request) {
+	var request []byte
+	var err error
+
+	switch r.Method {
+	case http.MethodPost:
+		request, err = io.ReadAll(io.LimitReader(r.Body, maxOcspRequestSize))
+	case http.MethodGet:
+		var encoded string
+		if encoded, err = url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), PkiApiOcspPath+"/")); err == nil {
+			request, err = base64.StdEncoding.DecodeString(encoded)
+		}
+	default:
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.appEnv.GetManagers().CertRevocation.CreateOcspResponse(request)
+	if err != nil {
+		pfxlog.Logger().WithError(err).Error("unable to create OCSP response")
+	}
+
+	rw.Header().Set("Content-Type", "application/ocsp-response")
+	if r.Method == http.MethodGet {
+		rw.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int64(model.IssuedOcspValidity.Seconds())))
+	}
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(response)
+}
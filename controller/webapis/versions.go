@@ -32,6 +32,8 @@ const (
 	ManagementRestApiBaseUrlV1        = ManagementRestApiBase + RestApiV1
 	ControllerHealthCheckApiBaseUrlV1 = ControllerHealthCheck + RestApiV1
 	OidcRestApiBaseUrl                = "/oidc"
+	PkiApiBaseUrl                     = "/pki"
+	EstApiBaseUrl                     = "/.well-known/est"
 
 	ClientRestApiBaseUrlLatest     = ClientRestApiBaseUrlV1
 	ManagementRestApiBaseUrlLatest = ManagementRestApiBaseUrlV1
@@ -48,6 +50,8 @@ const (
 	ManagementApiBinding            = "edge-management"
 	OidcApiBinding                  = "edge-oidc"
 	ControllerHealthCheckApiBinding = "health-checks"
+	PkiApiBinding                   = "edge-pki"
+	EstApiBinding                   = "edge-est"
 )
 
 // AllApiBindingVersions is a map of: API Binding -> Api Version -> API Path
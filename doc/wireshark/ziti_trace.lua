@@ -0,0 +1,82 @@
+-- Wireshark dissector for OpenZiti pipe trace records, as written by
+-- `ziti fabric stream traces --pcap <file>`. See doc/trace-pcapng.md for the record layout.
+--
+-- Records are recognised either with the LINKTYPE_USER0 link type or inside UDP on port 6262.
+
+local ziti_trace = Proto("ziti_trace", "OpenZiti Trace Record")
+
+local f = ziti_trace.fields
+f.magic = ProtoField.string("ziti_trace.magic", "Magic")
+f.version = ProtoField.uint8("ziti_trace.version", "Version")
+f.flags = ProtoField.uint8("ziti_trace.flags", "Flags", base.HEX)
+f.rx = ProtoField.bool("ziti_trace.rx", "Received", 8, nil, 0x01)
+f.timestamp = ProtoField.int64("ziti_trace.timestamp", "Timestamp (ns)")
+f.content_type = ProtoField.int32("ziti_trace.content_type", "Content Type")
+f.sequence = ProtoField.int32("ziti_trace.sequence", "Sequence")
+f.reply_for = ProtoField.int32("ziti_trace.reply_for", "Reply For")
+f.length = ProtoField.int32("ziti_trace.length", "Message Length")
+f.identity = ProtoField.string("ziti_trace.identity", "Identity")
+f.channel = ProtoField.string("ziti_trace.channel", "Channel")
+f.decode = ProtoField.string("ziti_trace.decode", "Decode")
+
+function ziti_trace.dissector(buffer, pinfo, tree)
+    if buffer:len() < 40 or buffer(0, 4):string() ~= "ZTRC" then
+        return 0
+    end
+
+    pinfo.cols.protocol = "ZITI_TRACE"
+
+    local subtree = tree:add(ziti_trace, buffer(), "OpenZiti Trace Record")
+    subtree:add(f.magic, buffer(0, 4))
+    subtree:add(f.version, buffer(4, 1))
+    local flags = subtree:add(f.flags, buffer(5, 1))
+    flags:add(f.rx, buffer(5, 1))
+    subtree:add(f.timestamp, buffer(8, 8))
+    subtree:add(f.content_type, buffer(16, 4))
+    subtree:add(f.sequence, buffer(20, 4))
+    subtree:add(f.reply_for, buffer(24, 4))
+    subtree:add(f.length, buffer(28, 4))
+
+    local offset = 32
+    local identityLen = buffer(offset, 2):uint()
+    offset = offset + 2
+    local identity = buffer(offset, identityLen):string()
+    subtree:add(f.identity, buffer(offset, identityLen))
+    offset = offset + identityLen
+
+    local channelLen = buffer(offset, 2):uint()
+    offset = offset + 2
+    local channel = buffer(offset, channelLen):string()
+    subtree:add(f.channel, buffer(offset, channelLen))
+    offset = offset + channelLen
+
+    local decodeLen = buffer(offset, 4):uint()
+    offset = offset + 4
+    local decode = ""
+    if decodeLen > 0 and offset + decodeLen <= buffer:len() then
+        decode = buffer(offset, decodeLen):string()
+        subtree:add(f.decode, buffer(offset, decodeLen))
+    end
+
+    local flow = "->"
+    if bit.band(buffer(5, 1):uint(), 0x01) ~= 0 then
+        flow = "<-"
+    end
+
+    local info = string.format("%s/%s %s #%d", identity, channel, flow, buffer(20, 4):int())
+    local replyFor = buffer(24, 4):int()
+    if replyFor ~= -1 then
+        info = info .. string.format(" >%d", replyFor)
+    end
+    if decode ~= "" then
+        info = info .. " " .. decode
+    else
+        info = info .. string.format(" content-type=%d", buffer(16, 4):int())
+    end
+    pinfo.cols.info = info
+
+    return buffer:len()
+end
+
+DissectorTable.get("wtap_encap"):add(wtap.USER0, ziti_trace)
+DissectorTable.get("udp.port"):add(6262, ziti_trace)
@@ -0,0 +1,353 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package ascode
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	SecretsInclude = "include"
+	SecretsRedact  = "redact"
+	SecretsEncrypt = "encrypt"
+
+	// SecretsPassphraseEnvVar is read when no passphrase file is given
+	SecretsPassphraseEnvVar = "ZITI_ASCODE_SECRETS_PASSPHRASE"
+
+	RedactedValue = "<redacted>"
+
+	encryptedPrefix  = "enc:v1:"
+	saltLength       = 16
+	keyLength        = 32
+	pbkdf2Iterations = 600000
+)
+
+// sensitiveKeyNames are the final words of keys which hold secrets. Only the final word is checked, so that keys
+// which merely describe a secret, such as tokenEndpoint or secretName, are left alone.
+var sensitiveKeyNames = map[string]struct{}{
+	"password":    {},
+	"passwd":      {},
+	"passphrase":  {},
+	"secret":      {},
+	"token":       {},
+	"credential":  {},
+	"credentials": {},
+	"privatekey":  {},
+	"apikey":      {},
+}
+
+// IsSensitiveKey reports whether values stored under the given key should be treated as secrets. The key is split
+// into words on camel case and separators, so clientSecret, client_secret and CLIENT-SECRET all match, and then
+// the last word, or the last two joined, is compared against the known secret names.
+func IsSensitiveKey(key string) bool {
+	words := keyWords(key)
+	if len(words) == 0 {
+		return false
+	}
+	last := words[len(words)-1]
+	if _, found := sensitiveKeyNames[last]; found {
+		return true
+	}
+	if len(words) > 1 {
+		_, found := sensitiveKeyNames[words[len(words)-2]+last]
+		return found
+	}
+	return false
+}
+
+// keyWords splits a key into lower case words at separators and camel case boundaries. A run of capitals is
+// treated as one word, so APIKey is split into api and key.
+func keyWords(key string) []string {
+	var words []string
+	runes := []rune(key)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextIsLower {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, strings.ToLower(string(runes[start:])))
+	}
+	return words
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// LoadSecretsPassphrase reads the passphrase from the given file, falling back to the environment
+func LoadSecretsPassphrase(file string) (string, error) {
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("unable to read secrets passphrase file %s (%w)", file, err)
+		}
+		return strings.TrimSpace(string(content)), nil
+	}
+	if passphrase := os.Getenv(SecretsPassphraseEnvVar); passphrase != "" {
+		return passphrase, nil
+	}
+	return "", fmt.Errorf("no secrets passphrase given, use a passphrase file or set %s", SecretsPassphraseEnvVar)
+}
+
+// SecretCipher encrypts values with AES-GCM using a key derived from a passphrase. Each encrypted value carries
+// the salt its key was derived with. Derived keys are cached, so a document encrypted in one run costs a single
+// derivation to decrypt.
+type SecretCipher struct {
+	passphrase string
+	salt       []byte
+	aeads      map[string]cipher.AEAD
+}
+
+func NewSecretCipher(passphrase string) (*SecretCipher, error) {
+	if passphrase == "" {
+		return nil, errors.New("secrets passphrase may not be empty")
+	}
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return &SecretCipher{
+		passphrase: passphrase,
+		salt:       salt,
+		aeads:      map[string]cipher.AEAD{},
+	}, nil
+}
+
+func (self *SecretCipher) getAEAD(salt []byte) (cipher.AEAD, error) {
+	if aead, found := self.aeads[string(salt)]; found {
+		return aead, nil
+	}
+	key, err := pbkdf2.Key(sha256.New, self.passphrase, salt, pbkdf2Iterations, keyLength)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	self.aeads[string(salt)] = aead
+	return aead, nil
+}
+
+func (self *SecretCipher) Encrypt(value string) (string, error) {
+	aead, err := self.getAEAD(self.salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nil, nonce, []byte(value), nil)
+
+	buf := make([]byte, 0, len(self.salt)+len(nonce)+len(sealed))
+	buf = append(buf, self.salt...)
+	buf = append(buf, nonce...)
+	buf = append(buf, sealed...)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(buf), nil
+}
+
+func (self *SecretCipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", errors.New("value is not encrypted")
+	}
+	buf, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value (%w)", err)
+	}
+	if len(buf) < saltLength {
+		return "", errors.New("invalid encrypted value, too short")
+	}
+	aead, err := self.getAEAD(buf[:saltLength])
+	if err != nil {
+		return "", err
+	}
+	buf = buf[saltLength:]
+	if len(buf) < aead.NonceSize() {
+		return "", errors.New("invalid encrypted value, too short")
+	}
+	plain, err := aead.Open(nil, buf[:aead.NonceSize()], buf[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("unable to decrypt value, check the secrets passphrase")
+	}
+	return string(plain), nil
+}
+
+// ProtectSecrets redacts or encrypts, depending on mode, the string values stored under sensitive keys anywhere
+// in the given value. It returns the number of values changed.
+func ProtectSecrets(v interface{}, mode string, secretCipher *SecretCipher) (int, error) {
+	if mode == SecretsInclude {
+		return 0, nil
+	}
+	if mode != SecretsRedact && mode != SecretsEncrypt {
+		return 0, fmt.Errorf("invalid secrets mode '%s', must be one of %s, %s or %s", mode, SecretsInclude, SecretsRedact, SecretsEncrypt)
+	}
+	if mode == SecretsEncrypt && secretCipher == nil {
+		return 0, errors.New("a secrets passphrase is required to encrypt secrets")
+	}
+
+	count := 0
+	err := walkSecrets(v, func(value string) (string, error) {
+		if value == "" || value == RedactedValue || IsEncrypted(value) {
+			return value, nil
+		}
+		count++
+		if mode == SecretsRedact {
+			return RedactedValue, nil
+		}
+		return secretCipher.Encrypt(value)
+	})
+	return count, err
+}
+
+// RevealSecrets decrypts any encrypted values in the given value. A nil cipher is only an error if encrypted values
+// are present. Redacted values are reported as an error, since importing them would overwrite the real secret.
+func RevealSecrets(v interface{}, secretCipher *SecretCipher) error {
+	return walkValues(v, "", func(path string, value string) (string, error) {
+		if value == RedactedValue {
+			return "", fmt.Errorf("%s was redacted on export, fill in the value or export with secrets encrypted", path)
+		}
+		if !IsEncrypted(value) {
+			return value, nil
+		}
+		if secretCipher == nil {
+			return "", fmt.Errorf("%s is encrypted, a secrets passphrase is required", path)
+		}
+		return secretCipher.Decrypt(value)
+	})
+}
+
+// HasEncryptedValues reports whether any string in the given value is encrypted
+func HasEncryptedValues(v interface{}) bool {
+	found := false
+	_ = walkValues(v, "", func(_ string, value string) (string, error) {
+		if IsEncrypted(value) {
+			found = true
+		}
+		return value, nil
+	})
+	return found
+}
+
+func walkSecrets(v interface{}, f func(value string) (string, error)) error {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if s, ok := child.(string); ok && IsSensitiveKey(k) {
+				updated, err := f(s)
+				if err != nil {
+					return err
+				}
+				val[k] = updated
+			} else if err := walkSecrets(child, f); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			if err := walkSecrets(child, f); err != nil {
+				return err
+			}
+		}
+	case []map[string]interface{}:
+		for _, child := range val {
+			if err := walkSecrets(child, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walkValues visits map keys in sorted order, so that errors name the same path on every run
+func walkValues(v interface{}, path string, f func(path string, value string) (string, error)) error {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, k := range slices.Sorted(maps.Keys(val)) {
+			child := val[k]
+			childPath := strings.TrimPrefix(path+"."+k, ".")
+			if s, ok := child.(string); ok {
+				updated, err := f(childPath, s)
+				if err != nil {
+					return err
+				}
+				val[k] = updated
+			} else if err := walkValues(child, childPath, f); err != nil {
+				return err
+			}
+		}
+	case map[string][]interface{}:
+		for _, k := range slices.Sorted(maps.Keys(val)) {
+			if err := walkValues(val[k], strings.TrimPrefix(path+"."+k, "."), f); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, child := range val {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			if s, ok := child.(string); ok {
+				updated, err := f(childPath, s)
+				if err != nil {
+					return err
+				}
+				val[i] = updated
+			} else if err := walkValues(child, childPath, f); err != nil {
+				return err
+			}
+		}
+	case []map[string]interface{}:
+		for i, child := range val {
+			if err := walkValues(child, fmt.Sprintf("%s[%d]", path, i), f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package ascode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newDoc() map[string]interface{} {
+	return map[string]interface{}{
+		"name": "cfg",
+		"data": map[string]interface{}{
+			"address":  "db.example",
+			"password": "hunter2",
+			"auth": []interface{}{
+				map[string]interface{}{"clientSecret": "s3cret", "clientId": "ziti"},
+			},
+			"tokenEndpoint":    "https://idp.example/token",
+			"secretName":       "db-credentials",
+			"maxTokenDuration": "1h",
+		},
+	}
+}
+
+func TestSecrets(t *testing.T) {
+	t.Run("redact", func(t *testing.T) {
+		req := require.New(t)
+		doc := newDoc()
+
+		count, err := ProtectSecrets(doc, SecretsRedact, nil)
+		req.NoError(err)
+		req.Equal(2, count)
+
+		data := doc["data"].(map[string]interface{})
+		req.Equal(RedactedValue, data["password"])
+		req.Equal("db.example", data["address"])
+		req.Equal("ziti", data["auth"].([]interface{})[0].(map[string]interface{})["clientId"])
+		req.Equal("https://idp.example/token", data["tokenEndpoint"])
+		req.Equal("db-credentials", data["secretName"])
+		req.Equal("1h", data["maxTokenDuration"])
+
+		req.ErrorContains(RevealSecrets(doc, nil), "data.auth[0].clientSecret")
+	})
+
+	t.Run("encrypt round trip", func(t *testing.T) {
+		req := require.New(t)
+		doc := newDoc()
+
+		encryptCipher, err := NewSecretCipher("passphrase")
+		req.NoError(err)
+		count, err := ProtectSecrets(doc, SecretsEncrypt, encryptCipher)
+		req.NoError(err)
+		req.Equal(2, count)
+		req.True(HasEncryptedValues(doc))
+
+		data := doc["data"].(map[string]interface{})
+		req.True(IsEncrypted(data["password"].(string)))
+
+		req.Error(RevealSecrets(newDocFrom(doc), nil))
+
+		wrongCipher, err := NewSecretCipher("wrong")
+		req.NoError(err)
+		req.Error(RevealSecrets(newDocFrom(doc), wrongCipher))
+
+		decryptCipher, err := NewSecretCipher("passphrase")
+		req.NoError(err)
+		req.NoError(RevealSecrets(doc, decryptCipher))
+		req.Equal(newDoc(), doc)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		req := require.New(t)
+		_, err := ProtectSecrets(newDoc(), "hide", nil)
+		req.Error(err)
+
+		count, err := ProtectSecrets(newDoc(), SecretsInclude, nil)
+		req.NoError(err)
+		req.Equal(0, count)
+	})
+}
+
+func TestIsSensitiveKey(t *testing.T) {
+	for _, key := range []string{"password", "clientSecret", "client_secret", "CLIENT-SECRET", "accessToken",
+		"privateKey", "private_key", "APIKey", "api_key", "apikey", "credentials", "keyPassphrase", "oauth2Token"} {
+		require.True(t, IsSensitiveKey(key), key)
+	}
+
+	for _, key := range []string{"tokenEndpoint", "secretName", "maxTokenDuration", "passwordPolicy", "keyType",
+		"clientId", "address", "tokens2", ""} {
+		require.False(t, IsSensitiveKey(key), key)
+	}
+}
+
+func newDocFrom(doc map[string]interface{}) map[string]interface{} {
+	data := doc["data"].(map[string]interface{})
+	auth := data["auth"].([]interface{})[0].(map[string]interface{})
+	return map[string]interface{}{
+		"name": doc["name"],
+		"data": map[string]interface{}{
+			"address":  data["address"],
+			"password": data["password"],
+			"auth": []interface{}{
+				map[string]interface{}{"clientSecret": auth["clientSecret"], "clientId": auth["clientId"]},
+			},
+			"tokenEndpoint":    data["tokenEndpoint"],
+			"secretName":       data["secretName"],
+			"maxTokenDuration": data["maxTokenDuration"],
+		},
+	}
+}
@@ -29,9 +29,11 @@ import (
 	"github.com/openziti/edge-api/rest_management_api_client/external_jwt_signer"
 	"github.com/openziti/edge-api/rest_management_api_client/identity"
 	"github.com/openziti/edge-api/rest_management_api_client/posture_checks"
+	"github.com/openziti/edge-api/rest_management_api_client/router"
 	"github.com/openziti/edge-api/rest_management_api_client/service"
 	"github.com/openziti/edge-api/rest_management_api_client/service_edge_router_policy"
 	"github.com/openziti/edge-api/rest_management_api_client/service_policy"
+	"github.com/openziti/edge-api/rest_management_api_client/terminator"
 	"github.com/openziti/edge-api/rest_model"
 	"github.com/openziti/ziti/v2/internal/rest/consts"
 	log "github.com/sirupsen/logrus"
@@ -239,6 +241,38 @@ func ServiceEdgeRouterPolicyFromFilter(client *rest_management_api_client.ZitiEd
 	return resp.Payload.Data[0]
 }
 
+func TransitRouterFromFilter(client *rest_management_api_client.ZitiEdgeManagement, filter string) *rest_model.RouterDetail {
+	params := &router.ListTransitRoutersParams{
+		Filter: &filter,
+	}
+	params.SetTimeout(internal_consts.DefaultTimeout)
+	resp, err := client.Router.ListTransitRouters(params, nil)
+	if err != nil {
+		log.Errorf("Could not obtain an ID for the routers with filter %s: %v", filter, err)
+		return nil
+	}
+	if resp == nil || resp.Payload == nil || resp.Payload.Data == nil || len(resp.Payload.Data) == 0 {
+		return nil
+	}
+	return resp.Payload.Data[0]
+}
+
+func TerminatorFromFilter(client *rest_management_api_client.ZitiEdgeManagement, filter string) *rest_model.TerminatorDetail {
+	params := &terminator.ListTerminatorsParams{
+		Filter: &filter,
+	}
+	params.SetTimeout(internal_consts.DefaultTimeout)
+	resp, err := client.Terminator.ListTerminators(params, nil)
+	if err != nil {
+		log.Errorf("Could not obtain an ID for the terminators with filter %s: %v", filter, err)
+		return nil
+	}
+	if resp == nil || resp.Payload == nil || resp.Payload.Data == nil || len(resp.Payload.Data) == 0 {
+		return nil
+	}
+	return resp.Payload.Data[0]
+}
+
 func NameFilter(name string) string {
 	return fmt.Sprintf("name = \"%s\"", name)
 }
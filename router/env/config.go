@@ -33,6 +33,7 @@ import (
 	"github.com/openziti/transport/v2"
 	"github.com/openziti/transport/v2/tls"
 	"github.com/openziti/ziti/v2/common/config"
+	"github.com/openziti/ziti/v2/common/locality"
 	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
 	"github.com/openziti/ziti/v2/controller/command"
 	"github.com/pkg/errors"
@@ -126,6 +127,7 @@ type Config struct {
 	IdConfig       *identity.Config
 	Id             *identity.TokenId
 	EnableDebugOps bool
+	Locality       locality.Locality
 	Forwarder      *ForwarderOptions
 	Trace          struct {
 		Handler *channel.TraceHandler
@@ -421,6 +423,12 @@ func LoadConfigWithOptions(path string, loadIdentity bool) (*Config, error) {
 		}
 	}
 
+	if value, found := cfgmap["locality"]; found {
+		if cfg.Locality, err = locality.Load(value); err != nil {
+			return nil, err
+		}
+	}
+
 	cfg.Forwarder = DefaultForwarderOptions()
 	if value, found := cfgmap["forwarder"]; found {
 		if submap, ok := value.(map[interface{}]interface{}); ok {
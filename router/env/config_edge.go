@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -40,6 +42,15 @@ const (
 	DefaultSessionValidateChunkSize   = 1000
 	DefaultSessionValidateMinInterval = "250ms"
 	DefaultSessionValidateMaxInterval = "1500ms"
+
+	AcmeChallengeHttp01    = "http-01"
+	AcmeChallengeTlsAlpn01 = "tls-alpn-01"
+
+	DefaultAcmeDirectory   = "https://acme-v02.api.letsencrypt.org/directory"
+	DefaultAcmeChallenge   = AcmeChallengeTlsAlpn01
+	DefaultAcmeHttpAddress = ":80"
+	DefaultAcmeKeyType     = "P256"
+	DefaultAcmeRenewBefore = 30 * 24 * time.Hour
 )
 
 type EdgeConfig struct {
@@ -53,6 +64,7 @@ type EdgeConfig struct {
 	SessionValidateMaxInterval time.Duration
 	Tcfg                       transport.Configuration
 	ForceExtendEnrollment      bool
+	Acme                       *AcmeConfig
 
 	RouterConfig *Config
 
@@ -69,6 +81,32 @@ type Csr struct {
 	Province           string `yaml:"province"`
 }
 
+// AcmeConfig configures obtaining and renewing a publicly trusted server certificate for the edge listeners
+// from an ACME CA. The certificate is served to clients which connect using one of the configured domains as
+// their TLS server name. All other clients continue to get the router's ziti issued server certificate.
+type AcmeConfig struct {
+	// Directory is the ACME directory URL of the CA
+	Directory string
+	// Email is the contact address registered with the ACME account
+	Email string
+	// Domains are the DNS names the certificate is issued for. They must resolve to this router.
+	Domains []string
+	// Challenge is the ACME challenge used to prove control of the domains, either http-01 or tls-alpn-01.
+	// tls-alpn-01 challenges are answered by the edge listeners, so they must be reachable on port 443.
+	Challenge string
+	// HttpAddress is the address a temporary http server listens on to answer http-01 challenges
+	HttpAddress string
+	// KeyType is the certificate key type, one of P256, P384, 2048, 3072, 4096 or 8192
+	KeyType string
+	// RenewBefore is how long before expiry the certificate is renewed
+	RenewBefore time.Duration
+	// StorageDir holds the ACME account key and the current certificate, so they survive restarts
+	StorageDir string
+	// CaCerts is an optional path to a PEM bundle used to verify the ACME directory's server certificate,
+	// for example when testing against a local ACME server
+	CaCerts string
+}
+
 type ApiProxy struct {
 	Enabled  bool
 	Listener string
@@ -189,10 +227,18 @@ func (config *EdgeConfig) LoadEdgeConfigFromMap(configMap map[interface{}]interf
 		return err
 	}
 
+	if err = config.loadAcme(edgeConfigMap); err != nil {
+		return err
+	}
+
 	if err = config.loadEdgeListener(configMap); err != nil {
 		return err
 	}
 
+	if err = config.validateAcmeDomains(); err != nil {
+		return err
+	}
+
 	if err = config.loadTransportConfig(configMap); err != nil {
 		return err
 	}
@@ -239,6 +285,134 @@ func (config *EdgeConfig) loadApiProxy(edgeConfigMap map[interface{}]interface{}
 	return nil
 }
 
+func (config *EdgeConfig) loadAcme(edgeConfigMap map[interface{}]interface{}) error {
+	value, found := edgeConfigMap["acme"]
+	if !found || value == nil {
+		return nil
+	}
+
+	submap, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return errors.Errorf("[edge.acme] must be a map, not %T", value)
+	}
+
+	if enabled, found := submap["enabled"]; found {
+		if enabledVal, ok := enabled.(bool); !ok {
+			return errors.Errorf("[edge.acme.enabled] must be a bool, not %T", enabled)
+		} else if !enabledVal {
+			return nil
+		}
+	}
+
+	acme := &AcmeConfig{
+		Directory:   DefaultAcmeDirectory,
+		Challenge:   DefaultAcmeChallenge,
+		HttpAddress: DefaultAcmeHttpAddress,
+		KeyType:     DefaultAcmeKeyType,
+		RenewBefore: DefaultAcmeRenewBefore,
+	}
+
+	if config.RouterConfig != nil && config.RouterConfig.path != "" {
+		acme.StorageDir = filepath.Join(filepath.Dir(config.RouterConfig.path), "acme")
+	}
+
+	stringValues := map[string]*string{
+		"directory":   &acme.Directory,
+		"email":       &acme.Email,
+		"challenge":   &acme.Challenge,
+		"httpAddress": &acme.HttpAddress,
+		"keyType":     &acme.KeyType,
+		"storageDir":  &acme.StorageDir,
+		"caCerts":     &acme.CaCerts,
+	}
+
+	for key, target := range stringValues {
+		if val, found := submap[key]; found {
+			if *target, ok = val.(string); !ok {
+				return errors.Errorf("[edge.acme.%s] must be a string, not %T", key, val)
+			}
+		}
+	}
+
+	if val, found := submap["domains"]; found {
+		domains, ok := val.([]interface{})
+		if !ok {
+			return errors.Errorf("[edge.acme.domains] must be a list, not %T", val)
+		}
+		for idx, domain := range domains {
+			domainStr, ok := domain.(string)
+			if !ok || domainStr == "" {
+				return errors.Errorf("[edge.acme.domains] value at position %v must be a non-empty string", idx+1)
+			}
+			if strings.HasPrefix(domainStr, "*.") {
+				return errors.Errorf("[edge.acme.domains] value '%s' is a wildcard, which http-01 and tls-alpn-01 challenges can't validate", domainStr)
+			}
+			acme.Domains = append(acme.Domains, strings.ToLower(domainStr))
+		}
+	}
+
+	if val, found := submap["renewBefore"]; found {
+		var err error
+		if acme.RenewBefore, err = time.ParseDuration(fmt.Sprintf("%v", val)); err != nil {
+			return errors.Wrap(err, "invalid value for [edge.acme.renewBefore]")
+		}
+	}
+
+	if len(acme.Domains) == 0 {
+		return errors.New("[edge.acme.domains] is required when acme is enabled")
+	}
+
+	if acme.Challenge != AcmeChallengeHttp01 && acme.Challenge != AcmeChallengeTlsAlpn01 {
+		return errors.Errorf("invalid [edge.acme.challenge] '%s', must be %s or %s", acme.Challenge, AcmeChallengeHttp01, AcmeChallengeTlsAlpn01)
+	}
+
+	if acme.Challenge == AcmeChallengeHttp01 {
+		if _, _, err := net.SplitHostPort(acme.HttpAddress); err != nil {
+			return errors.Wrapf(err, "invalid [edge.acme.httpAddress] '%s'", acme.HttpAddress)
+		}
+	}
+
+	switch acme.KeyType {
+	case "P256", "P384", "2048", "3072", "4096", "8192":
+	default:
+		return errors.Errorf("invalid [edge.acme.keyType] '%s', must be one of P256, P384, 2048, 3072, 4096 or 8192", acme.KeyType)
+	}
+
+	if acme.RenewBefore <= 0 {
+		return errors.New("[edge.acme.renewBefore] must be greater than zero")
+	}
+
+	if acme.StorageDir == "" {
+		return errors.New("[edge.acme.storageDir] is required when the router config path is unknown")
+	}
+
+	config.Acme = acme
+	return nil
+}
+
+// validateAcmeDomains rejects ACME domains which are also the advertised host of an edge listener. SDKs connect
+// to the advertised address and only trust the network's CA, so they'd fail the TLS handshake if presented with
+// the ACME certificate instead of the router's ziti issued one.
+func (config *EdgeConfig) validateAcmeDomains() error {
+	if config.Acme == nil {
+		return nil
+	}
+
+	for _, listener := range config.EdgeListeners {
+		if listener.Advertise == nil {
+			continue
+		}
+		host := strings.ToLower(listener.Advertise.Hostname)
+		if slices.Contains(config.Acme.Domains, host) {
+			return errors.Errorf("[edge.acme.domains] value '%s' is the advertised host of edge listener '%s'. SDKs "+
+				"connecting to it would be presented the ACME certificate, which they don't trust. Use a separate DNS name "+
+				"for the ACME certificate", host, listener.Advertise.Value)
+		}
+	}
+
+	return nil
+}
+
 func (config *EdgeConfig) loadEdgeListener(rootConfigMap map[interface{}]interface{}) error {
 	subArray := rootConfigMap["listeners"]
 
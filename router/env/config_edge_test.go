@@ -0,0 +1,164 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package env
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openziti/ziti/v2/common/pb/edge_ctrl_pb"
+	"github.com/stretchr/testify/require"
+)
+
+func loadAcme(t *testing.T, acme interface{}) (*EdgeConfig, error) {
+	t.Helper()
+	config := &EdgeConfig{
+		RouterConfig: &Config{path: "/etc/ziti/router.yml"},
+	}
+	err := config.loadAcme(map[interface{}]interface{}{"acme": acme})
+	return config, err
+}
+
+func Test_Acme_AbsentSection(t *testing.T) {
+	req := require.New(t)
+	config := &EdgeConfig{}
+	req.NoError(config.loadAcme(map[interface{}]interface{}{}))
+	req.Nil(config.Acme)
+}
+
+func Test_Acme_Defaults(t *testing.T) {
+	req := require.New(t)
+	config, err := loadAcme(t, map[interface{}]interface{}{
+		"domains": []interface{}{"Edge.Example.com"},
+	})
+	req.NoError(err)
+	req.NotNil(config.Acme)
+	req.Equal(DefaultAcmeDirectory, config.Acme.Directory)
+	req.Equal(DefaultAcmeChallenge, config.Acme.Challenge)
+	req.Equal(DefaultAcmeHttpAddress, config.Acme.HttpAddress)
+	req.Equal(DefaultAcmeKeyType, config.Acme.KeyType)
+	req.Equal(DefaultAcmeRenewBefore, config.Acme.RenewBefore)
+	req.Equal(filepath.Join("/etc/ziti", "acme"), config.Acme.StorageDir)
+	req.Equal([]string{"edge.example.com"}, config.Acme.Domains)
+}
+
+func Test_Acme_Values(t *testing.T) {
+	req := require.New(t)
+	config, err := loadAcme(t, map[interface{}]interface{}{
+		"directory":   "https://localhost:14000/dir",
+		"email":       "admin@example.com",
+		"domains":     []interface{}{"a.example.com", "b.example.com"},
+		"challenge":   AcmeChallengeHttp01,
+		"httpAddress": "127.0.0.1:8080",
+		"keyType":     "4096",
+		"renewBefore": "48h",
+		"storageDir":  "/var/lib/ziti/acme",
+		"caCerts":     "/etc/ziti/acme-ca.pem",
+	})
+	req.NoError(err)
+	req.Equal("https://localhost:14000/dir", config.Acme.Directory)
+	req.Equal("admin@example.com", config.Acme.Email)
+	req.Equal([]string{"a.example.com", "b.example.com"}, config.Acme.Domains)
+	req.Equal(AcmeChallengeHttp01, config.Acme.Challenge)
+	req.Equal("127.0.0.1:8080", config.Acme.HttpAddress)
+	req.Equal("4096", config.Acme.KeyType)
+	req.Equal(48*time.Hour, config.Acme.RenewBefore)
+	req.Equal("/var/lib/ziti/acme", config.Acme.StorageDir)
+	req.Equal("/etc/ziti/acme-ca.pem", config.Acme.CaCerts)
+}
+
+func Test_Acme_Disabled(t *testing.T) {
+	req := require.New(t)
+	config, err := loadAcme(t, map[interface{}]interface{}{
+		"enabled": false,
+		"domains": []interface{}{"edge.example.com"},
+	})
+	req.NoError(err)
+	req.Nil(config.Acme)
+}
+
+func Test_Acme_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		acme interface{}
+	}{
+		{name: "not a map", acme: "edge.example.com"},
+		{name: "enabled isn't a bool", acme: map[interface{}]interface{}{"enabled": "yes", "domains": []interface{}{"edge.example.com"}}},
+		{name: "no domains", acme: map[interface{}]interface{}{}},
+		{name: "domains isn't a list", acme: map[interface{}]interface{}{"domains": "edge.example.com"}},
+		{name: "empty domain", acme: map[interface{}]interface{}{"domains": []interface{}{""}}},
+		{name: "wildcard domain", acme: map[interface{}]interface{}{"domains": []interface{}{"*.example.com"}}},
+		{name: "unknown challenge", acme: map[interface{}]interface{}{"domains": []interface{}{"edge.example.com"}, "challenge": "dns-01"}},
+		{name: "invalid http address", acme: map[interface{}]interface{}{"domains": []interface{}{"edge.example.com"}, "challenge": AcmeChallengeHttp01, "httpAddress": "80"}},
+		{name: "unknown key type", acme: map[interface{}]interface{}{"domains": []interface{}{"edge.example.com"}, "keyType": "P521"}},
+		{name: "invalid renew before", acme: map[interface{}]interface{}{"domains": []interface{}{"edge.example.com"}, "renewBefore": "soon"}},
+		{name: "zero renew before", acme: map[interface{}]interface{}{"domains": []interface{}{"edge.example.com"}, "renewBefore": "0s"}},
+		{name: "string value isn't a string", acme: map[interface{}]interface{}{"domains": []interface{}{"edge.example.com"}, "email": 10}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := loadAcme(t, test.acme)
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("storage dir is required without a router config path", func(t *testing.T) {
+		req := require.New(t)
+		config := &EdgeConfig{}
+		err := config.loadAcme(map[interface{}]interface{}{
+			"acme": map[interface{}]interface{}{"domains": []interface{}{"edge.example.com"}},
+		})
+		req.ErrorContains(err, "storageDir")
+	})
+}
+
+func Test_Acme_AdvertisedHostOverlap(t *testing.T) {
+	newConfig := func(domains ...string) *EdgeConfig {
+		return &EdgeConfig{
+			Acme: &AcmeConfig{Domains: domains},
+			EdgeListeners: []*edge_ctrl_pb.Listener{
+				{
+					Advertise: &edge_ctrl_pb.Address{
+						Value:    "Router.Example.com:3022",
+						Hostname: "Router.Example.com",
+						Port:     3022,
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("a domain matching an advertised host is rejected", func(t *testing.T) {
+		req := require.New(t)
+		err := newConfig("edge.example.com", "router.example.com").validateAcmeDomains()
+		req.ErrorContains(err, "router.example.com")
+	})
+
+	t.Run("domains separate from the advertised hosts are allowed", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(newConfig("edge.example.com").validateAcmeDomains())
+	})
+
+	t.Run("nothing is checked without acme", func(t *testing.T) {
+		req := require.New(t)
+		config := newConfig()
+		config.Acme = nil
+		req.NoError(config.validateAcmeDomains())
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/michaelquigley/pfxlog"
 	"github.com/openziti/foundation/v2/versions"
 	"github.com/openziti/ziti/v2/common/ctrlchan"
+	"github.com/openziti/ziti/v2/common/locality"
 
 	"github.com/openziti/channel/v5"
 )
@@ -36,6 +37,7 @@ type NetworkController interface {
 	IsUnresponsive() bool
 	isMoreResponsive(other NetworkController) bool
 	GetVersion() *versions.VersionInfo
+	GetLocality() locality.Locality
 	TimeSinceLastContact() time.Duration
 	IsConnected() bool
 	GetLastReportedDataModelIndex() uint64
@@ -64,6 +66,7 @@ type networkCtrl struct {
 	latency          atomic.Int64
 	unresponsive     atomic.Bool
 	versionInfo      *versions.VersionInfo
+	locality         locality.Locality
 	lastContact      atomic.Int64
 	currentIndex     atomic.Uint64
 }
@@ -88,6 +91,10 @@ func (self *networkCtrl) GetVersion() *versions.VersionInfo {
 	return self.versionInfo
 }
 
+func (self *networkCtrl) GetLocality() locality.Locality {
+	return self.locality
+}
+
 func (self *networkCtrl) Address() string {
 	return self.address
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/openziti/ziti/v2/common/capabilities"
 	"github.com/openziti/ziti/v2/common/ctrlchan"
 	"github.com/openziti/ziti/v2/common/inspect"
+	"github.com/openziti/ziti/v2/common/locality"
 	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
 	"github.com/openziti/ziti/v2/common/pb/edge_ctrl_pb"
 	cmap "github.com/orcaman/concurrent-map/v2"
@@ -108,6 +109,7 @@ func NewNetworkControllers(dialEnv DialEnv, heartbeatOptions *HeartbeatOptions)
 		dialEnv:               dialEnv,
 		heartbeatOptions:      heartbeatOptions,
 		defaultRequestTimeout: dialEnv.GetConfig().Ctrl.DefaultRequestTimeout,
+		locality:              dialEnv.GetConfig().Locality,
 		idsBeingDialed:        cmap.New[struct{}](),
 	}
 }
@@ -117,6 +119,7 @@ type networkControllers struct {
 	dialEnv               DialEnv
 	heartbeatOptions      *HeartbeatOptions
 	defaultRequestTimeout time.Duration
+	locality              locality.Locality
 	idsBeingDialed        cmap.ConcurrentMap[string, struct{}]
 	ctrls                 concurrenz.CopyOnWriteMap[string, NetworkController]
 	leaderId              concurrenz.AtomicValue[string]
@@ -471,6 +474,7 @@ func (self *networkControllers) handleRouterDataModelIndexUpdate(m *channel.Mess
 // wire a close handler have to hold on to it.
 func (self *networkControllers) Add(address string, ctrlCh ctrlchan.CtrlChannel, ch channel.Channel, underlay channel.Underlay) (NetworkController, error) {
 	ctrl := newNetworkCtrl(ctrlCh, address, self.heartbeatOptions)
+	ctrl.locality = locality.FromHeaders(underlay.Headers())
 
 	if versionValue, found := underlay.Headers()[channel.HelloVersionHeader]; found {
 		if versionInfo, err := versions.StdVersionEncDec.Decode(versionValue); err == nil {
@@ -487,6 +491,10 @@ func (self *networkControllers) Add(address string, ctrlCh ctrlchan.CtrlChannel,
 		WithField("ch", ch.Label()).
 		WithField("address", address)
 
+	if ctrl.locality.IsSet() {
+		log = log.WithField("locality", ctrl.locality.String())
+	}
+
 	// Atomic against a concurrent Add for the same controller and against UpdateControllerDetails, which
 	// decides whether to dial from the same state. Two dials do race: initial endpoint dials carry no
 	// controller id, so idsBeingDialed cannot keep them apart.
@@ -662,16 +670,40 @@ func (self *networkControllers) GetAll() map[string]NetworkController {
 }
 
 func (self *networkControllers) AnyCtrlChannel() ctrlchan.CtrlChannel {
+	if current := self.getPreferred(); current != nil {
+		return current.CtrlChannel()
+	}
+	return nil
+}
+
+// getPreferred returns the controller to use for requests which any controller can serve, such as circuit
+// creation and session heartbeats.
+func (self *networkControllers) getPreferred() NetworkController {
 	var current NetworkController
 	for _, ctrl := range self.ctrls.AsMap() {
-		if current == nil || ctrl.isMoreResponsive(current) {
+		if current == nil || self.isPreferredOver(ctrl, current) {
 			current = ctrl
 		}
 	}
-	if current == nil {
-		return nil
+	return current
+}
+
+// isPreferredOver returns true if ctrl is a better choice than current. While both are connected and
+// responsive, the one closer to this router wins, so that requests don't cross regions while a controller in
+// the router's own region is available. Otherwise, or if they're equally close, the more responsive one wins.
+func (self *networkControllers) isPreferredOver(ctrl, current NetworkController) bool {
+	if isAvailable(ctrl) && isAvailable(current) {
+		ctrlAffinity := self.locality.AffinityTo(ctrl.GetLocality())
+		currentAffinity := self.locality.AffinityTo(current.GetLocality())
+		if ctrlAffinity != currentAffinity {
+			return ctrlAffinity > currentAffinity
+		}
 	}
-	return current.CtrlChannel()
+	return ctrl.isMoreResponsive(current)
+}
+
+func isAvailable(ctrl NetworkController) bool {
+	return ctrl.IsConnected() && !ctrl.IsUnresponsive()
 }
 
 func (self *networkControllers) AnyChannel() channel.Channel {
@@ -690,7 +722,7 @@ func (self *networkControllers) GetModelUpdateCtrlChannel() channel.Channel {
 	var current NetworkController
 	for _, ctrl := range self.ctrls.AsMap() {
 		if current == nil ||
-			(ctrl.isMoreResponsive(current) && !self.isLeader(current)) ||
+			(self.isPreferredOver(ctrl, current) && !self.isLeader(current)) ||
 			(!ctrl.IsUnresponsive() && self.isLeader(ctrl)) {
 			current = ctrl
 		}
@@ -801,9 +833,13 @@ func (self *networkControllers) GetLeader() NetworkController {
 func (self *networkControllers) Inspect() *inspect.ControllerInspectDetails {
 	result := &inspect.ControllerInspectDetails{
 		LeaderId:    self.leaderId.Load(),
+		Region:      self.locality.Region,
+		Zone:        self.locality.Zone,
 		Controllers: map[string]*inspect.ControllerInspectDetail{},
 	}
 
+	preferred := self.getPreferred()
+
 	for id, ctrl := range self.ctrls.AsMap() {
 		version := ""
 		if ctrl.GetVersion() != nil {
@@ -818,6 +854,10 @@ func (self *networkControllers) Inspect() *inspect.ControllerInspectDetails {
 			Version:              version,
 			TimeSinceLastContact: ctrl.TimeSinceLastContact().String(),
 			IsLeader:             id == self.leaderId.Load(),
+			Region:               ctrl.GetLocality().Region,
+			Zone:                 ctrl.GetLocality().Zone,
+			Affinity:             self.locality.AffinityTo(ctrl.GetLocality()).String(),
+			IsPreferred:          ctrl == preferred,
 		}
 	}
 
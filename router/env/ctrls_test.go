@@ -27,6 +27,7 @@ import (
 	"github.com/cenkalti/backoff/v4"
 
 	"github.com/openziti/foundation/v2/versions"
+	"github.com/openziti/ziti/v2/common/locality"
 	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
 
 	cmap "github.com/orcaman/concurrent-map/v2"
@@ -544,3 +545,78 @@ func TestNotifyOfConnectivityChange_ReportsEachEdgeOnce(t *testing.T) {
 	require.Equal(t, ControllerReconnected, events[1].Type)
 	require.Equal(t, 1, reconnectNotifications)
 }
+
+// newTestLocalCtrl registers a controller reporting the given locality and heartbeat latency.
+func newTestLocalCtrl(nc *networkControllers, ctrlId string, region, zone string, latency time.Duration) *networkCtrl {
+	ctrl, _ := newTestNetworkCtrl(nc, ctrlId, ctrlId)
+	ctrl.locality = locality.Locality{Region: region, Zone: zone}
+	ctrl.latency.Store(int64(latency))
+	nc.ctrls.Put(ctrlId, ctrl)
+	return ctrl
+}
+
+// TestGetPreferred_PrefersSameRegion: a router must keep sending circuit requests to a controller in its own
+// region even when a controller across regions currently answers heartbeats faster, and fall back across
+// regions only once nothing closer is available.
+func TestGetPreferred_PrefersSameRegion(t *testing.T) {
+	t.Run("same zone beats same region beats other regions", func(t *testing.T) {
+		req := require.New(t)
+		nc := newTestNetworkControllers()
+		nc.locality = locality.Locality{Region: "us-east", Zone: "us-east-1a"}
+
+		newTestLocalCtrl(nc, "remote", "eu-west", "eu-west-1a", time.Millisecond)
+		region := newTestLocalCtrl(nc, "region", "us-east", "us-east-1b", 10*time.Millisecond)
+		zone := newTestLocalCtrl(nc, "zone", "us-east", "us-east-1a", 20*time.Millisecond)
+
+		req.Same(zone, nc.getPreferred())
+
+		zone.unresponsive.Store(true)
+		req.Same(region, nc.getPreferred(), "an unresponsive controller must lose its locality preference")
+	})
+
+	t.Run("falls back across regions", func(t *testing.T) {
+		req := require.New(t)
+		nc := newTestNetworkControllers()
+		nc.locality = locality.Locality{Region: "us-east"}
+
+		remote := newTestLocalCtrl(nc, "remote", "eu-west", "", 100*time.Millisecond)
+		local := newTestLocalCtrl(nc, "local", "us-east", "", time.Millisecond)
+		req.Same(local, nc.getPreferred())
+
+		local.unresponsive.Store(true)
+		req.Same(remote, nc.getPreferred())
+	})
+
+	t.Run("latency decides without a locality", func(t *testing.T) {
+		req := require.New(t)
+		nc := newTestNetworkControllers()
+
+		newTestLocalCtrl(nc, "slow", "us-east", "", 50*time.Millisecond)
+		fast := newTestLocalCtrl(nc, "fast", "eu-west", "", time.Millisecond)
+		req.Same(fast, nc.getPreferred())
+	})
+}
+
+// TestInspect_ReportsLocality checks that controller inspections show which controller is preferred and why.
+func TestInspect_ReportsLocality(t *testing.T) {
+	req := require.New(t)
+	nc := newTestNetworkControllers()
+	nc.locality = locality.Locality{Region: "us-east", Zone: "us-east-1a"}
+
+	newTestLocalCtrl(nc, "remote", "eu-west", "", time.Millisecond)
+	newTestLocalCtrl(nc, "local", "us-east", "us-east-1b", 10*time.Millisecond)
+
+	details := nc.Inspect()
+	req.Equal("us-east", details.Region)
+	req.Equal("us-east-1a", details.Zone)
+
+	local := details.Controllers["local"]
+	req.True(local.IsPreferred)
+	req.Equal("region", local.Affinity)
+	req.Equal("us-east-1b", local.Zone)
+
+	remote := details.Controllers["remote"]
+	req.False(remote.IsPreferred)
+	req.Equal("none", remote.Affinity)
+	req.Equal("eu-west", remote.Region)
+}
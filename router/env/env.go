@@ -17,6 +17,7 @@
 package env
 
 import (
+	"crypto/tls"
 	"time"
 
 	"github.com/openziti/channel/v5"
@@ -65,6 +66,7 @@ type RouterEnv interface {
 	NotifyCertsUpdated()
 	GetAlerter() Alerter
 	GetXgressRegistry() *Registry
+	GetEdgeServerCertSource() EdgeServerCertSource
 
 	UpdateCtrlEndpointDetails(controllers []*ctrl_pb.CtrlDetail)
 	UpdateLeader(leaderId string)
@@ -72,6 +74,18 @@ type RouterEnv interface {
 	GetInspectHandler() channel.ContentTypeReceiver
 }
 
+// EdgeServerCertSource supplies edge listener server certificates which take precedence over the router
+// identity's own, such as a publicly trusted certificate obtained through ACME.
+type EdgeServerCertSource interface {
+	// GetCertificate returns the certificate to present for the given hello, or nil to fall back to the
+	// router identity's server certificates
+	GetCertificate(hello *tls.ClientHelloInfo) *tls.Certificate
+
+	// GetConfigForClient returns a config to use for the given hello in place of the listener's own, or nil to
+	// use the listener's. This allows answering ACME tls-alpn-01 challenges on the edge listeners.
+	GetConfigForClient(hello *tls.ClientHelloInfo) *tls.Config
+}
+
 type Alerter interface {
 	ReportError(message string, details []string, relatedEntities map[string]string)
 }
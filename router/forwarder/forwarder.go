@@ -27,6 +27,7 @@ import (
 	"github.com/openziti/foundation/v2/info"
 	"github.com/openziti/foundation/v2/uuidz"
 	"github.com/openziti/sdk-golang/v2/xgress"
+	"github.com/openziti/ziti/v2/common/ctrl_msg"
 	"github.com/openziti/ziti/v2/common/inspect"
 	"github.com/openziti/ziti/v2/common/logging"
 	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
@@ -160,6 +161,8 @@ func (forwarder *Forwarder) Route(ctrlId string, route *ctrl_pb.Route) error {
 	} else {
 		circuitFt = newForwardTable(ctrlId)
 	}
+	multipathMode := route.Tags[ctrl_msg.MultipathRouteTag]
+	destinationsBySource := map[xgress.Address][]xgress.Address{}
 	for _, forward := range route.Forwards {
 		if !forwarder.HasDestination(xgress.Address(forward.DstAddress)) {
 			if forward.DstType == ctrl_pb.DestType_Link {
@@ -171,13 +174,39 @@ func (forwarder *Forwarder) Route(ctrlId string, route *ctrl_pb.Route) error {
 			}
 			// It's an ingress destination, which isn't established until after routing has completed
 		}
-		circuitFt.setForwardAddress(xgress.Address(forward.SrcAddress), xgress.Address(forward.DstAddress))
+		srcAddr := xgress.Address(forward.SrcAddress)
+		dstAddr := xgress.Address(forward.DstAddress)
+		if _, found := destinationsBySource[srcAddr]; !found {
+			// the first destination listed for a source is the primary path
+			circuitFt.setForwardAddress(srcAddr, dstAddr)
+		}
+		destinationsBySource[srcAddr] = append(destinationsBySource[srcAddr], dstAddr)
 		routeLog.Debug("route added",
 			"circuitId", circuitId,
 			"source", forward.SrcAddress,
 			"destination", forward.DstAddress,
 		)
 	}
+	sourcesByDestination := map[xgress.Address]int{}
+	for srcAddr, dstAddrs := range destinationsBySource {
+		if multipathMode != "" && len(dstAddrs) > 1 {
+			circuitFt.setMultipath(srcAddr, multipathMode, dstAddrs)
+		} else {
+			circuitFt.clearMultipath(srcAddr)
+		}
+		for _, dstAddr := range dstAddrs {
+			sourcesByDestination[dstAddr]++
+		}
+	}
+	// where the paths of a multipath circuit meet, copies of each payload arrive over every path, so only the
+	// first copy is passed on
+	for dstAddr, sources := range sourcesByDestination {
+		if multipathMode != "" && sources > 1 {
+			circuitFt.setDeduplicated(dstAddr)
+		} else {
+			circuitFt.clearDeduplicated(dstAddr)
+		}
+	}
 	forwarder.circuits.setForwardTable(circuitId, circuitFt)
 	return nil
 }
@@ -207,38 +236,34 @@ func (forwarder *Forwarder) RetransmitPayload(srcAddr xgress.Address, payload *x
 func (forwarder *Forwarder) forwardPayload(srcAddr xgress.Address, payload *xgress.Payload, markActive bool, timeout time.Duration) error {
 	circuitId := payload.GetCircuitId()
 	if forwardTable, found := forwarder.circuits.getForwardTable(circuitId, markActive); found {
+		payloadType := xgress.PayloadTypeXg
+		if !markActive {
+			payloadType = xgress.PayloadTypeRtx
+		} else if timeout == 0 {
+			payloadType = xgress.PayloadTypeFwd
+		}
+
+		if mp, found := forwardTable.getMultipath(srcAddr); found {
+			return forwarder.sendMultipath(mp, func(dstAddr xgress.Address) error {
+				return forwarder.sendPayload(srcAddr, dstAddr, payload, timeout, payloadType)
+			})
+		}
+
 		if dstAddr, found := forwardTable.getForwardAddress(srcAddr); found {
-			if dst, found := forwarder.destinations.getDestination(dstAddr); found {
-				payloadType := xgress.PayloadTypeXg
-				if !markActive {
-					payloadType = xgress.PayloadTypeRtx
-				} else if timeout == 0 {
-					payloadType = xgress.PayloadTypeFwd
-				}
-				if err := dst.SendPayload(payload, timeout, payloadType); err != nil {
-					return err
-				}
-				// Per-payload hot path: build the logger only if Debug is enabled, so
-				// the level check is the only cost when this channel is below Debug.
+			// retransmits are always passed on, since the receiver must re-acknowledge a payload whose
+			// acknowledgement was lost
+			if !payload.IsRetransmitFlagSet() && forwardTable.isDuplicatePayload(dstAddr, payload.Sequence) {
 				if routeLog.Enabled(context.Background(), slog.LevelDebug) {
-					attrs := []any{
+					routeLog.Debug("dropped duplicate multipath payload",
 						"srcAddr", string(srcAddr),
 						"dstAddr", string(dstAddr),
 						"circuitId", circuitId,
 						"seq", payload.Sequence,
-						"origin", payload.GetOriginator(),
-					}
-					// Only present when the payload is being traced; lets operators
-					// correlate a single payload across hops.
-					if uuidVal, found := payload.Headers[xgress.HeaderKeyUUID]; found {
-						attrs = append(attrs, "uuid", uuidz.ToString(uuidVal))
-					}
-					routeLog.Debug("forwarded payload", attrs...)
+					)
 				}
 				return nil
-			} else {
-				return fmt.Errorf("cannot forward payload, no destination for circuit=%v src=%v dst=%v", circuitId, srcAddr, dstAddr)
 			}
+			return forwarder.sendPayload(srcAddr, dstAddr, payload, timeout, payloadType)
 		} else {
 			return fmt.Errorf("cannot forward payload, no destination address for circuit=%v src=%v", circuitId, srcAddr)
 		}
@@ -247,27 +272,46 @@ func (forwarder *Forwarder) forwardPayload(srcAddr xgress.Address, payload *xgre
 	}
 }
 
+func (forwarder *Forwarder) sendPayload(srcAddr, dstAddr xgress.Address, payload *xgress.Payload, timeout time.Duration, payloadType xgress.PayloadType) error {
+	circuitId := payload.GetCircuitId()
+	if dst, found := forwarder.destinations.getDestination(dstAddr); found {
+		if err := dst.SendPayload(payload, timeout, payloadType); err != nil {
+			return err
+		}
+		// Per-payload hot path: build the logger only if Debug is enabled, so
+		// the level check is the only cost when this channel is below Debug.
+		if routeLog.Enabled(context.Background(), slog.LevelDebug) {
+			attrs := []any{
+				"srcAddr", string(srcAddr),
+				"dstAddr", string(dstAddr),
+				"circuitId", circuitId,
+				"seq", payload.Sequence,
+				"origin", payload.GetOriginator(),
+			}
+			// Only present when the payload is being traced; lets operators
+			// correlate a single payload across hops.
+			if uuidVal, found := payload.Headers[xgress.HeaderKeyUUID]; found {
+				attrs = append(attrs, "uuid", uuidz.ToString(uuidVal))
+			}
+			routeLog.Debug("forwarded payload", attrs...)
+		}
+		return nil
+	} else {
+		return fmt.Errorf("cannot forward payload, no destination for circuit=%v src=%v dst=%v", circuitId, srcAddr, dstAddr)
+	}
+}
+
 func (forwarder *Forwarder) ForwardAcknowledgement(srcAddr xgress.Address, acknowledgement *xgress.Acknowledgement) error {
 	circuitId := acknowledgement.CircuitId
 	if forwardTable, found := forwarder.circuits.getForwardTable(circuitId, true); found {
-		if dstAddr, found := forwardTable.getForwardAddress(srcAddr); found {
-			if dst, found := forwarder.destinations.getDestination(dstAddr); found {
-				if err := dst.SendAcknowledgement(acknowledgement); err != nil {
-					return err
-				}
-				if routeLog.Enabled(context.Background(), slog.LevelDebug) {
-					routeLog.Debug("forwarded acknowledgement",
-						"srcAddr", string(srcAddr),
-						"dstAddr", string(dstAddr),
-						"circuitId", circuitId,
-					)
-				}
-				return nil
-
-			} else {
-				return fmt.Errorf("cannot acknowledge, no destination for circuit=%v src=%v dst=%v", circuitId, srcAddr, dstAddr)
-			}
+		if mp, found := forwardTable.getMultipath(srcAddr); found {
+			return forwarder.sendFailover(mp, func(dstAddr xgress.Address) error {
+				return forwarder.sendAcknowledgement(srcAddr, dstAddr, acknowledgement)
+			})
+		}
 
+		if dstAddr, found := forwardTable.getForwardAddress(srcAddr); found {
+			return forwarder.sendAcknowledgement(srcAddr, dstAddr, acknowledgement)
 		} else {
 			return fmt.Errorf("cannot acknowledge, no destination address for circuit=%v src=%v", circuitId, srcAddr)
 		}
@@ -277,6 +321,62 @@ func (forwarder *Forwarder) ForwardAcknowledgement(srcAddr xgress.Address, ackno
 	}
 }
 
+func (forwarder *Forwarder) sendAcknowledgement(srcAddr, dstAddr xgress.Address, acknowledgement *xgress.Acknowledgement) error {
+	circuitId := acknowledgement.CircuitId
+	if dst, found := forwarder.destinations.getDestination(dstAddr); found {
+		if err := dst.SendAcknowledgement(acknowledgement); err != nil {
+			return err
+		}
+		if routeLog.Enabled(context.Background(), slog.LevelDebug) {
+			routeLog.Debug("forwarded acknowledgement",
+				"srcAddr", string(srcAddr),
+				"dstAddr", string(dstAddr),
+				"circuitId", circuitId,
+			)
+		}
+		return nil
+	} else {
+		return fmt.Errorf("cannot acknowledge, no destination for circuit=%v src=%v dst=%v", circuitId, srcAddr, dstAddr)
+	}
+}
+
+// sendMultipath sends to the destinations of a multipath source. In duplicate mode every destination is sent to,
+// in spray mode destinations are tried in turn until one succeeds. Either way, sending only fails if no destination
+// accepted the message, since the point of multipath is to ride out the failure of a single path.
+func (forwarder *Forwarder) sendMultipath(mp *multipathForward, send func(dstAddr xgress.Address) error) error {
+	var errs []error
+	sent := false
+	for _, dstAddr := range mp.getTargets() {
+		if err := send(dstAddr); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sent = true
+		if !mp.isDuplicate() {
+			break
+		}
+	}
+	if sent {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// sendFailover sends over a single path of a multipath source, starting with the primary and only moving on to the
+// other paths if a path can't take the message. Acknowledgements go this way, since the far end would otherwise
+// process a copy of every acknowledgement for each path.
+func (forwarder *Forwarder) sendFailover(mp *multipathForward, send func(dstAddr xgress.Address) error) error {
+	var errs []error
+	for _, dstAddr := range mp.destinations {
+		err := send(dstAddr)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
 func (forwarder *Forwarder) ForwardControl(srcAddr xgress.Address, control *xgress.Control) error {
 	circuitId := control.CircuitId
 	log := routeLog.With("srcAddr", string(srcAddr), "circuitId", circuitId)
@@ -400,6 +500,11 @@ func (forwarder *Forwarder) InspectCircuits() *inspect.ForwarderCircuits {
 			forwarder.InspectDestination(k, detail)
 			forwarder.InspectDestination(v, detail)
 		}
+		ft.multipath.IterCb(func(_ string, mp *multipathForward) {
+			for _, dstAddr := range mp.destinations {
+				forwarder.InspectDestination(string(dstAddr), detail)
+			}
+		})
 
 		result.Circuits[key] = detail
 	})
@@ -19,10 +19,12 @@ package forwarder
 import (
 	"fmt"
 	"reflect"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/openziti/sdk-golang/v2/xgress"
+	"github.com/openziti/ziti/v2/common/ctrl_msg"
 	"github.com/openziti/ziti/v2/router/env"
 	"github.com/orcaman/concurrent-map/v2"
 )
@@ -67,20 +69,62 @@ func (st *circuitTable) debug() string {
 	return out
 }
 
-// forwardTable implements a directory of destinations, keyed by source address.
+// forwardTable implements a directory of destinations, keyed by source address. Sources which are routed over
+// more than one path also have an entry in multipath. The destinations entry for those sources holds the primary
+// path, which is used for control messages. Destinations which are reached from more than one path, such as the
+// xgress at either end of a multipath circuit, have an entry in received, which drops the copies of a payload
+// arriving over the other paths.
 type forwardTable struct {
 	ctrlId       string
 	last         int64
 	destinations cmap.ConcurrentMap[string, string]
+	multipath    cmap.ConcurrentMap[string, *multipathForward]
+	received     cmap.ConcurrentMap[string, *sequenceWindow]
 }
 
 func newForwardTable(ctrlId string) *forwardTable {
 	return &forwardTable{
 		ctrlId:       ctrlId,
 		destinations: cmap.New[string](),
+		multipath:    cmap.New[*multipathForward](),
+		received:     cmap.New[*sequenceWindow](),
 	}
 }
 
+// setDeduplicated tracks the payload sequences forwarded to the given destination. The window is kept across
+// reroutes, so that payloads already delivered aren't delivered again when the paths change.
+func (ft *forwardTable) setDeduplicated(dst xgress.Address) {
+	ft.received.SetIfAbsent(string(dst), &sequenceWindow{})
+}
+
+func (ft *forwardTable) clearDeduplicated(dst xgress.Address) {
+	ft.received.Remove(string(dst))
+}
+
+// isDuplicatePayload reports whether a payload with the given sequence has already been forwarded to the
+// destination. Destinations which are only reached over a single path are never deduplicated.
+func (ft *forwardTable) isDuplicatePayload(dst xgress.Address, sequence int32) bool {
+	if window, found := ft.received.Get(string(dst)); found {
+		return !window.accept(sequence)
+	}
+	return false
+}
+
+func (ft *forwardTable) setMultipath(src xgress.Address, mode string, destinations []xgress.Address) {
+	ft.multipath.Set(string(src), &multipathForward{
+		mode:         mode,
+		destinations: destinations,
+	})
+}
+
+func (ft *forwardTable) clearMultipath(src xgress.Address) {
+	ft.multipath.Remove(string(src))
+}
+
+func (ft *forwardTable) getMultipath(src xgress.Address) (*multipathForward, bool) {
+	return ft.multipath.Get(string(src))
+}
+
 func (ft *forwardTable) setForwardAddress(src, dst xgress.Address) {
 	ft.destinations.Set(string(src), string(dst))
 }
@@ -97,9 +141,108 @@ func (ft *forwardTable) debug() string {
 	for i := range ft.destinations.IterBuffered() {
 		out += fmt.Sprintf("\t\t@/%s -> @/%s\n", i.Key, i.Val)
 	}
+	for i := range ft.multipath.IterBuffered() {
+		out += fmt.Sprintf("\t\t@/%s => %v (%s)\n", i.Key, i.Val.destinations, i.Val.mode)
+	}
 	return out
 }
 
+// multipathForward holds the destinations for a source which is routed over more than one path
+type multipathForward struct {
+	mode         string
+	destinations []xgress.Address
+	next         atomic.Uint32
+}
+
+// getTargets returns the destinations to send to, in order. In duplicate mode every destination should be sent to.
+// In spray mode only the first destination which accepts the message should be used, and the starting destination
+// rotates on each call so that traffic is spread across the paths.
+func (mp *multipathForward) getTargets() []xgress.Address {
+	if mp.mode != ctrl_msg.MultipathModeSpray {
+		return mp.destinations
+	}
+	count := uint32(len(mp.destinations))
+	start := mp.next.Add(1)
+	result := make([]xgress.Address, 0, count)
+	for i := uint32(0); i < count; i++ {
+		result = append(result, mp.destinations[(start+i)%count])
+	}
+	return result
+}
+
+func (mp *multipathForward) isDuplicate() bool {
+	return mp.mode != ctrl_msg.MultipathModeSpray
+}
+
+// sequenceWindowSize is how many sequences behind the highest one seen are remembered. Anything older is assumed
+// to have been seen already.
+const sequenceWindowSize = 1024
+
+// sequenceWindow records which payload sequences have been forwarded to a destination, so that copies of a payload
+// sent over more than one path are only forwarded once.
+type sequenceWindow struct {
+	lock        sync.Mutex
+	initialized bool
+	highest     int32
+	seen        [sequenceWindowSize / 64]uint64
+}
+
+// accept returns true the first time a sequence is seen and false for every copy after that
+func (self *sequenceWindow) accept(sequence int32) bool {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if !self.initialized {
+		self.initialized = true
+		self.highest = sequence
+		self.set(sequence)
+		return true
+	}
+
+	if sequence > self.highest {
+		if int64(sequence)-int64(self.highest) >= sequenceWindowSize {
+			self.seen = [sequenceWindowSize / 64]uint64{}
+		} else {
+			for s := self.highest + 1; s < sequence; s++ {
+				self.clear(s)
+			}
+		}
+		self.highest = sequence
+		self.set(sequence)
+		return true
+	}
+
+	if int64(self.highest)-int64(sequence) >= sequenceWindowSize {
+		return false
+	}
+
+	if self.isSet(sequence) {
+		return false
+	}
+	self.set(sequence)
+	return true
+}
+
+func (self *sequenceWindow) index(sequence int32) (int, uint64) {
+	bit := uint32(sequence) % sequenceWindowSize
+	return int(bit / 64), 1 << (bit % 64)
+}
+
+func (self *sequenceWindow) set(sequence int32) {
+	idx, mask := self.index(sequence)
+	self.seen[idx] |= mask
+}
+
+func (self *sequenceWindow) clear(sequence int32) {
+	idx, mask := self.index(sequence)
+	self.seen[idx] &^= mask
+}
+
+func (self *sequenceWindow) isSet(sequence int32) bool {
+	idx, mask := self.index(sequence)
+	return self.seen[idx]&mask != 0
+}
+
 // destinationTable implements a directory of destinations, keyed by Address.
 type destinationTable struct {
 	destinations cmap.ConcurrentMap[string, env.Destination]
@@ -19,6 +19,10 @@ package forwarder
 import (
 	"sync/atomic"
 	"testing"
+
+	"github.com/openziti/sdk-golang/v2/xgress"
+	"github.com/openziti/ziti/v2/common/ctrl_msg"
+	"github.com/stretchr/testify/require"
 )
 
 // A simple test to check for failure of alignment on atomic operations for 64 bit variables in a struct
@@ -33,3 +37,94 @@ func Test64BitAlignment(t *testing.T) {
 
 	atomic.LoadInt64(&fTable.last)
 }
+
+func TestMultipathTargets(t *testing.T) {
+	t.Run("duplicate sends to every destination", func(t *testing.T) {
+		req := require.New(t)
+		mp := &multipathForward{
+			mode:         ctrl_msg.MultipathModeDuplicate,
+			destinations: []xgress.Address{"l0", "l1"},
+		}
+		req.True(mp.isDuplicate())
+		req.Equal([]xgress.Address{"l0", "l1"}, mp.getTargets())
+		req.Equal([]xgress.Address{"l0", "l1"}, mp.getTargets())
+	})
+
+	t.Run("spray rotates the starting destination", func(t *testing.T) {
+		req := require.New(t)
+		mp := &multipathForward{
+			mode:         ctrl_msg.MultipathModeSpray,
+			destinations: []xgress.Address{"l0", "l1"},
+		}
+		req.False(mp.isDuplicate())
+		first := mp.getTargets()
+		second := mp.getTargets()
+		req.Len(first, 2)
+		req.Equal(first[0], second[1])
+		req.Equal(first[1], second[0])
+	})
+}
+
+func TestSequenceWindow(t *testing.T) {
+	t.Run("each sequence is accepted once", func(t *testing.T) {
+		req := require.New(t)
+		window := &sequenceWindow{}
+		for seq := int32(0); seq < 10; seq++ {
+			req.True(window.accept(seq))
+			req.False(window.accept(seq))
+		}
+	})
+
+	t.Run("out of order sequences are accepted once", func(t *testing.T) {
+		req := require.New(t)
+		window := &sequenceWindow{}
+		req.True(window.accept(5))
+		req.True(window.accept(2))
+		req.True(window.accept(4))
+		req.False(window.accept(2))
+		req.True(window.accept(3))
+		req.False(window.accept(5))
+		req.True(window.accept(6))
+	})
+
+	t.Run("sequences skipped by a jump are still accepted", func(t *testing.T) {
+		req := require.New(t)
+		window := &sequenceWindow{}
+		req.True(window.accept(0))
+		req.True(window.accept(sequenceWindowSize + 10))
+		req.True(window.accept(sequenceWindowSize + 5))
+		req.False(window.accept(sequenceWindowSize + 5))
+
+		// bits reused from before the jump must not report sequences as seen
+		req.True(window.accept(2*sequenceWindowSize + 5))
+		req.True(window.accept(2*sequenceWindowSize + 4))
+	})
+
+	t.Run("sequences older than the window are dropped", func(t *testing.T) {
+		req := require.New(t)
+		window := &sequenceWindow{}
+		req.True(window.accept(sequenceWindowSize * 3))
+		req.False(window.accept(sequenceWindowSize))
+		req.True(window.accept(sequenceWindowSize*2 + 1))
+	})
+}
+
+func TestForwardTableDeduplication(t *testing.T) {
+	req := require.New(t)
+	ft := newForwardTable("ctrl")
+
+	req.False(ft.isDuplicatePayload("xg", 1))
+	req.False(ft.isDuplicatePayload("xg", 1), "destinations reached over a single path aren't deduplicated")
+
+	ft.setDeduplicated("xg")
+	req.False(ft.isDuplicatePayload("xg", 1))
+	req.True(ft.isDuplicatePayload("xg", 1))
+
+	// rerouting keeps what has already been delivered
+	ft.setDeduplicated("xg")
+	req.True(ft.isDuplicatePayload("xg", 1))
+	req.False(ft.isDuplicatePayload("xg", 2))
+
+	ft.clearDeduplicated("xg")
+	req.False(ft.isDuplicatePayload("xg", 2))
+}
@@ -112,6 +112,7 @@ type Router struct {
 	debugOperations     map[byte]func(c *bufio.ReadWriter) error
 	stateManager        state.Manager
 	certManager         *state.CertExpirationChecker
+	acmeCertManager     *state.AcmeCertManager
 	xwebs               []xweb.Instance
 	xwebFactoryRegistry xweb.Registry
 	agentBindHandlers   []channel.BindHandler
@@ -277,6 +278,7 @@ func (self *Router) GetChannelHeaders() (channel.Headers, error) {
 	}
 
 	headers[int32(ctrl_pb.ControlHeaders_CapabilitiesHeader)] = self.routerCapabilities.Bytes()
+	self.config.Locality.PutHeaders(headers)
 
 	ctrlListeners := &ctrl_pb.CtrlChanListeners{}
 	for _, listener := range self.config.Ctrl.Listeners {
@@ -339,6 +341,7 @@ func Create(cfg *env.Config, versionProvider versions.VersionProvider) *Router {
 	router.stateManager = state.NewManager(router)
 	router.certManager = state.NewCertExpirationChecker(router, true)
 	router.alertReporter = alert.NewAlertReporter(router.ctrls, cfg.Id.Token, 1000, 10)
+	router.acmeCertManager = state.NewAcmeCertManager(router)
 	router.configRegistry = managedconfig.NewRegistry(newManagedConfigAlertCallback(router.alertReporter))
 	router.linkSubsystem = link.NewSubsystem(cfg.Id)
 	router.linkSubsystem.SetConfigurationChangeHandler(router.onLinkSubsystemChanged)
@@ -386,6 +389,13 @@ func (self *Router) GetAlerter() env.Alerter {
 	return self.alertReporter
 }
 
+func (self *Router) GetEdgeServerCertSource() env.EdgeServerCertSource {
+	if self.acmeCertManager == nil {
+		return nil
+	}
+	return self.acmeCertManager
+}
+
 func (self *Router) createDataPlaneAdapter() xgress.DataPlaneAdapter {
 	payloadIngester := xgress.NewPayloadIngesterWithConfig(64, self.shutdownC)
 	ackSender := xgress_router.NewAcker(self.forwarder, self.metricsRegistry, self.shutdownC)
@@ -539,6 +549,11 @@ func (self *Router) Start() error {
 
 	self.startXgressListeners()
 
+	// tls-alpn-01 challenges are answered by the edge listeners, so they have to be up first
+	if self.acmeCertManager != nil {
+		go self.acmeCertManager.Run()
+	}
+
 	// Start web services
 	for _, web := range self.xwebs {
 		go web.Run()
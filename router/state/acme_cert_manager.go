@@ -0,0 +1,391 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package state
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/michaelquigley/pfxlog"
+	routerEnv "github.com/openziti/ziti/v2/router/env"
+	cmap "github.com/orcaman/concurrent-map/v2"
+)
+
+const (
+	acmeAccountKeyFile = "account.key"
+	acmeAccountFile    = "account.json"
+	acmeCertFile       = "cert.pem"
+	acmeKeyFile        = "key.pem"
+
+	acmeMinRetryInterval = time.Minute
+	acmeMaxRetryInterval = 6 * time.Hour
+)
+
+type AcmeEnv interface {
+	GetConfig() *routerEnv.Config
+	GetCloseNotify() <-chan struct{}
+	GetAlerter() routerEnv.Alerter
+}
+
+// acmeAccount is the ACME account used to request certificates. It's stored alongside the certificate so that
+// renewals reuse the account rather than registering a new one each time.
+type acmeAccount struct {
+	Directory    string                 `json:"directory"`
+	Email        string                 `json:"email"`
+	Registration *registration.Resource `json:"registration"`
+	key          crypto.PrivateKey
+}
+
+func (self *acmeAccount) GetEmail() string {
+	return self.Email
+}
+
+func (self *acmeAccount) GetRegistration() *registration.Resource {
+	return self.Registration
+}
+
+func (self *acmeAccount) GetPrivateKey() crypto.PrivateKey {
+	return self.key
+}
+
+// AcmeCertManager obtains a publicly trusted server certificate for the edge listeners from an ACME CA and
+// renews it before it expires. New certificates are picked up by the edge listeners on the next handshake, so
+// no restart is needed. It runs independently of the CertExpirationChecker, which renews the ziti issued
+// router identity.
+type AcmeCertManager struct {
+	config         *routerEnv.AcmeConfig
+	closeNotify    <-chan struct{}
+	alerter        routerEnv.Alerter
+	cert           atomic.Pointer[tls.Certificate]
+	challengeCerts cmap.ConcurrentMap[string, *tls.Certificate]
+}
+
+// NewAcmeCertManager returns a manager for the edge ACME config, or nil if ACME isn't configured
+func NewAcmeCertManager(env AcmeEnv) *AcmeCertManager {
+	edgeConfig := env.GetConfig().Edge
+	if edgeConfig == nil || edgeConfig.Acme == nil {
+		return nil
+	}
+
+	result := &AcmeCertManager{
+		config:         edgeConfig.Acme,
+		closeNotify:    env.GetCloseNotify(),
+		alerter:        env.GetAlerter(),
+		challengeCerts: cmap.New[*tls.Certificate](),
+	}
+
+	if err := result.loadStoredCert(); err != nil {
+		pfxlog.Logger().WithError(err).WithField("storageDir", result.config.StorageDir).
+			Warn("unable to load stored acme certificate, a new one will be requested")
+	}
+
+	return result
+}
+
+func (self *AcmeCertManager) Run() {
+	log := pfxlog.Logger().WithField("domains", self.config.Domains)
+
+	var retryInterval time.Duration
+	for {
+		durationToWait := max(self.getWaitTime(), retryInterval)
+		log.Infof("waiting %s to request acme certificate", durationToWait)
+
+		select {
+		case <-time.After(durationToWait):
+		case <-self.closeNotify:
+			return
+		}
+
+		if err := self.obtain(); err != nil {
+			retryInterval = min(max(retryInterval*2, acmeMinRetryInterval), acmeMaxRetryInterval)
+			log.WithError(err).Errorf("unable to obtain acme certificate, retrying in %s", retryInterval)
+			if self.alerter != nil {
+				self.alerter.ReportError("unable to obtain acme certificate for edge listeners", []string{err.Error()},
+					map[string]string{"domains": strings.Join(self.config.Domains, ",")})
+			}
+		} else {
+			retryInterval = 0
+		}
+	}
+}
+
+// getWaitTime returns how long to wait before requesting a certificate. Certificates are renewed
+// config.RenewBefore ahead of expiry, or a third of the way from the end of their lifetime for short-lived
+// certificates which wouldn't otherwise ever be considered current.
+func (self *AcmeCertManager) getWaitTime() time.Duration {
+	cert := self.cert.Load()
+	if cert == nil {
+		return 0
+	}
+
+	renewBefore := self.config.RenewBefore
+	if lifetime := cert.Leaf.NotAfter.Sub(cert.Leaf.NotBefore); renewBefore >= lifetime {
+		renewBefore = lifetime / 3
+	}
+
+	return max(time.Until(cert.Leaf.NotAfter.Add(-renewBefore)), 0)
+}
+
+// GetCertificate returns the ACME certificate if the client asked for one of the configured domains
+func (self *AcmeCertManager) GetCertificate(hello *tls.ClientHelloInfo) *tls.Certificate {
+	cert := self.cert.Load()
+	if cert == nil || !slices.Contains(self.config.Domains, strings.ToLower(hello.ServerName)) {
+		return nil
+	}
+	return cert
+}
+
+// GetConfigForClient answers tls-alpn-01 challenges from the ACME CA while a certificate request is in progress
+func (self *AcmeCertManager) GetConfigForClient(hello *tls.ClientHelloInfo) *tls.Config {
+	if !slices.Contains(hello.SupportedProtos, tlsalpn01.ACMETLS1Protocol) {
+		return nil
+	}
+
+	cert, found := self.challengeCerts.Get(strings.ToLower(hello.ServerName))
+	if !found {
+		return nil
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{*cert},
+		NextProtos:   []string{tlsalpn01.ACMETLS1Protocol},
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// Present implements challenge.Provider for tls-alpn-01 challenges, which are answered by the edge listeners
+func (self *AcmeCertManager) Present(domain, _, keyAuth string) error {
+	cert, err := tlsalpn01.ChallengeCert(domain, keyAuth)
+	if err != nil {
+		return err
+	}
+	self.challengeCerts.Set(strings.ToLower(domain), cert)
+	return nil
+}
+
+// CleanUp implements challenge.Provider for tls-alpn-01 challenges
+func (self *AcmeCertManager) CleanUp(domain, _, _ string) error {
+	self.challengeCerts.Remove(strings.ToLower(domain))
+	return nil
+}
+
+func (self *AcmeCertManager) obtain() error {
+	account, err := self.loadAccount()
+	if err != nil {
+		return err
+	}
+
+	legoConfig := lego.NewConfig(account)
+	legoConfig.CADirURL = self.config.Directory
+	legoConfig.Certificate.KeyType = certcrypto.KeyType(self.config.KeyType)
+
+	if self.config.CaCerts != "" {
+		pool, err := lego.CreateCertPool([]string{self.config.CaCerts}, false)
+		if err != nil {
+			return fmt.Errorf("unable to load acme ca certs from %s (%w)", self.config.CaCerts, err)
+		}
+		if transport, ok := legoConfig.HTTPClient.Transport.(*http.Transport); ok {
+			transport.TLSClientConfig.RootCAs = pool
+		}
+	}
+
+	client, err := lego.NewClient(legoConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create acme client for %s (%w)", self.config.Directory, err)
+	}
+
+	if self.config.Challenge == routerEnv.AcmeChallengeHttp01 {
+		var host, port string
+		if host, port, err = net.SplitHostPort(self.config.HttpAddress); err != nil {
+			return err
+		}
+		err = client.Challenge.SetHTTP01Provider(http01.NewProviderServer(host, port))
+	} else {
+		err = client.Challenge.SetTLSALPN01Provider(self)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to configure acme %s challenge (%w)", self.config.Challenge, err)
+	}
+
+	if account.Registration == nil {
+		if account.Registration, err = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true}); err != nil {
+			return fmt.Errorf("unable to register acme account (%w)", err)
+		}
+		if err = self.saveAccount(account); err != nil {
+			return err
+		}
+	}
+
+	resource, err := client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains: self.config.Domains,
+		Bundle:  true,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to obtain acme certificate (%w)", err)
+	}
+
+	cert, err := parseAcmeCert(resource.Certificate, resource.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	if err = self.writeFile(acmeCertFile, resource.Certificate); err != nil {
+		return err
+	}
+
+	if err = self.writeFile(acmeKeyFile, resource.PrivateKey); err != nil {
+		return err
+	}
+
+	self.cert.Store(cert)
+
+	pfxlog.Logger().WithField("domains", self.config.Domains).
+		WithField("notAfter", cert.Leaf.NotAfter).
+		Info("obtained acme certificate for edge listeners")
+
+	return nil
+}
+
+// loadStoredCert loads the certificate saved by a previous run, as long as it still covers the configured domains
+func (self *AcmeCertManager) loadStoredCert() error {
+	certPem, err := os.ReadFile(filepath.Join(self.config.StorageDir, acmeCertFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	keyPem, err := os.ReadFile(filepath.Join(self.config.StorageDir, acmeKeyFile))
+	if err != nil {
+		return err
+	}
+
+	cert, err := parseAcmeCert(certPem, keyPem)
+	if err != nil {
+		return err
+	}
+
+	for _, domain := range self.config.Domains {
+		if err = cert.Leaf.VerifyHostname(domain); err != nil {
+			return fmt.Errorf("stored acme certificate doesn't cover %s", domain)
+		}
+	}
+
+	if !time.Now().Before(cert.Leaf.NotAfter) {
+		return fmt.Errorf("stored acme certificate expired at %s", cert.Leaf.NotAfter)
+	}
+
+	self.cert.Store(cert)
+	return nil
+}
+
+// loadAccount loads the stored account, creating an account key if there isn't one yet. The registration is
+// discarded if it was made with a different key or for a different directory or email, so that the account gets
+// registered again.
+func (self *AcmeCertManager) loadAccount() (*acmeAccount, error) {
+	if err := os.MkdirAll(self.config.StorageDir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create acme storage dir %s (%w)", self.config.StorageDir, err)
+	}
+
+	account := &acmeAccount{}
+	newKey := false
+
+	keyPem, err := os.ReadFile(filepath.Join(self.config.StorageDir, acmeAccountKeyFile))
+	if errors.Is(err, os.ErrNotExist) {
+		newKey = true
+		if account.key, err = certcrypto.GeneratePrivateKey(certcrypto.EC256); err != nil {
+			return nil, fmt.Errorf("unable to generate acme account key (%w)", err)
+		}
+		if err = self.writeFile(acmeAccountKeyFile, certcrypto.PEMEncode(account.key)); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if account.key, err = certcrypto.ParsePEMPrivateKey(keyPem); err != nil {
+		return nil, fmt.Errorf("unable to parse acme account key (%w)", err)
+	}
+
+	accountJson, err := os.ReadFile(filepath.Join(self.config.StorageDir, acmeAccountFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if err == nil {
+		if err = json.Unmarshal(accountJson, account); err != nil {
+			return nil, fmt.Errorf("unable to parse acme account (%w)", err)
+		}
+	}
+
+	if newKey || account.Directory != self.config.Directory || account.Email != self.config.Email {
+		account.Registration = nil
+	}
+
+	account.Directory = self.config.Directory
+	account.Email = self.config.Email
+
+	return account, nil
+}
+
+func (self *AcmeCertManager) saveAccount(account *acmeAccount) error {
+	accountJson, err := json.MarshalIndent(account, "", "  ")
+	if err != nil {
+		return err
+	}
+	return self.writeFile(acmeAccountFile, accountJson)
+}
+
+func (self *AcmeCertManager) writeFile(name string, data []byte) error {
+	path := filepath.Join(self.config.StorageDir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("unable to write %s (%w)", path, err)
+	}
+	return nil
+}
+
+func parseAcmeCert(certPem, keyPem []byte) (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(certPem, keyPem)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse acme certificate (%w)", err)
+	}
+
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return nil, fmt.Errorf("unable to parse acme certificate (%w)", err)
+		}
+	}
+
+	return &cert, nil
+}
@@ -0,0 +1,317 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package state
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/letsencrypt/pebble/v2/ca"
+	"github.com/letsencrypt/pebble/v2/db"
+	"github.com/letsencrypt/pebble/v2/va"
+	"github.com/letsencrypt/pebble/v2/wfe"
+	routerEnv "github.com/openziti/ziti/v2/router/env"
+	cmap "github.com/orcaman/concurrent-map/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAcmeCertManager(t *testing.T, domains ...string) *AcmeCertManager {
+	return &AcmeCertManager{
+		config: &routerEnv.AcmeConfig{
+			Domains:     domains,
+			RenewBefore: 30 * 24 * time.Hour,
+			StorageDir:  t.TempDir(),
+		},
+		challengeCerts: cmap.New[*tls.Certificate](),
+	}
+}
+
+// newTestAcmeCertPem returns a self-signed certificate and key, PEM encoded as an ACME CA would return them
+func newTestAcmeCertPem(t *testing.T, notBefore, notAfter time.Time, domains ...string) ([]byte, []byte) {
+	req := require.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	req.NoError(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domains[0]},
+		DNSNames:     domains,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	req.NoError(err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	req.NoError(err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestAcmeCertManagerGetWaitTime(t *testing.T) {
+	t.Run("no certificate requests immediately", func(t *testing.T) {
+		mgr := newTestAcmeCertManager(t, "edge.example.com")
+		require.Zero(t, mgr.getWaitTime())
+	})
+
+	t.Run("renews ahead of expiry", func(t *testing.T) {
+		req := require.New(t)
+		mgr := newTestAcmeCertManager(t, "edge.example.com")
+
+		now := time.Now()
+		certPem, keyPem := newTestAcmeCertPem(t, now, now.Add(90*24*time.Hour), "edge.example.com")
+		cert, err := parseAcmeCert(certPem, keyPem)
+		req.NoError(err)
+		mgr.cert.Store(cert)
+
+		req.InDelta(float64(60*24*time.Hour), float64(mgr.getWaitTime()), float64(time.Minute))
+	})
+
+	t.Run("short-lived certificates renew a third from the end", func(t *testing.T) {
+		req := require.New(t)
+		mgr := newTestAcmeCertManager(t, "edge.example.com")
+
+		now := time.Now()
+		certPem, keyPem := newTestAcmeCertPem(t, now, now.Add(6*24*time.Hour), "edge.example.com")
+		cert, err := parseAcmeCert(certPem, keyPem)
+		req.NoError(err)
+		mgr.cert.Store(cert)
+
+		req.InDelta(float64(4*24*time.Hour), float64(mgr.getWaitTime()), float64(time.Minute))
+	})
+
+	t.Run("past the renewal point requests immediately", func(t *testing.T) {
+		req := require.New(t)
+		mgr := newTestAcmeCertManager(t, "edge.example.com")
+
+		now := time.Now()
+		certPem, keyPem := newTestAcmeCertPem(t, now.Add(-80*24*time.Hour), now.Add(10*24*time.Hour), "edge.example.com")
+		cert, err := parseAcmeCert(certPem, keyPem)
+		req.NoError(err)
+		mgr.cert.Store(cert)
+
+		req.Zero(mgr.getWaitTime())
+	})
+}
+
+func TestAcmeCertManagerGetCertificate(t *testing.T) {
+	req := require.New(t)
+	mgr := newTestAcmeCertManager(t, "edge.example.com")
+
+	req.Nil(mgr.GetCertificate(&tls.ClientHelloInfo{ServerName: "edge.example.com"}), "no certificate obtained yet")
+
+	now := time.Now()
+	certPem, keyPem := newTestAcmeCertPem(t, now, now.Add(90*24*time.Hour), "edge.example.com")
+	cert, err := parseAcmeCert(certPem, keyPem)
+	req.NoError(err)
+	mgr.cert.Store(cert)
+
+	req.Same(cert, mgr.GetCertificate(&tls.ClientHelloInfo{ServerName: "EDGE.example.com"}))
+	req.Nil(mgr.GetCertificate(&tls.ClientHelloInfo{ServerName: "router.ziti.internal"}),
+		"clients using other names must get the router identity's certificate")
+	req.Nil(mgr.GetCertificate(&tls.ClientHelloInfo{}))
+}
+
+func TestAcmeCertManagerTlsAlpnChallenge(t *testing.T) {
+	req := require.New(t)
+	mgr := newTestAcmeCertManager(t, "edge.example.com")
+
+	challengeHello := &tls.ClientHelloInfo{
+		ServerName:      "edge.example.com",
+		SupportedProtos: []string{tlsalpn01.ACMETLS1Protocol},
+	}
+
+	req.Nil(mgr.GetConfigForClient(challengeHello), "no challenge in progress")
+
+	req.NoError(mgr.Present("edge.example.com", "token", "keyAuth"))
+
+	cfg := mgr.GetConfigForClient(challengeHello)
+	req.NotNil(cfg)
+	req.Equal([]string{tlsalpn01.ACMETLS1Protocol}, cfg.NextProtos)
+	req.Len(cfg.Certificates, 1)
+
+	req.Nil(mgr.GetConfigForClient(&tls.ClientHelloInfo{ServerName: "edge.example.com", SupportedProtos: []string{"h2"}}),
+		"ordinary clients must not be handed the challenge certificate")
+
+	req.NoError(mgr.CleanUp("edge.example.com", "token", "keyAuth"))
+	req.Nil(mgr.GetConfigForClient(challengeHello))
+}
+
+func TestAcmeCertManagerLoadStoredCert(t *testing.T) {
+	writeCert := func(t *testing.T, mgr *AcmeCertManager, notAfter time.Time, domains ...string) {
+		certPem, keyPem := newTestAcmeCertPem(t, time.Now().Add(-time.Hour), notAfter, domains...)
+		require.NoError(t, os.WriteFile(filepath.Join(mgr.config.StorageDir, acmeCertFile), certPem, 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(mgr.config.StorageDir, acmeKeyFile), keyPem, 0600))
+	}
+
+	t.Run("nothing stored", func(t *testing.T) {
+		mgr := newTestAcmeCertManager(t, "edge.example.com")
+		require.NoError(t, mgr.loadStoredCert())
+		require.Nil(t, mgr.cert.Load())
+	})
+
+	t.Run("loads a current certificate", func(t *testing.T) {
+		mgr := newTestAcmeCertManager(t, "edge.example.com", "edge2.example.com")
+		writeCert(t, mgr, time.Now().Add(24*time.Hour), "edge.example.com", "edge2.example.com")
+		require.NoError(t, mgr.loadStoredCert())
+		require.NotNil(t, mgr.cert.Load())
+	})
+
+	t.Run("ignores a certificate missing a domain", func(t *testing.T) {
+		mgr := newTestAcmeCertManager(t, "edge.example.com", "edge2.example.com")
+		writeCert(t, mgr, time.Now().Add(24*time.Hour), "edge.example.com")
+		require.ErrorContains(t, mgr.loadStoredCert(), "edge2.example.com")
+		require.Nil(t, mgr.cert.Load())
+	})
+
+	t.Run("ignores an expired certificate", func(t *testing.T) {
+		mgr := newTestAcmeCertManager(t, "edge.example.com")
+		writeCert(t, mgr, time.Now().Add(-time.Minute), "edge.example.com")
+		require.ErrorContains(t, mgr.loadStoredCert(), "expired")
+		require.Nil(t, mgr.cert.Load())
+	})
+}
+
+func TestAcmeCertManagerLoadAccount(t *testing.T) {
+	req := require.New(t)
+	mgr := newTestAcmeCertManager(t, "edge.example.com")
+	mgr.config.Directory = "https://localhost:14000/dir"
+	mgr.config.Email = "ops@example.com"
+
+	account, err := mgr.loadAccount()
+	req.NoError(err)
+	req.NotNil(account.GetPrivateKey())
+	req.Nil(account.GetRegistration())
+
+	account.Registration = &registration.Resource{URI: "https://localhost:14000/my-account/1"}
+	req.NoError(mgr.saveAccount(account))
+
+	reloaded, err := mgr.loadAccount()
+	req.NoError(err)
+	req.Equal(account.GetPrivateKey(), reloaded.GetPrivateKey(), "the account key must be reused")
+	req.NotNil(reloaded.GetRegistration())
+
+	mgr.config.Directory = "https://acme.example.com/directory"
+	moved, err := mgr.loadAccount()
+	req.NoError(err)
+	req.Nil(moved.GetRegistration(), "a registration with another directory must not be reused")
+}
+
+// startTestAcmeServer runs an in-process Pebble ACME server, which validates tls-alpn-01 challenges by connecting
+// to the given port. It returns the directory URL and the path of a PEM file holding the server's certificate.
+func startTestAcmeServer(t *testing.T, tlsPort int) (string, string) {
+	req := require.New(t)
+
+	// pebble otherwise sleeps for up to 15 seconds before validating each challenge
+	t.Setenv("PEBBLE_VA_NOSLEEP", "1")
+
+	logger := log.New(io.Discard, "", 0)
+	store := db.NewMemoryStore()
+	authority := ca.New(logger, store, "", "ecdsa", 0, 1, map[string]ca.Profile{
+		"default": {Description: "default"},
+	})
+	validator := va.New(logger, 0, tlsPort, false, "", store)
+	frontEnd := wfe.New(logger, store, validator, authority, []string{"pebble.letsencrypt.org"}, false, false, 1, 1)
+
+	server := httptest.NewTLSServer(frontEnd.Handler())
+	t.Cleanup(server.Close)
+
+	caCerts := filepath.Join(t.TempDir(), "acme-ca.pem")
+	req.NoError(os.WriteFile(caCerts, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	return server.URL + wfe.DirectoryPath, caCerts
+}
+
+// startTestEdgeListener stands in for an edge listener, answering tls-alpn-01 challenges the way the edge
+// listeners do, through GetConfigForClient
+func startTestEdgeListener(t *testing.T, mgr *AcmeCertManager) int {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			return mgr.GetConfigForClient(hello), nil
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = conn.(*tls.Conn).Handshake()
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestAcmeCertManagerObtain(t *testing.T) {
+	req := require.New(t)
+
+	mgr := newTestAcmeCertManager(t, "localhost")
+	mgr.config.Challenge = routerEnv.AcmeChallengeTlsAlpn01
+	mgr.config.KeyType = routerEnv.DefaultAcmeKeyType
+	mgr.config.Directory, mgr.config.CaCerts = startTestAcmeServer(t, startTestEdgeListener(t, mgr))
+
+	req.NoError(mgr.obtain())
+
+	cert := mgr.GetCertificate(&tls.ClientHelloInfo{ServerName: "localhost"})
+	req.NotNil(cert)
+	req.Equal([]string{"localhost"}, cert.Leaf.DNSNames)
+	req.True(cert.Leaf.NotAfter.After(time.Now()))
+	req.Nil(mgr.GetCertificate(&tls.ClientHelloInfo{ServerName: "router.example.com"}))
+	req.Empty(mgr.challengeCerts.Keys(), "challenge certificates must be cleaned up")
+
+	// the account and certificate are stored, so they're reused after a restart
+	account, err := mgr.loadAccount()
+	req.NoError(err)
+	req.NotNil(account.GetRegistration())
+
+	restarted := newTestAcmeCertManager(t, "localhost")
+	restarted.config.StorageDir = mgr.config.StorageDir
+	req.NoError(restarted.loadStoredCert())
+	req.NotNil(restarted.GetCertificate(&tls.ClientHelloInfo{ServerName: "localhost"}))
+
+	// renewals reuse the registered account
+	req.NoError(mgr.obtain())
+	renewedAccount, err := mgr.loadAccount()
+	req.NoError(err)
+	req.Equal(account.GetRegistration().URI, renewedAccount.GetRegistration().URI)
+}
@@ -26,26 +26,30 @@ import (
 	"github.com/michaelquigley/pfxlog"
 	"github.com/openziti/identity"
 	"github.com/openziti/ziti/v2/common/pb/edge_ctrl_pb"
+	routerEnv "github.com/openziti/ziti/v2/router/env"
 )
 
 // certValidatingIdentity wraps an identity.Identity to add client certificate chain verification
 // at the TLS level via a VerifyConnection callback. It dynamically builds a CA pool from the
 // router data model's PublicKeys with ClientX509CertValidation usage, ensuring the latest
 // trust anchors are always used. Verification is skipped for TLS layers that don't require a
-// client certificate.
+// client certificate. If a certSource is set, its certificates are preferred over the identity's
+// server certificates.
 type certValidatingIdentity struct {
 	identity.Identity
 	stateManager Manager
+	certSource   routerEnv.EdgeServerCertSource
 }
 
 // WrapIdentityWithCertValidation returns a new identity.TokenId with the same Token and Data
 // but an Identity that adds TLS-level client certificate verification using the router's
-// data model CA pool.
-func WrapIdentityWithCertValidation(id *identity.TokenId, stateManager Manager) *identity.TokenId {
+// data model CA pool. certSource may be nil.
+func WrapIdentityWithCertValidation(id *identity.TokenId, stateManager Manager, certSource routerEnv.EdgeServerCertSource) *identity.TokenId {
 	return &identity.TokenId{
 		Identity: &certValidatingIdentity{
 			Identity:     id.Identity,
 			stateManager: stateManager,
+			certSource:   certSource,
 		},
 		Token: id.Token,
 		Data:  id.Data,
@@ -69,9 +73,40 @@ func (self *certValidatingIdentity) ServerTLSConfig() *tls.Config {
 		}
 		return self.verifyConnection(state)
 	}
+
+	if self.certSource != nil {
+		self.addCertSource(cfg)
+	}
+
 	return cfg
 }
 
+// addCertSource has the config present the cert source's certificates where it has one for the client,
+// falling back to the identity's server certificates otherwise
+func (self *certValidatingIdentity) addCertSource(cfg *tls.Config) {
+	getCertificate := cfg.GetCertificate
+	cfg.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if cert := self.certSource.GetCertificate(hello); cert != nil {
+			return cert, nil
+		}
+		if getCertificate != nil {
+			return getCertificate(hello)
+		}
+		return nil, nil
+	}
+
+	getConfigForClient := cfg.GetConfigForClient
+	cfg.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		if clientCfg := self.certSource.GetConfigForClient(hello); clientCfg != nil {
+			return clientCfg, nil
+		}
+		if getConfigForClient != nil {
+			return getConfigForClient(hello)
+		}
+		return nil, nil
+	}
+}
+
 // requiresClientCert reports whether the given client auth type makes a handshake fail when the
 // client presents no certificate.
 func requiresClientCert(clientAuth tls.ClientAuthType) bool {
@@ -90,3 +90,61 @@ func TestCertValidatingIdentityNilServerConfig(t *testing.T) {
 	wrapped := &certValidatingIdentity{Identity: &stubServerIdentity{}}
 	require.Nil(t, wrapped.ServerTLSConfig())
 }
+
+// stubCertSource hands out its certificate and config only for the server name it was given
+type stubCertSource struct {
+	serverName string
+	cert       *tls.Certificate
+	cfg        *tls.Config
+}
+
+func (self *stubCertSource) GetCertificate(hello *tls.ClientHelloInfo) *tls.Certificate {
+	if hello.ServerName == self.serverName {
+		return self.cert
+	}
+	return nil
+}
+
+func (self *stubCertSource) GetConfigForClient(hello *tls.ClientHelloInfo) *tls.Config {
+	if hello.ServerName == self.serverName {
+		return self.cfg
+	}
+	return nil
+}
+
+func TestCertValidatingIdentityPrefersCertSource(t *testing.T) {
+	req := require.New(t)
+
+	identityCert := &tls.Certificate{}
+	sourceCert := &tls.Certificate{}
+	sourceCfg := &tls.Config{}
+
+	wrapped := &certValidatingIdentity{
+		Identity: &stubServerIdentity{
+			cfg: &tls.Config{
+				GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+					return identityCert, nil
+				},
+			},
+		},
+		certSource: &stubCertSource{serverName: "edge.example.com", cert: sourceCert, cfg: sourceCfg},
+	}
+
+	cfg := wrapped.ServerTLSConfig()
+
+	cert, err := cfg.GetCertificate(&tls.ClientHelloInfo{ServerName: "edge.example.com"})
+	req.NoError(err)
+	req.Same(sourceCert, cert)
+
+	cert, err = cfg.GetCertificate(&tls.ClientHelloInfo{ServerName: "router.ziti.internal"})
+	req.NoError(err)
+	req.Same(identityCert, cert, "names the source doesn't cover must fall back to the identity's certificate")
+
+	clientCfg, err := cfg.GetConfigForClient(&tls.ClientHelloInfo{ServerName: "edge.example.com"})
+	req.NoError(err)
+	req.Same(sourceCfg, clientCfg)
+
+	clientCfg, err = cfg.GetConfigForClient(&tls.ClientHelloInfo{ServerName: "router.ziti.internal"})
+	req.NoError(err)
+	req.Nil(clientCfg, "a nil config keeps the listener's own config")
+}
@@ -174,7 +174,7 @@ func (factory *Factory) CreateListener(optionsData xgress.OptionsData) (xgress_r
 		edge.RouterCapabilitiesHeader:    factory.env.GetRouterCapabilities().Bytes(),
 	}
 
-	wrappedId := state.WrapIdentityWithCertValidation(factory.env.GetRouterId(), factory.stateManager)
+	wrappedId := state.WrapIdentityWithCertValidation(factory.env.GetRouterId(), factory.stateManager, factory.env.GetEdgeServerCertSource())
 	return newListener(wrappedId, factory, options, headers), nil
 }
 
@@ -59,6 +59,12 @@ func (self *tunneler) Dial(params xgress_router.DialParams) (xt.PeerData, error)
 		return nil, err
 	}
 
+	// the dialer identity must come from the router provided circuit data, never from the client supplied app data
+	delete(options, tunnel.DialerIdentityKey)
+	if dialerIdentityName, found := circuitId.Data[ctrl_msg.DialerIdentityNameHeader]; found && len(dialerIdentityName) > 0 {
+		options[tunnel.DialerIdentityKey] = string(dialerIdentityName)
+	}
+
 	//TODO: Figure out timeout
 	conn, halfClose, err := terminator.context.Dial(options)
 	if err != nil {
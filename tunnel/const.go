@@ -9,4 +9,8 @@ const (
 
 	SourceIpKey   = "src_ip"
 	SourcePortKey = "src_port"
+
+	// DialerIdentityKey is set by the hosting side, from the circuit, to the name of the identity which dialed the
+	// service. It is not accepted from the intercepting side.
+	DialerIdentityKey = "dialer_identity"
 )
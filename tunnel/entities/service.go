@@ -127,6 +127,7 @@ type HostV1Config struct {
 
 	ListenOptions *HostV1ListenOptions
 	Proxy         *ProxyConfiguration
+	ProxyProtocol *ProxyProtocolConfiguration
 
 	allowedAddrs []allowedAddress
 }
@@ -219,6 +220,12 @@ type ProxyConfiguration struct {
 	Type    string
 }
 
+// ProxyProtocolConfiguration enables sending a PROXY protocol header to the hosted server, carrying the address of
+// the intercepted client and, for version 2, the name of the dialing identity.
+type ProxyProtocolConfiguration struct {
+	Version int
+}
+
 func (self *HostV1Config) GetDialTimeout(defaultTimeout time.Duration) time.Duration {
 	if self.ListenOptions != nil {
 		if self.ListenOptions.ConnectTimeout != nil {
@@ -33,6 +33,7 @@ import (
 	"github.com/openziti/ziti/v2/tunnel"
 	"github.com/openziti/ziti/v2/tunnel/entities"
 	"github.com/openziti/ziti/v2/tunnel/health"
+	"github.com/openziti/ziti/v2/tunnel/proxyproto"
 	"github.com/openziti/ziti/v2/tunnel/router"
 	"github.com/openziti/ziti/v2/tunnel/utils"
 	"github.com/pkg/errors"
@@ -128,6 +129,11 @@ func newDefaultHostingContext(identity *rest_model.IdentityDetail, service *enti
 		return nil
 	}
 
+	if config.ProxyProtocol != nil && config.ProxyProtocol.Version != proxyproto.Version1 && config.ProxyProtocol.Version != proxyproto.Version2 {
+		log.Errorf("unsupported PROXY protocol version %d, must be 1 or 2", config.ProxyProtocol.Version)
+		return nil
+	}
+
 	var proxyConf *transport.ProxyConfiguration
 	if config.Proxy != nil {
 		proxyConf = &transport.ProxyConfiguration{
@@ -260,9 +266,77 @@ func (self *hostingContext) dialAddress(options map[string]interface{}, protocol
 		conn, err = dialer.Dial(protocol, address)
 	}
 
+	if err == nil && isTcp && self.config.ProxyProtocol != nil {
+		if err = self.writeProxyProtocolHeader(conn, options, protocol); err != nil {
+			_ = conn.Close()
+			return nil, false, err
+		}
+	}
+
 	return conn, enableHalfClose, err
 }
 
+// writeProxyProtocolHeader sends a PROXY protocol header describing the intercepted client connection. Headers are
+// only sent on stream connections, since for datagrams each packet would need its own header.
+func (self *hostingContext) writeProxyProtocolHeader(conn net.Conn, options map[string]interface{}, protocol string) error {
+	header := &proxyproto.Header{
+		Version:  self.config.ProxyProtocol.Version,
+		Protocol: protocol,
+	}
+
+	if srcAddr, ok := addrPortFromOptions(options, tunnel.SourceIpKey, tunnel.SourcePortKey); ok {
+		header.Source = srcAddr
+	}
+
+	if dstAddr, ok := addrPortFromOptions(options, tunnel.DestinationIpKey, tunnel.DestinationPortKey); ok {
+		header.Destination = dstAddr
+	} else if dstAddr, err := netip.ParseAddrPort(conn.RemoteAddr().String()); err == nil {
+		header.Destination = dstAddr
+	}
+
+	if dialerIdentity, ok := options[tunnel.DialerIdentityKey].(string); ok && dialerIdentity != "" && header.Version == proxyproto.Version2 {
+		header.Tlvs = append(header.Tlvs, proxyproto.Tlv{
+			Type:  proxyproto.TlvTypeIdentity,
+			Value: []byte(dialerIdentity),
+		})
+	}
+
+	encoded, err := header.Encode()
+	if err != nil {
+		return errors.Wrap(err, "unable to encode PROXY protocol header")
+	}
+
+	if err = conn.SetWriteDeadline(time.Now().Add(self.dialTimeout)); err != nil {
+		return errors.Wrap(err, "unable to set write deadline for PROXY protocol header")
+	}
+
+	if _, err = conn.Write(encoded); err != nil {
+		return errors.Wrap(err, "unable to write PROXY protocol header")
+	}
+
+	return conn.SetWriteDeadline(time.Time{})
+}
+
+func addrPortFromOptions(options map[string]interface{}, ipKey, portKey string) (netip.AddrPort, bool) {
+	ipStr, ok := options[ipKey].(string)
+	if !ok {
+		return netip.AddrPort{}, false
+	}
+	portStr, ok := options[portKey].(string)
+	if !ok {
+		return netip.AddrPort{}, false
+	}
+	ip, err := netip.ParseAddr(ipStr)
+	if err != nil {
+		return netip.AddrPort{}, false
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return netip.AddrPort{}, false
+	}
+	return netip.AddrPortFrom(ip, uint16(port)), true
+}
+
 func (self *hostingContext) SetCloseCallback(f func()) {
 	self.onClose = f
 }
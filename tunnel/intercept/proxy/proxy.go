@@ -264,7 +264,7 @@ func (p *interceptor) handleTCP(service *Service) error {
 				return
 			}
 			sourceAddr := service.TunnelService.GetSourceAddr(conn.RemoteAddr(), conn.LocalAddr())
-			appInfo := tunnel.GetAppInfo("tcp", "", p.interceptIP.String(), strconv.Itoa(service.Port), sourceAddr)
+			appInfo := tunnel.GetAppInfo("tcp", "", p.interceptIP.String(), strconv.Itoa(service.Port), sourceAddr, conn.RemoteAddr())
 			identity := service.TunnelService.GetDialIdentity(conn.RemoteAddr(), conn.LocalAddr())
 			go tunnel.DialAndRun(service.TunnelService.FabricProvider, service.TunnelService, identity, conn, appInfo, true)
 		}
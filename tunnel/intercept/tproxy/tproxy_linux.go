@@ -327,7 +327,7 @@ func (self *tProxy) acceptTCP() {
 		dstIp, dstPort := tunnel.GetIpAndPort(client.LocalAddr())
 		dstHostname, _ := self.resolver.Lookup(client.LocalAddr().(*net.TCPAddr).IP)
 		sourceAddr := self.service.GetSourceAddr(client.RemoteAddr(), client.LocalAddr())
-		appInfo := tunnel.GetAppInfo("tcp", dstHostname, dstIp, dstPort, sourceAddr)
+		appInfo := tunnel.GetAppInfo("tcp", dstHostname, dstIp, dstPort, sourceAddr, client.RemoteAddr())
 		identity := self.service.GetDialIdentity(client.RemoteAddr(), client.LocalAddr())
 		go tunnel.DialAndRun(self.service.FabricProvider, self.service, identity, client, appInfo, true)
 	}
@@ -151,6 +151,12 @@ func (self *contextProvider) accept(listener edge.Listener, hostCtx HostingConte
 			continue
 		}
 
+		// the dialer identity is only trusted when provided by the router, never from client supplied app data
+		delete(options, DialerIdentityKey)
+		if sourceIdentity := conn.SourceIdentifier(); sourceIdentity != "" {
+			options[DialerIdentityKey] = sourceIdentity
+		}
+
 		externalConn, halfClose, err := hostCtx.Dial(options)
 		if err != nil {
 			logger.WithError(err).Error("dial failed")
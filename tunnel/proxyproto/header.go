@@ -0,0 +1,167 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+// Package proxyproto encodes HAProxy PROXY protocol headers, as described in
+// https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt. Only the sending side is implemented, as the hosting
+// tunnelers use it to pass the original client address to the servers they dial.
+package proxyproto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/netip"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	Version1 = 1
+	Version2 = 2
+
+	// TlvTypeIdentity carries the name of the identity which dialed the service. It's taken from the range
+	// reserved for custom application use (PP2_TYPE_MIN_CUSTOM - PP2_TYPE_MAX_CUSTOM).
+	TlvTypeIdentity byte = 0xE0
+)
+
+var v2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+const (
+	v2VersionCommandProxy = 0x21
+
+	v2FamilyUnspec = 0x00
+	v2FamilyInet   = 0x10
+	v2FamilyInet6  = 0x20
+
+	v2TransportStream = 0x01
+	v2TransportDgram  = 0x02
+
+	// v1 headers are limited to 107 bytes, including the CRLF
+	v1MaxLength = 107
+)
+
+// Tlv is a type-length-value extension, only supported by version 2 headers
+type Tlv struct {
+	Type  byte
+	Value []byte
+}
+
+// Header describes a PROXY protocol header. If either the source or destination is not valid, the connection is
+// described as unknown (v1) or unspecified (v2), which tells the receiver to use the real connection addresses.
+type Header struct {
+	Version     int
+	Protocol    string
+	Source      netip.AddrPort
+	Destination netip.AddrPort
+	Tlvs        []Tlv
+}
+
+func (self *Header) addressesKnown() bool {
+	if !self.Source.IsValid() || !self.Destination.IsValid() {
+		return false
+	}
+	return self.Source.Addr().Unmap().Is4() == self.Destination.Addr().Unmap().Is4()
+}
+
+// Encode returns the wire representation of the header
+func (self *Header) Encode() ([]byte, error) {
+	switch self.Version {
+	case Version1:
+		return self.encodeV1()
+	case Version2:
+		return self.encodeV2()
+	default:
+		return nil, errors.Errorf("unsupported PROXY protocol version %d", self.Version)
+	}
+}
+
+func (self *Header) encodeV1() ([]byte, error) {
+	if self.Protocol != "tcp" {
+		return nil, errors.Errorf("PROXY protocol v1 does not support protocol '%s'", self.Protocol)
+	}
+
+	if !self.addressesKnown() {
+		return []byte("PROXY UNKNOWN\r\n"), nil
+	}
+
+	family := "TCP6"
+	src := self.Source.Addr().Unmap()
+	dst := self.Destination.Addr().Unmap()
+	if src.Is4() {
+		family = "TCP4"
+	}
+
+	result := fmt.Sprintf("PROXY %s %s %s %d %d\r\n", family, src, dst, self.Source.Port(), self.Destination.Port())
+	if len(result) > v1MaxLength {
+		return nil, errors.Errorf("PROXY protocol v1 header exceeds maximum length of %d", v1MaxLength)
+	}
+	return []byte(result), nil
+}
+
+func (self *Header) encodeV2() ([]byte, error) {
+	var transport byte
+	switch self.Protocol {
+	case "tcp":
+		transport = v2TransportStream
+	case "udp":
+		transport = v2TransportDgram
+	default:
+		return nil, errors.Errorf("PROXY protocol v2 does not support protocol '%s'", self.Protocol)
+	}
+
+	payload := &bytes.Buffer{}
+	familyAndTransport := byte(v2FamilyUnspec)
+
+	if self.addressesKnown() {
+		src := self.Source.Addr().Unmap()
+		dst := self.Destination.Addr().Unmap()
+		if src.Is4() {
+			familyAndTransport = v2FamilyInet | transport
+			srcBytes, dstBytes := src.As4(), dst.As4()
+			payload.Write(srcBytes[:])
+			payload.Write(dstBytes[:])
+		} else {
+			familyAndTransport = v2FamilyInet6 | transport
+			srcBytes, dstBytes := src.As16(), dst.As16()
+			payload.Write(srcBytes[:])
+			payload.Write(dstBytes[:])
+		}
+		_ = binary.Write(payload, binary.BigEndian, self.Source.Port())
+		_ = binary.Write(payload, binary.BigEndian, self.Destination.Port())
+	}
+
+	for _, tlv := range self.Tlvs {
+		if len(tlv.Value) > math.MaxUint16 {
+			return nil, errors.Errorf("PROXY protocol v2 TLV of type 0x%x is too large", tlv.Type)
+		}
+		payload.WriteByte(tlv.Type)
+		_ = binary.Write(payload, binary.BigEndian, uint16(len(tlv.Value)))
+		payload.Write(tlv.Value)
+	}
+
+	if payload.Len() > math.MaxUint16 {
+		return nil, errors.New("PROXY protocol v2 header is too large")
+	}
+
+	result := &bytes.Buffer{}
+	result.Write(v2Signature)
+	result.WriteByte(v2VersionCommandProxy)
+	result.WriteByte(familyAndTransport)
+	_ = binary.Write(result, binary.BigEndian, uint16(payload.Len()))
+	result.Write(payload.Bytes())
+	return result.Bytes(), nil
+}
@@ -0,0 +1,125 @@
+package proxyproto
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderV1(t *testing.T) {
+	t.Run("ipv4", func(t *testing.T) {
+		req := require.New(t)
+		header := &Header{
+			Version:     Version1,
+			Protocol:    "tcp",
+			Source:      netip.MustParseAddrPort("192.168.1.10:53122"),
+			Destination: netip.MustParseAddrPort("10.0.0.5:443"),
+		}
+		encoded, err := header.Encode()
+		req.NoError(err)
+		req.Equal("PROXY TCP4 192.168.1.10 10.0.0.5 53122 443\r\n", string(encoded))
+	})
+
+	t.Run("ipv6", func(t *testing.T) {
+		req := require.New(t)
+		header := &Header{
+			Version:     Version1,
+			Protocol:    "tcp",
+			Source:      netip.MustParseAddrPort("[2001:db8::1]:1000"),
+			Destination: netip.MustParseAddrPort("[2001:db8::2]:80"),
+		}
+		encoded, err := header.Encode()
+		req.NoError(err)
+		req.Equal("PROXY TCP6 2001:db8::1 2001:db8::2 1000 80\r\n", string(encoded))
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		req := require.New(t)
+		header := &Header{
+			Version:     Version1,
+			Protocol:    "tcp",
+			Destination: netip.MustParseAddrPort("10.0.0.5:443"),
+		}
+		encoded, err := header.Encode()
+		req.NoError(err)
+		req.Equal("PROXY UNKNOWN\r\n", string(encoded))
+	})
+
+	t.Run("udp is rejected", func(t *testing.T) {
+		req := require.New(t)
+		header := &Header{Version: Version1, Protocol: "udp"}
+		_, err := header.Encode()
+		req.Error(err)
+	})
+}
+
+func TestHeaderV2(t *testing.T) {
+	t.Run("ipv4 with identity tlv", func(t *testing.T) {
+		req := require.New(t)
+		header := &Header{
+			Version:     Version2,
+			Protocol:    "tcp",
+			Source:      netip.MustParseAddrPort("192.168.1.10:53122"),
+			Destination: netip.MustParseAddrPort("10.0.0.5:443"),
+			Tlvs: []Tlv{
+				{Type: TlvTypeIdentity, Value: []byte("alice")},
+			},
+		}
+		encoded, err := header.Encode()
+		req.NoError(err)
+
+		expected := append([]byte{}, v2Signature...)
+		expected = append(expected, 0x21, 0x11, 0x00, 12+3+5)
+		expected = append(expected, 192, 168, 1, 10, 10, 0, 0, 5)
+		expected = append(expected, 0xCF, 0x82, 0x01, 0xBB)
+		expected = append(expected, TlvTypeIdentity, 0x00, 0x05)
+		expected = append(expected, []byte("alice")...)
+		req.Equal(expected, encoded)
+	})
+
+	t.Run("ipv6 udp", func(t *testing.T) {
+		req := require.New(t)
+		header := &Header{
+			Version:     Version2,
+			Protocol:    "udp",
+			Source:      netip.MustParseAddrPort("[2001:db8::1]:1000"),
+			Destination: netip.MustParseAddrPort("[2001:db8::2]:53"),
+		}
+		encoded, err := header.Encode()
+		req.NoError(err)
+		req.Equal(byte(0x22), encoded[13])
+		req.Equal([]byte{0x00, 36}, encoded[14:16])
+		req.Len(encoded, 16+36)
+	})
+
+	t.Run("unknown source is unspecified", func(t *testing.T) {
+		req := require.New(t)
+		header := &Header{
+			Version:  Version2,
+			Protocol: "tcp",
+			Tlvs: []Tlv{
+				{Type: TlvTypeIdentity, Value: []byte("bob")},
+			},
+		}
+		encoded, err := header.Encode()
+		req.NoError(err)
+		req.Equal(byte(0x00), encoded[13])
+		req.Equal([]byte{0x00, 6}, encoded[14:16])
+		req.Equal([]byte{TlvTypeIdentity, 0x00, 0x03, 'b', 'o', 'b'}, encoded[16:])
+	})
+
+	t.Run("mixed address families are unspecified", func(t *testing.T) {
+		req := require.New(t)
+		header := &Header{
+			Version:     Version2,
+			Protocol:    "tcp",
+			Source:      netip.MustParseAddrPort("192.168.1.10:53122"),
+			Destination: netip.MustParseAddrPort("[2001:db8::2]:80"),
+		}
+		encoded, err := header.Encode()
+		req.NoError(err)
+		req.Equal(byte(0x00), encoded[13])
+		req.Len(encoded, 16)
+	})
+}
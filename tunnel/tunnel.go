@@ -69,7 +69,7 @@ func GetIpAndPort(addr net.Addr) (string, string) {
 	return "", ""
 }
 
-func GetAppInfo(protocol, dstHostname, dstIp, dstPort, sourceAddr string) map[string]string {
+func GetAppInfo(protocol, dstHostname, dstIp, dstPort, sourceAddr string, clientAddr net.Addr) map[string]string {
 	result := map[string]string{}
 	result[DestinationProtocolKey] = protocol
 	if dstHostname != "" {
@@ -80,6 +80,12 @@ func GetAppInfo(protocol, dstHostname, dstIp, dstPort, sourceAddr string) map[st
 	if sourceAddr != "" {
 		result[SourceAddrKey] = sourceAddr
 	}
+	if clientAddr != nil {
+		if srcIp, srcPort := GetIpAndPort(clientAddr); srcIp != "" {
+			result[SourceIpKey] = srcIp
+			result[SourcePortKey] = srcPort
+		}
+	}
 	return result
 }
 
@@ -97,6 +97,7 @@ func NewAgentCmd(p common.OptionsProvider) *cobra.Command {
 	clusterCmd.AddCommand(NewAgentClusterInit(p))
 	//clusterCmd.AddCommand(NewAgentClusterInitFromDb(p))
 	clusterCmd.AddCommand(NewAgentClusterRestoreFromDb(p))
+	clusterCmd.AddCommand(NewAgentClusterRestore(p))
 
 	routerCmd := &cobra.Command{
 		Use:     "router",
@@ -28,7 +28,8 @@ import (
 
 type AgentClusterAddAction struct {
 	AgentOptions
-	Voter bool
+	Voter    bool
+	NonVoter bool
 }
 
 func NewAgentClusterAdd(p common.OptionsProvider) *cobra.Command {
@@ -51,6 +52,8 @@ func NewAgentClusterAdd(p common.OptionsProvider) *cobra.Command {
 
 	action.AddAgentOptions(cmd)
 	cmd.Flags().BoolVar(&action.Voter, "voter", true, "Is this member a voting member")
+	cmd.Flags().BoolVar(&action.NonVoter, "non-voter", false, "Adds the member as a non-voter, which replicates state and serves reads, but doesn't count towards quorum")
+	cmd.MarkFlagsMutuallyExclusive("voter", "non-voter")
 
 	return cmd
 }
@@ -58,7 +61,7 @@ func NewAgentClusterAdd(p common.OptionsProvider) *cobra.Command {
 func (self *AgentClusterAddAction) makeRequest(ch channel.Channel) error {
 	msg := channel.NewMessage(int32(mgmt_pb.ContentType_RaftAddPeerRequestType), nil)
 	msg.PutStringHeader(controller.AgentAddrHeader, self.Args[0])
-	msg.PutBoolHeader(controller.AgentIsVoterHeader, self.Voter)
+	msg.PutBoolHeader(controller.AgentIsVoterHeader, self.Voter && !self.NonVoter)
 
 	reply, err := msg.WithTimeout(self.timeout).SendForReply(ch)
 	if err != nil {
@@ -51,7 +51,8 @@ Common inspect keys for routers:
 
 Common inspect keys for controllers:
   stackdump, config, metrics, connected-routers, connected-peers,
-  cluster-config, router-messaging, terminator-costs, data-model-index
+  cluster-config, cluster-member-status, router-messaging, terminator-costs,
+  data-model-index
 
 Common inspect keys for tunnelers:
   stackdump, sdk`,
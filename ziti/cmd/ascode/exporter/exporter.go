@@ -28,7 +28,9 @@ import (
 	"github.com/judedaryl/go-arrayutils"
 	"github.com/michaelquigley/pfxlog"
 	"github.com/openziti/edge-api/rest_management_api_client"
+	"github.com/openziti/edge-api/rest_management_api_client/service"
 	"github.com/openziti/ziti/v2/internal"
+	"github.com/openziti/ziti/v2/internal/ascode"
 	ziticobra "github.com/openziti/ziti/v2/internal/cobra"
 	"github.com/openziti/ziti/v2/ziti/cmd/api"
 	"github.com/openziti/ziti/v2/ziti/cmd/common"
@@ -43,13 +45,17 @@ import (
 var log = pfxlog.Logger()
 
 type Exporter struct {
-	Out              bufio.Writer
-	Err              io.Writer
-	configCache      map[string]any
-	configTypeCache  map[string]any
-	authPolicyCache  map[string]any
-	externalJwtCache map[string]any
-	Client           *rest_management_api_client.ZitiEdgeManagement
+	Out                    bufio.Writer
+	Err                    io.Writer
+	configCache            map[string]any
+	configTypeCache        map[string]any
+	authPolicyCache        map[string]any
+	externalJwtCache       map[string]any
+	serviceCache           map[string]any
+	Client                 *rest_management_api_client.ZitiEdgeManagement
+	SecretsMode            string
+	SecretCipher           *ascode.SecretCipher
+	EnrollmentPlaceholders bool
 }
 
 func NewExportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
@@ -61,6 +67,7 @@ func NewExportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 
 	var outputFormat string
 	var outputFile string
+	var secretsPassphraseFile string
 	var loginOpts = edge.LoginOptions{
 		Options: api.Options{
 			CommonOptions: common.CommonOptions{
@@ -74,7 +81,9 @@ func NewExportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 		Use:   "export [entity]",
 		Short: "Export entities",
 		Long: "Export all or comma separated list of selected entities.\n" +
-			"Valid entities are: [all|ca/certificate-authority|identity|edge-router|service|config|config-type|service-policy|edge-router-policy|service-edge-router-policy|external-jwt-signer|auth-policy|posture-check] (default all)",
+			"Valid entities are: [all|ca/certificate-authority|identity|edge-router|router|service|config|config-type|service-policy|edge-router-policy|service-edge-router-policy|terminator|external-jwt-signer|auth-policy|posture-check] (default all)\n" +
+			"Config values stored under sensitive keys, such as passwords, secrets and tokens, can be redacted or encrypted with --secrets. " +
+			"Encrypted values are decrypted on import with the same passphrase.",
 		Args: cobra.MinimumNArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
 
@@ -98,6 +107,18 @@ func NewExportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 				log.Fatalf("Invalid output format: %s", outputFormat)
 			}
 
+			if exporter.SecretsMode == ascode.SecretsEncrypt {
+				passphrase, err := ascode.LoadSecretsPassphrase(secretsPassphraseFile)
+				if err != nil {
+					return err
+				}
+				if exporter.SecretCipher, err = ascode.NewSecretCipher(passphrase); err != nil {
+					return err
+				}
+			} else if exporter.SecretsMode != ascode.SecretsInclude && exporter.SecretsMode != ascode.SecretsRedact {
+				log.Fatalf("Invalid secrets mode: %s", exporter.SecretsMode)
+			}
+
 			mgmtClient, mgmtClientErr := loginOpts.NewManagementClient(true)
 			if mgmtClientErr != nil {
 				log.WithError(mgmtClientErr).Error("Error creating management client")
@@ -186,6 +207,9 @@ func NewExportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 	ziticobra.SetHelpTemplate(cmd)
 
 	cmd.Flags().StringVarP(&outputFile, "output-file", "o", "", "Write output to local file")
+	cmd.Flags().StringVar(&exporter.SecretsMode, "secrets", ascode.SecretsInclude, "How to export sensitive config values: include, redact or encrypt")
+	cmd.Flags().StringVar(&secretsPassphraseFile, "secrets-passphrase-file", "", "File containing the passphrase used with --secrets encrypt. Defaults to $"+ascode.SecretsPassphraseEnvVar)
+	cmd.Flags().BoolVar(&exporter.EnrollmentPlaceholders, "enrollment-placeholders", false, "Request a new enrollment for each identity on import, since enrollments and credentials are not exported")
 
 	return cmd
 }
@@ -198,6 +222,10 @@ func (exporter *Exporter) Execute(input []string) (map[string]interface{}, error
 	exporter.configCache = map[string]any{}
 	exporter.configTypeCache = map[string]any{}
 	exporter.externalJwtCache = map[string]any{}
+	exporter.serviceCache = map[string]any{}
+	if exporter.SecretsMode == "" {
+		exporter.SecretsMode = ascode.SecretsInclude
+	}
 
 	result := map[string]interface{}{}
 
@@ -229,6 +257,15 @@ func (exporter *Exporter) Execute(input []string) (map[string]interface{}, error
 		result["edgeRouters"] = routers
 		_, _ = internal.FPrintfReusingLine(exporter.Err, "Exported %d Edge Routers\r\n", len(routers))
 	}
+	if exporter.IsTransitRouterExportRequired(args) {
+		_, _ = internal.FPrintfReusingLine(exporter.Err, "Exporting Routers\r")
+		routers, err := exporter.GetTransitRouters()
+		if err != nil {
+			return nil, err
+		}
+		result["routers"] = routers
+		_, _ = internal.FPrintfReusingLine(exporter.Err, "Exported %d Routers\r\n", len(routers))
+	}
 	if exporter.IsServiceExportRequired(args) {
 		_, _ = internal.FPrintfReusingLine(exporter.Err, "Exporting Services\r")
 		services, err := exporter.GetServices()
@@ -283,6 +320,15 @@ func (exporter *Exporter) Execute(input []string) (map[string]interface{}, error
 		result["serviceEdgeRouterPolicies"] = serviceRouterPolicies
 		_, _ = internal.FPrintfReusingLine(exporter.Err, "Exported %d Service Edge Router Policies\r\n", len(serviceRouterPolicies))
 	}
+	if exporter.IsTerminatorExportRequired(args) {
+		_, _ = internal.FPrintfReusingLine(exporter.Err, "Exporting Terminators")
+		terminators, err := exporter.GetTerminators()
+		if err != nil {
+			return nil, err
+		}
+		result["terminators"] = terminators
+		_, _ = internal.FPrintfReusingLine(exporter.Err, "Exported %d Terminators\r\n", len(terminators))
+	}
 	if exporter.IsExtJwtSignerExportRequired(args) {
 		_, _ = internal.FPrintfReusingLine(exporter.Err, "Exporting External JWT Signers")
 		externalJwtSigners, err := exporter.GetExternalJwtSigners()
@@ -369,6 +415,16 @@ func (exporter *Exporter) ToMap(input interface{}) (map[string]interface{}, erro
 	return m, nil
 }
 
+func (exporter *Exporter) getServiceName(id string) (string, error) {
+	detail, err := ascode.GetItemFromCache(exporter.serviceCache, id, func(id string) (interface{}, error) {
+		return exporter.Client.Service.DetailService(&service.DetailServiceParams{ID: id}, nil)
+	})
+	if err != nil {
+		return "", errors.Join(errors.New("error reading Service: "+id), err)
+	}
+	return *detail.(*service.DetailServiceOK).Payload.Data.Name, nil
+}
+
 func (exporter *Exporter) defaultRoleAttributes(m map[string]interface{}) {
 	if m["roleAttributes"] == nil {
 		m["roleAttributes"] = []string{}
@@ -77,6 +77,14 @@ func (exporter Exporter) GetConfigs() ([]map[string]interface{}, error) {
 			}
 			m["configType"] = "@" + *configType.(*config.DetailConfigTypeOK).Payload.Data.Name
 
+			protected, err := ascode.ProtectSecrets(m["data"], exporter.SecretsMode, exporter.SecretCipher)
+			if err != nil {
+				return nil, errors.Join(errors.New("error protecting secrets in Config: "+*item.Name), err)
+			}
+			if protected > 0 {
+				log.WithField("name", *item.Name).WithField("count", protected).Debugf("protected sensitive values with mode %s", exporter.SecretsMode)
+			}
+
 			return m, nil
 		})
 }
@@ -19,6 +19,7 @@ package exporter
 import (
 	"errors"
 	"slices"
+	"sort"
 
 	"github.com/openziti/edge-api/rest_management_api_client/auth_policy"
 	"github.com/openziti/edge-api/rest_management_api_client/identity"
@@ -70,7 +71,7 @@ func (exporter Exporter) GetIdentities() ([]map[string]interface{}, error) {
 
 			// filter unwanted properties
 			exporter.Filter(m, []string{"id", "_links", "createdAt", "updatedAt",
-				"defaultHostingCost", "defaultHostingPrecedence", "hasApiSession", "serviceHostingPrecedences", "enrollment",
+				"hasApiSession", "serviceHostingPrecedences", "enrollment",
 				"appData", "sdkInfo", "disabledAt", "disabledUntil", "serviceHostingCosts", "envInfo", "authenticators", "type", "authPolicyId",
 				"hasRouterConnection", "hasEdgeRouterConnection"})
 
@@ -86,6 +87,63 @@ func (exporter Exporter) GetIdentities() ([]map[string]interface{}, error) {
 				return nil, errors.Join(errors.New("error reading Auth Policy: "+*item.AuthPolicyID), lookupErr)
 			}
 			m["authPolicy"] = "@" + *authPolicy.(*auth_policy.DetailAuthPolicyOK).GetPayload().Data.Name
+
+			// hosting costs and precedences are keyed by service id
+			if len(item.ServiceHostingCosts) > 0 {
+				costs := map[string]interface{}{}
+				for serviceId, cost := range item.ServiceHostingCosts {
+					serviceName, err := exporter.getServiceName(serviceId)
+					if err != nil {
+						return nil, err
+					}
+					costs["@"+serviceName] = cost
+				}
+				m["serviceHostingCosts"] = costs
+			}
+			if len(item.ServiceHostingPrecedences) > 0 {
+				precedences := map[string]interface{}{}
+				for serviceId, precedence := range item.ServiceHostingPrecedences {
+					serviceName, err := exporter.getServiceName(serviceId)
+					if err != nil {
+						return nil, err
+					}
+					precedences["@"+serviceName] = precedence
+				}
+				m["serviceHostingPrecedences"] = precedences
+			}
+
+			// service config overrides
+			serviceConfigs, err := exporter.Client.Identity.ListIdentitysServiceConfigs(&identity.ListIdentitysServiceConfigsParams{ID: *item.ID}, nil)
+			if err != nil {
+				return nil, errors.Join(errors.New("error reading service configs for Identity: "+*item.Name), err)
+			}
+			if len(serviceConfigs.GetPayload().Data) > 0 {
+				overrides := []map[string]interface{}{}
+				for _, serviceConfig := range serviceConfigs.GetPayload().Data {
+					overrides = append(overrides, map[string]interface{}{
+						"service": "@" + serviceConfig.Service.Name,
+						"config":  "@" + serviceConfig.Config.Name,
+					})
+				}
+				// sort so that reconcile doesn't see a change when the controller returns them in another order
+				sort.Slice(overrides, func(i, j int) bool {
+					if overrides[i]["service"] != overrides[j]["service"] {
+						return overrides[i]["service"].(string) < overrides[j]["service"].(string)
+					}
+					return overrides[i]["config"].(string) < overrides[j]["config"].(string)
+				})
+				m["serviceConfigs"] = overrides
+			}
+
+			if exporter.EnrollmentPlaceholders && !*item.IsDefaultAdmin {
+				// identities are recreated without credentials, so ask for a fresh enrollment on import
+				if item.Authenticators != nil && item.Authenticators.Updb != nil {
+					m["enrollment"] = map[string]interface{}{"updb": item.Authenticators.Updb.Username}
+				} else {
+					m["enrollment"] = map[string]interface{}{"ott": true}
+				}
+			}
+
 			return m, nil
 		})
 
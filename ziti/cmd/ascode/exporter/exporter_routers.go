@@ -0,0 +1,103 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package exporter
+
+import (
+	"slices"
+
+	"github.com/openziti/edge-api/rest_management_api_client/edge_router"
+	"github.com/openziti/edge-api/rest_management_api_client/router"
+	"github.com/openziti/edge-api/rest_model"
+)
+
+func (exporter Exporter) IsTransitRouterExportRequired(args []string) bool {
+	return slices.Contains(args, "all") || len(args) == 0 || // explicit all or nothing specified
+		slices.Contains(args, "router") ||
+		slices.Contains(args, "transit-router")
+}
+
+func (exporter Exporter) GetTransitRouters() ([]map[string]interface{}, error) {
+
+	// the transit router list includes edge routers, which are exported separately
+	edgeRouterIds, err := exporter.getEdgeRouterIds()
+	if err != nil {
+		return nil, err
+	}
+
+	return exporter.getEntities(
+		"Routers",
+		func() (int64, error) {
+			limit := int64(1)
+			resp, err := exporter.Client.Router.ListTransitRouters(&router.ListTransitRoutersParams{Limit: &limit}, nil)
+			if err != nil {
+				return -1, err
+			}
+			return *resp.GetPayload().Meta.Pagination.TotalCount, nil
+		},
+
+		func(offset *int64, limit *int64) ([]interface{}, error) {
+			resp, err := exporter.Client.Router.ListTransitRouters(&router.ListTransitRoutersParams{Limit: limit, Offset: offset}, nil)
+			if err != nil {
+				return nil, err
+			}
+			entities := make([]interface{}, len(resp.GetPayload().Data))
+			for i, c := range resp.GetPayload().Data {
+				entities[i] = interface{}(c)
+			}
+			return entities, nil
+		},
+
+		func(entity interface{}) (map[string]interface{}, error) {
+
+			item := entity.(*rest_model.RouterDetail)
+			if _, found := edgeRouterIds[*item.ID]; found {
+				return nil, nil
+			}
+
+			// convert to a map of values
+			m, err := exporter.ToMap(item)
+			if err != nil {
+				log.WithError(err).Error("error converting Router to map")
+			}
+
+			// filter unwanted properties
+			exporter.Filter(m, []string{"id", "_links", "createdAt", "updatedAt",
+				"fingerprint", "isVerified", "isOnline", "enrollmentJwt", "enrollmentToken", "enrollmentCreatedAt", "enrollmentExpiresAt",
+				"unverifiedCertPem", "unverifiedFingerprint", "interfaces"})
+
+			return m, nil
+		})
+}
+
+func (exporter Exporter) getEdgeRouterIds() (map[string]struct{}, error) {
+	result := map[string]struct{}{}
+	offset := int64(0)
+	limit := int64(500)
+	for {
+		resp, err := exporter.Client.EdgeRouter.ListEdgeRouters(&edge_router.ListEdgeRoutersParams{Limit: &limit, Offset: &offset}, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, edgeRouter := range resp.GetPayload().Data {
+			result[*edgeRouter.ID] = struct{}{}
+		}
+		offset += limit
+		if offset >= *resp.GetPayload().Meta.Pagination.TotalCount {
+			return result, nil
+		}
+	}
+}
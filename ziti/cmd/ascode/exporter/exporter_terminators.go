@@ -0,0 +1,82 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package exporter
+
+import (
+	"slices"
+
+	"github.com/openziti/edge-api/rest_management_api_client/terminator"
+	"github.com/openziti/edge-api/rest_model"
+	"github.com/openziti/ziti/v2/common"
+)
+
+func (exporter Exporter) IsTerminatorExportRequired(args []string) bool {
+	return slices.Contains(args, "all") || len(args) == 0 || // explicit all or nothing specified
+		slices.Contains(args, "terminator")
+}
+
+func (exporter Exporter) GetTerminators() ([]map[string]interface{}, error) {
+
+	return exporter.getEntities(
+		"Terminators",
+		func() (int64, error) {
+			limit := int64(1)
+			resp, err := exporter.Client.Terminator.ListTerminators(&terminator.ListTerminatorsParams{Limit: &limit}, nil)
+			if err != nil {
+				return -1, err
+			}
+			return *resp.GetPayload().Meta.Pagination.TotalCount, nil
+		},
+
+		func(offset *int64, limit *int64) ([]interface{}, error) {
+			resp, err := exporter.Client.Terminator.ListTerminators(&terminator.ListTerminatorsParams{Limit: limit, Offset: offset}, nil)
+			if err != nil {
+				return nil, err
+			}
+			entities := make([]interface{}, len(resp.GetPayload().Data))
+			for i, c := range resp.GetPayload().Data {
+				entities[i] = interface{}(c)
+			}
+			return entities, nil
+		},
+
+		func(entity interface{}) (map[string]interface{}, error) {
+
+			item := entity.(*rest_model.TerminatorDetail)
+
+			// terminators hosted by sdk applications and tunnelers are recreated when the host reconnects
+			if *item.Binding == common.EdgeBinding || *item.Binding == common.TunnelBinding {
+				return nil, nil
+			}
+
+			// convert to a map of values
+			m, err := exporter.ToMap(item)
+			if err != nil {
+				log.WithError(err).Error("error converting Terminator to map")
+			}
+
+			// filter unwanted properties
+			exporter.Filter(m, []string{"id", "_links", "createdAt", "updatedAt",
+				"dynamicCost", "routerId", "serviceId", "router", "service"})
+
+			// translate ids to names
+			m["service"] = "@" + item.Service.Name
+			m["router"] = "@" + item.Router.Name
+
+			return m, nil
+		})
+}
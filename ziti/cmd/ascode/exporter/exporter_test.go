@@ -221,4 +221,27 @@ func Test_InputArgs(t *testing.T) {
 
 	})
 
+	t.Run("router export", func(t *testing.T) {
+
+		assert.True(t, exporter.IsTransitRouterExportRequired([]string{"router"}), "should be exported")
+		assert.True(t, exporter.IsTransitRouterExportRequired([]string{"transit-router"}), "should be exported")
+		assert.True(t, exporter.IsTransitRouterExportRequired([]string{"all"}), "should be exported")
+		assert.True(t, exporter.IsTransitRouterExportRequired([]string{}), "should be exported")
+
+		assert.False(t, exporter.IsTransitRouterExportRequired([]string{"edge-router"}), "should not be exported")
+		assert.False(t, exporter.IsTransitRouterExportRequired([]string{"terminator"}), "should not be exported")
+
+	})
+
+	t.Run("terminator export", func(t *testing.T) {
+
+		assert.True(t, exporter.IsTerminatorExportRequired([]string{"terminator"}), "should be exported")
+		assert.True(t, exporter.IsTerminatorExportRequired([]string{"all"}), "should be exported")
+		assert.True(t, exporter.IsTerminatorExportRequired([]string{}), "should be exported")
+
+		assert.False(t, exporter.IsTerminatorExportRequired([]string{"service"}), "should not be exported")
+		assert.False(t, exporter.IsTerminatorExportRequired([]string{"router"}), "should not be exported")
+
+	})
+
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/michaelquigley/pfxlog"
 	"github.com/openziti/edge-api/rest_management_api_client"
 	"github.com/openziti/ziti/v2/internal"
+	"github.com/openziti/ziti/v2/internal/ascode"
 	ziticobra "github.com/openziti/ziti/v2/internal/cobra"
 	"github.com/openziti/ziti/v2/ziti/cmd/api"
 	"github.com/openziti/ziti/v2/ziti/cmd/common"
@@ -53,6 +54,8 @@ type Importer struct {
 	authPolicyCache    map[string]any
 	extJwtSignersCache map[string]any
 	identityCache      map[string]any
+	postureCheckCache  map[string]any
+	routerCache        map[string]any
 }
 
 func NewImportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
@@ -63,6 +66,8 @@ func NewImportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 	}
 
 	var inputFormat string
+	var plan, reconcile, prune bool
+	var secretsPassphraseFile string
 	var loginOpts = edge.LoginOptions{
 		Options: api.Options{
 			CommonOptions: common.CommonOptions{
@@ -76,7 +81,10 @@ func NewImportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 		Use:   "import filename [entity]",
 		Short: "Import entities",
 		Long: "Import all or comma separated list of selected entities from the specified file.\n" +
-			"Valid entities are: [all|ca/certificate-authority|identity|edge-router|service|config|config-type|service-policy|edge-router-policy|service-edge-router-policy|external-jwt-signer|auth-policy|posture-check] (default all)",
+			"Valid entities are: [all|ca/certificate-authority|identity|edge-router|router|service|config|config-type|service-policy|edge-router-policy|service-edge-router-policy|terminator|external-jwt-signer|auth-policy|posture-check] (default all)\n" +
+			"Values encrypted on export are decrypted with the secrets passphrase. Files with redacted values are rejected.\n" +
+			"With --plan or --reconcile the file is compared with the controller and the entities to create, update and delete are listed. " +
+			"--reconcile then applies the changes, and --prune deletes entities previously imported this way which are no longer in the file.",
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 
@@ -113,6 +121,20 @@ func NewImportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 			}
 			importer.Data = data
 
+			var secretCipher *ascode.SecretCipher
+			if ascode.HasEncryptedValues(data) {
+				passphrase, err := ascode.LoadSecretsPassphrase(secretsPassphraseFile)
+				if err != nil {
+					return err
+				}
+				if secretCipher, err = ascode.NewSecretCipher(passphrase); err != nil {
+					return err
+				}
+			}
+			if err = ascode.RevealSecrets(data, secretCipher); err != nil {
+				return errors.Join(errors.New("unable to read secrets from input"), err)
+			}
+
 			mgmtClient, mgmtClientErr := loginOpts.NewManagementClient(true)
 			if mgmtClientErr != nil {
 				return mgmtClientErr
@@ -126,6 +148,14 @@ func NewImportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 				entities = []string{}
 			}
 
+			if prune && !plan && !reconcile {
+				return errors.New("--prune requires --plan or --reconcile")
+			}
+			if plan || reconcile {
+				_, reconcileErr := importer.Reconcile(entities, reconcile && !plan, prune)
+				return reconcileErr
+			}
+
 			executeErr := importer.Execute(entities)
 			if executeErr != nil {
 				return executeErr
@@ -145,6 +175,10 @@ func NewImportCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd.Flags().SetInterspersed(true)
 	cmd.Flags().StringVar(&inputFormat, "input-format", "JSON", "Parse input as either JSON or YAML (default JSON)")
 	cmd.Flags().StringVar(&loginOpts.ControllerUrl, "controller-url", "", "The url of the controller")
+	cmd.Flags().BoolVar(&plan, "plan", false, "Show the changes needed to make the controller match the file, without applying them")
+	cmd.Flags().BoolVar(&reconcile, "reconcile", false, "Create, update and delete entities so the controller matches the file")
+	cmd.Flags().StringVar(&secretsPassphraseFile, "secrets-passphrase-file", "", "File containing the passphrase for secrets encrypted on export. Defaults to $"+ascode.SecretsPassphraseEnvVar)
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete managed entities which are not in the file. Requires --plan or --reconcile")
 	ziticobra.SetHelpTemplate(cmd)
 
 	return cmd
@@ -160,6 +194,8 @@ func (importer *Importer) Execute(entities []string) error {
 	importer.authPolicyCache = map[string]any{}
 	importer.extJwtSignersCache = map[string]any{}
 	importer.identityCache = map[string]any{}
+	importer.postureCheckCache = map[string]any{}
+	importer.routerCache = map[string]any{}
 
 	result := map[string]any{}
 
@@ -243,6 +279,32 @@ func (importer *Importer) Execute(entities []string) error {
 	}
 	result["edgeRouters"] = routers
 
+	transitRouters := map[string]string{}
+	if importer.IsTransitRouterImportRequired(args) {
+		_, _ = internal.FPrintfReusingLine(importer.Err, "Creating Routers")
+		var err error
+		transitRouters, err = importer.ProcessTransitRouters(importer.Data)
+		if err != nil {
+			return err
+		}
+		log.WithField("routers", transitRouters).Debug("Routers created")
+		_, _ = internal.FPrintfReusingLine(importer.Err, "Created %d Routers\r\n", len(transitRouters))
+	}
+	result["routers"] = transitRouters
+
+	terminators := map[string]string{}
+	if importer.IsTerminatorImportRequired(args) {
+		_, _ = internal.FPrintfReusingLine(importer.Err, "Creating Terminators")
+		var err error
+		terminators, err = importer.ProcessTerminators(importer.Data)
+		if err != nil {
+			return err
+		}
+		log.WithField("terminators", terminators).Debug("Terminators created")
+		_, _ = internal.FPrintfReusingLine(importer.Err, "Created %d Terminators\r\n", len(terminators))
+	}
+	result["terminators"] = terminators
+
 	externalJwtSigners := map[string]string{}
 	if importer.IsExtJwtSignerImportRequired(args) {
 		_, _ = internal.FPrintfReusingLine(importer.Err, "Creating ExtJWTSigners")
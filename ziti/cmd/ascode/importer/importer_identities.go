@@ -124,6 +124,30 @@ func (importer *Importer) ProcessIdentities(input map[string][]interface{}) (map
 			create.AuthPolicyID = policy.(*rest_model.AuthPolicyDetail).ID
 		}
 
+		// hosting costs and precedences are keyed by service name in the input
+		if len(create.ServiceHostingCosts) > 0 {
+			costs := rest_model.TerminatorCostMap{}
+			for serviceRef, cost := range create.ServiceHostingCosts {
+				serviceId, err := importer.lookupServiceId(serviceRef)
+				if err != nil {
+					return nil, nil, err
+				}
+				costs[serviceId] = cost
+			}
+			create.ServiceHostingCosts = costs
+		}
+		if len(create.ServiceHostingPrecedences) > 0 {
+			precedences := rest_model.TerminatorPrecedenceMap{}
+			for serviceRef, precedence := range create.ServiceHostingPrecedences {
+				serviceId, err := importer.lookupServiceId(serviceRef)
+				if err != nil {
+					return nil, nil, err
+				}
+				precedences[serviceId] = precedence
+			}
+			create.ServiceHostingPrecedences = precedences
+		}
+
 		// do the actual creation since it doesn't exist, but only if it's a "default" identity
 		if *create.Type == rest_model.IdentityTypeDefault {
 			_, _ = internal.FPrintfReusingLine(importer.Err, "Creating Identity %s\r", *create.Name)
@@ -148,6 +172,10 @@ func (importer *Importer) ProcessIdentities(input map[string][]interface{}) (map
 				Info("Created identity")
 
 			createdResult[*create.Name] = created.Payload.Data.ID
+
+			if err := importer.associateServiceConfigs(*create.Name, created.Payload.Data.ID, doc); err != nil {
+				return nil, nil, err
+			}
 		}
 
 	}
@@ -177,3 +205,114 @@ func (importer *Importer) lookupIdentities(roles []string) ([]string, error) {
 	}
 	return identityRoles, nil
 }
+
+// associateServiceConfigs applies the identity's service config overrides, given as service and config names
+func (importer *Importer) associateServiceConfigs(name string, identityId string, doc *gabs.Container) error {
+	assignments, err := importer.resolveServiceConfigs(name, doc)
+	if err != nil {
+		return err
+	}
+	return importer.assignServiceConfigs(name, identityId, assignments)
+}
+
+// reconcileServiceConfigs makes the identity's service config overrides match the given list, removing overrides
+// which aren't in it and adding those which are missing
+func (importer *Importer) reconcileServiceConfigs(name string, identityId string, overrides interface{}) error {
+	desired, err := importer.resolveServiceConfigs(name, gabs.Wrap(map[string]interface{}{"serviceConfigs": overrides}))
+	if err != nil {
+		return err
+	}
+
+	resp, err := importer.Client.Identity.ListIdentitysServiceConfigs(&identity.ListIdentitysServiceConfigsParams{ID: identityId}, nil)
+	if err != nil {
+		return errors.Join(errors.New("error reading service configs for Identity: "+name), err)
+	}
+
+	isDesired := map[string]struct{}{}
+	for _, assignment := range desired {
+		isDesired[*assignment.ServiceID+"/"+*assignment.ConfigID] = struct{}{}
+	}
+
+	current := map[string]struct{}{}
+	removals := rest_model.ServiceConfigsAssignList{}
+	for _, serviceConfig := range resp.GetPayload().Data {
+		key := *serviceConfig.ServiceID + "/" + *serviceConfig.ConfigID
+		current[key] = struct{}{}
+		if _, found := isDesired[key]; !found {
+			removals = append(removals, &rest_model.ServiceConfigAssign{
+				ServiceID: serviceConfig.ServiceID,
+				ConfigID:  serviceConfig.ConfigID,
+			})
+		}
+	}
+
+	additions := rest_model.ServiceConfigsAssignList{}
+	for _, assignment := range desired {
+		if _, found := current[*assignment.ServiceID+"/"+*assignment.ConfigID]; !found {
+			additions = append(additions, assignment)
+		}
+	}
+
+	if len(removals) > 0 {
+		_, err = importer.Client.Identity.DisassociateIdentitysServiceConfigs(&identity.DisassociateIdentitysServiceConfigsParams{ID: identityId, ServiceConfigIDPairs: removals}, nil)
+		if err != nil {
+			log.WithError(err).WithField("name", name).Error("Unable to remove service configs from Identity")
+			return errors.Join(errors.New("unable to remove service configs from Identity: "+name), err)
+		}
+		log.WithFields(map[string]interface{}{
+			"name":       name,
+			"identityId": identityId,
+			"count":      len(removals),
+		}).Info("Removed service configs from Identity")
+	}
+
+	return importer.assignServiceConfigs(name, identityId, additions)
+}
+
+// resolveServiceConfigs converts the service config overrides in doc from names to ids
+func (importer *Importer) resolveServiceConfigs(name string, doc *gabs.Container) (rest_model.ServiceConfigsAssignList, error) {
+	assignments := rest_model.ServiceConfigsAssignList{}
+	for _, override := range doc.Path("serviceConfigs").Children() {
+		serviceRef, _ := override.Path("service").Data().(string)
+		configRef, _ := override.Path("config").Data().(string)
+		if serviceRef == "" || configRef == "" {
+			return nil, errors.New("service config override for Identity " + name + " requires a service and a config")
+		}
+
+		serviceId, err := importer.lookupServiceId(serviceRef)
+		if err != nil {
+			return nil, err
+		}
+		configName := strings.TrimPrefix(configRef, "@")
+		config, _ := ascode.GetItemFromCache(importer.configCache, configName, func(name string) (interface{}, error) {
+			return mgmt.ConfigFromFilter(importer.Client, mgmt.NameFilter(name)), nil
+		})
+		if isNilItem(config) {
+			return nil, errors.New("error reading Config: " + configName)
+		}
+
+		assignments = append(assignments, &rest_model.ServiceConfigAssign{
+			ServiceID: &serviceId,
+			ConfigID:  config.(*rest_model.ConfigDetail).ID,
+		})
+	}
+	return assignments, nil
+}
+
+func (importer *Importer) assignServiceConfigs(name string, identityId string, assignments rest_model.ServiceConfigsAssignList) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	_, err := importer.Client.Identity.AssociateIdentitysServiceConfigs(&identity.AssociateIdentitysServiceConfigsParams{ID: identityId, ServiceConfigs: assignments}, nil)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Error("Unable to assign service configs to Identity")
+		return errors.Join(errors.New("unable to assign service configs to Identity: "+name), err)
+	}
+	log.WithFields(map[string]interface{}{
+		"name":       name,
+		"identityId": identityId,
+		"count":      len(assignments),
+	}).Info("Assigned service configs to Identity")
+	return nil
+}
@@ -18,6 +18,7 @@ package importer
 
 import (
 	"encoding/json"
+	"errors"
 	"slices"
 	"strings"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/openziti/edge-api/rest_model"
 	"github.com/openziti/edge-api/rest_util"
 	"github.com/openziti/ziti/v2/internal"
+	"github.com/openziti/ziti/v2/internal/ascode"
 	"github.com/openziti/ziti/v2/internal/rest/mgmt"
 )
 
@@ -115,3 +117,23 @@ func (importer *Importer) ProcessPostureChecks(input map[string][]interface{}) (
 
 	return result, nil
 }
+
+func (importer *Importer) lookupPostureChecks(roles []string) ([]string, error) {
+	postureCheckRoles := []string{}
+	for _, role := range roles {
+		if role[0:1] == "@" {
+			value := role[1:]
+			postureCheck, _ := ascode.GetItemFromCache(importer.postureCheckCache, value, func(name string) (interface{}, error) {
+				return mgmt.PostureCheckFromFilter(importer.Client, mgmt.NameFilter(name)), nil
+			})
+			if isNilItem(postureCheck) {
+				return nil, errors.New("error reading PostureCheck: " + value)
+			}
+			postureCheckId := (*postureCheck.(*rest_model.PostureCheckDetail)).ID()
+			postureCheckRoles = append(postureCheckRoles, "@"+*postureCheckId)
+		} else {
+			postureCheckRoles = append(postureCheckRoles, role)
+		}
+	}
+	return postureCheckRoles, nil
+}
@@ -0,0 +1,917 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/openziti/edge-api/rest_management_api_client/auth_policy"
+	"github.com/openziti/edge-api/rest_management_api_client/certificate_authority"
+	"github.com/openziti/edge-api/rest_management_api_client/config"
+	"github.com/openziti/edge-api/rest_management_api_client/edge_router"
+	"github.com/openziti/edge-api/rest_management_api_client/edge_router_policy"
+	"github.com/openziti/edge-api/rest_management_api_client/external_jwt_signer"
+	"github.com/openziti/edge-api/rest_management_api_client/identity"
+	"github.com/openziti/edge-api/rest_management_api_client/posture_checks"
+	"github.com/openziti/edge-api/rest_management_api_client/router"
+	"github.com/openziti/edge-api/rest_management_api_client/service"
+	"github.com/openziti/edge-api/rest_management_api_client/service_edge_router_policy"
+	"github.com/openziti/edge-api/rest_management_api_client/service_policy"
+	"github.com/openziti/edge-api/rest_management_api_client/terminator"
+	"github.com/openziti/edge-api/rest_model"
+	"github.com/openziti/ziti/v2/internal"
+	"github.com/openziti/ziti/v2/internal/ascode"
+	"github.com/openziti/ziti/v2/internal/rest/mgmt"
+	"github.com/openziti/ziti/v2/ziti/cmd/ascode/exporter"
+)
+
+// ManagedTag is set on every entity created or updated by a reconcile. Only entities carrying it are candidates
+// for pruning, so entities managed by other means are never deleted.
+const ManagedTag = "ziti-ascode-managed"
+
+type PlanAction string
+
+const (
+	PlanCreate PlanAction = "create"
+	PlanUpdate PlanAction = "update"
+	PlanDelete PlanAction = "delete"
+)
+
+type PlanChange struct {
+	Action     PlanAction
+	EntityType string
+	Name       string
+	Fields     []string
+	Data       map[string]interface{}
+}
+
+type Plan struct {
+	Changes []*PlanChange
+}
+
+func (self *Plan) Count(action PlanAction) int {
+	count := 0
+	for _, change := range self.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+func (self *Plan) Print(out io.Writer) {
+	for _, change := range self.Changes {
+		label := reconcileLabel(change.EntityType)
+		switch change.Action {
+		case PlanCreate:
+			_, _ = fmt.Fprintf(out, "  + create %s %s\n", label, change.Name)
+		case PlanUpdate:
+			_, _ = fmt.Fprintf(out, "  ~ update %s %s (%s)\n", label, change.Name, strings.Join(change.Fields, ", "))
+		case PlanDelete:
+			_, _ = fmt.Fprintf(out, "  - delete %s %s\n", label, change.Name)
+		}
+	}
+	_, _ = fmt.Fprintf(out, "Plan: %d to create, %d to update, %d to delete\n",
+		self.Count(PlanCreate), self.Count(PlanUpdate), self.Count(PlanDelete))
+}
+
+type reconcileEntityType struct {
+	key        string
+	label      string
+	immutable  []string
+	createOnly []string
+	nameOf     func(m map[string]interface{}) string
+	isRequired func(importer *Importer, args []string) bool
+	lookupId   func(importer *Importer, name string) string
+	// lookupIdOf is used instead of lookupId for entities which are identified by several fields rather than a name
+	lookupIdOf func(importer *Importer, m map[string]interface{}) string
+	resolve    func(importer *Importer, body map[string]interface{}) error
+	patch      func(importer *Importer, id string, body map[string]interface{}) error
+	delete     func(importer *Importer, id string) error
+	// updaters apply fields which are managed through their own endpoints rather than the entity's patch
+	updaters map[string]func(importer *Importer, id string, name string, value interface{}) error
+}
+
+// reconcileEntityTypes lists the entity types in dependency order. Creates and updates are applied in this order,
+// deletes in reverse.
+var reconcileEntityTypes = []*reconcileEntityType{
+	{
+		key:        "certificateAuthorities",
+		label:      "CertificateAuthority",
+		immutable:  []string{"certPem"},
+		isRequired: (*Importer).IsCertificateAuthorityImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.CertificateAuthorityFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.CertificateAuthority.PatchCa(certificate_authority.NewPatchCaParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.CertificateAuthority.DeleteCa(&certificate_authority.DeleteCaParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "configTypes",
+		label:      "ConfigType",
+		isRequired: (*Importer).IsConfigTypeImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.ConfigTypeFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.Config.PatchConfigType(config.NewPatchConfigTypeParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.Config.DeleteConfigType(&config.DeleteConfigTypeParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "configs",
+		label:      "Config",
+		immutable:  []string{"configType"},
+		isRequired: (*Importer).IsConfigImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.ConfigFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.Config.PatchConfig(config.NewPatchConfigParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.Config.DeleteConfig(&config.DeleteConfigParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "services",
+		label:      "Service",
+		isRequired: (*Importer).IsServiceImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.ServiceFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		resolve: func(importer *Importer, body map[string]interface{}) error {
+			if configs, found := body["configs"]; found {
+				configIds := []string{}
+				for _, configRef := range toStrings(configs) {
+					value := strings.TrimPrefix(configRef, "@")
+					detail, _ := ascode.GetItemFromCache(importer.configCache, value, func(name string) (interface{}, error) {
+						return mgmt.ConfigFromFilter(importer.Client, mgmt.NameFilter(name)), nil
+					})
+					if isNilItem(detail) {
+						return errors.New("error reading Config: " + value)
+					}
+					configIds = append(configIds, *detail.(*rest_model.ConfigDetail).ID)
+				}
+				body["configs"] = configIds
+			}
+			return nil
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.Service.PatchService(service.NewPatchServiceParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.Service.DeleteService(&service.DeleteServiceParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "postureChecks",
+		label:      "PostureCheck",
+		immutable:  []string{"typeId"},
+		isRequired: (*Importer).IsPostureCheckImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.PostureCheckFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *(*detail).ID()
+			}
+			return ""
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.PostureChecks.PatchPostureCheck(posture_checks.NewPatchPostureCheckParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.PostureChecks.DeletePostureCheck(&posture_checks.DeletePostureCheckParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "edgeRouters",
+		label:      "EdgeRouter",
+		isRequired: (*Importer).IsEdgeRouterImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.EdgeRouterFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.EdgeRouter.PatchEdgeRouter(edge_router.NewPatchEdgeRouterParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.EdgeRouter.DeleteEdgeRouter(&edge_router.DeleteEdgeRouterParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "routers",
+		label:      "Router",
+		isRequired: (*Importer).IsTransitRouterImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.TransitRouterFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.Router.PatchTransitRouter(router.NewPatchTransitRouterParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.Router.DeleteTransitRouter(&router.DeleteTransitRouterParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "terminators",
+		label:      "Terminator",
+		immutable:  []string{"service", "router", "address"},
+		nameOf:     terminatorName,
+		isRequired: (*Importer).IsTerminatorImportRequired,
+		lookupIdOf: func(importer *Importer, m map[string]interface{}) string {
+			id, _ := importer.lookupTerminatorId(m)
+			return id
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.Terminator.PatchTerminator(terminator.NewPatchTerminatorParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.Terminator.DeleteTerminator(&terminator.DeleteTerminatorParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "externalJwtSigners",
+		label:      "ExtJWTSigner",
+		isRequired: (*Importer).IsExtJwtSignerImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.ExternalJWTSignerFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.ExternalJWTSigner.PatchExternalJWTSigner(external_jwt_signer.NewPatchExternalJWTSignerParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.ExternalJWTSigner.DeleteExternalJWTSigner(&external_jwt_signer.DeleteExternalJWTSignerParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "authPolicies",
+		label:      "AuthPolicy",
+		isRequired: (*Importer).IsAuthPolicyImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.AuthPolicyFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		resolve: func(importer *Importer, body map[string]interface{}) error {
+			if primary, ok := body["primary"].(map[string]interface{}); ok {
+				if extJwt, ok := primary["extJwt"].(map[string]interface{}); ok {
+					signerIds := []string{}
+					for _, signer := range toStrings(extJwt["allowedSigners"]) {
+						signerId, err := importer.lookupExtJwtSignerId(signer)
+						if err != nil {
+							return err
+						}
+						signerIds = append(signerIds, signerId)
+					}
+					extJwt["allowedSigners"] = signerIds
+				}
+			}
+			if secondary, ok := body["secondary"].(map[string]interface{}); ok {
+				if signer, ok := secondary["requireExtJwtSigner"].(string); ok {
+					signerId, err := importer.lookupExtJwtSignerId(signer)
+					if err != nil {
+						return err
+					}
+					secondary["requireExtJwtSigner"] = signerId
+				}
+			}
+			return nil
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.AuthPolicy.PatchAuthPolicy(auth_policy.NewPatchAuthPolicyParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.AuthPolicy.DeleteAuthPolicy(&auth_policy.DeleteAuthPolicyParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "identities",
+		label:      "Identity",
+		immutable:  []string{"typeId", "isDefaultAdmin"},
+		createOnly: []string{"enrollment"},
+		isRequired: (*Importer).IsIdentityImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.IdentityFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		resolve: func(importer *Importer, body map[string]interface{}) error {
+			if policyRef, ok := body["authPolicy"].(string); ok {
+				value := strings.TrimPrefix(policyRef, "@")
+				policy, _ := ascode.GetItemFromCache(importer.authPolicyCache, value, func(name string) (interface{}, error) {
+					return mgmt.AuthPolicyFromFilter(importer.Client, mgmt.NameFilter(name)), nil
+				})
+				if isNilItem(policy) {
+					return errors.New("error reading Auth Policy: " + value)
+				}
+				delete(body, "authPolicy")
+				body["authPolicyId"] = *policy.(*rest_model.AuthPolicyDetail).ID
+			}
+			for _, field := range []string{"serviceHostingCosts", "serviceHostingPrecedences"} {
+				if byName, ok := body[field].(map[string]interface{}); ok {
+					byId := map[string]interface{}{}
+					for serviceRef, value := range byName {
+						serviceId, err := importer.lookupServiceId(serviceRef)
+						if err != nil {
+							return err
+						}
+						byId[serviceId] = value
+					}
+					body[field] = byId
+				}
+			}
+			return nil
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.Identity.PatchIdentity(identity.NewPatchIdentityParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.Identity.DeleteIdentity(&identity.DeleteIdentityParams{ID: id}, nil)
+			return err
+		},
+		updaters: map[string]func(importer *Importer, id string, name string, value interface{}) error{
+			"serviceConfigs": (*Importer).reconcileServiceConfigs,
+		},
+	},
+	{
+		key:        "serviceEdgeRouterPolicies",
+		label:      "ServiceEdgeRouterPolicy",
+		isRequired: (*Importer).IsServiceEdgeRouterPolicyImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.ServiceEdgeRouterPolicyFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		resolve: func(importer *Importer, body map[string]interface{}) error {
+			if err := resolveRoles(body, "serviceRoles", importer.lookupServices); err != nil {
+				return err
+			}
+			return resolveRoles(body, "edgeRouterRoles", importer.lookupEdgeRouters)
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.ServiceEdgeRouterPolicy.PatchServiceEdgeRouterPolicy(service_edge_router_policy.NewPatchServiceEdgeRouterPolicyParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.ServiceEdgeRouterPolicy.DeleteServiceEdgeRouterPolicy(&service_edge_router_policy.DeleteServiceEdgeRouterPolicyParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "servicePolicies",
+		label:      "ServicePolicy",
+		isRequired: (*Importer).IsServicePolicyImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.ServicePolicyFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		resolve: func(importer *Importer, body map[string]interface{}) error {
+			if err := resolveRoles(body, "serviceRoles", importer.lookupServices); err != nil {
+				return err
+			}
+			if err := resolveRoles(body, "identityRoles", importer.lookupIdentities); err != nil {
+				return err
+			}
+			return resolveRoles(body, "postureCheckRoles", importer.lookupPostureChecks)
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.ServicePolicy.PatchServicePolicy(service_policy.NewPatchServicePolicyParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.ServicePolicy.DeleteServicePolicy(&service_policy.DeleteServicePolicyParams{ID: id}, nil)
+			return err
+		},
+	},
+	{
+		key:        "edgeRouterPolicies",
+		label:      "EdgeRouterPolicy",
+		isRequired: (*Importer).IsEdgeRouterPolicyImportRequired,
+		lookupId: func(importer *Importer, name string) string {
+			if detail := mgmt.EdgeRouterPolicyFromFilter(importer.Client, mgmt.NameFilter(name)); detail != nil {
+				return *detail.ID
+			}
+			return ""
+		},
+		resolve: func(importer *Importer, body map[string]interface{}) error {
+			if err := resolveRoles(body, "identityRoles", importer.lookupIdentities); err != nil {
+				return err
+			}
+			return resolveRoles(body, "edgeRouterRoles", importer.lookupEdgeRouters)
+		},
+		patch: func(importer *Importer, id string, body map[string]interface{}) error {
+			_, err := importer.Client.EdgeRouterPolicy.PatchEdgeRouterPolicy(edge_router_policy.NewPatchEdgeRouterPolicyParams(), nil, rawPatchBody(id, body))
+			return err
+		},
+		delete: func(importer *Importer, id string) error {
+			_, err := importer.Client.EdgeRouterPolicy.DeleteEdgeRouterPolicy(&edge_router_policy.DeleteEdgeRouterPolicyParams{ID: id}, nil)
+			return err
+		},
+	},
+}
+
+// Reconcile converges the controller on the input document. The current state is read with the exporter, so both
+// sides are in the same name-referencing format, and compared entity by entity. The resulting plan is printed and,
+// if apply is set, carried out. With prune set, managed entities which are no longer in the document are deleted.
+func (importer *Importer) Reconcile(entities []string, apply bool, prune bool) (*Plan, error) {
+	args := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		args = append(args, strings.ToLower(entity))
+	}
+
+	export := &exporter.Exporter{
+		Err:    importer.Err,
+		Client: importer.Client,
+	}
+	exported, err := export.Execute(nil)
+	if err != nil {
+		return nil, errors.Join(errors.New("unable to read current state from controller"), err)
+	}
+
+	current := map[string][]interface{}{}
+	if err = normalizeDocument(exported, &current); err != nil {
+		return nil, err
+	}
+	desired := map[string][]interface{}{}
+	if err = normalizeDocument(importer.Data, &desired); err != nil {
+		return nil, err
+	}
+
+	var types []*reconcileEntityType
+	for _, entityType := range reconcileEntityTypes {
+		if entityType.isRequired(importer, args) {
+			types = append(types, entityType)
+		}
+	}
+
+	plan, err := buildPlan(desired, current, types, prune)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Print(importer.Out)
+
+	if apply {
+		if err = importer.applyPlan(plan, args); err != nil {
+			return plan, err
+		}
+	}
+
+	return plan, nil
+}
+
+func (importer *Importer) applyPlan(plan *Plan, args []string) error {
+	creates := map[string][]interface{}{}
+	for _, change := range plan.Changes {
+		if change.Action == PlanCreate {
+			creates[change.EntityType] = append(creates[change.EntityType], change.Data)
+		}
+	}
+
+	if len(creates) > 0 {
+		importer.Data = creates
+		if err := importer.Execute(args); err != nil {
+			return err
+		}
+	}
+
+	// the caches may hold entities read before the creates, so start over
+	importer.configCache = map[string]any{}
+	importer.serviceCache = map[string]any{}
+	importer.edgeRouterCache = map[string]any{}
+	importer.authPolicyCache = map[string]any{}
+	importer.extJwtSignersCache = map[string]any{}
+	importer.identityCache = map[string]any{}
+	importer.postureCheckCache = map[string]any{}
+	importer.routerCache = map[string]any{}
+
+	for _, change := range plan.Changes {
+		if change.Action != PlanUpdate {
+			continue
+		}
+		entityType := getReconcileEntityType(change.EntityType)
+		id := entityType.findId(importer, change)
+		if id == "" {
+			return errors.New("error reading " + entityType.label + ": " + change.Name)
+		}
+
+		body := map[string]interface{}{}
+		var updaterFields []string
+		for _, field := range change.Fields {
+			if _, found := entityType.updaters[field]; found {
+				updaterFields = append(updaterFields, field)
+			} else {
+				body[field] = change.Data[field]
+			}
+		}
+		if entityType.resolve != nil {
+			if err := entityType.resolve(importer, body); err != nil {
+				return err
+			}
+		}
+
+		_, _ = internal.FPrintfReusingLine(importer.Err, "Updating %s %s\r", entityType.label, change.Name)
+		if len(body) > 0 {
+			if err := entityType.patch(importer, id, body); err != nil {
+				log.WithError(err).WithField("name", change.Name).Error("Unable to update " + entityType.label)
+				return errors.Join(errors.New("unable to update "+entityType.label+": "+change.Name), err)
+			}
+		}
+		for _, field := range updaterFields {
+			if err := entityType.updaters[field](importer, id, change.Name, change.Data[field]); err != nil {
+				return err
+			}
+		}
+		log.WithFields(map[string]interface{}{
+			"name":   change.Name,
+			"id":     id,
+			"fields": change.Fields,
+		}).Info("Updated " + entityType.label)
+	}
+
+	for _, change := range plan.Changes {
+		if change.Action != PlanDelete {
+			continue
+		}
+		entityType := getReconcileEntityType(change.EntityType)
+		id := entityType.findId(importer, change)
+		if id == "" {
+			continue
+		}
+
+		_, _ = internal.FPrintfReusingLine(importer.Err, "Deleting %s %s\r", entityType.label, change.Name)
+		if err := entityType.delete(importer, id); err != nil {
+			log.WithError(err).WithField("name", change.Name).Error("Unable to delete " + entityType.label)
+			return errors.Join(errors.New("unable to delete "+entityType.label+": "+change.Name), err)
+		}
+		log.WithFields(map[string]interface{}{
+			"name": change.Name,
+			"id":   id,
+		}).Info("Deleted " + entityType.label)
+	}
+
+	_, _ = internal.FPrintfReusingLine(importer.Err, "Reconcile complete\r\n")
+
+	return nil
+}
+
+// buildPlan compares the desired and current documents. Only fields present in the desired document are compared,
+// so fields left out of the input are left alone on the controller. The same goes for tags: only the tag keys set in
+// the input are owned by it, other tags on the controller are kept. Desired entities are tagged as managed, which
+// marks pre-existing entities for an update the first time they are reconciled. Changes to immutable fields fail the
+// plan, since they can't be patched and dropping them would leave the controller out of step with the input.
+func buildPlan(desired, current map[string][]interface{}, types []*reconcileEntityType, prune bool) (*Plan, error) {
+	plan := &Plan{}
+	var immutableErrs []error
+
+	for _, entityType := range types {
+		currentByName := map[string]map[string]interface{}{}
+		for _, item := range current[entityType.key] {
+			if m, ok := item.(map[string]interface{}); ok {
+				if name := entityType.getName(m); name != "" {
+					currentByName[name] = m
+				}
+			}
+		}
+
+		for _, item := range desired[entityType.key] {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid " + entityType.label + " entry in input")
+			}
+			name := entityType.getName(m)
+			if name == "" {
+				return nil, errors.New(entityType.label + " entry in input has no name")
+			}
+
+			tags, _ := m["tags"].(map[string]interface{})
+			if tags == nil {
+				tags = map[string]interface{}{}
+			}
+			tags[ManagedTag] = "true"
+			m["tags"] = tags
+
+			existing, found := currentByName[name]
+			if !found {
+				plan.Changes = append(plan.Changes, &PlanChange{
+					Action:     PlanCreate,
+					EntityType: entityType.key,
+					Name:       name,
+					Data:       m,
+				})
+				continue
+			}
+			m["tags"] = mergeTags(existing["tags"], tags)
+
+			var fields []string
+			for field, value := range m {
+				if field == "name" || slices.Contains(entityType.createOnly, field) {
+					continue
+				}
+				if valuesEqual(value, existing[field]) {
+					continue
+				}
+				if slices.Contains(entityType.immutable, field) {
+					immutableErrs = append(immutableErrs, fmt.Errorf("%s %s: %s can't be changed from %v to %v, delete or rename the %s to replace it",
+						entityType.label, name, field, existing[field], value, entityType.label))
+					continue
+				}
+				fields = append(fields, field)
+			}
+			if len(fields) > 0 {
+				sort.Strings(fields)
+				plan.Changes = append(plan.Changes, &PlanChange{
+					Action:     PlanUpdate,
+					EntityType: entityType.key,
+					Name:       name,
+					Fields:     fields,
+					Data:       m,
+				})
+			}
+		}
+	}
+
+	if len(immutableErrs) > 0 {
+		return nil, errors.Join(append([]error{errors.New("input changes fields which can only be set on create")}, immutableErrs...)...)
+	}
+
+	if prune {
+		for i := len(types) - 1; i >= 0; i-- {
+			entityType := types[i]
+			desiredNames := map[string]struct{}{}
+			for _, item := range desired[entityType.key] {
+				desiredNames[entityType.getName(item.(map[string]interface{}))] = struct{}{}
+			}
+
+			var names []string
+			pruned := map[string]map[string]interface{}{}
+			for _, item := range current[entityType.key] {
+				m, ok := item.(map[string]interface{})
+				if !ok || !isManaged(m) {
+					continue
+				}
+				name := entityType.getName(m)
+				if _, found := desiredNames[name]; !found {
+					names = append(names, name)
+					pruned[name] = m
+				}
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				plan.Changes = append(plan.Changes, &PlanChange{
+					Action:     PlanDelete,
+					EntityType: entityType.key,
+					Name:       name,
+					Data:       pruned[name],
+				})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// findId returns the id of the entity the change applies to, or an empty string if it doesn't exist
+func (self *reconcileEntityType) findId(importer *Importer, change *PlanChange) string {
+	if self.lookupIdOf != nil {
+		return self.lookupIdOf(importer, change.Data)
+	}
+	return self.lookupId(importer, change.Name)
+}
+
+func (self *reconcileEntityType) getName(m map[string]interface{}) string {
+	if self.nameOf != nil {
+		return self.nameOf(m)
+	}
+	name, _ := m["name"].(string)
+	return name
+}
+
+// mergeTags returns the current tags overlaid with the desired ones, so an update only changes the tag keys the input
+// sets. The current tags are copied rather than modified.
+func mergeTags(current interface{}, desired map[string]interface{}) map[string]interface{} {
+	currentTags, _ := current.(map[string]interface{})
+	result := make(map[string]interface{}, len(currentTags)+len(desired))
+	for k, v := range currentTags {
+		result[k] = v
+	}
+	for k, v := range desired {
+		result[k] = v
+	}
+	return result
+}
+
+func isManaged(m map[string]interface{}) bool {
+	tags, _ := m["tags"].(map[string]interface{})
+	return tags[ManagedTag] == "true"
+}
+
+// valuesEqual compares two normalized values. Lists of strings, such as roles and attributes, are compared as sets
+// and missing values are treated as empty.
+func valuesEqual(a, b interface{}) bool {
+	if isEmptyValue(a) && isEmptyValue(b) {
+		return true
+	}
+	return reflect.DeepEqual(canonicalValue(a), canonicalValue(b))
+}
+
+func isEmptyValue(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	case string:
+		return val == ""
+	}
+	return false
+}
+
+func canonicalValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		allStrings := true
+		for i, item := range val {
+			result[i] = canonicalValue(item)
+			if _, ok := item.(string); !ok {
+				allStrings = false
+			}
+		}
+		if allStrings {
+			sort.Slice(result, func(i, j int) bool {
+				return result[i].(string) < result[j].(string)
+			})
+		}
+		return result
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for k, item := range val {
+			if !isEmptyValue(item) {
+				result[k] = canonicalValue(item)
+			}
+		}
+		return result
+	}
+	return v
+}
+
+// normalizeDocument round trips a document through json so that values read from yaml, json and the controller
+// all have the same types
+func normalizeDocument(input interface{}, output *map[string][]interface{}) error {
+	jsonData, err := json.Marshal(input)
+	if err != nil {
+		return errors.Join(errors.New("unable to convert document"), err)
+	}
+	if err = json.Unmarshal(jsonData, output); err != nil {
+		return errors.Join(errors.New("unable to convert document"), err)
+	}
+	return nil
+}
+
+func getReconcileEntityType(key string) *reconcileEntityType {
+	for _, entityType := range reconcileEntityTypes {
+		if entityType.key == key {
+			return entityType
+		}
+	}
+	return nil
+}
+
+func reconcileLabel(key string) string {
+	if entityType := getReconcileEntityType(key); entityType != nil {
+		return entityType.label
+	}
+	return key
+}
+
+func resolveRoles(body map[string]interface{}, field string, lookup func([]string) ([]string, error)) error {
+	roles, found := body[field]
+	if !found {
+		return nil
+	}
+	resolved, err := lookup(toStrings(roles))
+	if err != nil {
+		return err
+	}
+	body[field] = resolved
+	return nil
+}
+
+func (importer *Importer) lookupExtJwtSignerId(ref string) (string, error) {
+	value := strings.TrimPrefix(ref, "@")
+	signer, _ := ascode.GetItemFromCache(importer.extJwtSignersCache, value, func(name string) (interface{}, error) {
+		return mgmt.ExternalJWTSignerFromFilter(importer.Client, mgmt.NameFilter(name)), nil
+	})
+	if isNilItem(signer) {
+		return "", errors.New("error reading ExtJwtSigner: " + value)
+	}
+	return *signer.(*rest_model.ExternalJWTSignerDetail).ID, nil
+}
+
+// isNilItem reports whether a cached lookup found nothing. The lookups return typed pointers, so a miss is not
+// a nil interface.
+func isNilItem(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).IsNil()
+}
+
+func toStrings(v interface{}) []string {
+	var result []string
+	if list, ok := v.([]interface{}); ok {
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+// rawPatchBody replaces the request parameters of a generated patch operation so that only the given fields are
+// sent. The generated patch models serialize unset role lists as null, which would clear them.
+func rawPatchBody(id string, body map[string]interface{}) func(*runtime.ClientOperation) {
+	return func(op *runtime.ClientOperation) {
+		op.Params = runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+			if err := r.SetPathParam("id", id); err != nil {
+				return err
+			}
+			return r.SetBodyParam(body)
+		})
+	}
+}
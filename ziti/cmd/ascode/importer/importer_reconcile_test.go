@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuildPlan(t *testing.T) {
+
+	types := []*reconcileEntityType{
+		getReconcileEntityType("configs"),
+		getReconcileEntityType("services"),
+		getReconcileEntityType("servicePolicies"),
+	}
+
+	managed := map[string]interface{}{ManagedTag: "true"}
+
+	newDesired := func() map[string][]interface{} {
+		return map[string][]interface{}{
+			"configs": {
+				map[string]interface{}{"name": "cfg", "configType": "@host.v1", "data": map[string]interface{}{"port": float64(22)}},
+			},
+			"services": {
+				map[string]interface{}{"name": "ssh", "configs": []interface{}{"@cfg"}, "roleAttributes": []interface{}{"b", "a"}},
+				map[string]interface{}{"name": "web"},
+			},
+			"servicePolicies": {
+				map[string]interface{}{"name": "ssh-dial", "type": "Dial", "identityRoles": []interface{}{"#users"}},
+			},
+		}
+	}
+
+	current := map[string][]interface{}{
+		"configs": {
+			map[string]interface{}{"name": "cfg", "configType": "@host.v1", "data": map[string]interface{}{"port": float64(22)}, "tags": managed},
+		},
+		"services": {
+			map[string]interface{}{"name": "ssh", "configs": []interface{}{"@cfg"}, "roleAttributes": []interface{}{"a", "b"}, "tags": managed},
+			map[string]interface{}{"name": "old", "tags": managed},
+			map[string]interface{}{"name": "manual", "tags": map[string]interface{}{}},
+		},
+		"servicePolicies": {
+			map[string]interface{}{"name": "ssh-dial", "type": "Bind", "identityRoles": []interface{}{"#users"}},
+			map[string]interface{}{"name": "old-dial", "tags": managed},
+		},
+	}
+
+	find := func(plan *Plan, action PlanAction, entityType, name string) *PlanChange {
+		for _, change := range plan.Changes {
+			if change.Action == action && change.EntityType == entityType && change.Name == name {
+				return change
+			}
+		}
+		return nil
+	}
+
+	t.Run("unchanged entities are left alone", func(t *testing.T) {
+		plan, err := buildPlan(newDesired(), current, types, false)
+		assert.NoError(t, err)
+
+		assert.Nil(t, find(plan, PlanUpdate, "configs", "cfg"))
+		assert.Nil(t, find(plan, PlanUpdate, "services", "ssh"), "attribute order should not cause an update")
+	})
+
+	t.Run("missing entities are created", func(t *testing.T) {
+		plan, err := buildPlan(newDesired(), current, types, false)
+		assert.NoError(t, err)
+
+		change := find(plan, PlanCreate, "services", "web")
+		assert.NotNil(t, change)
+		assert.Equal(t, "true", change.Data["tags"].(map[string]interface{})[ManagedTag], "created entities should be tagged")
+	})
+
+	t.Run("changed fields are updated", func(t *testing.T) {
+		plan, err := buildPlan(newDesired(), current, types, false)
+		assert.NoError(t, err)
+
+		change := find(plan, PlanUpdate, "servicePolicies", "ssh-dial")
+		assert.NotNil(t, change)
+		assert.Equal(t, []string{"tags", "type"}, change.Fields)
+	})
+
+	t.Run("tags not in the input are kept", func(t *testing.T) {
+		desired := map[string][]interface{}{
+			"services": {map[string]interface{}{"name": "ssh", "tags": map[string]interface{}{"owner": "ops"}}},
+		}
+		existing := map[string][]interface{}{
+			"services": {map[string]interface{}{"name": "ssh", "tags": map[string]interface{}{ManagedTag: "true", "owner": "dev", "team": "blue"}}},
+		}
+
+		plan, err := buildPlan(desired, existing, types, false)
+		assert.NoError(t, err)
+		change := find(plan, PlanUpdate, "services", "ssh")
+		assert.NotNil(t, change)
+		assert.Equal(t, []string{"tags"}, change.Fields)
+		assert.Equal(t, map[string]interface{}{ManagedTag: "true", "owner": "ops", "team": "blue"}, change.Data["tags"])
+
+		desired["services"][0].(map[string]interface{})["tags"] = map[string]interface{}{"owner": "dev"}
+		plan, err = buildPlan(desired, existing, types, false)
+		assert.NoError(t, err)
+		assert.Nil(t, find(plan, PlanUpdate, "services", "ssh"), "controller only tags should not cause an update")
+	})
+
+	t.Run("deletes only with prune", func(t *testing.T) {
+		plan, err := buildPlan(newDesired(), current, types, false)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, plan.Count(PlanDelete))
+
+		plan, err = buildPlan(newDesired(), current, types, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, plan.Count(PlanDelete))
+		assert.NotNil(t, find(plan, PlanDelete, "services", "old"))
+		assert.Nil(t, find(plan, PlanDelete, "services", "manual"), "unmanaged entities should not be pruned")
+
+		last := plan.Changes[len(plan.Changes)-1]
+		assert.Equal(t, "services", last.EntityType, "deletes should run in reverse dependency order")
+	})
+
+	t.Run("changes to immutable fields fail the plan", func(t *testing.T) {
+		desired := newDesired()
+		desired["configs"][0].(map[string]interface{})["configType"] = "@intercept.v1"
+
+		plan, err := buildPlan(desired, current, types, false)
+		assert.Nil(t, plan)
+		assert.ErrorContains(t, err, "Config cfg: configType can't be changed from @host.v1 to @intercept.v1")
+	})
+
+	t.Run("create only fields are ignored on update", func(t *testing.T) {
+		identityTypes := []*reconcileEntityType{getReconcileEntityType("identities")}
+		desired := map[string][]interface{}{
+			"identities": {map[string]interface{}{"name": "alice", "enrollment": map[string]interface{}{"ott": true}}},
+		}
+		existing := map[string][]interface{}{
+			"identities": {map[string]interface{}{"name": "alice", "tags": managed}},
+		}
+
+		plan, err := buildPlan(desired, existing, identityTypes, false)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(plan.Changes))
+	})
+
+	t.Run("identity service configs are updated", func(t *testing.T) {
+		identityTypes := []*reconcileEntityType{getReconcileEntityType("identities")}
+		desired := map[string][]interface{}{
+			"identities": {map[string]interface{}{"name": "alice", "serviceConfigs": []interface{}{
+				map[string]interface{}{"service": "@ssh", "config": "@cfg"},
+			}}},
+		}
+		existing := map[string][]interface{}{
+			"identities": {map[string]interface{}{"name": "alice", "tags": managed}},
+		}
+
+		plan, err := buildPlan(desired, existing, identityTypes, false)
+		assert.NoError(t, err)
+		change := find(plan, PlanUpdate, "identities", "alice")
+		assert.NotNil(t, change)
+		assert.Equal(t, []string{"serviceConfigs"}, change.Fields)
+		assert.Contains(t, getReconcileEntityType("identities").updaters, "serviceConfigs")
+	})
+
+	t.Run("entries need a name", func(t *testing.T) {
+		desired := map[string][]interface{}{
+			"services": {map[string]interface{}{"roleAttributes": []interface{}{"a"}}},
+		}
+		_, err := buildPlan(desired, current, types, false)
+		assert.Error(t, err)
+	})
+}
+
+func Test_TerminatorName(t *testing.T) {
+	a := terminatorName(map[string]interface{}{"service": "@web/api", "router": "@r1", "address": "tcp:host:80"})
+	b := terminatorName(map[string]interface{}{"service": "@web", "router": "api/@r1", "address": "tcp:host:80"})
+	assert.NotEqual(t, a, b, "names containing a '/' should not collide")
+
+	c := terminatorName(map[string]interface{}{"service": "@web", "router": "@r1", "address": "tcp:host/path"})
+	assert.Equal(t, `"@web"/"@r1"/"tcp:host/path"`, c)
+}
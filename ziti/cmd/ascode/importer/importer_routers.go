@@ -0,0 +1,102 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package importer
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/openziti/edge-api/rest_management_api_client/router"
+	"github.com/openziti/edge-api/rest_model"
+	"github.com/openziti/edge-api/rest_util"
+	"github.com/openziti/ziti/v2/internal"
+	"github.com/openziti/ziti/v2/internal/ascode"
+	"github.com/openziti/ziti/v2/internal/rest/mgmt"
+)
+
+func (importer *Importer) IsTransitRouterImportRequired(args []string) bool {
+	return slices.Contains(args, "all") || len(args) == 0 || // explicit all or nothing specified
+		slices.Contains(args, "router") ||
+		slices.Contains(args, "transit-router")
+}
+
+func (importer *Importer) ProcessTransitRouters(input map[string][]interface{}) (map[string]string, error) {
+
+	var result = map[string]string{}
+	for _, data := range input["routers"] {
+		create := FromMap(data, rest_model.RouterCreate{})
+
+		// see if the router already exists
+		existing := mgmt.TransitRouterFromFilter(importer.Client, mgmt.NameFilter(*create.Name))
+		if existing != nil {
+			log.WithFields(map[string]interface{}{
+				"name":     *create.Name,
+				"routerId": *existing.ID,
+			}).
+				Info("Found existing Router, skipping create")
+			_, _ = internal.FPrintfReusingLine(importer.Err, "Skipping Router %s\r", *create.Name)
+			continue
+		}
+
+		// do the actual create since it doesn't exist
+		_, _ = internal.FPrintfReusingLine(importer.Err, "Creating Router %s\r", *create.Name)
+		log.WithField("name", *create.Name).Debug("Creating Router")
+		created, createErr := importer.Client.Router.CreateTransitRouter(&router.CreateTransitRouterParams{Router: create}, nil)
+		if createErr != nil {
+			if payloadErr, ok := createErr.(rest_util.ApiErrorPayload); ok {
+				log.WithFields(map[string]interface{}{
+					"field":  payloadErr.GetPayload().Error.Cause.APIFieldError.Field,
+					"reason": payloadErr.GetPayload().Error.Cause.APIFieldError.Reason,
+				}).Error("Unable to create Router")
+				return nil, createErr
+			} else {
+				log.WithError(createErr).Error("Unable to create Router")
+				return nil, createErr
+			}
+		}
+		log.WithFields(map[string]interface{}{
+			"name":     *create.Name,
+			"routerId": created.Payload.Data.ID,
+		}).
+			Info("Created Router")
+
+		result[*create.Name] = created.Payload.Data.ID
+	}
+
+	return result, nil
+}
+
+// lookupRouterId resolves an "@name" reference to either an edge router or a transit router
+func (importer *Importer) lookupRouterId(ref string) (string, error) {
+	value := ref
+	if len(ref) > 0 && ref[0:1] == "@" {
+		value = ref[1:]
+	}
+	edgeRouter, _ := ascode.GetItemFromCache(importer.edgeRouterCache, value, func(name string) (interface{}, error) {
+		return mgmt.EdgeRouterFromFilter(importer.Client, mgmt.NameFilter(name)), nil
+	})
+	if !isNilItem(edgeRouter) {
+		return *edgeRouter.(*rest_model.EdgeRouterDetail).ID, nil
+	}
+	transitRouter, _ := ascode.GetItemFromCache(importer.routerCache, value, func(name string) (interface{}, error) {
+		return mgmt.TransitRouterFromFilter(importer.Client, mgmt.NameFilter(name)), nil
+	})
+	if isNilItem(transitRouter) {
+		return "", errors.New("error reading Router: " + value)
+	}
+	return *transitRouter.(*rest_model.RouterDetail).ID, nil
+}
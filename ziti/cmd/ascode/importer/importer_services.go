@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"slices"
+	"strings"
 
 	"github.com/Jeffail/gabs/v2"
 	"github.com/openziti/edge-api/rest_management_api_client/service"
@@ -124,3 +125,11 @@ func (importer *Importer) lookupServices(roles []string) ([]string, error) {
 	}
 	return serviceRoles, nil
 }
+
+func (importer *Importer) lookupServiceId(ref string) (string, error) {
+	serviceRoles, err := importer.lookupServices([]string{"@" + strings.TrimPrefix(ref, "@")})
+	if err != nil {
+		return "", err
+	}
+	return serviceRoles[0][1:], nil
+}
@@ -0,0 +1,148 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/openziti/edge-api/rest_management_api_client/terminator"
+	"github.com/openziti/edge-api/rest_model"
+	"github.com/openziti/edge-api/rest_util"
+	"github.com/openziti/ziti/v2/internal"
+	"github.com/openziti/ziti/v2/internal/rest/mgmt"
+)
+
+func (importer *Importer) IsTerminatorImportRequired(args []string) bool {
+	return slices.Contains(args, "all") || len(args) == 0 || // explicit all or nothing specified
+		slices.Contains(args, "terminator")
+}
+
+func (importer *Importer) ProcessTerminators(input map[string][]interface{}) (map[string]string, error) {
+
+	var result = map[string]string{}
+	for _, data := range input["terminators"] {
+		create := FromMap(data, rest_model.TerminatorCreate{})
+
+		// convert to a json doc so we can query inside the data
+		jsonData, _ := json.Marshal(data)
+		doc, jsonParseError := gabs.ParseJSON(jsonData)
+		if jsonParseError != nil {
+			log.WithError(jsonParseError).Error("Unable to parse json")
+			return nil, jsonParseError
+		}
+
+		// terminators have no name, so they're identified by service, router and address
+		m, _ := data.(map[string]interface{})
+		name := terminatorName(m)
+
+		serviceRef, _ := doc.Path("service").Data().(string)
+		routerRef, _ := doc.Path("router").Data().(string)
+		if serviceRef == "" || routerRef == "" || create.Address == nil {
+			return nil, errors.New("Terminator requires a service, router and address: " + name)
+		}
+
+		// look up the service and router ids from the names
+		serviceId, err := importer.lookupServiceId(serviceRef)
+		if err != nil {
+			return nil, err
+		}
+		create.Service = &serviceId
+
+		routerId, err := importer.lookupRouterId(routerRef)
+		if err != nil {
+			return nil, err
+		}
+		create.Router = &routerId
+
+		// see if the terminator already exists
+		existing := importer.findTerminator(serviceId, routerId, *create.Address)
+		if existing != nil {
+			log.WithFields(map[string]interface{}{
+				"name":         name,
+				"terminatorId": *existing.ID,
+			}).
+				Info("Found existing Terminator, skipping create")
+			_, _ = internal.FPrintfReusingLine(importer.Err, "Skipping Terminator %s\r", name)
+			continue
+		}
+
+		// do the actual create since it doesn't exist
+		_, _ = internal.FPrintfReusingLine(importer.Err, "Creating Terminator %s\r", name)
+		log.WithField("name", name).Debug("Creating Terminator")
+		created, createErr := importer.Client.Terminator.CreateTerminator(&terminator.CreateTerminatorParams{Terminator: create}, nil)
+		if createErr != nil {
+			if payloadErr, ok := createErr.(rest_util.ApiErrorPayload); ok {
+				log.WithFields(map[string]interface{}{
+					"field":  payloadErr.GetPayload().Error.Cause.APIFieldError.Field,
+					"reason": payloadErr.GetPayload().Error.Cause.APIFieldError.Reason,
+				}).Error("Unable to create Terminator")
+				return nil, createErr
+			} else {
+				log.WithError(createErr).Error("Unable to create Terminator")
+				return nil, createErr
+			}
+		}
+		log.WithFields(map[string]interface{}{
+			"name":         name,
+			"terminatorId": created.Payload.Data.ID,
+		}).
+			Info("Created Terminator")
+
+		result[name] = created.Payload.Data.ID
+	}
+
+	return result, nil
+}
+
+// lookupTerminatorId finds a terminator by the service and router names and address in m. An empty id is returned
+// if there's no such terminator.
+func (importer *Importer) lookupTerminatorId(m map[string]interface{}) (string, error) {
+	serviceRef, _ := m["service"].(string)
+	routerRef, _ := m["router"].(string)
+	address, _ := m["address"].(string)
+
+	serviceId, err := importer.lookupServiceId(serviceRef)
+	if err != nil {
+		return "", err
+	}
+	routerId, err := importer.lookupRouterId(routerRef)
+	if err != nil {
+		return "", err
+	}
+	if detail := importer.findTerminator(serviceId, routerId, address); detail != nil {
+		return *detail.ID, nil
+	}
+	return "", nil
+}
+
+func (importer *Importer) findTerminator(serviceId, routerId, address string) *rest_model.TerminatorDetail {
+	filter := fmt.Sprintf(`service = "%s" and router = "%s" and address = "%s"`, serviceId, routerId, address)
+	return mgmt.TerminatorFromFilter(importer.Client, filter)
+}
+
+// terminatorName builds a name for a terminator from the fields which identify it. It's used to match terminators
+// in the input with those on the controller, so each field is quoted, as any of them may contain a '/'.
+func terminatorName(m map[string]interface{}) string {
+	service, _ := m["service"].(string)
+	router, _ := m["router"].(string)
+	address, _ := m["address"].(string)
+	return fmt.Sprintf("%q/%q/%q", service, router, address)
+}
@@ -221,4 +221,27 @@ func Test_InputArgs(t *testing.T) {
 
 	})
 
+	t.Run("router import", func(t *testing.T) {
+
+		assert.True(t, importer.IsTransitRouterImportRequired([]string{"router"}), "should be imported")
+		assert.True(t, importer.IsTransitRouterImportRequired([]string{"transit-router"}), "should be imported")
+		assert.True(t, importer.IsTransitRouterImportRequired([]string{"all"}), "should be imported")
+		assert.True(t, importer.IsTransitRouterImportRequired([]string{}), "should be imported")
+
+		assert.False(t, importer.IsTransitRouterImportRequired([]string{"edge-router"}), "should not be imported")
+		assert.False(t, importer.IsTransitRouterImportRequired([]string{"terminator"}), "should not be imported")
+
+	})
+
+	t.Run("terminator import", func(t *testing.T) {
+
+		assert.True(t, importer.IsTerminatorImportRequired([]string{"terminator"}), "should be imported")
+		assert.True(t, importer.IsTerminatorImportRequired([]string{"all"}), "should be imported")
+		assert.True(t, importer.IsTerminatorImportRequired([]string{}), "should be imported")
+
+		assert.False(t, importer.IsTerminatorImportRequired([]string{"service"}), "should not be imported")
+		assert.False(t, importer.IsTerminatorImportRequired([]string{"router"}), "should not be imported")
+
+	})
+
 }
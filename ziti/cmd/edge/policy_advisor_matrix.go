@@ -0,0 +1,122 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package edge
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/Jeffail/gabs"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/openziti/ziti/v2/ziti/cmd/api"
+	"github.com/openziti/ziti/v2/ziti/cmd/common"
+	"github.com/openziti/ziti/v2/ziti/util"
+	"github.com/spf13/cobra"
+)
+
+type policyAdvisorMatrixOptions struct {
+	api.Options
+	identityFilter string
+	serviceFilter  string
+}
+
+// newPolicyAdvisorMatrixCmd creates the 'edge policy-advisor matrix' command
+func newPolicyAdvisorMatrixCmd(out io.Writer, errOut io.Writer) *cobra.Command {
+	options := &policyAdvisorMatrixOptions{
+		Options: api.Options{
+			CommonOptions: common.CommonOptions{Out: out, Err: errOut},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "matrix",
+		Short: "reports the effective dial/bind access, posture checks and usable edge routers for identities and services",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.Cmd = cmd
+			options.Args = args
+			return runPolicyAdvisorMatrix(options)
+		},
+		SuggestFor: []string{},
+	}
+
+	options.AddCommonFlags(cmd)
+	cmd.Flags().StringVar(&options.identityFilter, "identity-filter", "", "Only include identities matching the given filter, for example: 'name contains \"ops\"'")
+	cmd.Flags().StringVar(&options.serviceFilter, "service-filter", "", "Only include services matching the given filter")
+	cmd.Flags().BoolVar(&options.OutputCSV, "csv", false, "Output CSV instead of a formatted table")
+
+	return cmd
+}
+
+func runPolicyAdvisorMatrix(o *policyAdvisorMatrixOptions) error {
+	params := url.Values{}
+	if o.identityFilter != "" {
+		params.Set("identityFilter", o.identityFilter)
+	}
+	if o.serviceFilter != "" {
+		params.Set("serviceFilter", o.serviceFilter)
+	}
+
+	result, err := util.EdgeControllerList("policy-advisor/access-matrix", params, o.OutputJSONResponse, o.Out, o.Timeout, o.Verbose)
+	if err != nil || o.OutputJSONResponse {
+		return err
+	}
+
+	entries, err := result.S("data").Children()
+	if err != nil && err != gabs.ErrNotObjOrArray {
+		return err
+	}
+
+	names := func(entry *gabs.Container, path string, onlineOnly bool) string {
+		children, _ := entry.S(path).Children()
+		var result []string
+		for _, child := range children {
+			if online, _ := child.S("isOnline").Data().(bool); onlineOnly && !online {
+				continue
+			}
+			result = append(result, entityRefName(child))
+		}
+		return strings.Join(result, "\n")
+	}
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"Identity", "Service", "Dial", "Bind", "Dial Posture Checks", "Bind Posture Checks", "Edge Routers", "Online Edge Routers"})
+
+	for _, entry := range entries {
+		wrapper := api.Wrap(entry)
+		t.AppendRow(table.Row{
+			wrapper.String("identity.name"),
+			wrapper.String("service.name"),
+			wrapper.Bool("dial"),
+			wrapper.Bool("bind"),
+			names(entry, "dialPostureChecks", false),
+			names(entry, "bindPostureChecks", false),
+			names(entry, "edgeRouters", false),
+			names(entry, "edgeRouters", true),
+		})
+	}
+
+	api.RenderTable(&o.Options, t, nil)
+
+	if !o.OutputCSV {
+		_, err = fmt.Fprintf(o.Out, "%v identity/service pairs with access\n", len(entries))
+	}
+	return err
+}
@@ -0,0 +1,149 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package edge
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Jeffail/gabs"
+	"github.com/openziti/ziti/v2/ziti/cmd/api"
+	"github.com/openziti/ziti/v2/ziti/cmd/common"
+	"github.com/openziti/ziti/v2/ziti/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/resty.v1"
+)
+
+// newPolicyAdvisorSimulateCmd creates the 'edge policy-advisor simulate' command
+func newPolicyAdvisorSimulateCmd(out io.Writer, errOut io.Writer) *cobra.Command {
+	options := &policyAdvisorOptions{
+		Options: api.Options{
+			CommonOptions: common.CommonOptions{Out: out, Err: errOut},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "simulate <changes file | ->",
+		Short: "reports the access which would be gained or lost if the given changes were made, without making them",
+		Long: "Reports the dial/bind access, edge router access and service reachability which would be gained or lost " +
+			"if the given changes were made. Nothing is committed. The changes file uses the management API batch format, " +
+			"for example:\n\n" +
+			`{"operations": [{"method": "patch", "entityType": "service-policies", "id": "<id>", "body": {"identityRoles": ["#ops"]}}]}`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.Cmd = cmd
+			options.Args = args
+			return runPolicyAdvisorSimulate(options)
+		},
+		SuggestFor: []string{},
+	}
+
+	options.AddCommonFlags(cmd)
+
+	return cmd
+}
+
+func runPolicyAdvisorSimulate(o *policyAdvisorOptions) error {
+	var body []byte
+	var err error
+	if o.Args[0] == "-" {
+		body, err = io.ReadAll(os.Stdin)
+	} else {
+		body, err = os.ReadFile(o.Args[0])
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to read changes from %v", o.Args[0])
+	}
+
+	result, err := util.EdgeControllerRequest("policy-advisor/simulate", o.Out, o.OutputJSONResponse, o.Timeout, o.Verbose,
+		func(request *resty.Request, url string) (*resty.Response, error) {
+			return request.SetBody(body).Post(url)
+		})
+
+	if err != nil || o.OutputJSONResponse {
+		return err
+	}
+
+	data := result.S("data")
+	changeCount := 0
+
+	outputSection := func(title, path string, describe func(change *gabs.Container) string) error {
+		changes, err := data.S(path).Children()
+		if err != nil && err != gabs.ErrNotObjOrArray {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+
+		changeCount += len(changes)
+		if _, err = fmt.Fprintf(o.Out, "%v:\n", title); err != nil {
+			return err
+		}
+		for _, change := range changes {
+			marker := "+"
+			if change.S("change").Data() == "lost" {
+				marker = "-"
+			}
+			if _, err = fmt.Fprintf(o.Out, "  %v %v\n", marker, describe(change)); err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintln(o.Out)
+		return err
+	}
+
+	if err = outputSection("Service Access", "serviceAccess", func(change *gabs.Container) string {
+		return fmt.Sprintf("%v -> %v (%v)", entityRefName(change.S("identity")), entityRefName(change.S("service")), change.S("policyType").Data())
+	}); err != nil {
+		return err
+	}
+
+	if err = outputSection("Identity Edge Router Access", "edgeRouterAccess", func(change *gabs.Container) string {
+		return fmt.Sprintf("%v -> %v", entityRefName(change.S("identity")), entityRefName(change.S("edgeRouter")))
+	}); err != nil {
+		return err
+	}
+
+	if err = outputSection("Service Edge Router Access", "serviceEdgeRouters", func(change *gabs.Container) string {
+		return fmt.Sprintf("%v -> %v", entityRefName(change.S("service")), entityRefName(change.S("edgeRouter")))
+	}); err != nil {
+		return err
+	}
+
+	if err = outputSection("Service Reachability", "reachability", func(change *gabs.Container) string {
+		return fmt.Sprintf("%v -> %v", entityRefName(change.S("identity")), entityRefName(change.S("service")))
+	}); err != nil {
+		return err
+	}
+
+	if changeCount == 0 {
+		_, err = fmt.Fprintln(o.Out, "The changes would not gain or lose any access")
+	}
+	return err
+}
+
+// entityRefName returns the name of a policy advisor entity reference, or its id if it has no name
+func entityRefName(ref *gabs.Container) string {
+	if name, _ := ref.S("name").Data().(string); name != "" {
+		return name
+	}
+	id, _ := ref.S("id").Data().(string)
+	return id
+}
@@ -2,7 +2,10 @@ package fabric
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/openziti/ziti/v2/controller/rest_client/cluster"
 	"github.com/openziti/ziti/v2/controller/rest_model"
@@ -19,6 +22,7 @@ func NewClusterCmd(p common.OptionsProvider) *cobra.Command {
 	}
 
 	cmd.AddCommand(newClusterListMembersCmd(p))
+	cmd.AddCommand(newClusterStatusCmd(p))
 	cmd.AddCommand(newClusterAddMemberCmd(p))
 	cmd.AddCommand(newClusterRemoveMemberCmd(p))
 	cmd.AddCommand(newClusterTransferLeadershipCmd(p))
@@ -74,6 +78,95 @@ func (self *clusterListMembersAction) outputClusterMembers(o *api.Options, membe
 	return nil
 }
 
+func newClusterStatusCmd(p common.OptionsProvider) *cobra.Command {
+	action := &clusterStatusAction{
+		Options: api.Options{CommonOptions: p()},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "show cluster health, including per-member replication lag and how many member failures can be tolerated",
+		Args:  cobra.ExactArgs(0),
+		RunE:  action.run,
+	}
+
+	// allow interspersing positional args and flags
+	cmd.Flags().SetInterspersed(true)
+	action.AddCommonFlags(cmd)
+
+	return cmd
+}
+
+type clusterStatusAction struct {
+	api.Options
+}
+
+func (self *clusterStatusAction) run(cmd *cobra.Command, _ []string) error {
+	self.Cmd = cmd
+	client, err := util.NewFabricManagementClient(self)
+	if err != nil {
+		return err
+	}
+	status, err := client.Cluster.ClusterStatus(&cluster.ClusterStatusParams{
+		Context: context.Background(),
+	}, nil)
+	return outputResult(status, err, &self.Options, self.outputClusterStatus)
+}
+
+func (self *clusterStatusAction) outputClusterStatus(o *api.Options, result *cluster.ClusterStatusOK) error {
+	status := result.Payload.Data
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
+	t.AppendHeader(table.Row{"Id", "Voter", "Leader", "Version", "State", "Commit", "Applied", "Lag", "Last Contact", "Snapshot Index", "Snapshot Age", "Error"})
+	for _, member := range status.Members {
+		raftStatus := member.Raft
+		if raftStatus == nil {
+			raftStatus = &rest_model.ClusterMemberRaftStatus{}
+		}
+		lastContact := "-"
+		if !valOrDefault(member.Leader) {
+			lastContact = age(raftStatus.LastContact)
+		}
+		t.AppendRow(table.Row{
+			valOrDefault(member.ID),
+			valOrDefault(member.Voter),
+			valOrDefault(member.Leader),
+			valOrDefault(member.Version),
+			valOrDefault(raftStatus.State),
+			valOrDefault(raftStatus.CommitIndex),
+			valOrDefault(raftStatus.AppliedIndex),
+			valOrDefault(member.Lag),
+			lastContact,
+			valOrDefault(raftStatus.LastSnapshotIndex),
+			age(raftStatus.LastSnapshotTime),
+			member.Error,
+		})
+	}
+	api.RenderTable(o, t, nil)
+
+	_, err := fmt.Fprintf(self.Out, "leader: %v, read only: %v, version skew: %v\n",
+		valOrDefault(status.LeaderID), valOrDefault(status.ReadOnly), valOrDefault(status.VersionSkew))
+	if err != nil || status.Quorum == nil {
+		return err
+	}
+
+	quorum := status.Quorum
+	_, err = fmt.Fprintf(self.Out, "voters: %v, healthy voters: %v, quorum: %v, failures tolerable: %v\n",
+		valOrDefault(quorum.Voters), valOrDefault(quorum.HealthyVoters),
+		valOrDefault(quorum.Quorum), valOrDefault(quorum.FailuresTolerable))
+	return err
+}
+
+// age renders a timestamp as the time elapsed since then
+func age(val strfmt.DateTime) string {
+	t := time.Time(val)
+	if t.IsZero() {
+		return "-"
+	}
+	return time.Since(t).Round(time.Second).String()
+}
+
 func newClusterAddMemberCmd(p common.OptionsProvider) *cobra.Command {
 	action := &clusterAddMemberAction{
 		Options: api.Options{CommonOptions: p()},
@@ -89,7 +182,10 @@ func newClusterAddMemberCmd(p common.OptionsProvider) *cobra.Command {
 	// allow interspersing positional args and flags
 	cmd.Flags().SetInterspersed(true)
 	action.AddCommonFlags(cmd)
+	cmd.Flags().BoolVar(&action.nonVoting, "non-voter", false, "Adds the member as a non-voter, which replicates state and serves reads, but doesn't count towards quorum. "+
+		"If the member already exists with the same address, it's promoted or demoted in place")
 	cmd.Flags().BoolVar(&action.nonVoting, "non-voting", false, "Allows adding a non-voting member to the cluster")
+	_ = cmd.Flags().MarkDeprecated("non-voting", "use --non-voter")
 
 	return cmd
 }
@@ -31,6 +31,7 @@ func NewInspectCmd(p common.OptionsProvider) *cobra.Command {
 	cmd.AddCommand(action.newInspectSubCmd(p, "cluster-config", "gets a subset of cluster configuration from the requested nodes"))
 	cmd.AddCommand(action.newInspectSubCmd(p, "connected-routers", "gets information about which routers are connected to which controllers"))
 	cmd.AddCommand(action.newInspectSubCmd(p, "connected-peers", "gets information about which controllers are connected to which other controllers in the cluster"))
+	cmd.AddCommand(action.newInspectSubCmd(p, inspectCommon.ClusterMemberStatusKey, "gets each controller's view of its raft log, applied index and last snapshot"))
 	cmd.AddCommand(action.newInspectSubCmd(p, "links", "gets information from routers about their view of links"))
 	cmd.AddCommand(action.newInspectSubCmd(p, inspectCommon.SdkTerminatorsKey, "gets information from routers about their view of sdk terminators"))
 	cmd.AddCommand(action.newInspectSubCmd(p, inspectCommon.ErtTerminatorsKey, "gets information from routers about their view of ER/T terminators"))
@@ -36,6 +36,7 @@ type streamEventsAction struct {
 	all          bool
 	alerts       bool
 	apiSessions  bool
+	backups      bool
 	circuits     bool
 	cluster      bool
 	connect      bool
@@ -47,6 +48,7 @@ type streamEventsAction struct {
 	services     bool
 	sessions     bool
 	sdk          bool
+	signingKeys  bool
 	terminators  bool
 	usage        bool
 
@@ -75,6 +77,7 @@ func NewStreamEventsCmd(p common.OptionsProvider) *cobra.Command {
 	streamEventsCmd.Flags().BoolVar(&action.all, "all", false, "Include all events")
 	streamEventsCmd.Flags().BoolVar(&action.alerts, "alerts", false, "Include alert events")
 	streamEventsCmd.Flags().BoolVar(&action.apiSessions, "api-sessions", false, "Include api-session events")
+	streamEventsCmd.Flags().BoolVar(&action.backups, "backups", false, "Include database backup events")
 	streamEventsCmd.Flags().BoolVar(&action.circuits, "circuits", false, "Include circuit events")
 	streamEventsCmd.Flags().BoolVar(&action.cluster, "cluster", false, "Include cluster events")
 	streamEventsCmd.Flags().BoolVar(&action.connect, "connect", false, "Include connect events")
@@ -86,6 +89,7 @@ func NewStreamEventsCmd(p common.OptionsProvider) *cobra.Command {
 	streamEventsCmd.Flags().BoolVar(&action.sdk, "sdk", false, "Include sdk events")
 	streamEventsCmd.Flags().BoolVar(&action.services, "services", false, "Include service events")
 	streamEventsCmd.Flags().BoolVar(&action.sessions, "sessions", false, "Include session events")
+	streamEventsCmd.Flags().BoolVar(&action.signingKeys, "signing-keys", false, "Include signing key rotation events")
 	streamEventsCmd.Flags().BoolVar(&action.terminators, "terminators", false, "Include terminators events")
 	streamEventsCmd.Flags().BoolVar(&action.usage, "usage", false, "Include usage events")
 	streamEventsCmd.Flags().DurationVar(&action.entityCountsInterval, "entity-counts-interval", 5*time.Minute, "Specify the entity count event interval")
@@ -110,6 +114,12 @@ func (self *streamEventsAction) buildSubscriptions(cmd *cobra.Command) []*event.
 		})
 	}
 
+	if self.backups || (self.all && !cmd.Flags().Changed("backups")) {
+		subscriptions = append(subscriptions, &event.Subscription{
+			Type: event.BackupEventNS,
+		})
+	}
+
 	if self.circuits || (self.all && !cmd.Flags().Changed("circuits")) {
 		subscriptions = append(subscriptions, &event.Subscription{
 			Type: event.CircuitEventNS,
@@ -194,6 +204,12 @@ func (self *streamEventsAction) buildSubscriptions(cmd *cobra.Command) []*event.
 		})
 	}
 
+	if self.signingKeys || (self.all && !cmd.Flags().Changed("signing-keys")) {
+		subscriptions = append(subscriptions, &event.Subscription{
+			Type: event.SigningKeyEventNS,
+		})
+	}
+
 	if self.terminators || (self.all && !cmd.Flags().Changed("terminators")) {
 		subscriptions = append(subscriptions, &event.Subscription{
 			Type: event.TerminatorEventNS,
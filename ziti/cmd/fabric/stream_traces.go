@@ -19,6 +19,7 @@ package fabric
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"reflect"
 	"sort"
 	"time"
@@ -28,6 +29,8 @@ import (
 	"github.com/openziti/sdk-golang/v2/xgress"
 	"github.com/openziti/ziti/v2/common/pb/ctrl_pb"
 	"github.com/openziti/ziti/v2/common/pb/mgmt_pb"
+	"github.com/openziti/ziti/v2/common/trace"
+	"github.com/openziti/ziti/v2/common/trace/pcapng"
 	"github.com/openziti/ziti/v2/ziti/cmd/api"
 	"github.com/openziti/ziti/v2/ziti/cmd/common"
 	"github.com/spf13/cobra"
@@ -36,6 +39,9 @@ import (
 
 type streamTracesAction struct {
 	api.Options
+	pcapFile          string
+	pcapEncapsulation string
+	pcapSink          trace.EventHandler
 }
 
 func NewStreamTracesCmd(p common.OptionsProvider) *cobra.Command {
@@ -52,6 +58,9 @@ func NewStreamTracesCmd(p common.OptionsProvider) *cobra.Command {
 	}
 
 	action.AddCommonFlags(streamTracesCmd)
+	streamTracesCmd.Flags().StringVar(&action.pcapFile, "pcap", "", "Also write trace events to the given pcapng file, for analysis in Wireshark")
+	streamTracesCmd.Flags().StringVar(&action.pcapEncapsulation, "pcap-encapsulation", string(pcapng.EncapsulationUser0),
+		"How trace records are framed in the pcapng file. One of user0 (LINKTYPE_USER0) or udp (synthetic IPv4/UDP on port 6262)")
 
 	return streamTracesCmd
 }
@@ -78,6 +87,25 @@ func (self *streamTracesAction) streamTraces(_ *cobra.Command, args []string) {
 		}
 	}
 
+	if self.pcapFile != "" {
+		encapsulation, err := pcapng.ParseEncapsulation(self.pcapEncapsulation)
+		if err != nil {
+			panic(err)
+		}
+
+		pcapFile, err := os.Create(self.pcapFile)
+		if err != nil {
+			panic(err)
+		}
+		defer func() { _ = pcapFile.Close() }()
+
+		writer, err := pcapng.NewWriter(pcapFile, encapsulation, "ziti fabric stream traces")
+		if err != nil {
+			panic(err)
+		}
+		self.pcapSink = trace.NewPcapngSink(writer)
+	}
+
 	closeNotify := make(chan struct{})
 
 	bindHandler := func(binding channel.Binding) error {
@@ -149,6 +177,10 @@ func (self *streamTracesAction) HandleReceive(msg *channel.Message, _ channel.Ch
 		panic(err)
 	}
 
+	if self.pcapSink != nil {
+		self.pcapSink.Accept(event)
+	}
+
 	flow := "->"
 	if event.IsRx {
 		flow = "<-"
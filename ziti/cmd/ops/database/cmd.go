@@ -39,6 +39,7 @@ func NewCmdDb(out io.Writer, errOut io.Writer) *cobra.Command {
 	cmd.AddCommand(exploreCmd)
 	cmd.AddCommand(NewCompactAction())
 	cmd.AddCommand(NewDiskUsageAction())
+	cmd.AddCommand(NewDiffAction())
 	cmd.AddCommand(NewAddDebugAdminAction())
 	cmd.AddCommand(NewAnonymizeAction())
 	cmd.AddCommand(NewDeleteSessionsFromDbCmd())
@@ -0,0 +1,409 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openziti/ziti/v2/controller/command"
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/spf13/cobra"
+	"go.etcd.io/bbolt"
+	bbolterrors "go.etcd.io/bbolt/errors"
+)
+
+type DiffAction struct {
+	outputJson    bool
+	entityTypes   []string
+	ignoredFields []string
+	out           io.Writer
+}
+
+func NewDiffAction() *cobra.Command {
+	action := &DiffAction{}
+
+	cmd := &cobra.Command{
+		Use:   "diff <a.db> <b.db>",
+		Short: "Reports entity level differences between two controller databases",
+		Long: "Reports the entities which were added, removed or changed going from the first controller database to the " +
+			"second, including which fields changed. Both databases are opened read-only. Useful for investigating " +
+			"diverged cluster members or the effects of a migration.",
+		Args: cobra.ExactArgs(2),
+		RunE: action.Run,
+	}
+
+	cmd.Flags().BoolVarP(&action.outputJson, "json", "j", false, "Output the differences as JSON")
+	cmd.Flags().StringSliceVarP(&action.entityTypes, "entity-type", "t", nil, "Only compare the given entity types, for example: identities,services")
+	cmd.Flags().StringSliceVar(&action.ignoredFields, "ignore-field", nil, "Fields to ignore when comparing entities, for example: updatedAt")
+
+	return cmd
+}
+
+type dbDiff struct {
+	RaftIndexA uint64         `json:"raftIndexA"`
+	RaftIndexB uint64         `json:"raftIndexB"`
+	Stores     []*dbDiffStore `json:"stores"`
+}
+
+type dbDiffStore struct {
+	EntityType string          `json:"entityType"`
+	Added      []*dbDiffEntity `json:"added,omitempty"`
+	Removed    []*dbDiffEntity `json:"removed,omitempty"`
+	Changed    []*dbDiffEntity `json:"changed,omitempty"`
+}
+
+type dbDiffEntity struct {
+	Id     string         `json:"id"`
+	Name   string         `json:"name,omitempty"`
+	Fields []*dbDiffField `json:"fields,omitempty"`
+}
+
+type dbDiffField struct {
+	Field string  `json:"field"`
+	A     *string `json:"a"`
+	B     *string `json:"b"`
+}
+
+// dbRow holds the flattened fields of an entity, keyed by field path
+type dbRow map[string]*string
+
+// Run implements this command
+func (self *DiffAction) Run(cmd *cobra.Command, args []string) error {
+	self.out = cmd.OutOrStdout()
+
+	stores, cleanup, err := self.initStores()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	dbA, err := openReadOnly(args[0])
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbA.Close() }()
+
+	dbB, err := openReadOnly(args[1])
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbB.Close() }()
+
+	var storeList []boltz.Store
+	for _, store := range stores.GetStores() {
+		// child stores keep their fields in a sub-bucket of the parent entity, so they're covered by the parent
+		if store.IsChildStore() {
+			continue
+		}
+		if len(self.entityTypes) > 0 && !slices.Contains(self.entityTypes, store.GetEntityType()) {
+			continue
+		}
+		storeList = append(storeList, store)
+	}
+	sort.Slice(storeList, func(i, j int) bool {
+		return storeList[i].GetEntityType() < storeList[j].GetEntityType()
+	})
+
+	result := &dbDiff{}
+
+	err = dbA.View(func(txA *bbolt.Tx) error {
+		return dbB.View(func(txB *bbolt.Tx) error {
+			result.RaftIndexA = db.LoadCurrentRaftIndex(txA)
+			result.RaftIndexB = db.LoadCurrentRaftIndex(txB)
+
+			for _, store := range storeList {
+				if storeDiff := self.diffStore(store, txA, txB); storeDiff != nil {
+					result.Stores = append(result.Stores, storeDiff)
+				}
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if self.outputJson {
+		encoder := json.NewEncoder(self.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	return self.outputText(result)
+}
+
+// initStores creates the store definitions against a scratch database. Initializing stores runs migrations, which
+// must not be applied to the databases being compared.
+func (self *DiffAction) initStores() (*db.Stores, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "ziti-db-diff")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scratchDb, err := db.Open(filepath.Join(tmpDir, "scratch.db"))
+	if err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return nil, nil, err
+	}
+
+	cleanup := func() {
+		_ = scratchDb.Close()
+		_ = os.RemoveAll(tmpDir)
+	}
+
+	stores, err := db.InitStores(scratchDb, command.NoOpRateLimiter{}, nil)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	return stores, cleanup, nil
+}
+
+func openReadOnly(path string) (*bbolt.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	// a running controller holds an exclusive lock on its database, so only wait briefly for it
+	options := *bbolt.DefaultOptions
+	options.ReadOnly = true
+	options.Timeout = time.Second
+
+	result, err := bbolt.Open(path, 0400, &options)
+	if errors.Is(err, bbolterrors.ErrTimeout) {
+		return nil, fmt.Errorf("unable to open database [%s], it is in use, most likely by a running controller. "+
+			"Stop the controller or diff a snapshot of the database instead", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open database [%s] (%w)", path, err)
+	}
+	return result, nil
+}
+
+func (self *DiffAction) diffStore(store boltz.Store, txA, txB *bbolt.Tx) *dbDiffStore {
+	rowsA := self.loadRows(store, txA)
+	rowsB := self.loadRows(store, txB)
+
+	result := &dbDiffStore{
+		EntityType: store.GetEntityType(),
+	}
+
+	for _, id := range sortedKeys(rowsA) {
+		rowA := rowsA[id]
+		rowB, found := rowsB[id]
+		if !found {
+			result.Removed = append(result.Removed, &dbDiffEntity{Id: id, Name: rowA.name()})
+			continue
+		}
+
+		if fields := diffRows(rowA, rowB); len(fields) > 0 {
+			result.Changed = append(result.Changed, &dbDiffEntity{
+				Id:     id,
+				Name:   rowB.name(),
+				Fields: fields,
+			})
+		}
+	}
+
+	for _, id := range sortedKeys(rowsB) {
+		if _, found := rowsA[id]; !found {
+			result.Added = append(result.Added, &dbDiffEntity{Id: id, Name: rowsB[id].name()})
+		}
+	}
+
+	if len(result.Added) == 0 && len(result.Removed) == 0 && len(result.Changed) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+func (self *DiffAction) loadRows(store boltz.Store, tx *bbolt.Tx) map[string]dbRow {
+	result := map[string]dbRow{}
+
+	entitiesBucket := store.GetEntitiesBucket(tx)
+	if entitiesBucket == nil || entitiesBucket.Bucket == nil {
+		return result
+	}
+
+	cursor := entitiesBucket.Cursor()
+	for key, value := cursor.First(); key != nil; key, value = cursor.Next() {
+		if value != nil {
+			continue
+		}
+		entityBucket := entitiesBucket.Bucket.Bucket(key)
+		if entityBucket == nil {
+			continue
+		}
+
+		row := dbRow{}
+		boltz.Traverse(entityBucket, "", &rowVisitor{
+			row:           row,
+			ignoredFields: self.ignoredFields,
+		})
+		result[string(key)] = row
+	}
+
+	return result
+}
+
+func (row dbRow) name() string {
+	if name := row[db.FieldName]; name != nil {
+		return *name
+	}
+	return ""
+}
+
+type rowVisitor struct {
+	row           dbRow
+	ignoredFields []string
+}
+
+func (self *rowVisitor) VisitBucket(string, []byte, *bbolt.Bucket) bool {
+	return true
+}
+
+func (self *rowVisitor) VisitKeyValue(path string, key, value []byte) bool {
+	field := strings.TrimPrefix(path+"/"+string(key), "/")
+	if slices.Contains(self.ignoredFields, field) {
+		return true
+	}
+
+	fieldType, fieldValue := boltz.GetTypeAndValue(value)
+	if strVal := boltz.FieldToString(fieldType, fieldValue); strVal != nil {
+		self.row[field] = strVal
+	} else if fieldType == boltz.TypeNil {
+		self.row[field] = nil
+	} else {
+		// set members and other untyped values are compared by their raw bytes
+		rawVal := fmt.Sprintf("%x", value)
+		self.row[field] = &rawVal
+	}
+	return true
+}
+
+func diffRows(a, b dbRow) []*dbDiffField {
+	var result []*dbDiffField
+
+	fields := map[string]struct{}{}
+	for field := range a {
+		fields[field] = struct{}{}
+	}
+	for field := range b {
+		fields[field] = struct{}{}
+	}
+
+	for _, field := range sortedKeys(fields) {
+		valA, inA := a[field]
+		valB, inB := b[field]
+		if inA == inB && equalStringPtrs(valA, valB) {
+			continue
+		}
+		result = append(result, &dbDiffField{
+			Field: field,
+			A:     valA,
+			B:     valB,
+		})
+	}
+
+	return result
+}
+
+func equalStringPtrs(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (self *DiffAction) outputText(result *dbDiff) error {
+	if _, err := fmt.Fprintf(self.out, "raft index: a=%d, b=%d\n", result.RaftIndexA, result.RaftIndexB); err != nil {
+		return err
+	}
+
+	if len(result.Stores) == 0 {
+		_, err := fmt.Fprintln(self.out, "no differences found")
+		return err
+	}
+
+	describe := func(entity *dbDiffEntity) string {
+		if entity.Name == "" {
+			return entity.Id
+		}
+		return fmt.Sprintf("%s (%s)", entity.Id, entity.Name)
+	}
+
+	fieldValue := func(val *string) string {
+		if val == nil {
+			return "<nil>"
+		}
+		return fmt.Sprintf("%q", *val)
+	}
+
+	for _, store := range result.Stores {
+		if _, err := fmt.Fprintf(self.out, "\n%s: %d added, %d removed, %d changed\n",
+			store.EntityType, len(store.Added), len(store.Removed), len(store.Changed)); err != nil {
+			return err
+		}
+
+		for _, entity := range store.Added {
+			if _, err := fmt.Fprintf(self.out, "  + %s\n", describe(entity)); err != nil {
+				return err
+			}
+		}
+
+		for _, entity := range store.Removed {
+			if _, err := fmt.Fprintf(self.out, "  - %s\n", describe(entity)); err != nil {
+				return err
+			}
+		}
+
+		for _, entity := range store.Changed {
+			if _, err := fmt.Fprintf(self.out, "  ~ %s\n", describe(entity)); err != nil {
+				return err
+			}
+			for _, field := range entity.Fields {
+				if _, err := fmt.Fprintf(self.out, "      %s: %s -> %s\n", field.Field, fieldValue(field.A), fieldValue(field.B)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+	Copyright NetFoundry Inc.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openziti/ziti/v2/controller/db"
+	"github.com/openziti/ziti/v2/controller/storage/boltz"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+type testService struct {
+	id        string
+	name      string
+	updatedAt time.Time
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+
+	// a has svc-removed, b has svc-added, svc-changed is renamed and svc-touched only differs by updatedAt
+	dbA := newDiffFixture(t, "a.db",
+		testService{id: "svc-removed", name: "removed", updatedAt: now},
+		testService{id: "svc-changed", name: "before", updatedAt: now},
+		testService{id: "svc-same", name: "same", updatedAt: now},
+		testService{id: "svc-touched", name: "touched", updatedAt: now},
+	)
+	dbB := newDiffFixture(t, "b.db",
+		testService{id: "svc-added", name: "added", updatedAt: now},
+		testService{id: "svc-changed", name: "after", updatedAt: now},
+		testService{id: "svc-same", name: "same", updatedAt: now},
+		testService{id: "svc-touched", name: "touched", updatedAt: now.Add(time.Minute)},
+	)
+
+	runDiff := func(t *testing.T, args ...string) *dbDiff {
+		req := require.New(t)
+		out := &bytes.Buffer{}
+		cmd := NewDiffAction()
+		cmd.SetOut(out)
+		cmd.SetArgs(append([]string{"--json", "--entity-type", db.EntityTypeServices}, append(args, dbA, dbB)...))
+		req.NoError(cmd.Execute())
+
+		result := &dbDiff{}
+		req.NoError(json.Unmarshal(out.Bytes(), result))
+		return result
+	}
+
+	t.Run("added, removed and changed entities are reported", func(t *testing.T) {
+		req := require.New(t)
+		result := runDiff(t)
+
+		req.Len(result.Stores, 1)
+		store := result.Stores[0]
+		req.Equal(db.EntityTypeServices, store.EntityType)
+
+		req.Len(store.Added, 1)
+		req.Equal("svc-added", store.Added[0].Id)
+		req.Equal("added", store.Added[0].Name)
+
+		req.Len(store.Removed, 1)
+		req.Equal("svc-removed", store.Removed[0].Id)
+		req.Equal("removed", store.Removed[0].Name)
+
+		req.Len(store.Changed, 2)
+		req.Equal("svc-changed", store.Changed[0].Id)
+		req.Equal("after", store.Changed[0].Name)
+		req.Len(store.Changed[0].Fields, 1)
+		req.Equal(db.FieldName, store.Changed[0].Fields[0].Field)
+		req.Equal("before", *store.Changed[0].Fields[0].A)
+		req.Equal("after", *store.Changed[0].Fields[0].B)
+
+		req.Equal("svc-touched", store.Changed[1].Id)
+		req.Len(store.Changed[1].Fields, 1)
+		req.Equal(boltz.FieldUpdatedAt, store.Changed[1].Fields[0].Field)
+	})
+
+	t.Run("ignored fields aren't compared", func(t *testing.T) {
+		req := require.New(t)
+		result := runDiff(t, "--ignore-field", boltz.FieldUpdatedAt)
+
+		req.Len(result.Stores, 1)
+		store := result.Stores[0]
+		req.Len(store.Added, 1)
+		req.Len(store.Removed, 1)
+		req.Len(store.Changed, 1)
+		req.Equal("svc-changed", store.Changed[0].Id)
+	})
+
+	t.Run("identical databases have no differences", func(t *testing.T) {
+		req := require.New(t)
+		out := &bytes.Buffer{}
+		cmd := NewDiffAction()
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{dbA, dbA})
+		req.NoError(cmd.Execute())
+		req.Contains(out.String(), "no differences found")
+	})
+
+	t.Run("databases in use are reported rather than waited on", func(t *testing.T) {
+		req := require.New(t)
+		live, err := bbolt.Open(dbA, 0600, nil)
+		req.NoError(err)
+		defer func() { _ = live.Close() }()
+
+		start := time.Now()
+		_, err = openReadOnly(dbA)
+		req.ErrorContains(err, "it is in use")
+		req.Less(time.Since(start), 5*time.Second)
+	})
+
+	t.Run("text output marks each kind of difference", func(t *testing.T) {
+		req := require.New(t)
+		out := &bytes.Buffer{}
+		cmd := NewDiffAction()
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{"--entity-type", db.EntityTypeServices, dbA, dbB})
+		req.NoError(cmd.Execute())
+
+		output := out.String()
+		req.Contains(output, "services: 1 added, 1 removed, 2 changed")
+		req.Contains(output, "  + svc-added (added)")
+		req.Contains(output, "  - svc-removed (removed)")
+		req.Contains(output, "  ~ svc-changed (after)")
+		req.Contains(output, `      name: "before" -> "after"`)
+	})
+}
+
+// newDiffFixture writes the given services into a new database and returns its path
+func newDiffFixture(t *testing.T, name string, services ...testService) string {
+	req := require.New(t)
+	path := filepath.Join(t.TempDir(), name)
+
+	fixture, err := db.Open(path)
+	req.NoError(err)
+
+	err = fixture.Update(nil, func(ctx boltz.MutateContext) error {
+		for _, service := range services {
+			entityBucket := boltz.GetOrCreatePath(ctx.Tx(), db.RootBucket, db.EntityTypeServices, service.id)
+			entityBucket.SetString(db.FieldName, service.name, nil)
+			entityBucket.SetTime(boltz.FieldUpdatedAt, service.updatedAt, nil)
+			if err := entityBucket.GetError(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	req.NoError(err)
+	req.NoError(fixture.Close())
+
+	return path
+}